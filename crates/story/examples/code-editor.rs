@@ -4,8 +4,11 @@ use gpui_component::{
     dropdown::{Dropdown, DropdownEvent, DropdownState},
     h_flex,
     highlighter::{Language, LanguageConfig, LanguageRegistry},
-    input::{InputEvent, InputState, Marker, TabSize, TextInput},
-    v_flex, ActiveTheme, ContextModal, IconName, IndexPath, Selectable, Sizable,
+    input::{
+        CompletionItem, CompletionKind, CompletionTrigger, GoToLine, InputEvent, InputState,
+        Marker, TabSize, TextInput,
+    },
+    v_flex, ActiveTheme, IconName, IndexPath, Selectable, Sizable,
 };
 use story::Assets;
 
@@ -23,12 +26,53 @@ fn init(cx: &mut App) {
     );
 }
 
+/// A simple completion provider, the items are filtered by the editor.
+fn completion_items(trigger: CompletionTrigger) -> Vec<CompletionItem> {
+    if trigger == CompletionTrigger::Character('.') {
+        return vec![
+            CompletionItem::new("clone")
+                .kind(CompletionKind::Function)
+                .detail("fn(&self) -> Self")
+                .insert_text("clone()"),
+            CompletionItem::new("to_string")
+                .kind(CompletionKind::Function)
+                .detail("fn(&self) -> String")
+                .insert_text("to_string()"),
+            CompletionItem::new("len")
+                .kind(CompletionKind::Function)
+                .detail("fn(&self) -> usize")
+                .insert_text("len()"),
+        ];
+    }
+
+    vec![
+        CompletionItem::new("fn")
+            .kind(CompletionKind::Snippet)
+            .detail("Function")
+            .snippet("fn ${1:name}(${2}) {\n    $0\n}"),
+        CompletionItem::new("impl")
+            .kind(CompletionKind::Snippet)
+            .detail("Impl block")
+            .snippet("impl ${1:Type} {\n    $0\n}"),
+        CompletionItem::new("println")
+            .kind(CompletionKind::Function)
+            .detail("macro")
+            .snippet("println!(\"${1}\");"),
+        CompletionItem::new("let").kind(CompletionKind::Keyword),
+        CompletionItem::new("match").kind(CompletionKind::Keyword),
+        CompletionItem::new("return").kind(CompletionKind::Keyword),
+        CompletionItem::new("String")
+            .kind(CompletionKind::Module)
+            .detail("std::string"),
+    ]
+}
+
 pub struct Example {
     editor: Entity<InputState>,
-    go_to_line_state: Entity<InputState>,
     language_state: Entity<DropdownState<Vec<SharedString>>>,
     language: Lang,
     line_number: bool,
+    minimap: bool,
     need_update: bool,
     soft_wrap: bool,
     _subscribes: Vec<Subscription>,
@@ -96,6 +140,10 @@ impl Example {
             InputState::new(window, cx)
                 .code_editor(default_language.0.name().to_string())
                 .line_number(true)
+                .folding(true)
+                .show_minimap(true)
+                .subword(true)
+                .on_completion_request(|_, trigger, _, _| completion_items(trigger))
                 .tab_size(TabSize {
                     tab_size: 4,
                     hard_tabs: false,
@@ -104,7 +152,6 @@ impl Example {
                 .default_value(default_language.1)
                 .placeholder("Enter your code here...")
         });
-        let go_to_line_state = cx.new(|cx| InputState::new(window, cx));
         let language_state = cx.new(|cx| {
             DropdownState::new(
                 LANGUAGES.iter().map(|s| s.0.name().into()).collect(),
@@ -137,11 +184,11 @@ impl Example {
 
         Self {
             editor,
-            go_to_line_state,
             language_state,
             language: default_language.0,
             line_number: true,
-            need_update: false,
+            minimap: true,
+            need_update: true,
             soft_wrap: false,
             _subscribes,
         }
@@ -181,47 +228,16 @@ impl Example {
             state.set_value(code, window, cx);
             state.set_highlighter(language, cx);
         });
+        // Set the markers once, they move with the edits.
+        self.set_markers(window, cx);
 
         self.need_update = false;
     }
 
     fn go_to_line(&mut self, _: &ClickEvent, window: &mut Window, cx: &mut Context<Self>) {
-        let editor = self.editor.clone();
-        let input_state = self.go_to_line_state.clone();
-
-        window.open_modal(cx, move |modal, window, cx| {
-            input_state.update(cx, |state, cx| {
-                state.set_placeholder(format!("{}", editor.read(cx).line_column()), window, cx);
-                state.focus(window, cx);
-            });
-
-            modal
-                .title("Go to line")
-                .child(TextInput::new(&input_state))
-                .confirm()
-                .on_ok({
-                    let editor = editor.clone();
-                    let input_state = input_state.clone();
-                    move |_, window, cx| {
-                        let query = input_state.read(cx).value();
-                        let mut parts = query
-                            .split(':')
-                            .map(|s| s.trim().parse::<usize>().ok())
-                            .collect::<Vec<_>>()
-                            .into_iter();
-                        let Some(line) = parts.next().and_then(|l| l) else {
-                            return false;
-                        };
-                        let column = parts.next().and_then(|c| c);
-
-                        editor.update(cx, |state, cx| {
-                            state.go_to_line(line, column, window, cx);
-                        });
-
-                        true
-                    }
-                })
-        });
+        // Open the go to line panel of the editor, same as press `cmd-g` (`ctrl-g`).
+        self.editor.update(cx, |state, cx| state.focus(window, cx));
+        window.dispatch_action(Box::new(GoToLine), cx);
     }
 
     fn toggle_soft_wrap(&mut self, _: &ClickEvent, window: &mut Window, cx: &mut Context<Self>) {
@@ -236,7 +252,6 @@ impl Example {
 impl Render for Example {
     fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
         self.update_highlighter(window, cx);
-        self.set_markers(window, cx);
 
         v_flex().size_full().child(
             v_flex()
@@ -283,6 +298,20 @@ impl Render for Example {
                                             cx.notify();
                                         })),
                                 )
+                                .child(
+                                    Button::new("minimap")
+                                        .ghost()
+                                        .when(self.minimap, |this| this.icon(IconName::Check))
+                                        .label("Minimap")
+                                        .xsmall()
+                                        .on_click(cx.listener(|this, _, window, cx| {
+                                            this.minimap = !this.minimap;
+                                            this.editor.update(cx, |state, cx| {
+                                                state.set_show_minimap(this.minimap, window, cx);
+                                            });
+                                            cx.notify();
+                                        })),
+                                )
                                 .child({
                                     Button::new("soft-wrap")
                                         .ghost()
@@ -290,7 +319,29 @@ impl Render for Example {
                                         .label("Soft Wrap")
                                         .selected(self.soft_wrap)
                                         .on_click(cx.listener(Self::toggle_soft_wrap))
-                                }),
+                                })
+                                .child(
+                                    Button::new("fold-all")
+                                        .ghost()
+                                        .xsmall()
+                                        .label("Fold All")
+                                        .on_click(cx.listener(|this, _, window, cx| {
+                                            this.editor.update(cx, |state, cx| {
+                                                state.fold_all(window, cx);
+                                            });
+                                        })),
+                                )
+                                .child(
+                                    Button::new("unfold-all")
+                                        .ghost()
+                                        .xsmall()
+                                        .label("Unfold All")
+                                        .on_click(cx.listener(|this, _, window, cx| {
+                                            this.editor.update(cx, |state, cx| {
+                                                state.unfold_all(window, cx);
+                                            });
+                                        })),
+                                ),
                         )
                         .child({
                             let loc = self.editor.read(cx).line_column();
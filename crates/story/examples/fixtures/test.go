@@ -1,21 +1,102 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/template"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // Default timeout duration for operations
 const timeout = 5 * time.Second
 
+// Default TTL of the idempotency keys in GreetIdempotent
+const defaultIdempotencyTTL = 5 * time.Minute
+
+// tracerName is the instrumentation name of the global tracer, see SetTracer.
+const tracerName = "hello_world"
+
+// greetFlushSize is the size of the buffered greetings Greet writes at once,
+// so a large batch is written as it goes with a bounded buffer.
+const greetFlushSize = 4 << 10
+
+// Sentinel errors returned by HelloWorld, use errors.Is to check them.
 var (
-	instanceCount int
-	mu           sync.RWMutex
+	ErrClosed          = errors.New("greeter is closed")
+	ErrEmptyName       = errors.New("empty name")
+	ErrInvalidConfig   = errors.New("invalid config")
+	ErrNoWriter        = errors.New("no writer configured")
+	ErrNotDelivered    = errors.New("greeting not delivered")
+	ErrUnknownGroup    = errors.New("undefined group")
+	ErrUnknownInstance = errors.New("unknown instance")
 )
 
+// EmptyNameError is returned by Greet with PolicyError, it wraps ErrEmptyName.
+type EmptyNameError struct {
+	Index int
+}
+
+func (e *EmptyNameError) Error() string {
+	return fmt.Sprintf("%s at index %d", ErrEmptyName, e.Index)
+}
+
+func (e *EmptyNameError) Unwrap() error {
+	return ErrEmptyName
+}
+
+// RetryError is returned by Greet when a greeting is still not delivered after
+// the configured retries. Errors are the errors of the attempts in order, each
+// wraps ErrNotDelivered and the Ack.Err of its attempt if set.
+type RetryError struct {
+	Name     string
+	Attempts int
+	Errors   []error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%q: %d attempts: %v", e.Name, e.Attempts, e.Unwrap())
+}
+
+// Unwrap returns the error of the last attempt, see RetryError.
+func (e *RetryError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[len(e.Errors)-1]
+}
+
+// instanceCount is the number of the instances created by NewHelloWorld, see
+// InstanceCount.
+var instanceCount atomic.Int64
+
+// registry maps the names to the greeters registered by Register.
+var registry = struct {
+	sync.Mutex
+	instances map[string]*HelloWorld
+}{instances: make(map[string]*HelloWorld)}
+
 /**
  * HelloWorld represents a greeter with configuration options
  * Contains:
@@ -24,55 +105,2006 @@ var (
  * - options: Map of configuration options
  */
 type HelloWorld struct {
-	name      string
-	createdAt time.Time
-	options   map[string]interface{}
+	mu         sync.Mutex
+	name       string
+	createdAt  time.Time
+	options    map[string]interface{}
+	fields     map[string]interface{}
+	greetCount int
+	out        io.Writer
+	closed     bool
+	// outMu serializes the writes to out, the greetings of the concurrent
+	// calls are written one by one and never interleaved
+	outMu sync.Mutex
+	// writers are the writers added by AddWriter, the output is written to
+	// out and each of them
+	writers []io.Writer
+	// location is the time zone to render createdAt in the reports
+	location *time.Location
+	// idempotencyKeys maps the greeted idempotency keys to their expiry time
+	idempotencyKeys map[string]time.Time
+	// groups maps the group names to their members, see DefineGroup
+	groups map[string][]string
+	// seq is the sequence number of the last greeting, see Config.SeqSuffix
+	seq atomic.Uint64
+	// disabled pauses the greetings without closing the greeter, see Disable
+	disabled atomic.Bool
+	// template renders the greeting lines if set, see SetTemplate
+	template *template.Template
+	// dedup skips the names seen by other greeters if set, see SetDedupStore
+	dedup     DedupStore
+	dedupMode DedupFailMode
+	// activeGreets are the cancels of the in-flight greetings by id, see Cancel
+	activeGreets map[uint64]context.CancelFunc
+	greetID      uint64
+	middlewares  []GreetMiddleware
+	onGreet      GreetHook
+	stats        Stats
+	// tracer starts the spans of Greet if set, see SetTracer
+	tracer trace.Tracer
+	// processed and total are the progress of the last started Greet run
+	// progressRun, see Progress
+	processed   int
+	total       int
+	progressRun uint64
+	// rand shuffles the names with OrderShuffle, see WithRandSource
+	rand *rand.Rand
+	// queue are the names added by Enqueue and not greeted yet, queueReady
+	// wakes up ServeQueue after Enqueue, see Drain
+	queue      []string
+	queueReady chan struct{}
+	// draining rejects the later Enqueue calls, drainStarted is closed when
+	// it is set to stop ServeQueue once the queue is empty
+	draining     bool
+	drainStarted chan struct{}
+	// queueBusy counts the queued names being greeted by ServeQueue
+	queueBusy sync.WaitGroup
+	// filter and grammar are the Config.Filter and Config.Grammar, they are
+	// kept out of options to keep the options encodable in JSON
+	filter  func(name string) bool
+	grammar GrammarFunc
+}
+
+// Ack is the delivery confirmation of a greeting, returned by the GreetHook.
+type Ack struct {
+	Delivered bool
+	Latency   time.Duration
+	// Err is why the greeting is not delivered, it is kept in
+	// RetryError.Errors. It is ignored if Delivered.
+	Err error
+}
+
+// GreetHook is called after each greeting is written, the buffered line is
+// written to the writers before the hook is called. It returns nil if the
+// delivery is not confirmed.
+type GreetHook func(ctx context.Context, name string) *Ack
+
+// Stats aggregates the acks returned by the GreetHook.
+type Stats struct {
+	Delivered    int           `json:"delivered"`
+	Undelivered  int           `json:"undelivered"`
+	Retries      int           `json:"retries"`
+	TotalLatency time.Duration `json:"totalLatency"`
+	MaxLatency   time.Duration `json:"maxLatency"`
+	// Skipped is the count of names not greeted while disabled or rejected by
+	// Config.Filter.
+	Skipped int `json:"skipped"`
+	// Deduped is the count of names not greeted as seen by the DedupStore.
+	Deduped int `json:"deduped"`
+}
+
+// AvgLatency returns the average latency of all the acks.
+func (s Stats) AvgLatency() time.Duration {
+	acks := s.Delivered + s.Undelivered
+	if acks == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(acks)
+}
+
+// GreetFunc greets a single name.
+type GreetFunc func(ctx context.Context, name string) error
+
+// GreetMiddleware wraps a GreetFunc to add behavior around each greeting,
+// e.g. logging, metrics or rate limiting. Call next to continue the chain,
+// or return without calling it to stop.
+type GreetMiddleware func(next GreetFunc) GreetFunc
+
+// EmptyNamePolicy controls how Greet handles empty names.
+type EmptyNamePolicy int
+
+const (
+	// PolicyGreet greets the empty name as is, this is the default.
+	PolicyGreet EmptyNamePolicy = iota
+	// PolicySkip silently skips the empty names.
+	PolicySkip
+	// PolicyError returns an error with the index of the first empty name.
+	PolicyError
+)
+
+// DedupStore records the greeted names to greet each name only once, share
+// a backend like Redis to dedup the greeters across instances, see
+// SetDedupStore. Seen and Mark are called separately, so two greeters may
+// still greet the same name if they check it at the same time.
+type DedupStore interface {
+	Seen(ctx context.Context, key string) (bool, error)
+	Mark(ctx context.Context, key string) error
+}
+
+// MemoryDedupStore is an in-memory DedupStore, share one to dedup the
+// greeters in the same process. It is safe for concurrent use.
+type MemoryDedupStore struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{keys: make(map[string]struct{})}
+}
+
+func (s *MemoryDedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+func (s *MemoryDedupStore) Mark(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = struct{}{}
+	return nil
+}
+
+// DedupFailMode controls how Greet handles the errors of the DedupStore.
+type DedupFailMode int
+
+const (
+	// DedupFailOpen greets the name as not seen if the store fails, this is
+	// the default.
+	DedupFailOpen DedupFailMode = iota
+	// DedupFailClosed stops greeting and returns the error of the store.
+	DedupFailClosed
+)
+
+// CaseMode controls the case transformation of the greeting lines.
+type CaseMode int
+
+const (
+	// CaseNone keeps the greeting as is, this is the default.
+	CaseNone CaseMode = iota
+	// CaseUpper converts the greeting to upper case.
+	CaseUpper
+	// CaseLower converts the greeting to lower case, e.g. for legacy systems.
+	CaseLower
+	// CaseTitle converts the greeting to title case with the Unicode rules.
+	CaseTitle
+)
+
+// apply transforms the case of s, a new title caser is used for each call
+// because cases.Caser is not safe for concurrent use.
+func (m CaseMode) apply(s string) string {
+	switch m {
+	case CaseUpper:
+		return strings.ToUpper(s)
+	case CaseLower:
+		return strings.ToLower(s)
+	case CaseTitle:
+		return cases.Title(language.Und).String(s)
+	default:
+		return s
+	}
+}
+
+// OrderMode controls the order Greet greets the names in.
+type OrderMode int
+
+const (
+	// OrderInput greets the names in the given order, this is the default.
+	OrderInput OrderMode = iota
+	// OrderSorted greets the names in the lexical order.
+	OrderSorted
+	// OrderReverse greets the names in the reverse of the given order.
+	OrderReverse
+	// OrderShuffle greets the names in a random order, see WithRandSource.
+	OrderShuffle
+)
+
+// GrammarFunc returns the form of the name to greet by the rules of the name,
+// e.g. "Dr. Ada" for "Ada" from a lookup of honorifics, see Config.Grammar.
+// The first occurrence of the name in the rendered greeting line is replaced
+// by it, a line without the name is kept as is.
+//
+// A greeting line goes through the stages in order:
+//  1. Render: the template of SetTemplate, or the default "Hello, %s!".
+//  2. Grammar: the GrammarFunc of Config.Grammar, DefaultGrammar by default.
+//  3. Case: the Config.CaseMode, so it also applies to the grammar changes.
+//  4. Decoration: the sequence suffix of Config.SeqSuffix.
+//
+// The names skipped by the EmptyNamePolicy, Config.Filter or the DedupStore
+// are not rendered at all.
+type GrammarFunc func(name string) string
+
+// DefaultGrammar returns the name unchanged, it is used if Config.Grammar is
+// nil.
+func DefaultGrammar(name string) string {
+	return name
+}
+
+// applyGrammar replaces the first occurrence of the name in the greeting line
+// by the form of the grammar.
+func applyGrammar(grammar GrammarFunc, name, line string) string {
+	if name == "" {
+		return line
+	}
+	return strings.Replace(line, name, grammar(name), 1)
+}
+
+// renderGreeting returns the greeting line of the name without the newline.
+func renderGreeting(name string) string {
+	return fmt.Sprintf("Hello, %s!", name)
+}
+
+// GreetingData is the data of the greeting template, see SetTemplate.
+type GreetingData struct {
+	Name string
+	// App is the "app" field of the greeter, see SetField.
+	App string
+	Now time.Time
+}
+
+// renderTemplate returns the greeting line rendered by the template, see SetTemplate.
+func renderTemplate(tmpl *template.Template, data GreetingData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// seqText returns the sequence suffix appended to the greeting line.
+func seqText(seq uint64) string {
+	return fmt.Sprintf(" (#%d)", seq)
 }
 
 type Config struct {
 	Timeout  time.Duration `json:"timeout"`
 	Retries  int          `json:"retries"`
 	Debug    bool         `json:"debug"`
+	// DeadlineWarnThreshold warns once per Greet call when the context
+	// deadline is closer than this, only in Debug mode.
+	DeadlineWarnThreshold time.Duration `json:"deadlineWarnThreshold"`
+	EmptyNamePolicy       EmptyNamePolicy `json:"emptyNamePolicy"`
+	// IdempotencyTTL is how long GreetIdempotent remembers a key,
+	// default is 5 minutes.
+	IdempotencyTTL time.Duration `json:"idempotencyTTL"`
+	// CaseMode transforms the case of the greeting lines, default is CaseNone.
+	CaseMode CaseMode `json:"caseMode"`
+	// SeqSuffix appends a sequence number like " (#3)" to each greeting, the
+	// number increases across the instance's lifetime until Reset.
+	SeqSuffix bool `json:"seqSuffix"`
+	// Filter skips the names it returns false for in Greet, they are counted
+	// in Stats.Skipped. A nil Filter greets all the names. It is not encoded
+	// in JSON.
+	Filter func(name string) bool `json:"-"`
+	// StrictWriter returns ErrNoWriter from Greet if the writer set by
+	// SetWriter is nil and no other writer is added, instead of falling back
+	// to os.Stdout.
+	StrictWriter bool `json:"strictWriter"`
+	// OrderMode reorders a copy of the names before Greet greets them, the
+	// slice of the caller is never changed. Default is OrderInput.
+	OrderMode OrderMode `json:"orderMode"`
+	// Grammar returns the form of each name in its rendered greeting line,
+	// after the template and before the case mode, see GrammarFunc for the
+	// stages. A nil Grammar is DefaultGrammar. It is not encoded in JSON.
+	Grammar GrammarFunc `json:"-"`
+}
+
+// durationPattern matches the non-negative durations of time.ParseDuration, e.g. "1m30s".
+const durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// MarshalJSON encodes the durations as strings like "1m30s", to match ConfigSchema.
+func (c Config) MarshalJSON() ([]byte, error) {
+	type alias Config
+	return json.Marshal(struct {
+		alias
+		Timeout               string `json:"timeout"`
+		DeadlineWarnThreshold string `json:"deadlineWarnThreshold"`
+		IdempotencyTTL        string `json:"idempotencyTTL"`
+	}{alias(c), c.Timeout.String(), c.DeadlineWarnThreshold.String(), c.IdempotencyTTL.String()})
+}
+
+// UnmarshalJSON decodes the durations from strings like "1m30s", the missing
+// durations keep their current values.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type alias Config
+	aux := struct {
+		*alias
+		Timeout               *string `json:"timeout"`
+		DeadlineWarnThreshold *string `json:"deadlineWarnThreshold"`
+		IdempotencyTTL        *string `json:"idempotencyTTL"`
+	}{alias: (*alias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	for _, d := range []struct {
+		name  string
+		value *string
+		dst   *time.Duration
+	}{
+		{"timeout", aux.Timeout, &c.Timeout},
+		{"deadlineWarnThreshold", aux.DeadlineWarnThreshold, &c.DeadlineWarnThreshold},
+		{"idempotencyTTL", aux.IdempotencyTTL, &c.IdempotencyTTL},
+	} {
+		if d.value == nil {
+			continue
+		}
+		v, err := time.ParseDuration(*d.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w: %v", d.name, ErrInvalidConfig, err)
+		}
+		*d.dst = v
+	}
+	return nil
+}
+
+// ConfigSchema returns the draft-07 JSON Schema of Config, the property names
+// are the json tags of the fields. The output is stable, the keys are sorted.
+func ConfigSchema() ([]byte, error) {
+	durationType := reflect.TypeOf(time.Duration(0))
+	policyType := reflect.TypeOf(EmptyNamePolicy(0))
+	caseType := reflect.TypeOf(CaseMode(0))
+	orderType := reflect.TypeOf(OrderMode(0))
+
+	properties := make(map[string]interface{})
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		switch {
+		case field.Type == durationType:
+			properties[name] = map[string]interface{}{
+				"type":        "string",
+				"description": "A duration like \"1.5s\" or \"2m\", see time.ParseDuration.",
+				"pattern":     durationPattern,
+			}
+		case field.Type == policyType:
+			properties[name] = map[string]interface{}{
+				"type": "integer",
+				"enum": []EmptyNamePolicy{PolicyGreet, PolicySkip, PolicyError},
+			}
+		case field.Type == caseType:
+			properties[name] = map[string]interface{}{
+				"type": "integer",
+				"enum": []CaseMode{CaseNone, CaseUpper, CaseLower, CaseTitle},
+			}
+		case field.Type == orderType:
+			properties[name] = map[string]interface{}{
+				"type": "integer",
+				"enum": []OrderMode{OrderInput, OrderSorted, OrderReverse, OrderShuffle},
+			}
+		case field.Type.Kind() == reflect.Int:
+			properties[name] = map[string]interface{}{"type": "integer", "minimum": 0}
+		case field.Type.Kind() == reflect.Bool:
+			properties[name] = map[string]interface{}{"type": "boolean"}
+		default:
+			return nil, fmt.Errorf("config schema: unsupported type %s of %s", field.Type, field.Name)
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "Config",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}, "", "  ")
 }
 
 func NewHelloWorld(name string) *HelloWorld {
-	mu.Lock()
-	instanceCount++
-	mu.Unlock()
+	return NewHelloWorldAt(name, time.Now())
+}
+
+// NewHelloWorldAt is like NewHelloWorld but with the given createdAt instead of
+// time.Now(), to render the reports and the JSON encoding reproducibly in tests.
+func NewHelloWorldAt(name string, createdAt time.Time) *HelloWorld {
+	instanceCount.Add(1)
 	return &HelloWorld{
 		name:      name,
-		createdAt: time.Now(),
+		createdAt: createdAt,
 		options:   make(map[string]interface{}),
+		fields:    make(map[string]interface{}),
+		out:       os.Stdout,
+		location:  time.Local,
+
+		idempotencyKeys: make(map[string]time.Time),
+		groups:          make(map[string][]string),
+		activeGreets:    make(map[uint64]context.CancelFunc),
+		queueReady:      make(chan struct{}, 1),
+		drainStarted:    make(chan struct{}),
+	}
+}
+
+// InstanceCount returns the number of the instances created by NewHelloWorld
+// and NewHelloWorldAt since the process started.
+func InstanceCount() int64 {
+	return instanceCount.Load()
+}
+
+// helloWorldPool keeps the released instances for AcquireHelloWorld, the new
+// instances are created by NewHelloWorld so instanceCount counts each
+// allocation once, reusing a pooled instance does not count again.
+var helloWorldPool = sync.Pool{
+	New: func() interface{} {
+		return NewHelloWorld("")
+	},
+}
+
+// AcquireHelloWorld returns a HelloWorld from the pool, or a new one if the
+// pool is empty, it is in the same state as returned by NewHelloWorld.
+//
+// Call Release when done to return it to the pool. Do not keep or use any
+// reference to it after Release, it may be handed out to another caller.
+func AcquireHelloWorld(name string) *HelloWorld {
+	h := helloWorldPool.Get().(*HelloWorld)
+	h.mu.Lock()
+	h.name = name
+	h.createdAt = time.Now()
+	h.mu.Unlock()
+	return h
+}
+
+// Release resets the HelloWorld to the state of NewHelloWorld, e.g. the
+// options, fields, counters, stats, progress, hooks, writer and queue, and
+// puts it back to the pool. A nil h is ignored.
+//
+// The caller must not use h after Release, including any goroutine started
+// from it like StartReportTicker, stop them before releasing.
+func Release(h *HelloWorld) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	h.name = ""
+	h.createdAt = time.Time{}
+	// Keep the maps to reuse the allocations.
+	for key := range h.options {
+		delete(h.options, key)
+	}
+	for key := range h.fields {
+		delete(h.fields, key)
+	}
+	for key := range h.idempotencyKeys {
+		delete(h.idempotencyKeys, key)
+	}
+	for key := range h.groups {
+		delete(h.groups, key)
+	}
+	for id := range h.activeGreets {
+		delete(h.activeGreets, id)
+	}
+	h.greetCount = 0
+	h.seq.Store(0)
+	h.out = os.Stdout
+	h.writers = nil
+	h.location = time.Local
+	h.closed = false
+	h.disabled.Store(false)
+	h.template = nil
+	h.dedup = nil
+	h.dedupMode = DedupFailOpen
+	h.middlewares = nil
+	h.onGreet = nil
+	h.filter = nil
+	h.grammar = nil
+	h.stats = Stats{}
+	h.tracer = nil
+	h.rand = nil
+	h.processed, h.total, h.progressRun = 0, 0, 0
+	h.greetID = 0
+	h.queue = nil
+	h.queueReady = make(chan struct{}, 1)
+	h.draining = false
+	h.drainStarted = make(chan struct{})
+	h.mu.Unlock()
+
+	// Not to cancel the greetings of the next owner by the stale name.
+	registry.Lock()
+	for name, instance := range registry.instances {
+		if instance == h {
+			delete(registry.instances, name)
+		}
+	}
+	registry.Unlock()
+
+	helloWorldPool.Put(h)
+}
+
+// Greet greets the names in the order of Config.OrderMode and returns the count of names greeted
+// successfully. The greetings are buffered and written to the writer in
+// chunks of about 4 KiB, and the rest before returning, also when the context
+// is cancelled, so the already rendered greetings are never lost or
+// half-written. The lines of the concurrent calls are never split, but their
+// chunks may be interleaved. With OnGreet, each line is written before its hook
+// is called instead.
+//
+// Each call is traced by a span named "HelloWorld.Greet" under the span of
+// ctx, with a child span for each name when Debug is on, see SetTracer.
+func (h *HelloWorld) Greet(ctx context.Context, names ...string) (written int, err error) {
+	return h.greet(ctx, nil, names)
+}
+
+// greet is Greet, it also calls onLine with each greeting line rendered for a
+// name, without the newline, if onLine is not nil.
+func (h *HelloWorld) greet(ctx context.Context, onLine func(line string), names []string) (written int, err error) {
+	h.mu.Lock()
+	instance, tracer := h.name, h.tracer
+	closed, outs := h.closed, h.outputs()
+	middlewares, onGreet := h.middlewares, h.onGreet
+	retries, _ := h.options["retries"].(int)
+	debug, _ := h.options["debug"].(bool)
+	threshold, _ := h.options["deadlineWarnThreshold"].(time.Duration)
+	policy, _ := h.options["emptyNamePolicy"].(EmptyNamePolicy)
+	seqSuffix, _ := h.options["seqSuffix"].(bool)
+	filter := h.filter
+	order, _ := h.options["orderMode"].(OrderMode)
+	dedup, dedupMode := h.dedup, h.dedupMode
+	h.mu.Unlock()
+	render := h.renderer()
+	// The indexes of the EmptyNameError and Progress are in the ordered names.
+	names = h.orderNames(order, names)
+
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+	ctx, span := tracer.Start(ctx, "HelloWorld.Greet", trace.WithAttributes(
+		attribute.String("hello_world.instance", instance),
+		attribute.Int("hello_world.names", len(names)),
+	))
+	// Deferred first to run last, after the write of the buffered greetings.
+	defer func() {
+		span.SetAttributes(attribute.Int("hello_world.written", written))
+		endSpan(span, err)
+	}()
+
+	if closed {
+		return 0, fmt.Errorf("greet: %w", ErrClosed)
+	}
+	if h.disabled.Load() {
+		h.mu.Lock()
+		h.stats.Skipped += len(names)
+		h.mu.Unlock()
+		return 0, nil
+	}
+	if len(outs) == 0 {
+		return 0, fmt.Errorf("greet: %w", ErrNoWriter)
+	}
+
+	ctx, done := h.track(ctx)
+	defer done()
+
+	lw := &lineWriter{h: h, outs: outs}
+	// Write the rest on every return, including the cancellation.
+	defer func() {
+		lw.flush()
+		if lw.err != nil && err == nil {
+			err = fmt.Errorf("greet: write: %w", lw.err)
+		}
+	}()
+
+	greet := GreetFunc(func(ctx context.Context, name string) error {
+		return h.greetName(ctx, lw, name, render, onGreet, retries, seqSuffix, onLine)
+	})
+	// Wrap from the last, so the first registered middleware is the outermost.
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		greet = middlewares[i](greet)
+	}
+
+	run := h.startProgress(len(names))
+	defer func() {
+		if err == nil {
+			h.setProgress(run, len(names))
+		}
+	}()
+
+	warned := false
+	for i, name := range names {
+		h.setProgress(run, i)
+		select {
+		case <-ctx.Done():
+			return written, fmt.Errorf("greet: %w", ctx.Err())
+		default:
+			if name == "" {
+				switch policy {
+				case PolicySkip:
+					continue
+				case PolicyError:
+					return written, fmt.Errorf("greet: %w", &EmptyNameError{Index: i})
+				}
+			}
+			if filter != nil && !filter(name) {
+				h.mu.Lock()
+				h.stats.Skipped++
+				h.mu.Unlock()
+				continue
+			}
+			if debug && threshold > 0 && !warned {
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < threshold {
+					fmt.Fprintf(&lw.buf, "Warning: context deadline in %s\n", time.Until(deadline).Round(time.Millisecond))
+					warned = true
+				}
+			}
+			if dedup != nil {
+				seen, err := dedup.Seen(ctx, name)
+				if err != nil && dedupMode == DedupFailClosed {
+					return written, fmt.Errorf("greet: dedup %q: %w", name, err)
+				}
+				if seen {
+					h.mu.Lock()
+					h.stats.Deduped++
+					h.mu.Unlock()
+					continue
+				}
+			}
+			nameCtx := ctx
+			var nameSpan trace.Span
+			if debug {
+				nameCtx, nameSpan = tracer.Start(ctx, "HelloWorld.Greet.Name", trace.WithAttributes(
+					attribute.String("hello_world.name", name),
+				))
+			}
+			err := greet(nameCtx, name)
+			if nameSpan != nil {
+				endSpan(nameSpan, err)
+			}
+			if err != nil {
+				return written, fmt.Errorf("greet: %w", err)
+			}
+			written++
+			lw.flushFull()
+			if dedup != nil {
+				if err := dedup.Mark(ctx, name); err != nil && dedupMode == DedupFailClosed {
+					return written, fmt.Errorf("greet: dedup %q: %w", name, err)
+				}
+			}
+		}
+	}
+	return written, nil
+}
+
+// orderNames returns the names in the order of the mode, the names are copied
+// before being reordered so the slice of the caller is never changed.
+func (h *HelloWorld) orderNames(mode OrderMode, names []string) []string {
+	if mode == OrderInput || len(names) < 2 {
+		return names
+	}
+
+	ordered := append([]string(nil), names...)
+	switch mode {
+	case OrderSorted:
+		sort.Strings(ordered)
+	case OrderReverse:
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	case OrderShuffle:
+		// rand.Rand is not safe for concurrent use.
+		h.mu.Lock()
+		if h.rand == nil {
+			h.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		h.rand.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+		h.mu.Unlock()
+	}
+	return ordered
+}
+
+// WithRandSource sets the source to shuffle the names with OrderShuffle, use
+// a seeded source like rand.NewSource(42) for a reproducible order. A nil src
+// falls back to a source seeded by the current time. It returns h for chaining.
+func (h *HelloWorld) WithRandSource(src rand.Source) *HelloWorld {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rand = nil
+	if src != nil {
+		h.rand = rand.New(src)
+	}
+	return h
+}
+
+// SetTracer sets the tracer to start the spans of Greet, a nil tracer uses the
+// global tracer of otel.GetTracerProvider, which is a no-op unless it is set
+// by otel.SetTracerProvider.
+func (h *HelloWorld) SetTracer(tracer trace.Tracer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tracer = tracer
+}
+
+// endSpan marks the span as failed by err if not nil, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// GreetOne greets a single name like Greet(ctx, name), for the hot path of
+// greeting one name per request. It writes the line without the variadic
+// slice, the batch loop and, unless there is a hook to wait for, the
+// cancelable context. The middlewares, the filter, the dedup store and the
+// debug deadline warning need the machinery of Greet, so GreetOne falls back
+// to Greet when any of them is set. The fast path is traced like Greet, by
+// the tracer of SetTracer or the global tracer, with the same span, and sets
+// the span attributes only if the span is recording. It also updates Progress
+// like Greet, as a run of one name.
+func (h *HelloWorld) GreetOne(ctx context.Context, name string) (err error) {
+	h.mu.Lock()
+	filter := h.filter
+	debug, _ := h.options["debug"].(bool)
+	threshold, _ := h.options["deadlineWarnThreshold"].(time.Duration)
+	if len(h.middlewares) > 0 || filter != nil || h.dedup != nil || (debug && threshold > 0) {
+		h.mu.Unlock()
+		_, err := h.Greet(ctx, name)
+		return err
+	}
+	instance, tracer := h.name, h.tracer
+	closed, outs, onGreet := h.closed, h.outputs(), h.onGreet
+	retries, _ := h.options["retries"].(int)
+	policy, _ := h.options["emptyNamePolicy"].(EmptyNamePolicy)
+	seqSuffix, _ := h.options["seqSuffix"].(bool)
+	h.mu.Unlock()
+
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+	ctx, span := tracer.Start(ctx, "HelloWorld.Greet")
+	written := 0
+	defer func() {
+		if span.IsRecording() {
+			span.SetAttributes(
+				attribute.String("hello_world.instance", instance),
+				attribute.Int("hello_world.names", 1),
+				attribute.Int("hello_world.written", written),
+			)
+		}
+		endSpan(span, err)
+	}()
+
+	if closed {
+		return fmt.Errorf("greet: %w", ErrClosed)
+	}
+	if h.disabled.Load() {
+		h.mu.Lock()
+		h.stats.Skipped++
+		h.mu.Unlock()
+		return nil
+	}
+	if len(outs) == 0 {
+		return fmt.Errorf("greet: %w", ErrNoWriter)
+	}
+
+	run := h.startProgress(1)
+	defer func() {
+		if err == nil {
+			h.setProgress(run, 1)
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("greet: %w", err)
+	}
+	if name == "" {
+		switch policy {
+		case PolicySkip:
+			return nil
+		case PolicyError:
+			return fmt.Errorf("greet: %w", &EmptyNameError{Index: 0})
+		}
+	}
+	// Only the hook may block, to be cancelled by Cancel.
+	if onGreet != nil {
+		var done func()
+		ctx, done = h.track(ctx)
+		defer done()
+	}
+
+	lw := lineWriter{h: h, outs: outs}
+	err = h.greetName(ctx, &lw, name, h.renderer(), onGreet, retries, seqSuffix, nil)
+	lw.flush()
+	if lw.err == nil {
+		written = lw.lines
+	}
+	if lw.err != nil && err == nil {
+		return fmt.Errorf("greet: write: %w", lw.err)
+	}
+	if err != nil {
+		return fmt.Errorf("greet: %w", err)
+	}
+	return nil
+}
+
+// track returns a child context of ctx to be cancelled by Cancel, call done
+// when the greeting returns.
+func (h *HelloWorld) track(ctx context.Context) (_ context.Context, done func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	h.mu.Lock()
+	h.greetID++
+	greetID := h.greetID
+	h.activeGreets[greetID] = cancel
+	h.mu.Unlock()
+
+	return ctx, func() {
+		h.mu.Lock()
+		delete(h.activeGreets, greetID)
+		h.mu.Unlock()
+		cancel()
+	}
+}
+
+// lineWriter buffers the greeting lines of a call and writes them to outs on
+// flush. A failed write is kept in err and the later flushes still write, like
+// writeOut does for the other writers.
+type lineWriter struct {
+	h    *HelloWorld
+	outs []io.Writer
+	buf  bytes.Buffer
+	// lines is the count of the greeting lines written to buf.
+	lines int
+	err   error
+}
+
+// writeLine buffers the greeting line with the newline.
+func (w *lineWriter) writeLine(line string) {
+	w.buf.WriteString(line)
+	w.buf.WriteByte('\n')
+	w.lines++
+}
+
+// flushFull writes the buffered lines to outs once they reach
+// greetFlushSize.
+func (w *lineWriter) flushFull() {
+	if w.buf.Len() >= greetFlushSize {
+		w.flush()
+	}
+}
+
+// flush writes the buffered lines to outs, if any.
+func (w *lineWriter) flush() {
+	if w.buf.Len() == 0 {
+		return
+	}
+	if err := w.h.writeOut(w.outs, w.buf.Bytes()); err != nil && w.err == nil {
+		w.err = err
+	}
+	w.buf.Reset()
+}
+
+// greetName renders the greeting line of name to lw and waits for the ack of
+// onGreet, an undelivered greeting is retried up to retries. The line is also
+// passed to onLine if not nil. With onGreet, lw is flushed before the hook is
+// called, so the hook sees the line already written.
+//
+// The delivery is at-least-once on the hook: the line is rendered, numbered
+// and written once, and counted once in greetCount, a retry calls onGreet
+// again for the same line, so the hook may see the name up to retries+1 times.
+func (h *HelloWorld) greetName(ctx context.Context, lw *lineWriter, name string, render func(name string) (string, error), onGreet GreetHook, retries int, seqSuffix bool, onLine func(line string)) error {
+	line, err := render(name)
+	if err != nil {
+		return fmt.Errorf("%q: %w", name, err)
+	}
+	if seqSuffix {
+		line += seqText(h.seq.Add(1))
+	}
+	lw.writeLine(line)
+	h.mu.Lock()
+	h.greetCount++
+	h.mu.Unlock()
+	if onLine != nil {
+		onLine(line)
+	}
+
+	if onGreet == nil {
+		return nil
+	}
+	lw.flush()
+	var attemptErrs []error
+	for attempt := 0; ; attempt++ {
+		ack := onGreet(ctx, name)
+		if ack == nil {
+			return nil
+		}
+		h.recordAck(*ack, attempt > 0)
+		if ack.Delivered {
+			return nil
+		}
+		attemptErr := ErrNotDelivered
+		if ack.Err != nil {
+			attemptErr = fmt.Errorf("%w: %w", ErrNotDelivered, ack.Err)
+		}
+		attemptErrs = append(attemptErrs, attemptErr)
+		if attempt >= retries {
+			return &RetryError{Name: name, Attempts: attempt + 1, Errors: attemptErrs}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// GreetAtomic greets either all the names or none of them. The greetings are
+// rendered first, and written to the writers at once only if every name is
+// rendered and ctx is still valid, otherwise nothing is written and the error
+// is returned.
+//
+// Like Greet, the names are greeted in the order of Config.OrderMode, the
+// empty names follow the EmptyNamePolicy, and the names rejected by
+// Config.Filter are skipped and counted in Stats.Skipped. Unlike Greet, these
+// are not applied: the middlewares of Use, the OnGreet hook and so the
+// Retries, the DedupStore, the tracing spans, the Debug deadline warning and
+// the Progress.
+//
+// All the output is held in memory until it is written, so the memory use
+// grows with the number of names, use Greet for the large batches.
+func (h *HelloWorld) GreetAtomic(ctx context.Context, names ...string) error {
+	h.mu.Lock()
+	closed, outs := h.closed, h.outputs()
+	policy, _ := h.options["emptyNamePolicy"].(EmptyNamePolicy)
+	seqSuffix, _ := h.options["seqSuffix"].(bool)
+	filter := h.filter
+	order, _ := h.options["orderMode"].(OrderMode)
+	h.mu.Unlock()
+	render := h.renderer()
+	// The indexes of the EmptyNameError are in the ordered names like Greet.
+	names = h.orderNames(order, names)
+
+	if closed {
+		return fmt.Errorf("greet atomic: %w", ErrClosed)
+	}
+	if h.disabled.Load() {
+		h.mu.Lock()
+		h.stats.Skipped += len(names)
+		h.mu.Unlock()
+		return nil
+	}
+	if len(outs) == 0 {
+		return fmt.Errorf("greet atomic: %w", ErrNoWriter)
+	}
+
+	lines := make([]string, 0, len(names))
+	skipped := 0
+	for i, name := range names {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("greet atomic: %w", err)
+		}
+		if name == "" {
+			switch policy {
+			case PolicySkip:
+				continue
+			case PolicyError:
+				return fmt.Errorf("greet atomic: %w", &EmptyNameError{Index: i})
+			}
+		}
+		if filter != nil && !filter(name) {
+			skipped++
+			continue
+		}
+		line, err := render(name)
+		if err != nil {
+			return fmt.Errorf("greet atomic: %q: %w", name, err)
+		}
+		lines = append(lines, line)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("greet atomic: %w", err)
+	}
+
+	// Take the sequence numbers only once all the names are rendered.
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if seqSuffix {
+			line += seqText(h.seq.Add(1))
+		}
+		fmt.Fprintln(&buf, line)
+	}
+	if err := h.writeOut(outs, buf.Bytes()); err != nil {
+		return fmt.Errorf("greet atomic: write: %w", err)
+	}
+	h.mu.Lock()
+	h.greetCount += len(lines)
+	h.stats.Skipped += skipped
+	h.mu.Unlock()
+	return nil
+}
+
+// SetDedupStore sets the store to skip the names already greeted by this or
+// other greeters sharing it, and how to handle the store errors. A nil store
+// disables the dedup, which is the default.
+func (h *HelloWorld) SetDedupStore(store DedupStore, mode DedupFailMode) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dedup = store
+	h.dedupMode = mode
+}
+
+// SetTemplate compiles the text/template to render the greeting lines, with
+// GreetingData as the data, e.g. "Hello {{.Name}}, welcome to {{.App}}!". It
+// returns the compile error and keeps the current template. An empty tmpl
+// removes the template to greet with the default "Hello, %s!".
+func (h *HelloWorld) SetTemplate(tmpl string) error {
+	var t *template.Template
+	if tmpl != "" {
+		var err error
+		if t, err = template.New("greeting").Parse(tmpl); err != nil {
+			return fmt.Errorf("set template: %w", err)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.template = t
+	return nil
+}
+
+// renderer returns the function to render the greeting line of a name
+// without the newline, with the current template, grammar and case mode in
+// the order of GrammarFunc.
+func (h *HelloWorld) renderer() func(name string) (string, error) {
+	h.mu.Lock()
+	tmpl := h.template
+	caseMode, _ := h.options["caseMode"].(CaseMode)
+	grammar := h.grammar
+	app := ""
+	if v, ok := h.fields["app"]; ok {
+		app = fmt.Sprint(v)
+	}
+	h.mu.Unlock()
+
+	if grammar == nil {
+		grammar = DefaultGrammar
+	}
+
+	return func(name string) (string, error) {
+		line := renderGreeting(name)
+		if tmpl != nil {
+			var err error
+			line, err = renderTemplate(tmpl, GreetingData{Name: name, App: app, Now: time.Now()})
+			if err != nil {
+				return "", err
+			}
+		}
+		return caseMode.apply(applyGrammar(grammar, name, line)), nil
+	}
+}
+
+// SetWriter replaces the writer of the greetings and reports, default is
+// os.Stdout. A nil w falls back to os.Stdout, or is an error of Greet
+// wrapping ErrNoWriter with Config.StrictWriter.
+func (h *HelloWorld) SetWriter(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.out = w
+}
+
+// AddWriter adds a writer to also write the greetings and reports to, e.g. a
+// log file besides os.Stdout. A failed writer does not stop writing to the
+// others, the errors of all the writers are returned together. A nil w is
+// skipped.
+func (h *HelloWorld) AddWriter(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writers = append(h.writers, w)
+}
+
+// outputs returns out and the writers added by AddWriter, skipping nil ones.
+// A nil out falls back to os.Stdout unless Config.StrictWriter is set.
+// The caller must hold h.mu.
+func (h *HelloWorld) outputs() []io.Writer {
+	outs := make([]io.Writer, 0, len(h.writers)+1)
+	if !isNilWriter(h.out) {
+		outs = append(outs, h.out)
+	} else if strict, _ := h.options["strictWriter"].(bool); !strict {
+		outs = append(outs, os.Stdout)
+	}
+	for _, w := range h.writers {
+		if !isNilWriter(w) {
+			outs = append(outs, w)
+		}
+	}
+	return outs
+}
+
+// isNilWriter reports whether w is nil, including a nil pointer in a non-nil
+// interface like a nil *bytes.Buffer, which panics on Write.
+func isNilWriter(w io.Writer) bool {
+	if w == nil {
+		return true
+	}
+	v := reflect.ValueOf(w)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Flush flushes the writers if they buffer the output, e.g. a *bufio.Writer.
+// Greet flushes them before returning, this is for the other writes like reports.
+func (h *HelloWorld) Flush() error {
+	h.mu.Lock()
+	outs := h.outputs()
+	h.mu.Unlock()
+
+	var errs []error
+	for _, out := range outs {
+		if err := flushWriter(out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeOut writes p to each of outs at once and flushes it, the writes of the
+// concurrent greetings and reports are serialized by outMu. A failed writer
+// does not stop the others, their errors are joined.
+func (h *HelloWorld) writeOut(outs []io.Writer, p []byte) error {
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+
+	var errs []error
+	for _, out := range outs {
+		if _, err := out.Write(p); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := flushWriter(out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		// e.g. http.Flusher of a chunked response.
+		f.Flush()
 	}
+	return nil
+}
+
+// OnGreet sets the hook to confirm the delivery of each greeting, a nil hook
+// removes it. When the ack is not delivered, the hook is called again for the
+// same greeting up to the configured Retries and then Greet returns an error
+// wrapping a *RetryError of ErrNotDelivered. The greeting line is written
+// once however many times it is retried.
+func (h *HelloWorld) OnGreet(hook GreetHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onGreet = hook
+}
+
+// Stats returns the aggregated delivery statistics.
+func (h *HelloWorld) Stats() Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stats
 }
 
-func (h *HelloWorld) Greet(ctx context.Context, names ...string) error {
-	for _, name := range names {
+// Progress returns the count of the names processed and the total names of
+// the in-flight Greet or GreetOne, or of the last one when no greeting is
+// active. The counters are reset when Greet or GreetOne starts, the names
+// skipped, filtered or deduplicated count as processed. Safe to call from
+// another goroutine, e.g. to render a progress bar; with concurrent calls it
+// reports the latest started one.
+func (h *HelloWorld) Progress() (processed, total int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.processed, h.total
+}
+
+// startProgress resets the progress for a Greet run of total names and
+// returns the run to pass to setProgress.
+func (h *HelloWorld) startProgress(total int) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.progressRun++
+	h.processed, h.total = 0, total
+	return h.progressRun
+}
+
+// setProgress updates the processed count of the run, unless a later run has
+// started.
+func (h *HelloWorld) setProgress(run uint64, processed int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if run == h.progressRun {
+		h.processed = processed
+	}
+}
+
+func (h *HelloWorld) recordAck(ack Ack, retry bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ack.Delivered {
+		h.stats.Delivered++
+	} else {
+		h.stats.Undelivered++
+	}
+	if retry {
+		h.stats.Retries++
+	}
+	h.stats.TotalLatency += ack.Latency
+	if ack.Latency > h.stats.MaxLatency {
+		h.stats.MaxLatency = ack.Latency
+	}
+}
+
+// Use appends middlewares around the per-name greeting of Greet, they run in
+// the registration order, the first one is the outermost.
+func (h *HelloWorld) Use(mw ...GreetMiddleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.middlewares = append(h.middlewares, mw...)
+}
+
+// GreetChan greets each name received from the channel until it is closed
+// or the context is done. A nil channel returns immediately.
+func (h *HelloWorld) GreetChan(ctx context.Context, names <-chan string) error {
+	if names == nil {
+		return nil
+	}
+	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case name, ok := <-names:
+			if !ok {
+				return nil
+			}
+			if _, err := h.Greet(ctx, name); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Enqueue adds the names to the internal queue to be greeted in order by
+// ServeQueue or Drain. It returns ErrClosed after Drain starts or Close.
+func (h *HelloWorld) Enqueue(names ...string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || h.draining {
+		return fmt.Errorf("enqueue: %w", ErrClosed)
+	}
+	h.queue = append(h.queue, names...)
+	select {
+	case h.queueReady <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// ServeQueue greets the names added by Enqueue one by one with Greet, and
+// waits for more when the queue is empty. It returns nil once Drain starts and
+// the queue is empty, or the error of ctx or Greet, a failed name is not
+// queued again. Run it in a goroutine, e.g. like GreetChan for a channel.
+func (h *HelloWorld) ServeQueue(ctx context.Context) error {
+	for {
+		h.mu.Lock()
+		if len(h.queue) == 0 {
+			drainStarted := h.drainStarted
+			h.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("serve queue: %w", ctx.Err())
+			case <-drainStarted:
+				h.mu.Lock()
+				empty := len(h.queue) == 0
+				h.mu.Unlock()
+				if empty {
+					return nil
+				}
+			case <-h.queueReady:
+			}
+			continue
+		}
+		name := h.queue[0]
+		h.queue = h.queue[1:]
+		h.queueBusy.Add(1)
+		h.mu.Unlock()
+
+		_, err := h.Greet(ctx, name)
+		h.queueBusy.Done()
+		if err != nil {
+			return fmt.Errorf("serve queue: %w", err)
+		}
+	}
+}
+
+// Drain greets the names left in the queue of Enqueue with Greet, waits for
+// the names being greeted by ServeQueue and returns, to shut down without
+// losing the queued names. The Enqueue calls after Drain starts return
+// ErrClosed. If ctx is done first, it returns the error of ctx and the names
+// not greeted yet are left in the queue.
+func (h *HelloWorld) Drain(ctx context.Context) error {
+	h.mu.Lock()
+	if !h.draining {
+		h.draining = true
+		close(h.drainStarted)
+	}
+	h.mu.Unlock()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("drain: %w", err)
+		}
+		h.mu.Lock()
+		if len(h.queue) == 0 {
+			h.mu.Unlock()
+			break
+		}
+		name := h.queue[0]
+		h.queue = h.queue[1:]
+		h.mu.Unlock()
+
+		if _, err := h.Greet(ctx, name); err != nil {
+			return fmt.Errorf("drain: %w", err)
+		}
+	}
+
+	// No name is dequeued after the queue is empty, Wait does not race with Add.
+	done := make(chan struct{})
+	go func() {
+		h.queueBusy.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("drain: %w", ctx.Err())
+	}
+}
+
+// GreetAsync greets the names in a goroutine and returns a channel that
+// receives the error of Greet (nil on success) exactly once and is then
+// closed, so it can be used in a select with other channels. The channel is
+// buffered, the goroutine does not leak if the caller never reads from it.
+func (h *HelloWorld) GreetAsync(ctx context.Context, names ...string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		_, err := h.Greet(ctx, names...)
+		done <- err
+	}()
+	return done
+}
+
+// GreetChunked greets the names in chunks of chunkSize with Greet and calls
+// onChunk (if not nil) with the count of processed names after each chunk, to
+// report the progress of a huge batch. A chunkSize <= 0 greets all the names in
+// one chunk. It stops at the first failed chunk, or before the next chunk if
+// ctx is done. The Index of an EmptyNameError is relative to its chunk.
+func (h *HelloWorld) GreetChunked(ctx context.Context, chunkSize int, onChunk func(done, total int), names ...string) error {
+	total := len(names)
+	if chunkSize <= 0 {
+		chunkSize = total
+	}
+
+	for start := 0; start < total; start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("greet chunked: %w", err)
+		}
+		end := start + chunkSize
+		if end > total {
+			end = total
+		}
+		if _, err := h.Greet(ctx, names[start:end]...); err != nil {
+			return fmt.Errorf("greet chunked: names %d-%d: %w", start, end-1, err)
+		}
+		if onChunk != nil {
+			onChunk(end, total)
+		}
+	}
+	return nil
+}
+
+// GreetRepeat greets the name times times with a Greet call for each, e.g. to
+// load test the writer, so each greeting goes through the middlewares like a
+// rate limiter and is written as soon as it is greeted. It returns the count
+// of the greetings sent, and stops at the first failed greeting or before the
+// next one if ctx is done. A times <= 0 greets nothing and returns 0, nil.
+func (h *HelloWorld) GreetRepeat(ctx context.Context, name string, times int) (sent int, err error) {
+	for i := 0; i < times; i++ {
+		if err := ctx.Err(); err != nil {
+			return sent, fmt.Errorf("greet repeat: %w", err)
+		}
+		n, err := h.Greet(ctx, name)
+		sent += n
+		if err != nil {
+			return sent, fmt.Errorf("greet repeat: %d of %d: %w", i+1, times, err)
+		}
+	}
+	return sent, nil
+}
+
+// GreetWithSignals greets the names and stops on SIGINT or SIGTERM, the
+// greetings before the signal are still written and the writer is flushed.
+// The default signal handling is restored before returning.
+func (h *HelloWorld) GreetWithSignals(names ...string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	_, err := h.Greet(ctx, names...)
+	if ferr := h.Flush(); ferr != nil && err == nil {
+		err = fmt.Errorf("greet with signals: flush: %w", ferr)
+	}
+	return err
+}
+
+// Register registers h by its name, to look it up by the name in e.g.
+// CancelInstance. It replaces the greeter registered with the same name.
+func Register(h *HelloWorld) {
+	h.mu.Lock()
+	name := h.name
+	h.mu.Unlock()
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.instances[name] = h
+}
+
+// Unregister removes the greeter registered by the name, if any.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.instances, name)
+}
+
+// CancelInstance cancels the in-flight greetings of the greeter registered by
+// the name, e.g. to abort a runaway job from an admin endpoint. It returns nil
+// if the greeter is not greeting.
+func CancelInstance(name string) error {
+	registry.Lock()
+	h, ok := registry.instances[name]
+	registry.Unlock()
+	if !ok {
+		return fmt.Errorf("cancel instance %q: %w", name, ErrUnknownInstance)
+	}
+
+	h.Cancel()
+	return nil
+}
+
+// Cancel cancels the in-flight greetings of h, they return the written count
+// so far with context.Canceled. The later greetings are not affected.
+func (h *HelloWorld) Cancel() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, cancel := range h.activeGreets {
+		cancel()
+	}
+}
+
+// GreetResult is the result of greeting a single name, see GreetStreaming.
+type GreetResult struct {
+	Name     string `json:"name"`
+	Greeting string `json:"greeting,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// Skipped is true if the name is not greeted without an error, e.g. an
+	// empty name with PolicySkip or a name rejected by Config.Filter.
+	Skipped bool                   `json:"skipped,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+}
+
+// NamedEntry is a name to greet with the caller-provided metadata, e.g. the
+// tier of the user, that is passed through to the JSON output.
+type NamedEntry struct {
+	Name string
+	Meta map[string]interface{}
+}
+
+// GreetWithMeta greets the names of the entries like Greet, with the same
+// order and empty name rules. The text greetings do not include the metadata,
+// use GreetStreamingWithMeta to write it with the results.
+func (h *HelloWorld) GreetWithMeta(ctx context.Context, entries []NamedEntry) (written int, err error) {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return h.Greet(ctx, names...)
+}
+
+// GreetStreaming greets the names in order and writes a GreetResult as a JSON
+// object to w for each name as soon as it is processed, w is flushed after each
+// result so a client can consume e.g. a chunked HTTP response incrementally.
+// It stops at the first failed name after writing its result.
+func (h *HelloWorld) GreetStreaming(ctx context.Context, w io.Writer, names ...string) error {
+	entries := make([]NamedEntry, len(names))
+	for i, name := range names {
+		entries[i] = NamedEntry{Name: name}
+	}
+	return h.GreetStreamingWithMeta(ctx, w, entries)
+}
+
+// GreetStreamingWithMeta is like GreetStreaming, the metadata of each entry is
+// written in the meta field of its result. The greeting of a result is the
+// line written by Greet, with its sequence suffix, and a name greeted without
+// writing is reported as skipped like GreetWithResult.
+func (h *HelloWorld) GreetStreamingWithMeta(ctx context.Context, w io.Writer, entries []NamedEntry) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		result, gerr := h.greetResult(ctx, entry)
+		if err := enc.Encode(result); err != nil {
+			return fmt.Errorf("greet streaming: %w", err)
+		}
+		if err := flushWriter(w); err != nil {
+			return fmt.Errorf("greet streaming: flush: %w", err)
+		}
+		if gerr != nil {
+			return gerr
+		}
+	}
+	return nil
+}
+
+// greetResult greets the name of the entry and returns its result with the
+// written greeting line, and the error of Greet.
+func (h *HelloWorld) greetResult(ctx context.Context, entry NamedEntry) (GreetResult, error) {
+	result := GreetResult{Name: entry.Name, Meta: entry.Meta}
+	written, err := h.greet(ctx, func(line string) {
+		result.Greeting = line
+	}, []string{entry.Name})
+	switch {
+	case err != nil:
+		// The name failed even if its line is written, e.g. not confirmed by the hook.
+		result.Greeting = ""
+		result.Error = err.Error()
+	case written == 0:
+		result.Skipped = true
+	}
+	return result, err
+}
+
+// Summary aggregates the results of GreetAll or GreetWithResult.
+type Summary struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// GreetAll greets the names one by one and returns a GreetResult for each name
+// with the summary of the run. Unlike Greet, it continues after a failed name.
+func (h *HelloWorld) GreetAll(ctx context.Context, names ...string) ([]GreetResult, Summary) {
+	entries := make([]NamedEntry, len(names))
+	for i, name := range names {
+		entries[i] = NamedEntry{Name: name}
+	}
+	return h.GreetWithResult(ctx, entries)
+}
+
+// GreetWithResult is like GreetAll, the metadata of each entry is set in the
+// meta field of its result. A name greeted without writing, e.g. an empty name
+// with PolicySkip or a name seen by the DedupStore, is counted as skipped.
+func (h *HelloWorld) GreetWithResult(ctx context.Context, entries []NamedEntry) ([]GreetResult, Summary) {
+	start := time.Now()
+
+	results := make([]GreetResult, 0, len(entries))
+	summary := Summary{Total: len(entries)}
+	for _, entry := range entries {
+		result, err := h.greetResult(ctx, entry)
+		switch {
+		case err != nil:
+			summary.Failed++
+		case result.Skipped:
+			summary.Skipped++
 		default:
-			fmt.Printf("Hello, %s!\n", name)
+			summary.Succeeded++
+		}
+		results = append(results, result)
+	}
+	summary.Duration = time.Since(start)
+	return results, summary
+}
+
+// PrioritizedName is a name to greet with a priority, higher is greeted first.
+type PrioritizedName struct {
+	Name     string
+	Priority int
+}
+
+// GreetPrioritized greets the names by priority from high to low, names with
+// the same priority keep the input order. If ctx is done, the remaining
+// lower-priority names are not greeted.
+func (h *HelloWorld) GreetPrioritized(ctx context.Context, names []PrioritizedName) error {
+	sorted := make([]PrioritizedName, len(names))
+	copy(sorted, names)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	for _, name := range sorted {
+		if _, err := h.Greet(ctx, name.Name); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (h *HelloWorld) Configure(cfg Config) {
+// DefineGroup defines the group with the names as its members, redefining a
+// group replaces its members. The names are copied.
+func (h *HelloWorld) DefineGroup(group string, names ...string) {
+	members := make([]string, len(names))
+	copy(members, names)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.groups[group] = members
+}
+
+// GreetGroup greets the members of the group in the defined order, it returns
+// an error wrapping ErrUnknownGroup if the group is not defined.
+func (h *HelloWorld) GreetGroup(ctx context.Context, group string) error {
+	h.mu.Lock()
+	members, ok := h.groups[group]
+	h.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("greet group %q: %w", group, ErrUnknownGroup)
+	}
+
+	_, err := h.Greet(ctx, members...)
+	return err
+}
+
+// GreetIdempotent greets the names only once for the same key and names
+// within the IdempotencyTTL, the duplicated calls are skipped.
+// If the greeting fails, the key is released so the caller can retry.
+func (h *HelloWorld) GreetIdempotent(ctx context.Context, key string, names ...string) (skipped bool, err error) {
+	id := key + "\x00" + strings.Join(names, "\x00")
+	now := time.Now()
+
+	h.mu.Lock()
+	ttl, _ := h.options["idempotencyTTL"].(time.Duration)
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	// Clean up the expired keys lazily.
+	for k, expiry := range h.idempotencyKeys {
+		if !now.Before(expiry) {
+			delete(h.idempotencyKeys, k)
+		}
+	}
+	if _, ok := h.idempotencyKeys[id]; ok {
+		h.mu.Unlock()
+		return true, nil
+	}
+	// Reserve the key before greeting to skip the concurrent duplicates.
+	h.idempotencyKeys[id] = now.Add(ttl)
+	h.mu.Unlock()
+
+	if _, err := h.Greet(ctx, names...); err != nil {
+		h.mu.Lock()
+		delete(h.idempotencyKeys, id)
+		h.mu.Unlock()
+		return false, err
+	}
+	return false, nil
+}
+
+// Configure applies the config, it returns an error wrapping ErrInvalidConfig
+// if any of the durations or retries is negative, or ErrClosed after Close.
+func (h *HelloWorld) Configure(cfg Config) error {
+	if cfg.Timeout < 0 || cfg.Retries < 0 || cfg.DeadlineWarnThreshold < 0 || cfg.IdempotencyTTL < 0 {
+		return fmt.Errorf("configure: %w", ErrInvalidConfig)
+	}
+	if cfg.EmptyNamePolicy < PolicyGreet || cfg.EmptyNamePolicy > PolicyError {
+		return fmt.Errorf("configure: %w: unknown empty name policy %d", ErrInvalidConfig, cfg.EmptyNamePolicy)
+	}
+	if cfg.CaseMode < CaseNone || cfg.CaseMode > CaseTitle {
+		return fmt.Errorf("configure: %w: unknown case mode %d", ErrInvalidConfig, cfg.CaseMode)
+	}
+	if cfg.OrderMode < OrderInput || cfg.OrderMode > OrderShuffle {
+		return fmt.Errorf("configure: %w: unknown order mode %d", ErrInvalidConfig, cfg.OrderMode)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return fmt.Errorf("configure: %w", ErrClosed)
+	}
 	h.options["timeout"] = cfg.Timeout
 	h.options["retries"] = cfg.Retries
 	h.options["debug"] = cfg.Debug
+	h.options["deadlineWarnThreshold"] = cfg.DeadlineWarnThreshold
+	h.options["emptyNamePolicy"] = cfg.EmptyNamePolicy
+	h.options["idempotencyTTL"] = cfg.IdempotencyTTL
+	h.options["caseMode"] = cfg.CaseMode
+	h.options["seqSuffix"] = cfg.SeqSuffix
+	h.filter = cfg.Filter
+	h.options["strictWriter"] = cfg.StrictWriter
+	h.options["orderMode"] = cfg.OrderMode
+	h.grammar = cfg.Grammar
+	return nil
+}
+
+// Close closes the greeter, the later Greet and Configure calls return ErrClosed.
+// Closing a closed greeter returns ErrClosed as well.
+func (h *HelloWorld) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return fmt.Errorf("close: %w", ErrClosed)
+	}
+	h.closed = true
+	return nil
+}
+
+// Disable pauses the greeter, the later Greet calls return nil without
+// writing or calling the hooks, the skipped names are counted in
+// Stats.Skipped. Unlike Close, it can be resumed by Enable.
+func (h *HelloWorld) Disable() {
+	h.disabled.Store(true)
+}
+
+// Enable resumes the greeter paused by Disable.
+func (h *HelloWorld) Enable() {
+	h.disabled.Store(false)
+}
+
+// Health is the state of the greeter, see HelloWorld.Health.
+type Health struct {
+	Closed   bool `json:"closed"`
+	Disabled bool `json:"disabled"`
+}
+
+// Health reports whether the greeter is closed or disabled.
+func (h *HelloWorld) Health() Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Health{Closed: h.closed, Disabled: h.disabled.Load()}
+}
+
+// Reset clears all options and the greeting sequence number, keeping name and
+// createdAt intact. When resetCounters is true, greetCount is reset to zero as well.
+func (h *HelloWorld) Reset(resetCounters bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.options = make(map[string]interface{})
+	h.filter = nil
+	h.grammar = nil
+	h.seq.Store(0)
+	if resetCounters {
+		h.greetCount = 0
+		h.stats = Stats{}
+	}
+}
+
+// SetField attaches a key/value metadata, e.g. team, environment, version,
+// which is included in every report.
+func (h *HelloWorld) SetField(key string, value interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fields[key] = value
+}
+
+// Fields returns a copy of the attached metadata.
+func (h *HelloWorld) Fields() map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fields := make(map[string]interface{}, len(h.fields))
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	return fields
+}
+
+// OptionKeys returns the sorted keys of the options set by Configure and the
+// fields set by SetField, a key in both is listed once.
+func (h *HelloWorld) OptionKeys() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.options)+len(h.fields))
+	for key := range h.options {
+		keys = append(keys, key)
+	}
+	for key := range h.fields {
+		if _, ok := h.options[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetLocation sets the time zone to render createdAt in the reports and the
+// JSON encoding, the default is time.Local. A nil loc falls back to UTC.
+func (h *HelloWorld) SetLocation(loc *time.Location) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.location = loc
+}
+
+// localCreatedAt returns createdAt in the configured location, the caller
+// must hold h.mu.
+func (h *HelloWorld) localCreatedAt() time.Time {
+	loc := h.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return h.createdAt.In(loc)
+}
+
+// MarshalJSON encodes the greeter, map keys are sorted by encoding/json.
+func (h *HelloWorld) MarshalJSON() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return json.Marshal(struct {
+		Name      string                 `json:"name"`
+		CreatedAt time.Time              `json:"createdAt"`
+		Options   map[string]interface{} `json:"options"`
+		Fields    map[string]interface{} `json:"fields"`
+		Stats     Stats                  `json:"stats"`
+	}{h.name, h.localCreatedAt(), h.options, h.fields, h.stats})
+}
+
+// ReportFormat is the output format of Report.
+type ReportFormat int
+
+const (
+	FormatText ReportFormat = iota
+	FormatJSON
+	FormatCSV
+)
+
+// Report renders the greeter report in the given format.
+func (h *HelloWorld) Report(format ReportFormat) (string, error) {
+	switch format {
+	case FormatText:
+		return h.generateReport(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(h, "", "  ")
+		return string(data), err
+	case FormatCSV:
+		return h.generateCSVReport()
+	default:
+		return "", fmt.Errorf("unknown report format: %d", format)
+	}
+}
+
+// WriteReportCompressed renders the report in the given format and writes
+// it gzip-compressed to w. The gzip stream is closed even if the write fails,
+// w itself is left open.
+func (h *HelloWorld) WriteReportCompressed(w io.Writer, format ReportFormat) error {
+	report, err := h.Report(format)
+	if err != nil {
+		return fmt.Errorf("write compressed report: %w", err)
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := io.WriteString(gz, report); err != nil {
+		gz.Close()
+		return fmt.Errorf("write compressed report: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write compressed report: %w", err)
+	}
+	return nil
+}
+
+// GreetAndReport greets the names and then renders the report in the given
+// format. The report is rendered even if the greeting fails, so it includes
+// the undelivered count, and the greeting error is returned with it.
+func (h *HelloWorld) GreetAndReport(ctx context.Context, format ReportFormat, names ...string) (report string, err error) {
+	_, gerr := h.Greet(ctx, names...)
+	report, rerr := h.Report(format)
+	if gerr != nil {
+		return report, gerr
+	}
+	if rerr != nil {
+		return report, fmt.Errorf("greet and report: %w", rerr)
+	}
+	return report, nil
+}
+
+// generateCSVReport writes a header row and a single data row,
+// quoting and escaping is handled by encoding/csv.
+func (h *HelloWorld) generateCSVReport() (string, error) {
+	h.mu.Lock()
+	timeout, _ := h.options["timeout"].(time.Duration)
+	retries, _ := h.options["retries"].(int)
+	debug, _ := h.options["debug"].(bool)
+	record := []string{
+		h.name,
+		h.localCreatedAt().Format(time.RFC3339),
+		timeout.String(),
+		fmt.Sprint(retries),
+		fmt.Sprint(debug),
+		fmt.Sprint(h.stats.Delivered),
+		fmt.Sprint(h.stats.Undelivered),
+	}
+	h.mu.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"name", "created", "timeout", "retries", "debug", "delivered", "undelivered"})
+	w.Write(record)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StartReportTicker writes the report to the configured writer every interval
+// until ctx is cancelled. The returned stop function cancels the ticker and
+// waits for the goroutine to exit, it is safe to call more than once.
+//
+// It returns an error wrapping ErrInvalidConfig without starting the goroutine
+// if the interval is not positive.
+func (h *HelloWorld) StartReportTicker(ctx context.Context, interval time.Duration, format ReportFormat) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("start report ticker: %w: non-positive interval %s", ErrInvalidConfig, interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := h.Report(format)
+				if err != nil {
+					report = fmt.Sprintf("Error generating report: %v", err)
+				}
+				h.mu.Lock()
+				outs := h.outputs()
+				h.mu.Unlock()
+				h.writeOut(outs, []byte(report+"\n"))
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
 }
 
 func (h *HelloWorld) generateReport() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	data, _ := json.MarshalIndent(h.options, "", "  ")
+	fields, _ := json.MarshalIndent(h.fields, "", "  ")
 	return fmt.Sprintf(`
 		HelloWorld Report
 		================
 		Name: %s
 		Created: %s
 		Options: %s
-	`, h.name, h.createdAt.Format(time.RFC3339), string(data))
+		Fields: %s
+		Delivered: %d, Undelivered: %d, Retries: %d, Avg Latency: %s
+	`, h.name, h.localCreatedAt().Format(time.RFC3339), string(data), string(fields),
+		h.stats.Delivered, h.stats.Undelivered, h.stats.Retries, h.stats.AvgLatency())
 }
 
 func main() {
@@ -80,14 +2112,17 @@ func main() {
 	defer cancel()
 
 	greeter := NewHelloWorld("Go")
-	greeter.Configure(Config{
+	if err := greeter.Configure(Config{
 		Timeout: timeout,
 		Retries: 3,
 		Debug:   true,
-	})
+	}); err != nil {
+		fmt.Printf("Error configuring: %v\n", err)
+		return
+	}
 
-	if err := greeter.Greet(ctx, "Alice", "Bob"); err != nil {
-		fmt.Printf("Error greeting: %v\n", err)
+	if n, err := greeter.Greet(ctx, "Alice", "Bob"); err != nil {
+		fmt.Printf("Error greeting after %d names: %v\n", n, err)
 	}
 	fmt.Println(greeter.generateReport())
 }
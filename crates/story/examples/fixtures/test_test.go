@@ -0,0 +1,470 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestGreetConcurrentWrites(t *testing.T) {
+	const goroutines, names = 50, 20
+
+	h := NewHelloWorld("concurrent")
+	var buf bytes.Buffer
+	h.SetWriter(&buf)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			batch := make([]string, names)
+			for i := range batch {
+				batch[i] = fmt.Sprintf("g%d-n%d", g, i)
+			}
+			if _, err := h.Greet(context.Background(), batch...); err != nil {
+				t.Errorf("greet: %v", err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	line := regexp.MustCompile(`^Hello, g\d+-n\d+!$`)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != goroutines*names {
+		t.Fatalf("got %d lines, want %d", len(lines), goroutines*names)
+	}
+	seen := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		if !line.MatchString(l) {
+			t.Fatalf("garbled line %q", l)
+		}
+		if seen[l] {
+			t.Fatalf("duplicated line %q", l)
+		}
+		seen[l] = true
+	}
+}
+
+func TestInstanceCountConcurrent(t *testing.T) {
+	const goroutines = 100
+
+	before := InstanceCount()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			NewHelloWorld(fmt.Sprintf("instance-%d", g))
+		}(g)
+	}
+	wg.Wait()
+
+	if got := InstanceCount() - before; got != goroutines {
+		t.Fatalf("InstanceCount grew by %d, want %d", got, goroutines)
+	}
+}
+
+func TestReportWithFilter(t *testing.T) {
+	h := NewHelloWorld("filtered")
+	if err := h.Configure(Config{
+		Retries: 1,
+		Filter:  func(name string) bool { return name != "Bob" },
+		Grammar: DefaultGrammar,
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	var buf bytes.Buffer
+	h.SetWriter(&buf)
+	if _, err := h.Greet(context.Background(), "Alice", "Bob"); err != nil {
+		t.Fatalf("greet: %v", err)
+	}
+	if got := buf.String(); got != "Hello, Alice!\n" {
+		t.Fatalf("got %q, want only Alice greeted", got)
+	}
+
+	report, err := h.Report(FormatJSON)
+	if err != nil {
+		t.Fatalf("json report: %v", err)
+	}
+	var decoded struct {
+		Options map[string]interface{} `json:"options"`
+	}
+	if err := json.Unmarshal([]byte(report), &decoded); err != nil {
+		t.Fatalf("decode json report: %v", err)
+	}
+	if decoded.Options["retries"] != float64(1) {
+		t.Fatalf("got options %v, want retries 1", decoded.Options)
+	}
+
+	if text := h.generateReport(); !strings.Contains(text, `"retries": 1`) {
+		t.Fatalf("text report misses the options:\n%s", text)
+	}
+}
+
+func TestRetryErrorKeepsAttemptErrors(t *testing.T) {
+	h := NewHelloWorld("retry")
+	h.SetWriter(io.Discard)
+	if err := h.Configure(Config{Retries: 2}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	errTimeout := errors.New("timeout")
+	attempt := 0
+	h.OnGreet(func(ctx context.Context, name string) *Ack {
+		attempt++
+		if attempt == 2 {
+			return &Ack{}
+		}
+		return &Ack{Err: fmt.Errorf("attempt %d: %w", attempt, errTimeout)}
+	})
+
+	_, err := h.Greet(context.Background(), "Alice")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("got %v, want a *RetryError", err)
+	}
+	if len(retryErr.Errors) != 3 {
+		t.Fatalf("got %d attempt errors, want 3", len(retryErr.Errors))
+	}
+	for i, attemptErr := range retryErr.Errors {
+		if !errors.Is(attemptErr, ErrNotDelivered) {
+			t.Errorf("attempt %d: got %v, want ErrNotDelivered", i+1, attemptErr)
+		}
+		if want := i != 1; errors.Is(attemptErr, errTimeout) != want {
+			t.Errorf("attempt %d: got %v, want the timeout %t", i+1, attemptErr, want)
+		}
+	}
+	if !errors.Is(err, errTimeout) {
+		t.Fatalf("got %v, want the timeout of the last attempt", err)
+	}
+}
+
+func TestGreetOneAllocatesLess(t *testing.T) {
+	h := NewHelloWorld("allocs")
+	h.SetWriter(io.Discard)
+	ctx := context.Background()
+
+	one := testing.AllocsPerRun(100, func() { h.GreetOne(ctx, "Alice") })
+	batch := testing.AllocsPerRun(100, func() { h.Greet(ctx, "Alice") })
+	if one >= batch {
+		t.Fatalf("GreetOne allocates %v per greeting, want less than the %v of Greet", one, batch)
+	}
+}
+
+// BenchmarkGreetOne and BenchmarkGreet compare the allocations of the fast
+// path of GreetOne to Greet of the same name.
+func BenchmarkGreetOne(b *testing.B) {
+	h := NewHelloWorld("bench")
+	h.SetWriter(io.Discard)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := h.GreetOne(ctx, "Alice"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGreet(b *testing.B) {
+	h := NewHelloWorld("bench")
+	h.SetWriter(io.Discard)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.Greet(ctx, "Alice"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// recordingProvider records the names of the spans started by its tracers.
+type recordingProvider struct {
+	noop.TracerProvider
+	mu    sync.Mutex
+	spans []string
+}
+
+func (p *recordingProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	provider *recordingProvider
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.provider.mu.Lock()
+	t.provider.spans = append(t.provider.spans, name)
+	t.provider.mu.Unlock()
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+func TestGreetOneGlobalTracer(t *testing.T) {
+	provider := &recordingProvider{}
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	h := NewHelloWorld("traced")
+	h.SetWriter(io.Discard)
+	if err := h.GreetOne(context.Background(), "Alice"); err != nil {
+		t.Fatalf("greet one: %v", err)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.spans) != 1 || provider.spans[0] != "HelloWorld.Greet" {
+		t.Fatalf("got spans %v, want one HelloWorld.Greet", provider.spans)
+	}
+}
+
+func TestNilWriterStrict(t *testing.T) {
+	h := NewHelloWorld("strict")
+	if err := h.Configure(Config{StrictWriter: true}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	var nilBuf *bytes.Buffer
+	h.SetWriter(nilBuf)
+	h.AddWriter(nil)
+
+	if _, err := h.Greet(context.Background(), "Alice"); !errors.Is(err, ErrNoWriter) {
+		t.Fatalf("greet: got %v, want ErrNoWriter", err)
+	}
+	if err := h.GreetOne(context.Background(), "Alice"); !errors.Is(err, ErrNoWriter) {
+		t.Fatalf("greet one: got %v, want ErrNoWriter", err)
+	}
+
+	var buf bytes.Buffer
+	h.AddWriter(&buf)
+	if _, err := h.Greet(context.Background(), "Alice"); err != nil {
+		t.Fatalf("greet with an added writer: %v", err)
+	}
+	if got := buf.String(); got != "Hello, Alice!\n" {
+		t.Fatalf("got %q from the added writer", got)
+	}
+}
+
+func TestNilWriterLenient(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer r.Close()
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	h := NewHelloWorld("lenient")
+	h.SetWriter(nil)
+	h.AddWriter(nil)
+	_, greetErr := h.Greet(context.Background(), "Alice")
+	os.Stdout = stdout
+	w.Close()
+	if greetErr != nil {
+		t.Fatalf("greet: %v", greetErr)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+	if got := string(out); got != "Hello, Alice!\n" {
+		t.Fatalf("got %q on stdout, want the greeting once", got)
+	}
+}
+
+func TestGrammar(t *testing.T) {
+	h := NewHelloWorld("grammar")
+	var buf bytes.Buffer
+	h.SetWriter(&buf)
+	honorifics := map[string]string{"Ada": "Dr."}
+	if err := h.Configure(Config{
+		CaseMode: CaseUpper,
+		Grammar: func(name string) string {
+			if honorific, ok := honorifics[name]; ok {
+				return honorific + " " + name
+			}
+			return name
+		},
+	}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+
+	if _, err := h.Greet(context.Background(), "Ada", "Bob"); err != nil {
+		t.Fatalf("greet: %v", err)
+	}
+	if got, want := buf.String(), "HELLO, DR. ADA!\nHELLO, BOB!\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestGreetHookSeesWrittenLine(t *testing.T) {
+	h := NewHelloWorld("hook")
+	var buf bytes.Buffer
+	h.SetWriter(&buf)
+	h.OnGreet(func(ctx context.Context, name string) *Ack {
+		if want := "Hello, " + name + "!\n"; !strings.HasSuffix(buf.String(), want) {
+			t.Errorf("hook of %s: got output %q, want the line %q written", name, buf.String(), want)
+		}
+		return &Ack{Delivered: true}
+	})
+
+	if _, err := h.Greet(context.Background(), "Alice", "Bob"); err != nil {
+		t.Fatalf("greet: %v", err)
+	}
+	if err := h.GreetOne(context.Background(), "Carol"); err != nil {
+		t.Fatalf("greet one: %v", err)
+	}
+	if got, want := buf.String(), "Hello, Alice!\nHello, Bob!\nHello, Carol!\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// writeRecorder records the size of each write.
+type writeRecorder struct {
+	bytes.Buffer
+	writes []int
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, len(p))
+	return w.Buffer.Write(p)
+}
+
+func TestGreetWritesInChunks(t *testing.T) {
+	const names = 2000
+
+	h := NewHelloWorld("chunks")
+	var out writeRecorder
+	h.SetWriter(&out)
+	batch := make([]string, names)
+	for i := range batch {
+		batch[i] = fmt.Sprintf("name-%d", i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Use(func(next GreetFunc) GreetFunc {
+		return func(ctx context.Context, name string) error {
+			if name == "name-1500" {
+				cancel()
+			}
+			return next(ctx, name)
+		}
+	})
+
+	written, err := h.Greet(ctx, batch...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != written {
+		t.Fatalf("got %d lines, want the %d greeted before the cancellation", got, written)
+	}
+	if len(out.writes) < 2 {
+		t.Fatalf("got %d writes, want the batch written in chunks", len(out.writes))
+	}
+	for _, n := range out.writes {
+		if n > greetFlushSize+64 {
+			t.Fatalf("got a write of %d bytes, want at most about %d", n, greetFlushSize)
+		}
+	}
+}
+
+func TestGreetOneProgress(t *testing.T) {
+	h := NewHelloWorld("progress")
+	h.SetWriter(io.Discard)
+	if _, err := h.Greet(context.Background(), "Alice", "Bob", "Carol"); err != nil {
+		t.Fatalf("greet: %v", err)
+	}
+	if processed, total := h.Progress(); processed != 3 || total != 3 {
+		t.Fatalf("after Greet: got %d/%d, want 3/3", processed, total)
+	}
+
+	if err := h.GreetOne(context.Background(), "Dave"); err != nil {
+		t.Fatalf("greet one: %v", err)
+	}
+	if processed, total := h.Progress(); processed != 1 || total != 1 {
+		t.Fatalf("after GreetOne: got %d/%d, want 1/1", processed, total)
+	}
+}
+
+func TestReleaseResetsReusedInstance(t *testing.T) {
+	// The pool may drop an instance, e.g. with the race detector, so retry
+	// until the released one is handed out again.
+	var reused *HelloWorld
+	for attempt := 0; attempt < 20 && reused == nil; attempt++ {
+		reused = releaseAndReacquire(t)
+	}
+	if reused == nil {
+		t.Skip("the pool dropped the released instance")
+	}
+	defer Release(reused)
+
+	if processed, total := reused.Progress(); processed != 0 || total != 0 {
+		t.Fatalf("got progress %d/%d, want 0/0", processed, total)
+	}
+	if n := len(reused.queueReady); n != 0 {
+		t.Fatalf("got %d pending queue signals, want none", n)
+	}
+
+	fresh := NewHelloWorldAt("second", reused.createdAt)
+	for _, format := range []ReportFormat{FormatText, FormatJSON} {
+		got, err := reused.Report(format)
+		if err != nil {
+			t.Fatalf("report %d of the reused instance: %v", format, err)
+		}
+		want, err := fresh.Report(format)
+		if err != nil {
+			t.Fatalf("report %d of a new instance: %v", format, err)
+		}
+		if got != want {
+			t.Fatalf("report %d of the reused instance:\n%s\nwant as a new instance:\n%s", format, got, want)
+		}
+	}
+}
+
+// releaseAndReacquire uses and releases an instance, and returns it if the pool
+// hands it out again, otherwise nil.
+func releaseAndReacquire(t *testing.T) *HelloWorld {
+	t.Helper()
+	h := AcquireHelloWorld("first")
+	h.SetWriter(io.Discard)
+	h.SetField("team", "greeters")
+	if err := h.Configure(Config{Retries: 2}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if _, err := h.Greet(context.Background(), "Alice", "Bob"); err != nil {
+		t.Fatalf("greet: %v", err)
+	}
+	if err := h.Enqueue("Carol"); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	Release(h)
+
+	var others []*HelloWorld
+	defer func() {
+		for _, g := range others {
+			Release(g)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		g := AcquireHelloWorld("second")
+		if g == h {
+			return g
+		}
+		others = append(others, g)
+	}
+	return nil
+}
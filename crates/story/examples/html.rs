@@ -81,7 +81,7 @@ impl Render for Example {
                                 window,
                                 cx,
                             )
-                            .selectable(),
+                            .selectable(true),
                         ),
                 ),
             )
@@ -85,7 +85,7 @@ impl Render for Example {
                                 window,
                                 cx,
                             )
-                            .selectable()
+                            .selectable(true)
                             .style(TextViewStyle {
                                 highlight_theme: theme.clone(),
                                 is_dark,
@@ -1,10 +1,10 @@
 use gpui::{
-    App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement, ParentElement, Render,
-    Styled, Window,
+    prelude::FluentBuilder as _, App, AppContext, Context, Entity, FocusHandle, Focusable,
+    IntoElement, ParentElement, Render, Styled, Window,
 };
 use gpui_component::{
     alert::Alert,
-    button::{Button, ButtonGroup},
+    button::{Button, ButtonGroup, ButtonVariants as _},
     dock::PanelControl,
     text::TextView,
     v_flex, IconName, Selectable as _, Sizable as _, Size,
@@ -15,6 +15,7 @@ use crate::section;
 pub struct AlertStory {
     size: Size,
     banner_visible: bool,
+    update_visible: bool,
     focus_handle: gpui::FocusHandle,
 }
 
@@ -23,6 +24,7 @@ impl AlertStory {
         Self {
             size: Size::default(),
             banner_visible: true,
+            update_visible: true,
             focus_handle: cx.focus_handle(),
         }
     }
@@ -165,6 +167,89 @@ impl Render for AlertStory {
                         ),
                 ),
             )
+            .child(
+                section("Solid").w_2_3().child(
+                    v_flex()
+                        .w_full()
+                        .gap_3()
+                        .child(
+                            Alert::info("solid-info", "This is a solid info alert.")
+                                .solid()
+                                .with_size(self.size)
+                                .title("Info message"),
+                        )
+                        .child(
+                            Alert::success("solid-success", "Your changes have been saved.")
+                                .solid()
+                                .with_size(self.size),
+                        )
+                        .child(
+                            Alert::warning("solid-warning", "Your trial ends in 3 days.")
+                                .solid()
+                                .with_size(self.size),
+                        )
+                        .child(
+                            Alert::error("solid-error", "The server is not responding.")
+                                .solid()
+                                .with_size(self.size),
+                        ),
+                ),
+            )
+            .child(
+                section("With Actions").w_2_3().child(
+                    v_flex()
+                        .w_full()
+                        .gap_3()
+                        .child(
+                            Alert::info(
+                                "update",
+                                TextView::markdown(
+                                    "update-message",
+                                    "A new version is available, see the \
+                                    [release notes](https://github.com/longbridge/gpui-component) \
+                                    for details.",
+                                    window,
+                                    cx,
+                                ),
+                            )
+                            .title("Update available")
+                            .with_size(self.size)
+                            .visible(self.update_visible)
+                            .on_close(cx.listener(|this, _, _, cx| {
+                                this.update_visible = false;
+                                cx.notify();
+                            }))
+                            .action(Button::new("update-now").small().primary().label("Update"))
+                            .action(
+                                Button::new("update-later")
+                                    .small()
+                                    .outline()
+                                    .label("Later")
+                                    .on_click(cx.listener(|this, _, _, cx| {
+                                        this.update_visible = false;
+                                        cx.notify();
+                                    })),
+                            ),
+                        )
+                        .child(
+                            Alert::warning("banner-action", "Your session will expire soon.")
+                                .banner()
+                                .with_size(self.size)
+                                .action(Button::new("renew").xsmall().outline().label("Renew")),
+                        )
+                        .when(!self.update_visible, |this| {
+                            this.child(
+                                Button::new("reset-update")
+                                    .small()
+                                    .label("Show the update alert")
+                                    .on_click(cx.listener(|this, _, _, cx| {
+                                        this.update_visible = true;
+                                        cx.notify();
+                                    })),
+                            )
+                        }),
+                ),
+            )
             .child(
                 section("Banner").w_2_3().child(
                     v_flex()
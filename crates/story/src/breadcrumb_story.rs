@@ -0,0 +1,119 @@
+use gpui::{
+    div, px, App, AppContext, Context, Entity, Focusable, IntoElement, ParentElement, Render,
+    SharedString, Styled, Window,
+};
+
+use gpui_component::{
+    breadcrumb::{Breadcrumb, BreadcrumbItem, BreadcrumbSeparator},
+    v_flex, IconName,
+};
+
+use crate::section;
+
+const PATH: [&str; 6] = [
+    "gpui-component",
+    "crates",
+    "ui",
+    "src",
+    "menu",
+    "popup_menu.rs",
+];
+
+pub struct BreadcrumbStory {
+    focus_handle: gpui::FocusHandle,
+    clicked: Option<SharedString>,
+}
+
+impl super::Story for BreadcrumbStory {
+    fn title() -> &'static str {
+        "Breadcrumb"
+    }
+
+    fn description() -> &'static str {
+        "Displays the path to the current resource using a hierarchy of links."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl BreadcrumbStory {
+    pub(crate) fn new(_: &mut Window, cx: &mut App) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+            clicked: None,
+        }
+    }
+
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn path_breadcrumb(&self, cx: &mut Context<Self>) -> Breadcrumb {
+        PATH.iter()
+            .enumerate()
+            .fold(Breadcrumb::new(), |this, (ix, name)| {
+                let item = BreadcrumbItem::new(ix, *name);
+                this.item(if ix < PATH.len() - 1 {
+                    item.icon(IconName::Folder)
+                } else {
+                    item
+                })
+            })
+            .on_click(cx.listener(|this, ix: &usize, _, cx| {
+                this.clicked = Some(PATH[*ix].into());
+                cx.notify();
+            }))
+    }
+}
+
+impl Focusable for BreadcrumbStory {
+    fn focus_handle(&self, _: &gpui::App) -> gpui::FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for BreadcrumbStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        v_flex()
+            .gap_6()
+            .child(
+                section("Breadcrumb").child(
+                    Breadcrumb::new()
+                        .id("basic")
+                        .item(BreadcrumbItem::new("home", "Home"))
+                        .item(BreadcrumbItem::new("components", "Components"))
+                        .item(BreadcrumbItem::new("breadcrumb", "Breadcrumb")),
+                ),
+            )
+            .child(
+                section("Slash Separator").child(
+                    self.path_breadcrumb(cx)
+                        .id("slash-path")
+                        .separator(BreadcrumbSeparator::Slash),
+                ),
+            )
+            .child(
+                section("Collapsed").child(
+                    self.path_breadcrumb(cx)
+                        .id("collapsed-path")
+                        .max_visible_items(3),
+                ),
+            )
+            .child(
+                section("Collapse to Fit").child(
+                    div()
+                        .w(px(260.))
+                        .child(self.path_breadcrumb(cx).id("fit-path")),
+                ),
+            )
+            .child(
+                section("Clicked").child(
+                    self.clicked
+                        .clone()
+                        .unwrap_or_else(|| "Click a segment above.".into()),
+                ),
+            )
+    }
+}
@@ -4,7 +4,9 @@ use gpui::{
 };
 
 use gpui_component::{
-    button::{Button, ButtonCustomVariant, ButtonGroup, ButtonVariants as _, DropdownButton},
+    button::{
+        Button, ButtonCustomVariant, ButtonGroup, ButtonStyle, ButtonVariants as _, DropdownButton,
+    },
     checkbox::Checkbox,
     h_flex, v_flex, ActiveTheme, Disableable as _, Icon, IconName, Selectable as _, Sizable as _,
     Theme,
@@ -916,5 +918,24 @@ impl Render for ButtonStory {
                             .on_click(Self::on_click),
                     ),
             )
+            .child(
+                section("Style Overrides").child(
+                    Button::new("button-style-overrides")
+                        .label("Style Overrides")
+                        .style_overrides(ButtonStyle {
+                            bg: Some(cx.theme().cyan),
+                            fg: Some(cx.theme().primary_foreground),
+                            border: Some(cx.theme().cyan),
+                            hover_bg: Some(cx.theme().cyan.opacity(0.9)),
+                            radius: Some(px(16.)),
+                            ..Default::default()
+                        })
+                        .disabled(disabled)
+                        .selected(selected)
+                        .loading(loading)
+                        .when(compact, |this| this.compact())
+                        .on_click(Self::on_click),
+                ),
+            )
     }
 }
@@ -0,0 +1,125 @@
+use gpui::{
+    div, hsla, px, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement,
+    ParentElement, Render, Styled, Window,
+};
+
+use gpui_component::{
+    button::{Button, ButtonVariants as _},
+    card::Card,
+    h_flex, v_flex, ActiveTheme as _, Disableable as _, IconName, Sizable as _,
+};
+
+use crate::section;
+
+pub struct CardStory {
+    focus_handle: FocusHandle,
+    clicks: usize,
+}
+
+impl super::Story for CardStory {
+    fn title() -> &'static str {
+        "Card"
+    }
+
+    fn description() -> &'static str {
+        "A container with the optional media, header and footer, \
+        it can be clickable with the hover and pressed states."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl CardStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(_: &mut Window, cx: &mut Context<Self>) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+            clicks: 0,
+        }
+    }
+}
+
+impl Focusable for CardStory {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for CardStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        v_flex()
+            .gap_6()
+            .child(
+                section("Header, Body and Footer").child(
+                    Card::new("basic")
+                        .w_96()
+                        .title("Create project")
+                        .subtitle("Deploy your new project in one-click.")
+                        .action(Button::new("more").ghost().small().icon(IconName::Ellipsis))
+                        .child("The project will be created with the default settings.")
+                        .footer(
+                            h_flex()
+                                .w_full()
+                                .justify_end()
+                                .gap_2()
+                                .child(Button::new("cancel").label("Cancel"))
+                                .child(Button::new("deploy").primary().label("Deploy")),
+                        ),
+                ),
+            )
+            .child(
+                section("Media").child(
+                    Card::new("media")
+                        .w_72()
+                        .media(div().h_40().bg(hsla(0.6, 0.6, 0.7, 1.)))
+                        .title("Gallery")
+                        .subtitle("128 photos")
+                        .child(
+                            div()
+                                .text_sm()
+                                .text_color(cx.theme().muted_foreground)
+                                .child("The media is at the top without the padding."),
+                        ),
+                ),
+            )
+            .child(
+                section("Clickable")
+                    .child(
+                        Card::new("clickable")
+                            .w_72()
+                            .title("Clickable")
+                            .subtitle("Click, or focus and press Enter.")
+                            .child(format!("Clicked {} times", self.clicks))
+                            .on_click(cx.listener(|this, _, _, cx| {
+                                this.clicks += 1;
+                                cx.notify();
+                            })),
+                    )
+                    .child(
+                        Card::new("disabled")
+                            .w_72()
+                            .title("Disabled")
+                            .subtitle("The disabled card can't be clicked.")
+                            .disabled(true)
+                            .on_click(|_, _, _| {}),
+                    ),
+            )
+            .child(
+                section("No Border and Shadow").child(
+                    Card::new("plain")
+                        .w_72()
+                        .bordered(false)
+                        .shadow(false)
+                        .padding(px(8.))
+                        .bg(cx.theme().muted)
+                        .title("Plain")
+                        .child("A card with a smaller padding."),
+                ),
+            )
+    }
+}
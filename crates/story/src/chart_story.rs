@@ -7,7 +7,9 @@ use gpui_component::{
     chart::{AreaChart, BarChart, LineChart, PieChart},
     divider::Divider,
     dock::PanelControl,
-    h_flex, v_flex, ActiveTheme, StyledExt,
+    h_flex,
+    number_format::NumberFormat,
+    v_flex, ActiveTheme, StyledExt,
 };
 use serde::Deserialize;
 
@@ -219,7 +221,7 @@ impl Render for ChartStory {
                         BarChart::new(self.monthly_devices.clone())
                             .x(|d| d.month.clone())
                             .y(|d| d.desktop)
-                            .label(|d| d.desktop.to_string()),
+                            .label_format(NumberFormat::decimal()),
                         false,
                         cx,
                     )),
@@ -4,7 +4,10 @@ use gpui::{
 };
 
 use gpui_component::{
-    checkbox::Checkbox, h_flex, text::TextView, v_flex, ActiveTheme, Disableable as _, Sizable,
+    checkbox::{CheckState, Checkbox},
+    h_flex,
+    text::TextView,
+    v_flex, ActiveTheme, Disableable as _, Sizable,
 };
 
 use crate::section;
@@ -16,6 +19,7 @@ pub struct CheckboxStory {
     check3: bool,
     check4: bool,
     check5: bool,
+    fruits: [bool; 3],
 }
 
 impl super::Story for CheckboxStory {
@@ -45,6 +49,21 @@ impl CheckboxStory {
             check3: true,
             check4: false,
             check5: false,
+            fruits: [true, false, false],
+        }
+    }
+}
+
+const FRUITS: [&str; 3] = ["Apple", "Banana", "Orange"];
+
+impl CheckboxStory {
+    fn fruits_state(&self) -> CheckState {
+        if self.fruits.iter().all(|checked| *checked) {
+            CheckState::Checked
+        } else if self.fruits.iter().any(|checked| *checked) {
+            CheckState::Indeterminate
+        } else {
+            CheckState::Unchecked
         }
     }
 }
@@ -81,6 +100,30 @@ impl Render for CheckboxStory {
                                 })),
                         ),
                 )
+                .child(
+                    section("Indeterminate").child(
+                        v_flex()
+                            .gap_2()
+                            .child(
+                                Checkbox::new("select-all")
+                                    .label("Select all")
+                                    .state(self.fruits_state())
+                                    .on_click(cx.listener(|v, checked: &bool, _, _| {
+                                        v.fruits = [*checked; 3];
+                                    })),
+                            )
+                            .child(v_flex().pl_6().gap_2().children(
+                                FRUITS.iter().enumerate().map(|(ix, fruit)| {
+                                    Checkbox::new(("fruit", ix))
+                                        .label(*fruit)
+                                        .checked(self.fruits[ix])
+                                        .on_click(cx.listener(move |v, checked: &bool, _, _| {
+                                            v.fruits[ix] = *checked;
+                                        }))
+                                }),
+                            )),
+                    ),
+                )
                 .child(
                     section("Without label").child(
                         Checkbox::new("check1")
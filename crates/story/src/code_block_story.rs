@@ -0,0 +1,110 @@
+use gpui::{
+    px, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement, ParentElement,
+    Render, Styled, Window,
+};
+use gpui_component::{checkbox::Checkbox, code_block::CodeBlock, h_flex, v_flex};
+
+use crate::section;
+
+const RUST_CODE: &str = r#"fn main() {
+    let names = vec!["Alice", "Bob"];
+    for name in names {
+        println!("Hello, {}!", name);
+    }
+}
+"#;
+
+pub struct CodeBlockStory {
+    focus_handle: FocusHandle,
+    line_numbers: bool,
+    soft_wrap: bool,
+}
+
+impl super::Story for CodeBlockStory {
+    fn title() -> &'static str {
+        "CodeBlock"
+    }
+
+    fn description() -> &'static str {
+        "A read-only code block with the syntax highlighting and a copy button."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl CodeBlockStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(_: &mut Window, cx: &mut Context<Self>) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+            line_numbers: true,
+            soft_wrap: false,
+        }
+    }
+}
+
+impl Focusable for CodeBlockStory {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for CodeBlockStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        v_flex()
+            .gap_y_3()
+            .child(
+                h_flex()
+                    .gap_3()
+                    .child(
+                        Checkbox::new("line-numbers")
+                            .label("Line Numbers")
+                            .checked(self.line_numbers)
+                            .on_click(cx.listener(|this, checked: &bool, _, cx| {
+                                this.line_numbers = *checked;
+                                cx.notify();
+                            })),
+                    )
+                    .child(
+                        Checkbox::new("soft-wrap")
+                            .label("Soft Wrap")
+                            .checked(self.soft_wrap)
+                            .on_click(cx.listener(|this, checked: &bool, _, cx| {
+                                this.soft_wrap = *checked;
+                                cx.notify();
+                            })),
+                    ),
+            )
+            .child(
+                section("Basic").child(
+                    CodeBlock::new("rust", RUST_CODE, "rust")
+                        .w_full()
+                        .line_numbers(self.line_numbers)
+                        .soft_wrap(self.soft_wrap),
+                ),
+            )
+            .child(
+                section("Highlight Lines").child(
+                    CodeBlock::new("highlight", RUST_CODE, "rust")
+                        .w_full()
+                        .line_numbers(self.line_numbers)
+                        .soft_wrap(self.soft_wrap)
+                        .highlight_lines([3, 4, 5]),
+                ),
+            )
+            .child(
+                section("Max Height").child(
+                    CodeBlock::new("go", include_str!("../examples/fixtures/test.go"), "go")
+                        .w_full()
+                        .max_height(px(320.))
+                        .line_numbers(self.line_numbers)
+                        .soft_wrap(self.soft_wrap),
+                ),
+            )
+    }
+}
@@ -0,0 +1,199 @@
+use gpui::{
+    App, AppContext, Context, Entity, FocusHandle, Focusable, InteractiveElement, IntoElement,
+    ParentElement, Render, SharedString, Styled, Subscription, Window,
+};
+
+use gpui_component::{
+    button::Button,
+    command_palette::{Command, CommandPalette, CommandPaletteEvent, ToggleCommandPalette},
+    h_flex, v_flex, ActiveTheme as _, ContextModal as _, IconName, Theme, ThemeMode,
+};
+
+use crate::section;
+
+const FILES: &[&str] = &[
+    "crates/ui/src/lib.rs",
+    "crates/ui/src/command_palette.rs",
+    "crates/ui/src/list/list.rs",
+    "crates/ui/src/list/delegate.rs",
+    "crates/ui/src/modal.rs",
+    "crates/story/src/main.rs",
+    "crates/story/src/command_palette_story.rs",
+    "Cargo.toml",
+    "README.md",
+];
+
+pub struct CommandPaletteStory {
+    focus_handle: FocusHandle,
+    palette: Entity<CommandPalette>,
+    last_executed: Option<SharedString>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl super::Story for CommandPaletteStory {
+    fn title() -> &'static str {
+        "CommandPalette"
+    }
+
+    fn description() -> &'static str {
+        "A modal to search and execute commands by keyboard."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl CommandPaletteStory {
+    pub(crate) fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let palette = cx.new(|cx| {
+            let mut palette = CommandPalette::new(window, cx);
+            // Plain query to search files.
+            palette.commands(
+                FILES.iter().map(|path| {
+                    Command::new(format!("open:{}", path), *path).icon(IconName::BookOpen)
+                }),
+                window,
+                cx,
+            );
+            // Start with `>` to search commands.
+            palette.provider(
+                ">",
+                vec![
+                    Command::new("theme.toggle", "Toggle Dark Mode")
+                        .category("Theme")
+                        .icon(IconName::Moon)
+                        .on_action(|_, cx| {
+                            let mode = match cx.theme().mode.is_dark() {
+                                true => ThemeMode::Light,
+                                false => ThemeMode::Dark,
+                            };
+                            Theme::change(mode, None, cx);
+                        }),
+                    Command::new("notification.show", "Show Notification")
+                        .category("View")
+                        .icon(IconName::Bell)
+                        .on_action(|window, cx| {
+                            window.push_notification("Hello from the command palette.", cx)
+                        }),
+                    Command::new("notification.clear", "Clear Notifications")
+                        .category("View")
+                        .icon(IconName::Delete)
+                        .on_action(|window, cx| window.clear_notifications(cx)),
+                    Command::new("file.new", "New File")
+                        .category("File")
+                        .icon(IconName::Plus)
+                        .keybinding("cmd-n"),
+                    Command::new("file.save", "Save")
+                        .category("File")
+                        .keybinding("cmd-s"),
+                    Command::new("file.save_all", "Save All")
+                        .category("File")
+                        .keybinding("cmd-alt-s"),
+                    Command::new("edit.copy", "Copy")
+                        .category("Edit")
+                        .icon(IconName::Copy)
+                        .keybinding("cmd-c"),
+                    Command::new("settings.open", "Open Settings")
+                        .icon(IconName::Settings)
+                        .keybinding("cmd-,"),
+                ],
+                window,
+                cx,
+            );
+            palette
+        });
+
+        let _subscriptions = vec![cx.subscribe(&palette, |this, _, event, cx| match event {
+            CommandPaletteEvent::Execute(id) => {
+                this.last_executed = Some(id.clone());
+                cx.notify();
+            }
+        })];
+
+        Self {
+            focus_handle: cx.focus_handle(),
+            palette,
+            last_executed: None,
+            _subscriptions,
+        }
+    }
+
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn on_action_toggle(
+        &mut self,
+        _: &ToggleCommandPalette,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        CommandPalette::toggle(&self.palette, ">", window, cx);
+    }
+}
+
+impl Focusable for CommandPaletteStory {
+    fn focus_handle(&self, _: &gpui::App) -> gpui::FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for CommandPaletteStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let recent = self.palette.read(cx).recent(cx);
+
+        v_flex()
+            .track_focus(&self.focus_handle)
+            .on_action(cx.listener(Self::on_action_toggle))
+            .gap_6()
+            .child(
+                section("Command Palette").child(
+                    v_flex()
+                        .gap_3()
+                        .items_center()
+                        .child(
+                            h_flex()
+                                .gap_3()
+                                .child(
+                                    Button::new("open-commands")
+                                        .label("Show Commands")
+                                        .on_click(cx.listener(|this, _, window, cx| {
+                                            CommandPalette::toggle(&this.palette, ">", window, cx)
+                                        })),
+                                )
+                                .child(Button::new("open-files").label("Go to File").on_click(
+                                    cx.listener(|this, _, window, cx| {
+                                        CommandPalette::toggle(&this.palette, "", window, cx)
+                                    }),
+                                )),
+                        )
+                        .child(
+                            "Press `cmd-shift-p` (`ctrl-shift-p`) to show commands, \
+                            remove the `>` prefix to search files.",
+                        ),
+                ),
+            )
+            .child(
+                section("Last Executed").child(
+                    v_flex()
+                        .gap_1()
+                        .items_center()
+                        .child(self.last_executed.clone().unwrap_or_else(|| "None".into()))
+                        .child(
+                            h_flex()
+                                .text_sm()
+                                .text_color(cx.theme().muted_foreground)
+                                .child(format!(
+                                    "Recently used: {}",
+                                    recent
+                                        .iter()
+                                        .map(|id| id.as_ref())
+                                        .collect::<Vec<&str>>()
+                                        .join(", ")
+                                )),
+                        ),
+                ),
+            )
+    }
+}
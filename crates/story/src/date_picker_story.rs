@@ -19,6 +19,7 @@ pub struct DatePickerStory {
     date_picker_value: Option<String>,
     date_range_picker: Entity<DatePickerState>,
     default_range_mode_picker: Entity<DatePickerState>,
+    editable_range_picker: Entity<DatePickerState>,
     without_appearance_picker: Entity<DatePickerState>,
     _subscriptions: Vec<Subscription>,
 }
@@ -88,6 +89,9 @@ impl DatePickerStory {
 
         let default_range_mode_picker = cx.new(|cx| DatePickerState::range(window, cx));
 
+        let editable_range_picker =
+            cx.new(|cx| DatePickerState::range(window, cx).date_format("%Y-%m-%d"));
+
         let without_appearance_picker = cx.new(|cx| DatePickerState::new(window, cx));
 
         let _subscriptions = vec![
@@ -106,6 +110,11 @@ impl DatePickerStory {
                     this.date_picker_value = date.format("%Y-%m-%d").map(|s| s.to_string());
                 }
             }),
+            cx.subscribe(&editable_range_picker, |this, _, ev, _| match ev {
+                DatePickerEvent::Change(date) => {
+                    this.date_picker_value = date.format("%Y-%m-%d").map(|s| s.to_string());
+                }
+            }),
         ];
 
         Self {
@@ -115,6 +124,7 @@ impl DatePickerStory {
             data_picker_custom,
             date_range_picker,
             default_range_mode_picker,
+            editable_range_picker,
             without_appearance_picker,
             date_picker_value: None,
             _subscriptions,
@@ -207,6 +217,15 @@ impl Render for DatePickerStory {
                         .presets(range_presets.clone()),
                 ),
             )
+            .child(
+                section("Default Presets and Editable").max_w_128().child(
+                    DatePicker::new(&self.editable_range_picker)
+                        .placeholder("Type or select a range")
+                        .cleanable()
+                        .editable(true)
+                        .presets(DateRangePreset::defaults()),
+                ),
+            )
             .child(
                 section("Date Picker Value").max_w_128().child(
                     format!("Date picker value: {:?}", self.date_picker_value).into_element(),
@@ -0,0 +1,124 @@
+use gpui::{
+    px, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement, ParentElement,
+    Render, Styled, Window,
+};
+use gpui_component::{
+    diff::{DiffMode, DiffView},
+    radio::RadioGroup,
+    v_flex,
+};
+
+use crate::section;
+
+const OLD_CODE: &str = r#"use std::collections::HashMap;
+
+fn main() {
+    let mut scores = HashMap::new();
+    scores.insert("Alice", 10);
+    scores.insert("Bob", 20);
+
+    for (name, score) in &scores {
+        println!("{}: {}", name, score);
+    }
+
+    let total: i32 = scores.values().sum();
+    println!("Total: {}", total);
+
+    let best = scores.iter().max_by_key(|(_, score)| **score);
+    if let Some((name, _)) = best {
+        println!("Best: {}", name);
+    }
+
+    println!("Done");
+}
+"#;
+
+const NEW_CODE: &str = r#"use std::collections::BTreeMap;
+
+fn main() {
+    let mut scores = BTreeMap::new();
+    scores.insert("Alice", 10);
+    scores.insert("Bob", 20);
+    scores.insert("Carol", 30);
+
+    for (name, score) in &scores {
+        println!("{}: {}", name, score);
+    }
+
+    let total: i32 = scores.values().sum();
+    println!("Total: {}", total);
+
+    let best = scores.iter().max_by_key(|(_, score)| **score);
+    if let Some((name, score)) = best {
+        println!("Best: {} ({})", name, score);
+    }
+}
+"#;
+
+pub struct DiffStory {
+    focus_handle: FocusHandle,
+    diff: Entity<DiffView>,
+    mode: DiffMode,
+}
+
+impl super::Story for DiffStory {
+    fn title() -> &'static str {
+        "DiffView"
+    }
+
+    fn description() -> &'static str {
+        "Show the changes of two texts side by side or inline, with the syntax highlighting."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl DiffStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let diff = cx.new(|cx| DiffView::new(OLD_CODE, NEW_CODE, "rust", window, cx));
+
+        Self {
+            focus_handle: cx.focus_handle(),
+            diff,
+            mode: DiffMode::default(),
+        }
+    }
+}
+
+impl Focusable for DiffStory {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for DiffStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        v_flex()
+            .gap_y_3()
+            .child(
+                RadioGroup::horizontal("mode")
+                    .children(["Side by Side", "Inline"])
+                    .selected_index(Some(match self.mode {
+                        DiffMode::SideBySide => 0,
+                        DiffMode::Inline => 1,
+                    }))
+                    .on_change(cx.listener(|this, ix: &usize, _, cx| {
+                        this.mode = if *ix == 0 {
+                            DiffMode::SideBySide
+                        } else {
+                            DiffMode::Inline
+                        };
+                        this.diff
+                            .update(cx, |diff, cx| diff.set_mode(this.mode, cx));
+                        cx.notify();
+                    })),
+            )
+            .child(section("Diff").child(v_flex().w_full().h(px(480.)).child(self.diff.clone())))
+    }
+}
@@ -0,0 +1,117 @@
+use gpui::{
+    div, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement, ParentElement,
+    Render, SharedString, Styled, Subscription, Window,
+};
+
+use gpui_component::{
+    emoji_picker::{EmojiPicker, EmojiPickerButton, EmojiPickerEvent, EMOJI_FONT_FAMILY},
+    h_flex,
+    input::{InputState, TextInput},
+    v_flex, ActiveTheme as _,
+};
+
+use crate::section;
+
+pub struct EmojiPickerStory {
+    focus_handle: FocusHandle,
+    message_input: Entity<InputState>,
+    picker: Entity<EmojiPicker>,
+    picked: Option<SharedString>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl super::Story for EmojiPickerStory {
+    fn title() -> &'static str {
+        "EmojiPicker"
+    }
+
+    fn description() -> &'static str {
+        "A searchable emoji picker with the categories, skin tones and recently used emoji."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl EmojiPickerStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let message_input =
+            cx.new(|cx| InputState::new(window, cx).placeholder("Type a message..."));
+        let picker = cx.new(|cx| EmojiPicker::new(window, cx));
+
+        let _subscriptions = vec![cx.subscribe_in(
+            &picker,
+            window,
+            |this, _, event: &EmojiPickerEvent, _, cx| match event {
+                EmojiPickerEvent::Pick(emoji) => {
+                    this.picked = Some(emoji.clone());
+                    cx.notify();
+                }
+            },
+        )];
+
+        Self {
+            focus_handle: cx.focus_handle(),
+            message_input,
+            picker,
+            picked: None,
+            _subscriptions,
+        }
+    }
+}
+
+impl Focusable for EmojiPickerStory {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for EmojiPickerStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        v_flex()
+            .gap_6()
+            .child(
+                section("Input with Emoji Picker").child(
+                    h_flex()
+                        .w_96()
+                        .gap_1()
+                        .child(TextInput::new(&self.message_input))
+                        .child(EmojiPickerButton::new("message-emoji").input(&self.message_input)),
+                ),
+            )
+            .child(
+                section("Picker").child(
+                    h_flex()
+                        .items_start()
+                        .gap_6()
+                        .child(
+                            div()
+                                .p_2()
+                                .border_1()
+                                .border_color(cx.theme().border)
+                                .rounded(cx.theme().radius)
+                                .child(self.picker.clone()),
+                        )
+                        .child(
+                            v_flex()
+                                .gap_2()
+                                .text_sm()
+                                .text_color(cx.theme().muted_foreground)
+                                .child("Picked")
+                                .child(
+                                    div()
+                                        .font_family(EMOJI_FONT_FAMILY)
+                                        .text_3xl()
+                                        .text_color(cx.theme().foreground)
+                                        .child(self.picked.clone().unwrap_or("-".into())),
+                                ),
+                        ),
+                ),
+            )
+    }
+}
@@ -0,0 +1,219 @@
+use std::time::Duration;
+
+use gpui::{
+    div, hsla, px, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement,
+    ParentElement, Pixels, Render, SharedString, Styled, Subscription, Timer, Window,
+};
+use gpui_component::{
+    button::{Button, ButtonGroup},
+    card::Card,
+    grid::{Grid, GridDelegate, GridEvent},
+    h_flex, v_flex, ActiveTheme as _, Selectable as _, Sizable as _,
+};
+
+const MAX_ITEMS: usize = 2000;
+
+struct CardDelegate {
+    count: usize,
+    loading: bool,
+}
+
+impl CardDelegate {
+    /// The aspect ratio (height / width) of the card, to vary the heights for the masonry.
+    fn ratio(ix: usize) -> f32 {
+        [1.0, 1.4, 0.7, 1.2, 0.9, 1.6][ix % 6]
+    }
+}
+
+impl GridDelegate for CardDelegate {
+    fn items_count(&self, _: &App) -> usize {
+        self.count
+    }
+
+    fn item_height(&self, ix: usize, width: Pixels, _: &App) -> Pixels {
+        width * Self::ratio(ix)
+    }
+
+    fn render_item(
+        &self,
+        ix: usize,
+        _: &mut Window,
+        cx: &mut Context<Grid<Self>>,
+    ) -> Option<impl IntoElement> {
+        let hue = (ix * 37 % 360) as f32 / 360.;
+
+        Some(
+            Card::new(ix)
+                .size_full()
+                .padding(px(8.))
+                .title(div().text_sm().child(format!("Card {}", ix)))
+                .child(
+                    div()
+                        .flex_1()
+                        .rounded(cx.theme().radius)
+                        .bg(hsla(hue, 0.6, 0.7, 1.)),
+                ),
+        )
+    }
+
+    fn is_eof(&self, _: &App) -> bool {
+        !self.loading && self.count < MAX_ITEMS
+    }
+
+    fn load_more(&mut self, window: &mut Window, cx: &mut Context<Grid<Self>>) {
+        self.loading = true;
+        cx.spawn_in(window, async move |view, window| {
+            // Simulate network request, delay 0.5s to load data.
+            Timer::after(Duration::from_millis(500)).await;
+
+            _ = view.update_in(window, |view, _, cx| {
+                let delegate = view.delegate_mut();
+                delegate.count = (delegate.count + 100).min(MAX_ITEMS);
+                delegate.loading = false;
+                cx.notify();
+            });
+        })
+        .detach();
+    }
+}
+
+#[derive(Clone, Copy, PartialEq)]
+enum GridMode {
+    Fixed,
+    Responsive,
+    Masonry,
+}
+
+pub struct GridStory {
+    focus_handle: FocusHandle,
+    grid: Entity<Grid<CardDelegate>>,
+    mode: GridMode,
+    last_event: Option<SharedString>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl super::Story for GridStory {
+    fn title() -> &'static str {
+        "Grid"
+    }
+
+    fn description() -> &'static str {
+        "A virtualized grid to arrange the items into columns, \
+        or a masonry layout for the variable height items."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl GridStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let delegate = CardDelegate {
+            count: 200,
+            loading: false,
+        };
+        let grid = cx.new(|cx| Grid::new(delegate, window, cx).columns(4));
+
+        let _subscriptions = vec![cx.subscribe(&grid, |this, _, event: &GridEvent, cx| {
+            this.last_event = match event {
+                GridEvent::Select(ix) => Some(format!("Selected: Card {}", ix).into()),
+                GridEvent::Confirm(ix) => Some(format!("Clicked: Card {}", ix).into()),
+                GridEvent::Cancel => None,
+            };
+            cx.notify();
+        })];
+
+        Self {
+            focus_handle: cx.focus_handle(),
+            grid,
+            mode: GridMode::Fixed,
+            last_event: None,
+            _subscriptions,
+        }
+    }
+
+    fn set_mode(&mut self, mode: GridMode, cx: &mut Context<Self>) {
+        self.mode = mode;
+        self.grid.update(cx, |grid, cx| {
+            match mode {
+                GridMode::Fixed => grid.set_columns(4, cx),
+                GridMode::Responsive | GridMode::Masonry => grid.set_min_column_width(px(160.), cx),
+            }
+            grid.set_masonry(mode == GridMode::Masonry, cx);
+        });
+        cx.notify();
+    }
+}
+
+impl Focusable for GridStory {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for GridStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let count = self.grid.read(cx).delegate().count;
+
+        v_flex()
+            .size_full()
+            .gap_3()
+            .child(
+                h_flex()
+                    .gap_3()
+                    .child(
+                        ButtonGroup::new("mode")
+                            .small()
+                            .child(
+                                Button::new("fixed")
+                                    .label("4 Columns")
+                                    .selected(self.mode == GridMode::Fixed),
+                            )
+                            .child(
+                                Button::new("responsive")
+                                    .label("Responsive")
+                                    .selected(self.mode == GridMode::Responsive),
+                            )
+                            .child(
+                                Button::new("masonry")
+                                    .label("Masonry")
+                                    .selected(self.mode == GridMode::Masonry),
+                            )
+                            .on_click(cx.listener(|this, selected: &Vec<usize>, _, cx| {
+                                let mode = match selected.first() {
+                                    Some(1) => GridMode::Responsive,
+                                    Some(2) => GridMode::Masonry,
+                                    _ => GridMode::Fixed,
+                                };
+                                this.set_mode(mode, cx);
+                            })),
+                    )
+                    .child(
+                        div()
+                            .text_sm()
+                            .text_color(cx.theme().muted_foreground)
+                            .child(format!("{} items", count)),
+                    )
+                    .children(
+                        self.last_event
+                            .clone()
+                            .map(|event| div().text_sm().child(event)),
+                    ),
+            )
+            .child(
+                div()
+                    .flex_1()
+                    .w_full()
+                    .border_1()
+                    .border_color(cx.theme().border)
+                    .rounded(cx.theme().radius)
+                    .p_2()
+                    .child(self.grid.clone()),
+            )
+    }
+}
@@ -1,6 +1,7 @@
 use gpui::{
-    div, App, AppContext as _, Context, Entity, FocusHandle, Focusable, InteractiveElement,
-    IntoElement, KeyBinding, ParentElement as _, Render, Styled, Subscription, Window,
+    div, prelude::FluentBuilder as _, App, AppContext as _, Context, Entity, FocusHandle,
+    Focusable, InteractiveElement, IntoElement, KeyBinding, ParentElement as _, Render,
+    SharedString, Styled, Subscription, Window,
 };
 
 use crate::{section, Tab, TabPrev};
@@ -8,6 +9,31 @@ use gpui_component::{button::*, input::*, *};
 
 const CONTEXT: &str = "InputStory";
 
+const LANGUAGES: &[&str] = &[
+    "C",
+    "C++",
+    "C#",
+    "Clojure",
+    "Dart",
+    "Elixir",
+    "Erlang",
+    "Go",
+    "Haskell",
+    "Java",
+    "JavaScript",
+    "Kotlin",
+    "Lua",
+    "OCaml",
+    "PHP",
+    "Python",
+    "Ruby",
+    "Rust",
+    "Scala",
+    "Swift",
+    "TypeScript",
+    "Zig",
+];
+
 pub fn init(cx: &mut App) {
     cx.bind_keys([
         KeyBinding::new("shift-tab", TabPrev, Some(CONTEXT)),
@@ -30,6 +56,18 @@ pub struct InputStory {
     mask_input2: Entity<InputState>,
     currency_input: Entity<InputState>,
     custom_input: Entity<InputState>,
+    max_length_input: Entity<InputState>,
+    rich_input: Entity<InputState>,
+    language_combobox: Entity<ComboboxState>,
+    user_combobox: Entity<ComboboxState>,
+    users: Vec<SharedString>,
+    selected_user: Option<SharedString>,
+    tag_input: Entity<TagInputState>,
+    email_input: Entity<TagInputState>,
+    emails: Vec<String>,
+    attachment_input: Entity<InputState>,
+    attachments: Vec<SharedString>,
+    attachment_error: Option<SharedString>,
 
     _subscriptions: Vec<Subscription>,
 }
@@ -93,11 +131,73 @@ impl InputStory {
         });
         let custom_input =
             cx.new(|cx| InputState::new(window, cx).placeholder("here is a custom input"));
+        let max_length_input = cx.new(|cx| {
+            InputState::new(window, cx)
+                .max_length(20)
+                .placeholder("Up to 20 characters, emoji 👍🏽 counts as one.")
+        });
+        let rich_input = cx.new(|cx| {
+            let mut state = InputState::new(window, cx)
+                .multi_line()
+                .rows(3)
+                .rich_text(true);
+            state.set_markdown_value(
+                "Select text and press **cmd-b**, *cmd-i* or <u>cmd-u</u>, see [GPUI](https://gpui.rs).",
+                window,
+                cx,
+            );
+            state
+        });
+
+        let language_combobox = cx.new(|cx| {
+            ComboboxState::new(window, cx).placeholder(
+                "Type a language, or any text...",
+                window,
+                cx,
+            )
+        });
+        let user_combobox = cx.new(|cx| {
+            ComboboxState::new(window, cx).strict(true).placeholder(
+                "Pick one of 10000 users",
+                window,
+                cx,
+            )
+        });
+        let tag_input = cx.new(|cx| {
+            TagInputState::new(window, cx)
+                .default_tags(["gpui", "rust"])
+                .dedup(true)
+                .placeholder("Add tags...", window, cx)
+        });
+        let email_input = cx.new(|cx| {
+            TagInputState::new(window, cx)
+                .dedup(true)
+                .max_tags(5)
+                .validate(|s| s.contains('@'))
+                .placeholder(
+                    "Up to 5 recipients, paste a comma separated list",
+                    window,
+                    cx,
+                )
+        });
+        let attachment_input = cx.new(|cx| {
+            InputState::new(window, cx)
+                .placeholder("Drop the images or PDFs here, or paste an image...")
+                .accept_files(true)
+                .file_extensions(["png", "jpg", "jpeg", "gif", "pdf"])
+        });
+        let users = (0..10000)
+            .map(|ix| SharedString::from(format!("user-{:04}", ix)))
+            .collect();
 
         let _subscriptions = vec![
             cx.subscribe_in(&input1, window, Self::on_input_event),
             cx.subscribe_in(&input2, window, Self::on_input_event),
             cx.subscribe_in(&phone_input, window, Self::on_input_event),
+            cx.subscribe_in(&language_combobox, window, Self::on_language_event),
+            cx.subscribe_in(&user_combobox, window, Self::on_user_event),
+            cx.subscribe_in(&email_input, window, Self::on_email_event),
+            cx.subscribe_in(&attachment_input, window, Self::on_attachment_event),
         ];
 
         Self {
@@ -120,6 +220,18 @@ impl InputStory {
             mask_input2,
             currency_input,
             custom_input,
+            max_length_input,
+            rich_input,
+            language_combobox,
+            user_combobox,
+            users,
+            selected_user: None,
+            tag_input,
+            email_input,
+            emails: Vec::new(),
+            attachment_input,
+            attachments: Vec::new(),
+            attachment_error: None,
             _subscriptions,
         }
     }
@@ -144,8 +256,116 @@ impl InputStory {
             InputEvent::PressEnter { secondary } => println!("PressEnter secondary: {}", secondary),
             InputEvent::Focus => println!("Focus"),
             InputEvent::Blur => println!("Blur"),
+            _ => {}
         };
     }
+
+    fn on_attachment_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::FilesDropped(paths) => {
+                self.attachment_error = None;
+                self.attachments.extend(
+                    paths
+                        .iter()
+                        .map(|path| SharedString::from(path.display().to_string())),
+                );
+            }
+            InputEvent::FilesRejected(paths) => {
+                let extensions = self
+                    .attachment_input
+                    .read(cx)
+                    .accepted_file_extensions()
+                    .iter()
+                    .map(|ext| ext.as_ref())
+                    .collect::<Vec<&str>>();
+                self.attachment_error = Some(
+                    format!(
+                        "{} file(s) rejected, only {} are accepted.",
+                        paths.len(),
+                        extensions.join(", ")
+                    )
+                    .into(),
+                );
+            }
+            InputEvent::ImagePasted(bytes, mime) => {
+                self.attachment_error = None;
+                self.attachments
+                    .push(format!("Pasted {} ({} bytes)", mime, bytes.len()).into());
+            }
+            _ => return,
+        }
+        cx.notify();
+    }
+
+    fn on_language_event(
+        &mut self,
+        state: &Entity<ComboboxState>,
+        event: &ComboboxEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            ComboboxEvent::QueryChange(query) => {
+                let query = query.to_lowercase();
+                let suggestions = LANGUAGES
+                    .iter()
+                    .filter(|lang| !query.is_empty() && lang.to_lowercase().starts_with(&query))
+                    .map(|lang| SharedString::from(*lang))
+                    .collect::<Vec<_>>();
+                state.update(cx, |state, cx| {
+                    state.set_suggestions(suggestions, window, cx)
+                });
+            }
+            ComboboxEvent::Select(value) => println!("Select language: {}", value),
+        }
+    }
+
+    fn on_email_event(
+        &mut self,
+        _: &Entity<TagInputState>,
+        event: &TagInputEvent,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            TagInputEvent::Change(emails) => {
+                self.emails = emails.clone();
+                cx.notify();
+            }
+        }
+    }
+
+    fn on_user_event(
+        &mut self,
+        state: &Entity<ComboboxState>,
+        event: &ComboboxEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            ComboboxEvent::QueryChange(query) => {
+                let suggestions = self
+                    .users
+                    .iter()
+                    .filter(|user| user.contains(query.as_str()))
+                    .cloned()
+                    .collect::<Vec<_>>();
+                state.update(cx, |state, cx| {
+                    state.set_suggestions(suggestions, window, cx)
+                });
+            }
+            ComboboxEvent::Select(value) => {
+                self.selected_user = Some(value.clone());
+                cx.notify();
+            }
+        }
+    }
 }
 
 impl FocusableCycle for InputStory {
@@ -164,6 +384,10 @@ impl FocusableCycle for InputStory {
             self.large_input.focus_handle(cx),
             self.small_input.focus_handle(cx),
             self.input_esc.focus_handle(cx),
+            self.language_combobox.focus_handle(cx),
+            self.user_combobox.focus_handle(cx),
+            self.tag_input.focus_handle(cx),
+            self.email_input.focus_handle(cx),
         ]
         .to_vec()
     }
@@ -219,7 +443,12 @@ impl Render for InputStory {
             .child(
                 section("Currency Input with thousands separator")
                     .max_w_md()
-                    .child(TextInput::new(&self.currency_input))
+                    .child(
+                        TextInput::new(&self.currency_input)
+                            .cleanable()
+                            .prefix(div().text_color(cx.theme().muted_foreground).child("$"))
+                            .suffix(div().text_color(cx.theme().muted_foreground).child("USD")),
+                    )
                     .child(
                         div().child(format!("Value: {:?}", self.currency_input.read(cx).value())),
                     ),
@@ -256,11 +485,68 @@ impl Render for InputStory {
                     .child(TextInput::new(&self.large_input).large().cleanable())
                     .child(TextInput::new(&self.small_input).small().cleanable()),
             )
+            .child(
+                section("Combobox")
+                    .max_w_md()
+                    .child(Combobox::new(&self.language_combobox).cleanable())
+                    .child(Combobox::new(&self.user_combobox))
+                    .child(div().child(format!("Selected user: {:?}", self.selected_user))),
+            )
+            .child(
+                section("Tag Input")
+                    .max_w_md()
+                    .child(TagInput::new(&self.tag_input))
+                    .child(TagInput::new(&self.email_input))
+                    .child(div().child(format!("Recipients: {:?}", self.emails))),
+            )
+            .child(
+                section("Drop Files and Paste Images")
+                    .max_w_md()
+                    .child(TextInput::new(&self.attachment_input))
+                    .children(
+                        self.attachments
+                            .iter()
+                            .map(|attachment| div().text_sm().child(attachment.clone())),
+                    )
+                    .when_some(self.attachment_error.clone(), |this, error| {
+                        this.child(div().text_sm().text_color(cx.theme().danger).child(error))
+                    }),
+            )
             .child(
                 section("Cleanable and ESC to clean")
                     .max_w_md()
                     .child(TextInput::new(&self.input_esc).cleanable()),
             )
+            .child(
+                section("Max Length and Count")
+                    .max_w_md()
+                    .child(TextInput::new(&self.max_length_input).show_count()),
+            )
+            .child(
+                section("Rich Text").max_w_md().child(
+                    v_flex()
+                        .w_full()
+                        .gap_2()
+                        .child(RichTextToolbar::new(&self.rich_input).on_link(
+                            |state, window, cx| {
+                                state.update(cx, |state, cx| {
+                                    state.set_link(
+                                        Some("https://github.com/longbridge/gpui-component".into()),
+                                        window,
+                                        cx,
+                                    );
+                                })
+                            },
+                        ))
+                        .child(TextInput::new(&self.rich_input))
+                        .child(
+                            div()
+                                .text_sm()
+                                .whitespace_normal()
+                                .child(self.rich_input.read(cx).markdown_value()),
+                        ),
+                ),
+            )
             .child(
                 section("Focused Input")
                     .max_w_md()
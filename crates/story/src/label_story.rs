@@ -181,5 +181,22 @@ impl Render for LabelStory {
                         ),
                 ),
             )
+            .child(
+                section("Selectable Label").max_w_md().child(
+                    v_flex()
+                        .w_full()
+                        .gap_2()
+                        .child(
+                            Label::new("Drag to select, double click to select a word.")
+                                .selectable(true)
+                                .highlights(&self.highlights_text),
+                        )
+                        .child(
+                            Label::new("Order ID")
+                                .secondary("ORD-2024-09-18-0042")
+                                .selectable(true),
+                        ),
+                ),
+            )
     }
 }
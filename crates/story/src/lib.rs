@@ -3,18 +3,25 @@ mod alert_story;
 mod assets;
 mod avatar_story;
 mod badge_story;
+mod breadcrumb_story;
 mod button_story;
 mod calendar_story;
+mod card_story;
 mod chart_story;
 mod checkbox_story;
 mod clipboard_story;
+mod code_block_story;
 mod color_picker_story;
+mod command_palette_story;
 mod date_picker_story;
 mod description_list_story;
+mod diff_story;
 mod drawer_story;
 mod dropdown_story;
+mod emoji_picker_story;
 mod form_story;
 mod go_board_story;
+mod grid_story;
 mod group_box_story;
 mod icon_story;
 mod image_story;
@@ -28,6 +35,7 @@ mod modal_story;
 mod notification_story;
 mod number_input_story;
 mod otp_input_story;
+mod pagination_story;
 mod popover_story;
 mod progress_story;
 mod radio_story;
@@ -36,6 +44,7 @@ mod scrollable_story;
 mod sidebar_story;
 mod skeleton_story;
 mod slider_story;
+mod spinner_story;
 mod switch_story;
 mod table_story;
 mod tabs_story;
@@ -62,18 +71,25 @@ pub use accordion_story::AccordionStory;
 pub use alert_story::AlertStory;
 pub use avatar_story::AvatarStory;
 pub use badge_story::BadgeStory;
+pub use breadcrumb_story::BreadcrumbStory;
 pub use button_story::ButtonStory;
 pub use calendar_story::CalendarStory;
+pub use card_story::CardStory;
 pub use chart_story::ChartStory;
 pub use checkbox_story::CheckboxStory;
 pub use clipboard_story::ClipboardStory;
+pub use code_block_story::CodeBlockStory;
 pub use color_picker_story::ColorPickerStory;
+pub use command_palette_story::CommandPaletteStory;
 pub use date_picker_story::DatePickerStory;
 pub use description_list_story::DescriptionListStory;
+pub use diff_story::DiffStory;
 pub use drawer_story::DrawerStory;
 pub use dropdown_story::DropdownStory;
+pub use emoji_picker_story::EmojiPickerStory;
 pub use form_story::FormStory;
 pub use go_board_story::GoBoardStory;
+pub use grid_story::GridStory;
 pub use group_box_story::GroupBoxStory;
 pub use icon_story::IconStory;
 pub use image_story::ImageStory;
@@ -87,6 +103,7 @@ pub use modal_story::ModalStory;
 pub use notification_story::NotificationStory;
 pub use number_input_story::NumberInputStory;
 pub use otp_input_story::OtpInputStory;
+pub use pagination_story::PaginationStory;
 pub use popover_story::PopoverStory;
 pub use progress_story::ProgressStory;
 pub use radio_story::RadioStory;
@@ -96,6 +113,7 @@ use serde::{Deserialize, Serialize};
 pub use sidebar_story::SidebarStory;
 pub use skeleton_story::SkeletonStory;
 pub use slider_story::SliderStory;
+pub use spinner_story::SpinnerStory;
 pub use switch_story::SwitchStory;
 pub use table_story::TableStory;
 pub use tabs_story::TabsStory;
@@ -137,6 +155,10 @@ pub struct SelectFont(usize);
 #[action(namespace = story, no_json)]
 pub struct SelectRadius(usize);
 
+#[derive(Action, Clone, PartialEq, Eq, Deserialize)]
+#[action(namespace = story, no_json)]
+pub struct SelectAnimations(bool);
+
 actions!(story, [Quit, Open, CloseWindow, ToggleSearch]);
 
 const PANEL_NAME: &str = "StoryContainer";
@@ -637,6 +659,7 @@ impl StoryState {
             "InputStory" => story!(InputStory),
             "ListStory" => story!(ListStory),
             "ModalStory" => story!(ModalStory),
+            "PaginationStory" => story!(PaginationStory),
             "PopoverStory" => story!(PopoverStory),
             "ProgressStory" => story!(ProgressStory),
             "ResizableStory" => story!(ResizableStory),
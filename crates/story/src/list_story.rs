@@ -194,6 +194,20 @@ impl CompanyListDelegate {
         self.prepare(self.query.clone());
     }
 
+    fn move_company(&mut self, from: IndexPath, to: IndexPath) {
+        let Some(companies) = self.matched_companies.get_mut(from.section) else {
+            return;
+        };
+        if from.row >= companies.len() {
+            return;
+        }
+
+        let company = companies.remove(from.row);
+        if let Some(companies) = self.matched_companies.get_mut(to.section) {
+            companies.insert(to.row.min(companies.len()), company);
+        }
+    }
+
     fn selected_company(&self) -> Option<Rc<Company>> {
         let Some(ix) = self.selected_index else {
             return None;
@@ -336,6 +350,7 @@ pub struct ListStory {
     focus_handle: FocusHandle,
     company_list: Entity<List<CompanyListDelegate>>,
     selected_company: Option<Rc<Company>>,
+    vim_mode: bool,
     _subscriptions: Vec<Subscription>,
 }
 
@@ -371,12 +386,17 @@ impl ListStory {
         };
         delegate.extend_more(100);
 
-        let company_list =
-            cx.new(|cx| List::new(delegate, window, cx).paddings(Edges::all(px(8.))));
+        let company_list = cx.new(|cx| {
+            List::new(delegate, window, cx)
+                .paddings(Edges::all(px(8.)))
+                .sticky_section_headers(true)
+                .collapsible_sections(true)
+                .reorderable(true)
+        });
 
         let _subscriptions =
             vec![
-                cx.subscribe(&company_list, |_, _, ev: &ListEvent, _| match ev {
+                cx.subscribe(&company_list, |_, list, ev: &ListEvent, cx| match ev {
                     ListEvent::Select(ix) => {
                         println!("List Selected: {:?}", ix);
                     }
@@ -386,6 +406,13 @@ impl ListStory {
                     ListEvent::Cancel => {
                         println!("List Cancelled");
                     }
+                    ListEvent::Reordered { from, to } => {
+                        println!("List Reordered: {:?} -> {:?}", from, to);
+                        list.update(cx, |list, cx| {
+                            list.delegate_mut().move_company(*from, *to);
+                            cx.notify();
+                        });
+                    }
                 }),
             ];
 
@@ -415,6 +442,7 @@ impl ListStory {
             focus_handle: cx.focus_handle(),
             company_list,
             selected_company: None,
+            vim_mode: false,
             _subscriptions,
         }
     }
@@ -537,6 +565,18 @@ impl Render for ListStory {
                                     cx.notify();
                                 })
                             })),
+                    )
+                    .child(
+                        Checkbox::new("vim-mode")
+                            .label("Vim Mode")
+                            .checked(self.vim_mode)
+                            .on_click(cx.listener(|this, check: &bool, window, cx| {
+                                this.vim_mode = *check;
+                                this.company_list.update(cx, |list, cx| {
+                                    list.set_vim_mode(*check, window, cx);
+                                    list.focus(window, cx);
+                                })
+                            })),
                     ),
             )
             .child(
@@ -41,19 +41,26 @@ impl Gallery {
                     StoryContainer::panel::<AlertStory>(window, cx),
                     StoryContainer::panel::<AvatarStory>(window, cx),
                     StoryContainer::panel::<BadgeStory>(window, cx),
+                    StoryContainer::panel::<BreadcrumbStory>(window, cx),
                     StoryContainer::panel::<ButtonStory>(window, cx),
                     StoryContainer::panel::<CalendarStory>(window, cx),
+                    StoryContainer::panel::<CardStory>(window, cx),
                     StoryContainer::panel::<ChartStory>(window, cx),
                     StoryContainer::panel::<CheckboxStory>(window, cx),
                     StoryContainer::panel::<ClipboardStory>(window, cx),
+                    StoryContainer::panel::<CodeBlockStory>(window, cx),
                     StoryContainer::panel::<ColorPickerStory>(window, cx),
+                    StoryContainer::panel::<CommandPaletteStory>(window, cx),
                     StoryContainer::panel::<DatePickerStory>(window, cx),
                     StoryContainer::panel::<DescriptionListStory>(window, cx),
+                    StoryContainer::panel::<DiffStory>(window, cx),
                     StoryContainer::panel::<DrawerStory>(window, cx),
                     StoryContainer::panel::<DropdownStory>(window, cx),
+                    StoryContainer::panel::<EmojiPickerStory>(window, cx),
                     StoryContainer::panel::<FormStory>(window, cx),
                     StoryContainer::panel::<GroupBoxStory>(window, cx),
                     StoryContainer::panel::<GoBoardStory>(window, cx),
+                    StoryContainer::panel::<GridStory>(window, cx),
                     StoryContainer::panel::<IconStory>(window, cx),
                     StoryContainer::panel::<ImageStory>(window, cx),
                     StoryContainer::panel::<IndicatorStory>(window, cx),
@@ -66,6 +73,7 @@ impl Gallery {
                     StoryContainer::panel::<NotificationStory>(window, cx),
                     StoryContainer::panel::<NumberInputStory>(window, cx),
                     StoryContainer::panel::<OtpInputStory>(window, cx),
+                    StoryContainer::panel::<PaginationStory>(window, cx),
                     StoryContainer::panel::<PopoverStory>(window, cx),
                     StoryContainer::panel::<ProgressStory>(window, cx),
                     StoryContainer::panel::<RadioStory>(window, cx),
@@ -74,6 +82,7 @@ impl Gallery {
                     StoryContainer::panel::<SidebarStory>(window, cx),
                     StoryContainer::panel::<SkeletonStory>(window, cx),
                     StoryContainer::panel::<SliderStory>(window, cx),
+                    StoryContainer::panel::<SpinnerStory>(window, cx),
                     StoryContainer::panel::<SwitchStory>(window, cx),
                     StoryContainer::panel::<TableStory>(window, cx),
                     StoryContainer::panel::<TabsStory>(window, cx),
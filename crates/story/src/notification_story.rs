@@ -1,11 +1,13 @@
+use std::time::Duration;
+
 use gpui::{
     div, App, AppContext, Context, Entity, FocusHandle, Focusable, InteractiveElement as _,
-    IntoElement, ParentElement, Render, Styled, Window,
+    IntoElement, ParentElement, Render, Styled, Timer, Window,
 };
 
 use gpui_component::{
     button::{Button, ButtonVariants},
-    notification::{Notification, NotificationType},
+    notification::{Notification, NotificationExt as _, NotificationType},
     text::TextView,
     ContextModal as _,
 };
@@ -213,5 +215,38 @@ impl Render for NotificationStory {
                             })),
                     )
             })
+            .child({
+                struct RepeatedNotification;
+
+                section("Collapse Repeated").child(
+                    Button::new("repeated-notify")
+                        .outline()
+                        .label("Show Repeated Error")
+                        .on_click(cx.listener(|_, _, _, cx| {
+                            // Push from the App without a Window, collapsed with a count by the id.
+                            cx.push_notification(
+                                Notification::error("Failed to connect to the server.")
+                                    .id1::<RepeatedNotification>("connect"),
+                            );
+                        })),
+                )
+            })
+            .child(
+                section("Loading to Result").child(
+                    Button::new("loading-notify")
+                        .outline()
+                        .label("Save")
+                        .on_click(cx.listener(|_, _, _, cx| {
+                            let handle = cx.push_notification(Notification::loading("Saving..."));
+                            cx.spawn(async move |_, cx| {
+                                Timer::after(Duration::from_secs(2)).await;
+                                cx.update(|cx| {
+                                    handle.update(Notification::success("Saved."), cx);
+                                })
+                            })
+                            .detach();
+                        })),
+                ),
+            )
     }
 }
@@ -8,6 +8,7 @@ use crate::{section, Tab, TabPrev};
 use gpui_component::{
     button::{Button, ButtonVariants},
     input::{InputEvent, InputState, MaskPattern, NumberInput, NumberInputEvent, StepAction},
+    number_format::NumberFormat,
     v_flex, ActiveTheme, Disableable, FocusableCycle, IconName, Sizable,
 };
 
@@ -30,6 +31,8 @@ pub struct NumberInputStory {
     number_input4: Entity<InputState>,
     number_input4_value: f64,
     disabled_input: Entity<InputState>,
+    currency_input: Entity<InputState>,
+    currency_format: NumberFormat,
 
     _subscriptions: Vec<Subscription>,
 }
@@ -95,6 +98,13 @@ impl NumberInputStory {
                 .placeholder("Disabled input")
         });
 
+        let currency_format = NumberFormat::currency("$");
+        let currency_input = cx.new(|cx| {
+            let mut input = InputState::new(window, cx).placeholder("Currency Input");
+            input.set_number_value(1234.5, &currency_format, window, cx);
+            input
+        });
+
         let _subscriptions = vec![
             cx.subscribe_in(&number_input1, window, Self::on_input_event),
             cx.subscribe_in(&number_input1, window, Self::on_number_input_event),
@@ -118,6 +128,8 @@ impl NumberInputStory {
             number_input4,
             number_input4_value: 0.0,
             disabled_input,
+            currency_input,
+            currency_format,
             _subscriptions,
         }
     }
@@ -159,6 +171,7 @@ impl NumberInputStory {
             }
             InputEvent::Focus => println!("Focus"),
             InputEvent::Blur => println!("Blur"),
+            _ => {}
         }
     }
 
@@ -265,6 +278,9 @@ impl Render for NumberInputStory {
                     .max_w_md()
                     .child(NumberInput::new(&self.number_input3)),
             )
+            .child(section("Currency format").max_w_md().child(
+                NumberInput::new(&self.currency_input).number_format(self.currency_format.clone()),
+            ))
             .child(
                 section("Without appearance").max_w_md().child(
                     div()
@@ -0,0 +1,112 @@
+use gpui::{
+    App, AppContext, Context, Entity, Focusable, IntoElement, ParentElement, Render, SharedString,
+    Styled, Subscription, Window,
+};
+
+use gpui_component::{
+    pagination::{Pagination, PaginationEvent, PaginationState},
+    v_flex, Sizable as _,
+};
+
+use crate::section;
+
+pub struct PaginationStory {
+    focus_handle: gpui::FocusHandle,
+    pagination: Entity<PaginationState>,
+    few_pagination: Entity<PaginationState>,
+    last_event: Option<SharedString>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl super::Story for PaginationStory {
+    fn title() -> &'static str {
+        "Pagination"
+    }
+
+    fn description() -> &'static str {
+        "Navigate the pages of a large data set, e.g.: a server-paged table."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl PaginationStory {
+    pub(crate) fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let pagination = cx.new(|cx| PaginationState::new(cx).total(1234).page_size(20));
+        let few_pagination = cx.new(|cx| PaginationState::new(cx).total(42));
+
+        let _subscriptions = vec![cx.subscribe_in(&pagination, window, Self::on_pagination_event)];
+
+        Self {
+            focus_handle: cx.focus_handle(),
+            pagination,
+            few_pagination,
+            last_event: None,
+            _subscriptions,
+        }
+    }
+
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn on_pagination_event(
+        &mut self,
+        _: &Entity<PaginationState>,
+        event: &PaginationEvent,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.last_event = Some(match event {
+            PaginationEvent::PageChanged(page) => format!("PageChanged: {}", page).into(),
+            PaginationEvent::PageSizeChanged(size) => format!("PageSizeChanged: {}", size).into(),
+        });
+        cx.notify();
+    }
+}
+
+impl Focusable for PaginationStory {
+    fn focus_handle(&self, _: &gpui::App) -> gpui::FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for PaginationStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let items_range = self.pagination.read(cx).items_range();
+
+        v_flex()
+            .gap_6()
+            .child(
+                section("Pagination").child(
+                    v_flex()
+                        .gap_2()
+                        .child(
+                            Pagination::new("pagination", &self.pagination)
+                                .first_last(true)
+                                .page_sizes(vec![10, 20, 50, 100])
+                                .summary(true),
+                        )
+                        .child(format!(
+                            "Items: {}..{}, Last event: {}",
+                            items_range.start,
+                            items_range.end,
+                            self.last_event.clone().unwrap_or("-".into())
+                        )),
+                ),
+            )
+            .child(
+                section("Few Pages")
+                    .child(Pagination::new("few-pagination", &self.few_pagination).small()),
+            )
+            .child(
+                section("Compact").child(
+                    Pagination::new("compact-pagination", &self.pagination)
+                        .compact(true)
+                        .xsmall(),
+                ),
+            )
+    }
+}
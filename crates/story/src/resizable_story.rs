@@ -1,9 +1,12 @@
 use gpui::{
-    div, px, AnyElement, App, AppContext, Context, Entity, FocusHandle, Focusable, IntoElement,
-    ParentElement as _, Pixels, Render, SharedString, Styled, Window,
+    div, px, size, AnyElement, App, AppContext, Context, Entity, FocusHandle, Focusable,
+    IntoElement, ParentElement as _, Pixels, Render, SharedString, Styled, Subscription, Window,
 };
 use gpui_component::{
-    resizable::{h_resizable, resizable_panel, v_resizable, ResizableState},
+    resizable::{
+        h_resizable, resizable_box, resizable_panel, v_resizable, ResizableBoxEvent,
+        ResizableBoxState, ResizableState,
+    },
     v_flex, ActiveTheme,
 };
 
@@ -12,6 +15,8 @@ pub struct ResizableStory {
     state1: Entity<ResizableState>,
     state2: Entity<ResizableState>,
     state3: Entity<ResizableState>,
+    box_state: Entity<ResizableBoxState>,
+    _subscriptions: Vec<Subscription>,
 }
 
 impl super::Story for ResizableStory {
@@ -39,16 +44,25 @@ impl ResizableStory {
         cx.new(|cx| Self::new(window, cx))
     }
 
-    fn new(_: &mut Window, cx: &mut App) -> Self {
+    fn new(_: &mut Window, cx: &mut Context<Self>) -> Self {
         let state1 = ResizableState::new(cx);
         let state2 = ResizableState::new(cx);
         let state3 = ResizableState::new(cx);
+        let box_state = ResizableBoxState::new(cx);
+
+        let _subscriptions = vec![cx.subscribe(&box_state, |_, _, event, cx| {
+            let ResizableBoxEvent::Resized(size) = event;
+            println!("Resized box to: {:?}", size);
+            cx.notify();
+        })];
 
         Self {
             focus_handle: cx.focus_handle(),
             state1,
             state2,
             state3,
+            box_state,
+            _subscriptions,
         }
     }
 }
@@ -114,5 +128,24 @@ impl Render for ResizableStory {
                             .child(resizable_panel().child(panel_box("Right (Grow)", cx))),
                     ),
             )
+            .child(
+                resizable_box("resizable-box", self.box_state.clone())
+                    .initial_size(size(px(300.), px(150.)))
+                    .width_range(px(200.)..px(600.))
+                    .height_range(px(100.)..px(300.))
+                    .border_1()
+                    .border_color(cx.theme().border)
+                    .child(panel_box(
+                        match self.box_state.read(cx).size() {
+                            Some(size) => format!(
+                                "Drag the corner to resize: {:.0} x {:.0}",
+                                size.width.0, size.height.0
+                            ),
+                            None => "Drag the corner to resize (200px .. 600px x 100px .. 300px)"
+                                .to_string(),
+                        },
+                        cx,
+                    )),
+            )
     }
 }
@@ -13,10 +13,10 @@ use gpui_component::{
     popup_menu::PopupMenuExt,
     sidebar::{
         Sidebar, SidebarFooter, SidebarGroup, SidebarHeader, SidebarMenu, SidebarMenuItem,
-        SidebarToggleButton,
+        SidebarState, SidebarToggleButton,
     },
     switch::Switch,
-    v_flex, ActiveTheme, Icon, IconName, Side, Sizable,
+    v_flex, ActiveTheme, Breakpoint, Icon, IconName, Side, Sizable, WindowBreakpointExt as _,
 };
 use serde::Deserialize;
 
@@ -28,7 +28,7 @@ pub struct SidebarStory {
     active_items: HashMap<Item, bool>,
     last_active_item: Item,
     active_subitem: Option<SubItem>,
-    collapsed: bool,
+    state: SidebarState,
     side: Side,
     focus_handle: gpui::FocusHandle,
     checked: bool,
@@ -47,13 +47,27 @@ impl SidebarStory {
             active_items,
             last_active_item: Item::Playground,
             active_subitem: None,
-            collapsed: false,
+            state: SidebarState::default(),
             side: Side::Left,
             focus_handle: cx.focus_handle(),
             checked: false,
         }
     }
 
+    fn toggle_group(
+        &self,
+        group: &'static str,
+        cx: &mut Context<Self>,
+    ) -> impl Fn(&bool, &mut Window, &mut App) + 'static {
+        let view = cx.entity();
+        move |expanded, _, cx| {
+            view.update(cx, |this, cx| {
+                this.state.set_group_expanded(group, *expanded);
+                cx.notify();
+            })
+        }
+    }
+
     fn render_content(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
         v_flex().child(
             h_flex().gap_2().child(
@@ -228,19 +242,18 @@ impl Render for SidebarStory {
         window: &mut gpui::Window,
         cx: &mut gpui::Context<Self>,
     ) -> impl gpui::IntoElement {
-        let groups: [Vec<Item>; 2] = [
+        let groups: [Vec<Item>; 3] = [
             vec![
                 Item::Playground,
                 Item::Models,
                 Item::Documentation,
                 Item::Settings,
             ],
-            vec![
-                Item::DesignEngineering,
-                Item::SalesAndMarketing,
-                Item::Travel,
-            ],
+            vec![Item::DesignEngineering, Item::SalesAndMarketing],
+            vec![Item::Travel],
         ];
+        // The sidebar is collapsed into the rail mode in a narrow window.
+        let collapsed = self.state.collapsed || window.breakpoint(cx) < Breakpoint::Md;
 
         h_flex()
             .rounded(cx.theme().radius)
@@ -250,7 +263,8 @@ impl Render for SidebarStory {
             .when(self.side.is_right(), |this| this.flex_row_reverse())
             .child(
                 Sidebar::new(self.side)
-                    .collapsed(self.collapsed)
+                    .collapsed(self.state.collapsed)
+                    .collapse_below(Breakpoint::Md)
                     .header(
                         SidebarHeader::new()
                             .w_full()
@@ -264,17 +278,17 @@ impl Render for SidebarStory {
                                     .text_color(cx.theme().success_foreground)
                                     .size_8()
                                     .flex_shrink_0()
-                                    .when(!self.collapsed, |this| {
+                                    .when(!collapsed, |this| {
                                         this.child(Icon::new(IconName::GalleryVerticalEnd))
                                     })
-                                    .when(self.collapsed, |this| {
+                                    .when(collapsed, |this| {
                                         this.size_4()
                                             .bg(cx.theme().transparent)
                                             .text_color(cx.theme().foreground)
                                             .child(Icon::new(IconName::GalleryVerticalEnd))
                                     }),
                             )
-                            .when(!self.collapsed, |this| {
+                            .when(!collapsed, |this| {
                                 this.child(
                                     v_flex()
                                         .gap_0()
@@ -287,7 +301,7 @@ impl Render for SidebarStory {
                                         .child(div().child("Enterprise").text_xs()),
                                 )
                             })
-                            .when(!self.collapsed, |this| {
+                            .when(!collapsed, |this| {
                                 this.child(
                                     Icon::new(IconName::ChevronsUpDown).size_4().flex_shrink_0(),
                                 )
@@ -308,8 +322,10 @@ impl Render for SidebarStory {
                             }),
                     )
                     .child(
-                        SidebarGroup::new("Platform").child(SidebarMenu::new().children(
-                            groups[0].iter().map(|item| {
+                        SidebarGroup::new("Platform")
+                            .expanded(self.state.is_group_expanded("Platform"))
+                            .on_toggle(self.toggle_group("Platform", cx))
+                            .child(SidebarMenu::new().children(groups[0].iter().map(|item| {
                                 SidebarMenuItem::new(item.label())
                                     .icon(item.icon())
                                     .active(self.active_items.contains_key(item))
@@ -333,25 +349,43 @@ impl Render for SidebarStory {
                                         },
                                     ))
                                     .on_click(cx.listener(item.handler()))
-                            }),
-                        )),
+                            }))),
                     )
                     .child(
-                        SidebarGroup::new("Projects").child(SidebarMenu::new().children(
-                            groups[1].iter().enumerate().map(|(ix, item)| {
-                                SidebarMenuItem::new(item.label())
-                                    .icon(item.icon())
-                                    .active(self.last_active_item == *item)
-                                    .when(ix == 0, |this| {
-                                        this.suffix(
-                                            Badge::new().dot().count(1).child(
-                                                div().p_0p5().child(Icon::new(IconName::Bell)),
-                                            ),
-                                        )
-                                    })
-                                    .when(ix == 1, |this| this.suffix(IconName::Settings2))
-                            }),
-                        )),
+                        SidebarGroup::new("Projects")
+                            .expanded(self.state.is_group_expanded("Projects"))
+                            .on_toggle(self.toggle_group("Projects", cx))
+                            .child(
+                                SidebarMenu::new()
+                                    .active_route(self.last_active_item.label())
+                                    .children(groups[1].iter().enumerate().map(|(ix, item)| {
+                                        SidebarMenuItem::new(item.label())
+                                            .icon(item.icon())
+                                            .route(item.label())
+                                            .when(ix == 0, |this| {
+                                                this.suffix(Badge::new().dot().count(1).child(
+                                                    div().p_0p5().child(Icon::new(IconName::Bell)),
+                                                ))
+                                            })
+                                            .when(ix == 1, |this| this.suffix(IconName::Settings2))
+                                            .on_click(cx.listener(item.handler()))
+                                    })),
+                            )
+                            .group(
+                                SidebarGroup::new("Archived")
+                                    .expanded(self.state.is_group_expanded("Archived"))
+                                    .on_toggle(self.toggle_group("Archived", cx))
+                                    .child(
+                                        SidebarMenu::new()
+                                            .active_route(self.last_active_item.label())
+                                            .children(groups[2].iter().map(|item| {
+                                                SidebarMenuItem::new(item.label())
+                                                    .icon(item.icon())
+                                                    .route(item.label())
+                                                    .on_click(cx.listener(item.handler()))
+                                            })),
+                                    ),
+                            ),
                     )
                     .footer(
                         SidebarFooter::new()
@@ -360,9 +394,9 @@ impl Render for SidebarStory {
                                 h_flex()
                                     .gap_2()
                                     .child(IconName::CircleUser)
-                                    .when(!self.collapsed, |this| this.child("Jason Lee")),
+                                    .when(!collapsed, |this| this.child("Jason Lee")),
                             )
-                            .when(!self.collapsed, |this| {
+                            .when(!collapsed, |this| {
                                 this.child(Icon::new(IconName::ChevronsUpDown).size_4())
                             }),
                     ),
@@ -382,9 +416,9 @@ impl Render for SidebarStory {
                             .child(
                                 SidebarToggleButton::left()
                                     .side(self.side)
-                                    .collapsed(self.collapsed)
+                                    .collapsed(collapsed)
                                     .on_click(cx.listener(|this, _, _, cx| {
-                                        this.collapsed = !this.collapsed;
+                                        this.state.toggle_collapsed();
                                         cx.notify();
                                     })),
                             )
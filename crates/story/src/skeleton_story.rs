@@ -1,14 +1,24 @@
 use gpui::{
-    px, App, AppContext, Context, Entity, Focusable, IntoElement, ParentElement, Render, Styled,
-    Window,
+    div, px, App, AppContext, Context, Entity, Focusable, IntoElement, ParentElement, Render,
+    Styled, Window,
+};
+use gpui_component::{
+    avatar::Avatar,
+    button::{Button, ButtonVariants as _},
+    h_flex,
+    loading_overlay::LoadingOverlay,
+    skeleton::{Skeleton, SkeletonLoader},
+    switch::Switch,
+    v_flex,
 };
-use gpui_component::{skeleton::Skeleton, v_flex};
 
 use crate::section;
 
 pub struct SkeletonStory {
     focus_handle: gpui::FocusHandle,
     value: f32,
+    loading: bool,
+    overlay_loading: bool,
 }
 
 impl super::Story for SkeletonStory {
@@ -34,6 +44,8 @@ impl SkeletonStory {
         Self {
             focus_handle: cx.focus_handle(),
             value: 50.,
+            loading: true,
+            overlay_loading: false,
         }
     }
 
@@ -49,7 +61,7 @@ impl Focusable for SkeletonStory {
 }
 
 impl Render for SkeletonStory {
-    fn render(&mut self, _: &mut Window, _: &mut Context<Self>) -> impl IntoElement {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
         v_flex()
             .items_center()
             .gap_y_3()
@@ -77,5 +89,88 @@ impl Render for SkeletonStory {
                         ),
                 ),
             )
+            .child(
+                section("Shimmer").max_w_md().child(
+                    h_flex().gap_3().child(Skeleton::circle()).child(
+                        v_flex()
+                            .flex_1()
+                            .gap_2()
+                            .child(Skeleton::text().w(px(200.)))
+                            .child(Skeleton::text().w(px(150.)))
+                            .child(Skeleton::rect().h(px(80.)).rounded_md()),
+                    ),
+                ),
+            )
+            .child(
+                section(
+                    h_flex().gap_3().child("Loader").child(
+                        Switch::new("loading")
+                            .label("Loading")
+                            .checked(self.loading)
+                            .on_click(cx.listener(|this, checked, _, cx| {
+                                this.loading = *checked;
+                                cx.notify();
+                            })),
+                    ),
+                )
+                .max_w_md()
+                .child(
+                    SkeletonLoader::new("profile")
+                        .w(px(250.))
+                        .loading(self.loading)
+                        .skeleton(
+                            h_flex().gap_3().child(Skeleton::circle()).child(
+                                v_flex()
+                                    .flex_1()
+                                    .gap_2()
+                                    .child(Skeleton::text().w(px(120.)))
+                                    .child(Skeleton::text().w(px(80.)).secondary(true)),
+                            ),
+                        )
+                        .child(
+                            h_flex()
+                                .gap_3()
+                                .child(Avatar::new().name("Jason Lee"))
+                                .child(
+                                    v_flex()
+                                        .child("Jason Lee")
+                                        .child(div().text_sm().child("jason@example.com")),
+                                ),
+                        ),
+                ),
+            )
+            .child(
+                section(
+                    h_flex().gap_3().child("Loading Overlay").child(
+                        Switch::new("overlay-loading")
+                            .label("Loading")
+                            .checked(self.overlay_loading)
+                            .on_click(cx.listener(|this, checked, _, cx| {
+                                this.overlay_loading = *checked;
+                                cx.notify();
+                            })),
+                    ),
+                )
+                .max_w_md()
+                .child(
+                    LoadingOverlay::new("overlay")
+                        .w(px(250.))
+                        .loading(self.overlay_loading)
+                        .message("Saving...")
+                        .child(
+                            v_flex().gap_3().p_4().child("Profile settings").child(
+                                h_flex()
+                                    .gap_2()
+                                    .child(Button::new("cancel").label("Cancel"))
+                                    .child(Button::new("save").primary().label("Save").on_click(
+                                        cx.listener(|this, _, _, cx| {
+                                            this.overlay_loading = true;
+                                            cx.notify();
+                                        }),
+                                    )),
+                            ),
+                        ),
+                ),
+            )
     }
 }
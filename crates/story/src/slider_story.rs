@@ -6,6 +6,7 @@ use gpui_component::{
     checkbox::Checkbox,
     clipboard::Clipboard,
     h_flex,
+    number_format::NumberFormat,
     slider::{Slider, SliderEvent, SliderState},
     v_flex, ActiveTheme, Colorize as _, ContextModal, StyledExt,
 };
@@ -275,6 +276,7 @@ impl Render for SliderStory {
                         .child(
                             Slider::new(&self.slider_hsl[1])
                                 .vertical()
+                                .number_format(NumberFormat::percent())
                                 .disabled(self.disabled),
                         )
                         .child(
@@ -293,6 +295,7 @@ impl Render for SliderStory {
                         .child(
                             Slider::new(&self.slider_hsl[2])
                                 .vertical()
+                                .number_format(NumberFormat::percent())
                                 .disabled(self.disabled),
                         )
                         .child(
@@ -311,6 +314,7 @@ impl Render for SliderStory {
                         .child(
                             Slider::new(&self.slider_hsl[3])
                                 .vertical()
+                                .number_format(NumberFormat::percent())
                                 .disabled(self.disabled),
                         )
                         .child(
@@ -0,0 +1,123 @@
+use gpui::{
+    div, px, App, AppContext, Context, Entity, Focusable, IntoElement, ParentElement, Render,
+    Styled, Window,
+};
+use gpui_component::{
+    button::{Button, ButtonVariants as _},
+    spinner::{Spinner, SpinnerVariant},
+    v_flex, ActiveTheme as _, Sizable,
+};
+
+use crate::section;
+
+pub struct SpinnerStory {
+    focus_handle: gpui::FocusHandle,
+}
+
+impl super::Story for SpinnerStory {
+    fn title() -> &'static str {
+        "Spinner"
+    }
+
+    fn description() -> &'static str {
+        "Displays a loading spinner with an optional label."
+    }
+
+    fn new_view(window: &mut Window, cx: &mut App) -> Entity<impl Render + Focusable> {
+        Self::view(window, cx)
+    }
+}
+
+impl SpinnerStory {
+    pub fn view(window: &mut Window, cx: &mut App) -> Entity<Self> {
+        cx.new(|cx| Self::new(window, cx))
+    }
+
+    fn new(_: &mut Window, cx: &mut Context<Self>) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+        }
+    }
+}
+
+impl Focusable for SpinnerStory {
+    fn focus_handle(&self, _: &gpui::App) -> gpui::FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl Render for SpinnerStory {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let variants = [
+            SpinnerVariant::Arc,
+            SpinnerVariant::Dots,
+            SpinnerVariant::Bars,
+        ];
+
+        v_flex()
+            .gap_y_3()
+            .child(
+                section("Variants")
+                    .gap_x_4()
+                    .children(variants.map(|variant| Spinner::new().variant(variant))),
+            )
+            .child(section("Sizes").gap_x_4().children(variants.map(|variant| {
+                v_flex()
+                    .gap_2()
+                    .items_center()
+                    .child(Spinner::new().variant(variant).xsmall())
+                    .child(Spinner::new().variant(variant).small())
+                    .child(Spinner::new().variant(variant))
+                    .child(Spinner::new().variant(variant).large())
+            })))
+            .child(
+                section("With Label")
+                    .gap_x_4()
+                    .child(Spinner::new().label("Loading..."))
+                    .child(
+                        Spinner::new()
+                            .variant(SpinnerVariant::Dots)
+                            .small()
+                            .label("Syncing"),
+                    )
+                    .child(
+                        Spinner::new()
+                            .variant(SpinnerVariant::Bars)
+                            .color(cx.theme().blue)
+                            .label("Uploading"),
+                    ),
+            )
+            .child(
+                section("In Button")
+                    .gap_x_2()
+                    .child(
+                        Button::new("loading")
+                            .primary()
+                            .loading(true)
+                            .label("Saving"),
+                    )
+                    .child(
+                        Button::new("loading-outline")
+                            .outline()
+                            .loading(true)
+                            .label("Saving"),
+                    ),
+            )
+            .child(
+                section("Centered").child(
+                    div()
+                        .h(px(160.))
+                        .w_full()
+                        .border_1()
+                        .border_color(cx.theme().border)
+                        .rounded(cx.theme().radius)
+                        .child(
+                            Spinner::new()
+                                .centered(true)
+                                .large()
+                                .label("Loading data..."),
+                        ),
+                ),
+            )
+    }
+}
@@ -1,3 +1,5 @@
+use std::time::Duration;
+
 use gpui::{
     px, App, AppContext, Context, Div, Entity, FocusHandle, Focusable, IntoElement, ParentElement,
     Render, SharedString, Styled, Window,
@@ -14,6 +16,8 @@ pub struct SwitchStory {
     switch1: bool,
     switch2: bool,
     switch3: bool,
+    switch4: bool,
+    switch4_loading: bool,
 }
 
 impl super::Story for SwitchStory {
@@ -41,6 +45,8 @@ impl SwitchStory {
             switch1: true,
             switch2: false,
             switch3: true,
+            switch4: false,
+            switch4_loading: false,
         }
     }
 }
@@ -143,6 +149,43 @@ impl Render for SwitchStory {
                                 cx.notify();
                             })),
                     ),
+                )
+                .child(
+                    section("Large Size").child(
+                        Switch::new("switch-large")
+                            .checked(self.switch3)
+                            .label("Large Size")
+                            .large()
+                            .on_click(cx.listener(move |view, checked, _, cx| {
+                                view.switch3 = *checked;
+                                cx.notify();
+                            })),
+                    ),
+                )
+                .child(
+                    section("Async Confirm").child(
+                        Switch::new("switch4")
+                            .checked(self.switch4)
+                            .loading(self.switch4_loading)
+                            .state_labels("On", "Off")
+                            .label("Sync to cloud")
+                            .on_change(cx.listener(move |view, checked: &bool, _, cx| {
+                                let checked = *checked;
+                                view.switch4_loading = true;
+                                cx.notify();
+
+                                // Simulate a network request, confirm the value when it is done.
+                                cx.spawn(async move |this, cx| {
+                                    cx.background_executor().timer(Duration::from_secs(1)).await;
+                                    _ = this.update(cx, |view, cx| {
+                                        view.switch4 = checked;
+                                        view.switch4_loading = false;
+                                        cx.notify();
+                                    });
+                                })
+                                .detach();
+                            })),
+                    ),
                 ),
         )
     }
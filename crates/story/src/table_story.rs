@@ -1,4 +1,5 @@
 use std::{
+    cmp::Ordering,
     ops::Range,
     sync::LazyLock,
     time::{self, Duration},
@@ -6,9 +7,10 @@ use std::{
 
 use fake::Fake;
 use gpui::{
-    div, prelude::FluentBuilder as _, Action, AnyElement, App, AppContext, ClickEvent, Context,
-    Entity, Focusable, InteractiveElement, IntoElement, ParentElement, Render, SharedString,
-    StatefulInteractiveElement, Styled, TextAlign, Timer, Window,
+    div, prelude::FluentBuilder as _, Action, AnyElement, App, AppContext, ClickEvent,
+    ClipboardItem, Context, Entity, Focusable, InteractiveElement, IntoElement, ParentElement,
+    Pixels, Point, Render, SharedString, StatefulInteractiveElement, Styled, Task, TextAlign,
+    Timer, Window,
 };
 use gpui_component::{
     button::Button,
@@ -18,8 +20,10 @@ use gpui_component::{
     input::{InputEvent, InputState, TextInput},
     label::Label,
     popup_menu::{PopupMenu, PopupMenuExt},
-    table::{Column, ColumnFixed, ColumnSort, Table, TableDelegate, TableEvent},
-    v_flex, ActiveTheme as _, Selectable, Sizable as _, Size, StyleSized as _, StyledExt,
+    scroll::ScrollAlign,
+    table::{CellEditor, CellValue, Column, ColumnFixed, Table, TableDelegate, TableEvent},
+    v_flex, ActiveTheme as _, Disableable as _, Selectable, Sizable as _, Size, StyleSized as _,
+    StyledExt,
 };
 use serde::{Deserialize, Serialize};
 
@@ -57,6 +61,7 @@ impl Counter {
 struct Stock {
     id: usize,
     counter: Counter,
+    watched: bool,
     price: f64,
     change: f64,
     change_percent: f64,
@@ -177,6 +182,7 @@ struct StockTableDelegate {
     size: Size,
     loading: bool,
     full_loading: bool,
+    error: Option<SharedString>,
     eof: bool,
     visible_rows: Range<usize>,
     visible_cols: Range<usize>,
@@ -190,10 +196,12 @@ impl StockTableDelegate {
             columns: vec![
                 Column::new("id", "ID")
                     .width(60.)
+                    .sortable()
                     .fixed(ColumnFixed::Left)
                     .resizable(false),
                 Column::new("market", "Market")
                     .width(60.)
+                    .sortable()
                     .fixed(ColumnFixed::Left)
                     .resizable(false),
                 Column::new("name", "Name")
@@ -203,6 +211,7 @@ impl StockTableDelegate {
                     .width(100.)
                     .fixed(ColumnFixed::Left)
                     .sortable(),
+                Column::new("watched", "Watch").width(60.).sortable(),
                 Column::new("price", "Price").sortable().text_right().p_0(),
                 Column::new("change", "Chg").sortable().text_right().p_0(),
                 Column::new("change_percent", "Chg%")
@@ -256,6 +265,7 @@ impl StockTableDelegate {
             ],
             loading: false,
             full_loading: false,
+            error: None,
             eof: false,
             visible_cols: Range::default(),
             visible_rows: Range::default(),
@@ -389,6 +399,79 @@ impl TableDelegate for StockTableDelegate {
             }))
     }
 
+    fn is_editable(&self, _: usize, col_ix: usize, _: &App) -> bool {
+        matches!(
+            self.columns[col_ix].key.as_ref(),
+            "name" | "market" | "watched" | "price"
+        )
+    }
+
+    fn editor_for(&self, _: usize, col_ix: usize, _: &App) -> CellEditor {
+        match self.columns[col_ix].key.as_ref() {
+            "market" => CellEditor::Dropdown(vec!["US".into(), "HK".into()]),
+            "watched" => CellEditor::Checkbox,
+            "price" => CellEditor::Number,
+            _ => CellEditor::Text,
+        }
+    }
+
+    fn cell_value(&self, row_ix: usize, col_ix: usize, _: &App) -> CellValue {
+        let stock = &self.stocks[row_ix];
+        match self.columns[col_ix].key.as_ref() {
+            "market" => CellValue::Text(stock.counter.market.clone()),
+            "watched" => CellValue::Bool(stock.watched),
+            "price" => CellValue::Number(stock.price),
+            _ => CellValue::Text(stock.counter.name.clone()),
+        }
+    }
+
+    fn cell_text(&self, row_ix: usize, col_ix: usize, _: &App) -> SharedString {
+        let stock = &self.stocks[row_ix];
+        match self.columns[col_ix].key.as_ref() {
+            "id" => stock.id.to_string().into(),
+            "market" => stock.counter.market.clone(),
+            "symbol" => stock.counter.symbol_code(),
+            "name" => stock.counter.name.clone(),
+            "watched" => stock.watched.to_string().into(),
+            "price" => format!("{:.3}", stock.price).into(),
+            "change" => format!("{:.3}", stock.change).into(),
+            "change_percent" => format!("{:.2}%", stock.change_percent * 100.).into(),
+            "volume" => format!("{:.3}", stock.volume).into(),
+            "turnover" => format!("{:.3}", stock.turnover).into(),
+            "market_cap" => format!("{:.3}", stock.market_cap).into(),
+            _ => SharedString::default(),
+        }
+    }
+
+    fn on_cell_edited(
+        &mut self,
+        row_ix: usize,
+        col_ix: usize,
+        value: CellValue,
+        _: &mut Window,
+        _: &mut Context<Table<Self>>,
+    ) -> Result<(), SharedString> {
+        let stock = &mut self.stocks[row_ix];
+        match (self.columns[col_ix].key.as_ref(), value) {
+            ("name", CellValue::Text(name)) => {
+                if name.trim().is_empty() {
+                    return Err("Name can't be empty".into());
+                }
+                stock.counter.name = name;
+            }
+            ("market", CellValue::Text(market)) => stock.counter.market = market,
+            ("watched", CellValue::Bool(watched)) => stock.watched = watched,
+            ("price", CellValue::Number(price)) => {
+                if price < 0. {
+                    return Err("Price must be positive".into());
+                }
+                stock.price = price;
+            }
+            _ => {}
+        }
+        Ok(())
+    }
+
     /// NOTE: Performance metrics
     ///
     /// last render 561 cells total: 232.745µs, avg: 414ns
@@ -421,6 +504,9 @@ impl TableDelegate for StockTableDelegate {
                 .into_any_element(),
             "symbol" => stock.counter.symbol_code().into_any_element(),
             "name" => stock.counter.name.clone().into_any_element(),
+            "watched" => Checkbox::new(("watched", row_ix))
+                .checked(stock.watched)
+                .into_any_element(),
             "price" => self.render_value_cell(&col, stock.price, cx),
             "change" => self.render_value_cell(&col, stock.change, cx),
             "change_percent" => self.render_percent(&col, stock.change_percent, cx),
@@ -506,39 +592,66 @@ impl TableDelegate for StockTableDelegate {
         self.columns.insert(to_ix, col);
     }
 
-    fn perform_sort(
+    fn compare_rows(&self, col_ix: usize, row_a: usize, row_b: usize, _: &App) -> Option<Ordering> {
+        let (a, b) = (self.stocks.get(row_a)?, self.stocks.get(row_b)?);
+        let col = self.columns.get(col_ix)?;
+
+        match col.key.as_ref() {
+            "id" => Some(a.id.cmp(&b.id)),
+            "market" => Some(a.counter.market.cmp(&b.counter.market)),
+            "symbol" => Some(a.counter.symbol.cmp(&b.counter.symbol)),
+            "watched" => Some(a.watched.cmp(&b.watched)),
+            "price" => a.price.partial_cmp(&b.price),
+            "change" => a.change.partial_cmp(&b.change),
+            "change_percent" => a.change_percent.partial_cmp(&b.change_percent),
+            _ => None,
+        }
+    }
+
+    fn reorder_rows(&mut self, order: &[usize], _: &mut Window, _: &mut Context<Table<Self>>) {
+        self.stocks = order.iter().map(|ix| self.stocks[*ix].clone()).collect();
+    }
+
+    fn filter(
         &mut self,
-        col_ix: usize,
-        sort: ColumnSort,
+        query: &str,
+        rows: Range<usize>,
         _: &mut Window,
-        _: &mut Context<Table<Self>>,
-    ) {
-        if let Some(col) = self.columns.get_mut(col_ix) {
-            match col.key.as_ref() {
-                "id" => self.stocks.sort_by(|a, b| match sort {
-                    ColumnSort::Descending => b.id.cmp(&a.id),
-                    _ => a.id.cmp(&b.id),
-                }),
-                "symbol" => self.stocks.sort_by(|a, b| match sort {
-                    ColumnSort::Descending => b.counter.symbol.cmp(&a.counter.symbol),
-                    _ => a.id.cmp(&b.id),
-                }),
-                "change" | "change_percent" => self.stocks.sort_by(|a, b| match sort {
-                    ColumnSort::Descending => b
-                        .change
-                        .partial_cmp(&a.change)
-                        .unwrap_or(std::cmp::Ordering::Equal),
-                    _ => a.id.cmp(&b.id),
-                }),
-                _ => {}
-            }
-        }
+        cx: &mut Context<Table<Self>>,
+    ) -> Task<Vec<usize>> {
+        let query = query.to_lowercase();
+        let counters = self.stocks[rows.clone()]
+            .iter()
+            .map(|stock| (stock.counter.symbol.clone(), stock.counter.name.clone()))
+            .collect::<Vec<_>>();
+
+        cx.background_spawn(async move {
+            rows.zip(counters)
+                .filter(|(_, (symbol, name))| {
+                    symbol.to_lowercase().contains(&query) || name.to_lowercase().contains(&query)
+                })
+                .map(|(row_ix, _)| row_ix)
+                .collect()
+        })
     }
 
     fn loading(&self, _: &App) -> bool {
         self.full_loading
     }
 
+    fn error(&self, _: &App) -> Option<SharedString> {
+        self.error.clone()
+    }
+
+    fn can_retry(&self, _: &App) -> bool {
+        true
+    }
+
+    fn perform_retry(&mut self, _: &mut Window, cx: &mut Context<Table<Self>>) {
+        self.error = None;
+        cx.notify();
+    }
+
     fn is_eof(&self, _: &App) -> bool {
         return !self.loading && !self.eof;
     }
@@ -587,9 +700,12 @@ impl TableDelegate for StockTableDelegate {
 pub struct TableStory {
     table: Entity<Table<StockTableDelegate>>,
     num_stocks_input: Entity<InputState>,
+    filter_input: Entity<InputState>,
     stripe: bool,
+    vim_mode: bool,
     refresh_data: bool,
     size: Size,
+    saved_scroll_offset: Option<Point<Pixels>>,
 }
 
 impl super::Story for TableStory {
@@ -631,6 +747,9 @@ impl TableStory {
             input
         });
 
+        let filter_input =
+            cx.new(|cx| InputState::new(window, cx).placeholder("Filter by symbol or name"));
+
         let delegate = StockTableDelegate::new(5000);
         let table = cx.new(|cx| Table::new(delegate, window, cx));
 
@@ -638,6 +757,8 @@ impl TableStory {
             .detach();
         cx.subscribe_in(&num_stocks_input, window, Self::on_num_stocks_input_change)
             .detach();
+        cx.subscribe_in(&filter_input, window, Self::on_filter_input_change)
+            .detach();
 
         // Spawn a background to random refresh the list
         cx.spawn(async move |this, cx| {
@@ -670,9 +791,12 @@ impl TableStory {
         Self {
             table,
             num_stocks_input,
+            filter_input,
             stripe: false,
+            vim_mode: false,
             refresh_data: false,
             size: Size::default(),
+            saved_scroll_offset: None,
         }
     }
 
@@ -703,6 +827,27 @@ impl TableStory {
         }
     }
 
+    fn on_filter_input_change(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::Change(text) => {
+                self.table.update(cx, |table, cx| {
+                    table.filter(text.clone(), window, cx);
+                });
+            }
+            // Back to the table to navigate the matched rows.
+            InputEvent::PressEnter { .. } if self.vim_mode => {
+                self.table.read(cx).focus_handle(cx).focus(window);
+            }
+            _ => {}
+        }
+    }
+
     fn toggle_loop_selection(&mut self, checked: &bool, _: &mut Window, cx: &mut Context<Self>) {
         self.table.update(cx, |table, cx| {
             table.loop_selection = *checked;
@@ -754,6 +899,14 @@ impl TableStory {
         });
     }
 
+    fn toggle_vim_mode(&mut self, checked: &bool, _: &mut Window, cx: &mut Context<Self>) {
+        self.vim_mode = *checked;
+        let vim_mode = self.vim_mode;
+        self.table.update(cx, |table, cx| {
+            table.set_vim_mode(vim_mode, cx);
+        });
+    }
+
     fn on_change_size(&mut self, a: &ChangeSize, _: &mut Window, cx: &mut Context<Self>) {
         self.size = a.0;
         self.table.update(cx, |table, cx| {
@@ -771,8 +924,8 @@ impl TableStory {
         &mut self,
         _: &Entity<Table<StockTableDelegate>>,
         event: &TableEvent,
-        _window: &mut Window,
-        _cx: &mut Context<Self>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
     ) {
         match event {
             TableEvent::ColumnWidthsChanged(col_widths) => {
@@ -784,6 +937,13 @@ impl TableStory {
             TableEvent::MoveColumn(origin_idx, target_idx) => {
                 println!("Move col index: {} -> {}", origin_idx, target_idx);
             }
+            TableEvent::SortChanged(sorts) => println!("Sort changed: {:?}", sorts),
+            TableEvent::FilterChanged(_) => cx.notify(),
+            TableEvent::ActivateRow(ix) => println!("Activate row: {}", ix),
+            TableEvent::StartFilter => {
+                self.filter_input
+                    .update(cx, |input, cx| input.focus(window, cx));
+            }
         }
     }
 }
@@ -793,6 +953,8 @@ impl Render for TableStory {
         let table = &self.table.read(cx);
         let delegate = table.delegate();
         let rows_count = delegate.rows_count(cx);
+        let filter_progress = table.filter_progress();
+        let filter_input = TextInput::new(&self.filter_input).small().cleanable();
         let size = self.size;
 
         v_flex()
@@ -847,6 +1009,12 @@ impl Render for TableStory {
                             .selected(self.stripe)
                             .on_click(cx.listener(Self::toggle_stripe)),
                     )
+                    .child(
+                        Checkbox::new("vim-mode")
+                            .label("Vim Mode")
+                            .selected(self.vim_mode)
+                            .on_click(cx.listener(Self::toggle_vim_mode)),
+                    )
                     .child(
                         Checkbox::new("loading")
                             .label("Loading")
@@ -858,6 +1026,18 @@ impl Render for TableStory {
                                 })
                             })),
                     )
+                    .child(
+                        Checkbox::new("error")
+                            .label("Error")
+                            .checked(self.table.read(cx).delegate().error.is_some())
+                            .on_click(cx.listener(|this, check: &bool, _, cx| {
+                                this.table.update(cx, |this, cx| {
+                                    this.delegate_mut().error =
+                                        check.then(|| "Failed to load the stocks.".into());
+                                    cx.notify();
+                                })
+                            })),
+                    )
                     .child(
                         Checkbox::new("refresh-data")
                             .label("Refresh Data")
@@ -917,6 +1097,53 @@ impl Render for TableStory {
                                     table.scroll_to_row(table.delegate().rows_count(cx) - 1, cx);
                                 })
                             })),
+                    )
+                    .child(
+                        Button::new("scroll-center")
+                            .outline()
+                            .small()
+                            .child("Scroll to 100 (center)")
+                            .on_click(cx.listener(|this, _, _, cx| {
+                                this.table.update(cx, |table, cx| {
+                                    table.scroll_to(100, ScrollAlign::Center, cx);
+                                })
+                            })),
+                    )
+                    .child(
+                        Button::new("save-scroll")
+                            .outline()
+                            .small()
+                            .child("Save Scroll")
+                            .on_click(cx.listener(|this, _, _, cx| {
+                                this.saved_scroll_offset =
+                                    Some(this.table.read(cx).scroll_offset());
+                                cx.notify();
+                            })),
+                    )
+                    .child(
+                        Button::new("restore-scroll")
+                            .outline()
+                            .small()
+                            .child("Restore Scroll")
+                            .disabled(self.saved_scroll_offset.is_none())
+                            .on_click(cx.listener(|this, _, _, cx| {
+                                let Some(offset) = this.saved_scroll_offset else {
+                                    return;
+                                };
+                                this.table.update(cx, |table, cx| {
+                                    table.set_scroll_offset(offset, cx);
+                                })
+                            })),
+                    )
+                    .child(
+                        Button::new("export-csv")
+                            .outline()
+                            .small()
+                            .child("Copy as CSV")
+                            .on_click(cx.listener(|this, _, _, cx| {
+                                let csv = this.table.read(cx).export_csv(true, cx);
+                                cx.write_to_clipboard(ClipboardItem::new_string(csv));
+                            })),
                     ), // .child(
                        //     Button::new("scroll-first-col")
                        //         .child("Scroll to First Column")
@@ -956,6 +1183,17 @@ impl Render for TableStory {
                                         .child(TextInput::new(&self.num_stocks_input).small())
                                         .into_any_element(),
                                 )
+                                .child(h_flex().min_w_48().child(filter_input).into_any_element())
+                                .when_some(filter_progress, |this, progress| {
+                                    this.child(if progress.is_done() {
+                                        format!("Matched: {}", progress.matched)
+                                    } else {
+                                        format!(
+                                            "Matched: {}, filtering {}/{}...",
+                                            progress.matched, progress.scanned, progress.total
+                                        )
+                                    })
+                                })
                                 .when(delegate.loading, |this| {
                                     this.child(
                                         h_flex()
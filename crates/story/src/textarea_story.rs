@@ -1,18 +1,82 @@
+use std::{cell::RefCell, collections::HashSet};
+
 use gpui::{
     px, App, AppContext as _, ClickEvent, Context, Entity, FocusHandle, Focusable,
-    InteractiveElement, IntoElement, KeyBinding, ParentElement as _, Render, Styled, Window,
+    InteractiveElement, IntoElement, KeyBinding, ParentElement as _, Render, SharedString, Styled,
+    Window,
 };
 
 use crate::{section, Tab, TabPrev};
 use gpui_component::{
     button::Button,
+    checkbox::Checkbox,
     h_flex,
-    input::{InputState, TextInput},
+    input::{InputState, SpellChecker, TextInput},
     v_flex, FocusableCycle, Sizable,
 };
 
 const CONTEXT: &str = "TextareaStory";
 
+const DICTIONARY: &str = "a an and are as at be by call can check code component components
+    for from hello here i in input is it like line menu misspelled of on or right see spelling
+    suggestion suggestions text the this to type under use with word words you";
+
+/// A demo spell checker with a tiny dictionary, suggests the words within 2 edits.
+struct DemoSpellChecker {
+    words: RefCell<HashSet<String>>,
+}
+
+impl DemoSpellChecker {
+    fn new() -> Self {
+        Self {
+            words: RefCell::new(DICTIONARY.split_whitespace().map(String::from).collect()),
+        }
+    }
+}
+
+/// Returns the Levenshtein distance of the `a` and `b`.
+fn edit_distance(a: &str, b: &str) -> usize {
+    let b = b.chars().collect::<Vec<_>>();
+    let mut row = (0..=b.len()).collect::<Vec<_>>();
+    for (i, ca) in a.chars().enumerate() {
+        let mut prev = row[0];
+        row[0] = i + 1;
+        for (j, cb) in b.iter().enumerate() {
+            let cost = if ca == *cb { prev } else { prev + 1 };
+            prev = row[j + 1];
+            row[j + 1] = cost.min(row[j] + 1).min(prev + 1);
+        }
+    }
+    row[b.len()]
+}
+
+impl SpellChecker for DemoSpellChecker {
+    fn check(&self, word: &str) -> Option<Vec<SharedString>> {
+        let word = word.to_lowercase();
+        let words = self.words.borrow();
+        if words.contains(&word) {
+            return None;
+        }
+
+        let mut suggestions = words
+            .iter()
+            .map(|w| (edit_distance(&word, w), w))
+            .filter(|(distance, _)| *distance <= 2)
+            .collect::<Vec<_>>();
+        suggestions.sort();
+        Some(
+            suggestions
+                .into_iter()
+                .map(|(_, w)| w.clone().into())
+                .collect(),
+        )
+    }
+
+    fn add_word(&self, word: &str) {
+        self.words.borrow_mut().insert(word.to_lowercase());
+    }
+}
+
 pub fn init(cx: &mut App) {
     cx.bind_keys([
         KeyBinding::new("shift-tab", TabPrev, Some(CONTEXT)),
@@ -24,6 +88,8 @@ pub struct TextareaStory {
     textarea: Entity<InputState>,
     textarea_auto_grow: Entity<InputState>,
     textarea_no_wrap: Entity<InputState>,
+    textarea_spellcheck: Entity<InputState>,
+    spellcheck_code_mode: bool,
 }
 
 impl super::Story for TextareaStory {
@@ -93,10 +159,21 @@ impl TextareaStory {
                 .default_value("This is a very long line of text to test if the horizontal scrolling function is working properly, and it should not wrap automatically but display a horizontal scrollbar.\nThe second line is also very long text, used to test the horizontal scrolling effect under multiple lines, and you can input more content to test.\nThe third line: Here you can input other long text content that requires horizontal scrolling.\n")
         });
 
+        let textarea_spellcheck = cx.new(|cx| {
+            InputState::new(window, cx)
+                .multi_line()
+                .rows(4)
+                .spell_checker(DemoSpellChecker::new())
+                .spellcheck_code_mode(true)
+                .default_value("Type the txt in this input, the mispelled words are underlined.\nRight click a word to see the sugestions, the `code_spans`, callMe() and std::io are skipped in the code mode.")
+        });
+
         Self {
             textarea,
             textarea_auto_grow,
             textarea_no_wrap,
+            textarea_spellcheck,
+            spellcheck_code_mode: true,
         }
     }
 
@@ -193,5 +270,25 @@ impl Render for TextareaStory {
                     .max_w_md()
                     .child(TextInput::new(&self.textarea_no_wrap).h(px(200.))),
             )
+            .child(
+                section("Spellcheck").child(
+                    v_flex()
+                        .gap_2()
+                        .w_full()
+                        .child(TextInput::new(&self.textarea_spellcheck))
+                        .child(
+                            Checkbox::new("spellcheck-code-mode")
+                                .label("Code Mode")
+                                .checked(self.spellcheck_code_mode)
+                                .on_click(cx.listener(|this, checked: &bool, window, cx| {
+                                    this.spellcheck_code_mode = *checked;
+                                    this.textarea_spellcheck.update(cx, |state, cx| {
+                                        state.set_spellcheck_code_mode(*checked, window, cx);
+                                    });
+                                    cx.notify();
+                                })),
+                        ),
+                ),
+            )
     }
 }
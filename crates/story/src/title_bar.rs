@@ -6,6 +6,7 @@ use gpui::{
     Render, SharedString, Styled as _, Subscription, Window,
 };
 use gpui_component::{
+    animation::AnimationSettings as _,
     badge::Badge,
     button::{Button, ButtonVariants as _},
     locale,
@@ -15,7 +16,10 @@ use gpui_component::{
     TitleBar,
 };
 
-use crate::{themes::ThemeSwitcher, SelectFont, SelectLocale, SelectRadius, SelectScrollbarShow};
+use crate::{
+    themes::ThemeSwitcher, SelectAnimations, SelectFont, SelectLocale, SelectRadius,
+    SelectScrollbarShow,
+};
 
 pub struct AppTitleBar {
     title: SharedString,
@@ -214,6 +218,15 @@ impl FontSizeSelector {
         Theme::global_mut(cx).scrollbar_show = show.0;
         window.refresh();
     }
+
+    fn on_select_animations(
+        &mut self,
+        animations: &SelectAnimations,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        cx.set_animations_enabled(animations.0);
+    }
 }
 
 impl Render for FontSizeSelector {
@@ -222,6 +235,7 @@ impl Render for FontSizeSelector {
         let font_size = cx.theme().font_size.0 as i32;
         let radius = cx.theme().radius.0 as i32;
         let scroll_show = cx.theme().scrollbar_show;
+        let animations = cx.animations_enabled();
 
         div()
             .id("font-size-selector")
@@ -229,6 +243,7 @@ impl Render for FontSizeSelector {
             .on_action(cx.listener(Self::on_select_font))
             .on_action(cx.listener(Self::on_select_radius))
             .on_action(cx.listener(Self::on_select_scrollbar_show))
+            .on_action(cx.listener(Self::on_select_animations))
             .child(
                 Button::new("btn")
                     .small()
@@ -272,6 +287,18 @@ impl Render for FontSizeSelector {
                                 scroll_show == ScrollbarShow::Always,
                                 Box::new(SelectScrollbarShow(ScrollbarShow::Always)),
                             )
+                            .separator()
+                            .label("Animations")
+                            .menu_with_check(
+                                "Enabled",
+                                animations,
+                                Box::new(SelectAnimations(true)),
+                            )
+                            .menu_with_check(
+                                "Disabled",
+                                !animations,
+                                Box::new(SelectAnimations(false)),
+                            )
                     })
                     .anchor(Corner::TopRight),
             )
@@ -1,16 +1,17 @@
 use std::{ops::Range, rc::Rc};
 
 use gpui::{
-    div, px, size, App, AppContext, Context, Div, Entity, FocusHandle, Focusable,
-    InteractiveElement, IntoElement, ParentElement, Pixels, Render, ScrollStrategy, Size, Styled,
-    Window,
+    div, prelude::FluentBuilder as _, px, size, App, AppContext, Context, Div, Entity, FocusHandle,
+    Focusable, InteractiveElement, IntoElement, ParentElement, Pixels, Render, ScrollStrategy,
+    Size, Styled, Window,
 };
 use gpui_component::{
     button::{Button, ButtonGroup},
     divider::Divider,
     h_flex,
     scroll::{Scrollbar, ScrollbarAxis, ScrollbarState},
-    v_flex, v_virtual_list, ActiveTheme as _, Selectable, Sizable, VirtualListScrollHandle,
+    v_flex, v_virtual_list, v_virtual_list_measured, ActiveTheme as _, Selectable, Sizable,
+    VirtualListScrollHandle,
 };
 
 pub struct VirtualListStory {
@@ -62,9 +63,21 @@ impl VirtualListStory {
                 .map(|i| format!("Item {}", i))
                 .collect::<Vec<_>>();
             self.columns_count = 100;
-        } else {
+        } else if n == 3 {
             self.items = (0..5).map(|i| format!("Item {}", i)).collect::<Vec<_>>();
             self.columns_count = 10;
+        } else {
+            // Variable heights, like chat messages.
+            self.items = (0..1000)
+                .map(|i| {
+                    (0..=(i * 7) % 5)
+                        .map(|line| format!("Message {}, line {}", i, line))
+                        .collect::<Vec<_>>()
+                        .join("\n")
+                })
+                .collect::<Vec<_>>();
+            self.columns_count = 1;
+            self.scroll_handle.invalidate_item_sizes();
         }
 
         self.item_sizes = Rc::new(self.items.iter().map(|_| ITEM_SIZE).collect());
@@ -112,6 +125,11 @@ impl VirtualListStory {
                                             .label("Size 3")
                                             .selected(self.size_mode == 3),
                                     )
+                                    .child(
+                                        Button::new("test-4")
+                                            .label("Variable")
+                                            .selected(self.size_mode == 4),
+                                    )
                                     .on_click(cx.listener(|view, clicks: &Vec<usize>, _, cx| {
                                         if clicks.contains(&0) {
                                             view.change_test_cases(0, cx)
@@ -121,6 +139,8 @@ impl VirtualListStory {
                                             view.change_test_cases(2, cx)
                                         } else if clicks.contains(&3) {
                                             view.change_test_cases(3, cx)
+                                        } else if clicks.contains(&4) {
+                                            view.change_test_cases(4, cx)
                                         }
                                     })),
                             )
@@ -233,6 +253,7 @@ impl Render for VirtualListStory {
         cx: &mut gpui::Context<Self>,
     ) -> impl gpui::IntoElement {
         let columns_count = self.columns_count;
+        let is_variable = self.size_mode == 4;
 
         fn render_item(cx: &App) -> Div {
             div()
@@ -257,35 +278,71 @@ impl Render for VirtualListStory {
                             .id("list")
                             .relative()
                             .size_full()
-                            .child(
-                                v_virtual_list(
-                                    cx.entity().clone(),
-                                    "items",
-                                    self.item_sizes.clone(),
-                                    move |story, visible_range, _, cx| {
-                                        story.visible_range = visible_range.clone();
+                            .when(is_variable, |this| {
+                                this.child(
+                                    v_virtual_list_measured(
+                                        cx.entity().clone(),
+                                        "variable-items",
+                                        self.items.len(),
+                                        px(30.),
+                                        move |story, visible_range, _, cx| {
+                                            story.visible_range = visible_range.clone();
 
-                                        visible_range
-                                            .map(|ix| {
-                                                h_flex().gap_1().items_center().children(
-                                                    (0..columns_count).map(|i| {
-                                                        render_item(cx).child(if i == 0 {
-                                                            format!("row: {}", ix)
-                                                        } else {
-                                                            format!("{}", i)
-                                                        })
-                                                    }),
-                                                )
-                                            })
-                                            .collect()
-                                    },
+                                            visible_range
+                                                .map(|ix| {
+                                                    v_flex()
+                                                        .p_2()
+                                                        .text_sm()
+                                                        .rounded(cx.theme().radius)
+                                                        .bg(cx.theme().secondary)
+                                                        .children(
+                                                            story.items[ix]
+                                                                .lines()
+                                                                .map(|line| line.to_string())
+                                                                .collect::<Vec<_>>(),
+                                                        )
+                                                })
+                                                .collect()
+                                        },
+                                    )
+                                    .track_scroll(&self.scroll_handle)
+                                    .p_4()
+                                    .border_1()
+                                    .border_color(cx.theme().border)
+                                    .gap_1(),
                                 )
-                                .track_scroll(&self.scroll_handle)
-                                .p_4()
-                                .border_1()
-                                .border_color(cx.theme().border)
-                                .gap_1(),
-                            )
+                            })
+                            .when(!is_variable, |this| {
+                                this.child(
+                                    v_virtual_list(
+                                        cx.entity().clone(),
+                                        "items",
+                                        self.item_sizes.clone(),
+                                        move |story, visible_range, _, cx| {
+                                            story.visible_range = visible_range.clone();
+
+                                            visible_range
+                                                .map(|ix| {
+                                                    h_flex().gap_1().items_center().children(
+                                                        (0..columns_count).map(|i| {
+                                                            render_item(cx).child(if i == 0 {
+                                                                format!("row: {}", ix)
+                                                            } else {
+                                                                format!("{}", i)
+                                                            })
+                                                        }),
+                                                    )
+                                                })
+                                                .collect()
+                                        },
+                                    )
+                                    .track_scroll(&self.scroll_handle)
+                                    .p_4()
+                                    .border_1()
+                                    .border_color(cx.theme().border)
+                                    .gap_1(),
+                                )
+                            })
                             .child({
                                 div()
                                     .absolute()
@@ -54,7 +54,7 @@ impl Render for WelcomeStory {
     ) -> impl gpui::IntoElement {
         v_flex().p_4().gap_5().child(
             TextView::markdown("intro", include_str!("../../../README.md"), window, cx)
-                .selectable(),
+                .selectable(true),
         )
     }
 }
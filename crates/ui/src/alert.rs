@@ -1,7 +1,7 @@
 use std::rc::Rc;
 
 use gpui::{
-    div, prelude::FluentBuilder as _, px, rems, App, ClickEvent, ElementId, Empty, Hsla,
+    div, prelude::FluentBuilder as _, px, rems, AnyElement, App, ClickEvent, ElementId, Hsla,
     InteractiveElement, IntoElement, ParentElement as _, RenderOnce, SharedString,
     StatefulInteractiveElement, StyleRefinement, Styled, Window,
 };
@@ -33,6 +33,17 @@ impl AlertVariant {
         }
     }
 
+    /// The foreground color for the solid style.
+    fn solid_fg(&self, cx: &App) -> Hsla {
+        match self {
+            AlertVariant::Secondary => cx.theme().secondary_foreground,
+            AlertVariant::Info => cx.theme().info_foreground,
+            AlertVariant::Success => cx.theme().success_foreground,
+            AlertVariant::Warning => cx.theme().warning_foreground,
+            AlertVariant::Error => cx.theme().danger_foreground,
+        }
+    }
+
     fn color(&self, cx: &App) -> Hsla {
         match self {
             AlertVariant::Secondary => cx.theme().secondary,
@@ -65,6 +76,8 @@ pub struct Alert {
     message: Text,
     size: Size,
     banner: bool,
+    solid: bool,
+    actions: Vec<AnyElement>,
     on_close: Option<Rc<dyn Fn(&ClickEvent, &mut Window, &mut App) + 'static>>,
     visible: bool,
 }
@@ -81,6 +94,8 @@ impl Alert {
             message: message.into(),
             size: Size::default(),
             banner: false,
+            solid: false,
+            actions: Vec::new(),
             visible: true,
             on_close: None,
         }
@@ -141,6 +156,20 @@ impl Alert {
         self
     }
 
+    /// Use the solid fill style, default is the subtle style with a light background.
+    pub fn solid(mut self) -> Self {
+        self.solid = true;
+        self
+    }
+
+    /// Add an action to the alert, e.g.: a [`Button`](crate::button::Button).
+    ///
+    /// The actions are displayed below the message, or at the end in `banner` style.
+    pub fn action(mut self, action: impl IntoElement) -> Self {
+        self.actions.push(action.into_any_element());
+        self
+    }
+
     /// Set alert as closable, true will show Close icon.
     pub fn on_close(
         mut self,
@@ -173,7 +202,8 @@ impl Styled for Alert {
 impl RenderOnce for Alert {
     fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
         if !self.visible {
-            return Empty.into_any_element();
+            // Use `display: none` to not take the gap of the parent.
+            return div().id(self.id).hidden().into_any_element();
         }
 
         let (radius, padding_x, padding_y, gap) = match self.size {
@@ -184,14 +214,26 @@ impl RenderOnce for Alert {
         };
 
         let color = self.variant.color(cx);
-        let fg = self.variant.fg(cx);
-        let border_color = self.variant.border_color(cx);
+        let (bg, fg, border_color) = if self.solid {
+            (color, self.variant.solid_fg(cx), color)
+        } else {
+            (
+                color.opacity(0.08),
+                self.variant.fg(cx),
+                self.variant.border_color(cx),
+            )
+        };
+        let hover_color = if self.solid { fg } else { color };
+        let (actions, banner_actions) = match self.banner {
+            true => (vec![], self.actions),
+            false => (self.actions, vec![]),
+        };
 
         h_flex()
             .id(self.id)
             .w_full()
             .text_color(fg)
-            .bg(color.opacity(0.08))
+            .bg(bg)
             .px(padding_x)
             .py(padding_y)
             .gap(gap)
@@ -228,17 +270,23 @@ impl RenderOnce for Alert {
                             .child(
                                 self.message
                                     .style(TextViewStyle::default().paragraph_gap(rems(0.2))),
-                            ),
+                            )
+                            .when(!actions.is_empty(), |this| {
+                                this.child(h_flex().mt_2().gap_2().children(actions))
+                            }),
                     ),
             )
+            .when(!banner_actions.is_empty(), |this| {
+                this.child(h_flex().flex_shrink_0().gap_2().children(banner_actions))
+            })
             .when_some(self.on_close, |this, on_close| {
                 this.child(
                     div()
                         .id("close")
                         .p_0p5()
                         .rounded(cx.theme().radius)
-                        .hover(|this| this.bg(color.opacity(0.1)))
-                        .active(|this| this.bg(color.opacity(0.2)))
+                        .hover(|this| this.bg(hover_color.opacity(0.1)))
+                        .active(|this| this.bg(hover_color.opacity(0.2)))
                         .on_click(move |ev, window, cx| {
                             on_close(ev, window, cx);
                         })
@@ -1,3 +1,10 @@
+use std::sync::Mutex;
+
+use gpui::App;
+use smol::channel::Sender;
+
+use crate::Theme;
+
 /// A cubic bezier function like CSS `cubic-bezier`.
 ///
 /// Builder:
@@ -17,3 +24,232 @@ pub fn cubic_bezier(x1: f32, y1: f32, x2: f32, y2: f32) -> impl Fn(f32) -> f32 {
         y
     }
 }
+
+/// Extends [`App`] to control the non-essential animations of the components globally.
+///
+/// e.g.: Notification, Modal, Drawer slide, Skeleton shimmer, Switch and Checkbox toggle.
+pub trait AnimationSettings {
+    /// Returns true if the non-essential animations are enabled.
+    fn animations_enabled(&self) -> bool;
+
+    /// Enable or disable the non-essential animations.
+    ///
+    /// By default this follows the OS "reduce motion" setting read by
+    /// [`Theme::sync_reduced_motion`], disable it is also useful to make the snapshot tests
+    /// deterministic.
+    fn set_animations_enabled(&mut self, enabled: bool);
+}
+
+impl AnimationSettings for App {
+    #[inline]
+    fn animations_enabled(&self) -> bool {
+        !Theme::global(self).reduced_motion
+    }
+
+    fn set_animations_enabled(&mut self, enabled: bool) {
+        Theme::global_mut(self).reduced_motion = !enabled;
+        self.refresh_windows();
+    }
+}
+
+/// Returns the `GPUI_REDUCED_MOTION` env to override the OS setting, e.g.: `GPUI_REDUCED_MOTION=1`.
+pub(crate) fn reduced_motion_override() -> Option<bool> {
+    let value = std::env::var("GPUI_REDUCED_MOTION").ok()?;
+    Some(matches!(value.trim(), "1" | "true"))
+}
+
+/// The OS "reduce motion" setting, None until it's read by [`system_reduced_motion`].
+static SYSTEM_REDUCED_MOTION: Mutex<Option<bool>> = Mutex::new(None);
+
+/// Returns true if the OS "reduce motion" accessibility setting is turned on.
+///
+/// The setting is read once and cached, [`watch_system_reduced_motion`] reads it again on a
+/// change. The first read runs `defaults` or `gsettings` on macOS and Linux, so it blocks and
+/// must not be called on the UI thread.
+pub(crate) fn system_reduced_motion() -> bool {
+    let Ok(mut cached) = SYSTEM_REDUCED_MOTION.lock() else {
+        return false;
+    };
+    *cached.get_or_insert_with(|| read_system_reduced_motion().unwrap_or(false))
+}
+
+/// Read the OS setting again into the cache, returns the previous value if it's changed.
+///
+/// Returns None if the setting is not read yet, the pending [`system_reduced_motion`] reads the
+/// new value.
+fn reread_system_reduced_motion() -> Option<bool> {
+    let mut cached = SYSTEM_REDUCED_MOTION.lock().ok()?;
+    let previous = (*cached)?;
+    let reduced_motion = read_system_reduced_motion().unwrap_or(false);
+    *cached = Some(reduced_motion);
+    (previous != reduced_motion).then_some(previous)
+}
+
+/// Watch the OS "reduce motion" setting, it is started by [`crate::theme::init`].
+///
+/// A change is applied to the [`Theme`], unless the animations are toggled away from the OS
+/// setting by [`AnimationSettings::set_animations_enabled`] or the `GPUI_REDUCED_MOTION` env.
+pub(crate) fn watch_system_reduced_motion(cx: &mut App) {
+    if reduced_motion_override().is_some() {
+        return;
+    }
+
+    let (tx, rx) = smol::channel::bounded(1);
+    let Some(watcher) = watch_system_setting(tx, cx) else {
+        return;
+    };
+
+    cx.spawn(async move |cx| {
+        let _watcher = watcher;
+        while rx.recv().await.is_ok() {
+            let changed = cx
+                .background_executor()
+                .spawn(async { reread_system_reduced_motion() })
+                .await;
+            let Some(previous) = changed else {
+                continue;
+            };
+
+            _ = cx.update(|cx| {
+                let theme = Theme::global_mut(cx);
+                if theme.reduced_motion != previous {
+                    return;
+                }
+                theme.reduced_motion = !previous;
+                cx.refresh_windows();
+            });
+        }
+    })
+    .detach();
+}
+
+#[cfg(target_os = "macos")]
+fn read_system_reduced_motion() -> Option<bool> {
+    read_command_output(
+        "defaults",
+        &["read", "com.apple.universalaccess", "reduceMotion"],
+    )
+    .map(|value| value == "1")
+}
+
+#[cfg(target_os = "linux")]
+fn read_system_reduced_motion() -> Option<bool> {
+    read_command_output(
+        "gsettings",
+        &["get", "org.gnome.desktop.interface", "enable-animations"],
+    )
+    .map(|value| value == "false")
+}
+
+#[cfg(target_os = "windows")]
+fn read_system_reduced_motion() -> Option<bool> {
+    use windows::Win32::{
+        Foundation::BOOL,
+        UI::WindowsAndMessaging::{
+            SystemParametersInfoW, SPI_GETCLIENTAREAANIMATION, SYSTEM_PARAMETERS_INFO_UPDATE_FLAGS,
+        },
+    };
+
+    let mut enabled = BOOL::default();
+    unsafe {
+        SystemParametersInfoW(
+            SPI_GETCLIENTAREAANIMATION,
+            0,
+            Some(&mut enabled as *mut BOOL as *mut _),
+            SYSTEM_PARAMETERS_INFO_UPDATE_FLAGS::default(),
+        )
+    }
+    .ok()?;
+    Some(!enabled.as_bool())
+}
+
+#[cfg(not(any(target_os = "macos", target_os = "linux", target_os = "windows")))]
+fn read_system_reduced_motion() -> Option<bool> {
+    None
+}
+
+/// Run the command and returns the last word of the output.
+#[cfg(any(target_os = "macos", target_os = "linux"))]
+fn read_command_output(program: &str, args: &[&str]) -> Option<String> {
+    let output = std::process::Command::new(program)
+        .args(args)
+        .output()
+        .ok()?;
+    if !output.status.success() {
+        return None;
+    }
+
+    String::from_utf8_lossy(&output.stdout)
+        .split_whitespace()
+        .last()
+        .map(|value| value.to_string())
+}
+
+/// Returns the file the OS writes the setting to.
+#[cfg(target_os = "macos")]
+fn system_setting_path() -> Option<std::path::PathBuf> {
+    let home = std::path::PathBuf::from(std::env::var_os("HOME")?);
+    Some(home.join("Library/Preferences/com.apple.universalaccess.plist"))
+}
+
+/// Returns the file the OS writes the setting to.
+#[cfg(target_os = "linux")]
+fn system_setting_path() -> Option<std::path::PathBuf> {
+    let config_dir = match std::env::var_os("XDG_CONFIG_HOME") {
+        Some(dir) => std::path::PathBuf::from(dir),
+        None => std::path::PathBuf::from(std::env::var_os("HOME")?).join(".config"),
+    };
+    Some(config_dir.join("dconf/user"))
+}
+
+/// Send to the `tx` when the file of the setting is changed, the setting is read only then.
+///
+/// The directory is watched, because the file is replaced rather than written in place.
+#[cfg(any(target_os = "macos", target_os = "linux"))]
+fn watch_system_setting(tx: Sender<()>, _: &App) -> Option<notify::RecommendedWatcher> {
+    use notify::Watcher as _;
+
+    let path = system_setting_path()?;
+    let dir = path.parent()?.to_path_buf();
+    let mut watcher = notify::recommended_watcher(move |res: notify::Result<notify::Event>| {
+        let Ok(event) = res else {
+            return;
+        };
+        match event.kind {
+            notify::EventKind::Create(_)
+            | notify::EventKind::Modify(_)
+            | notify::EventKind::Remove(_) => {
+                if event.paths.contains(&path) {
+                    _ = tx.try_send(());
+                }
+            }
+            _ => {}
+        }
+    })
+    .ok()?;
+
+    if let Err(err) = watcher.watch(&dir, notify::RecursiveMode::NonRecursive) {
+        tracing::error!("Failed to watch the reduced motion setting: {:?}", err);
+        return None;
+    }
+    Some(watcher)
+}
+
+/// Send to the `tx` every 2 seconds, there is no file to watch, but the setting is read by
+/// the platform API without starting a process.
+#[cfg(target_os = "windows")]
+fn watch_system_setting(tx: Sender<()>, cx: &App) -> Option<gpui::Task<()>> {
+    Some(cx.background_executor().spawn(async move {
+        loop {
+            smol::Timer::after(std::time::Duration::from_secs(2)).await;
+            if tx.send(()).await.is_err() {
+                break;
+            }
+        }
+    }))
+}
+
+#[cfg(not(any(target_os = "macos", target_os = "linux", target_os = "windows")))]
+fn watch_system_setting(_: Sender<()>, _: &App) -> Option<()> {
+    None
+}
@@ -1,17 +1,32 @@
-use std::rc::Rc;
+use std::{collections::HashMap, ops::Range, rc::Rc};
 
 use gpui::{
-    div, prelude::FluentBuilder as _, App, ClickEvent, ElementId, InteractiveElement as _,
-    IntoElement, ParentElement, RenderOnce, SharedString, StatefulInteractiveElement,
-    StyleRefinement, Styled, Window,
+    canvas, div, prelude::FluentBuilder as _, px, App, ClickEvent, ElementId, Entity,
+    InteractiveElement as _, IntoElement, ParentElement, Pixels, RenderOnce, SharedString,
+    StatefulInteractiveElement, StyleRefinement, Styled, Window,
 };
 
-use crate::{h_flex, ActiveTheme, Icon, IconName, StyledExt};
+use crate::{
+    button::{Button, ButtonVariants as _},
+    h_flex,
+    popup_menu::PopupMenuExt as _,
+    ActiveTheme, Icon, IconName, Sizable as _, StyledExt,
+};
+
+/// The width of the ellipsis menu button until it is measured.
+const ELLIPSIS_WIDTH: Pixels = px(20.);
+
+type OnClick = Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>;
+type OnSelect = Rc<dyn Fn(&usize, &mut Window, &mut App)>;
 
 #[derive(IntoElement)]
 pub struct Breadcrumb {
+    id: ElementId,
     style: StyleRefinement,
     items: Vec<BreadcrumbItem>,
+    separator: BreadcrumbSeparator,
+    max_visible_items: Option<usize>,
+    on_click: Option<OnSelect>,
 }
 
 #[derive(IntoElement)]
@@ -19,9 +34,12 @@ pub struct BreadcrumbItem {
     id: ElementId,
     style: StyleRefinement,
     text: SharedString,
-    on_click: Option<Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>>,
+    icon: Option<Icon>,
+    on_click: Option<OnClick>,
     disabled: bool,
+    ix: usize,
     is_last: bool,
+    on_select: Option<OnSelect>,
 }
 
 impl BreadcrumbItem {
@@ -30,17 +48,27 @@ impl BreadcrumbItem {
             id: id.into(),
             style: StyleRefinement::default(),
             text: text.into(),
+            icon: None,
             on_click: None,
             disabled: false,
+            ix: 0,
             is_last: false,
+            on_select: None,
         }
     }
 
+    /// Set the icon to show before the text.
+    pub fn icon(mut self, icon: impl Into<Icon>) -> Self {
+        self.icon = Some(icon.into());
+        self
+    }
+
     pub fn disabled(mut self, disabled: bool) -> Self {
         self.disabled = disabled;
         self
     }
 
+    /// Set the click handler, the last item is not clickable.
     pub fn on_click(
         mut self,
         on_click: impl Fn(&ClickEvent, &mut Window, &mut App) + 'static,
@@ -54,6 +82,13 @@ impl BreadcrumbItem {
         self.is_last = is_last;
         self
     }
+
+    /// For internal use only.
+    fn on_select(mut self, ix: usize, on_select: Option<OnSelect>) -> Self {
+        self.ix = ix;
+        self.on_select = on_select;
+        self
+    }
 }
 
 impl Styled for BreadcrumbItem {
@@ -64,8 +99,16 @@ impl Styled for BreadcrumbItem {
 
 impl RenderOnce for BreadcrumbItem {
     fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
-        div()
+        let ix = self.ix;
+        let hover_color = cx.theme().foreground;
+        let clickable = !self.is_last
+            && !self.disabled
+            && (self.on_click.is_some() || self.on_select.is_some());
+
+        h_flex()
             .id(self.id)
+            .gap_1()
+            .when_some(self.icon, |this, icon| this.child(icon.size_3p5()))
             .child(self.text)
             .text_color(cx.theme().muted_foreground)
             .when(self.is_last, |this| this.text_color(cx.theme().foreground))
@@ -73,12 +116,19 @@ impl RenderOnce for BreadcrumbItem {
                 this.text_color(cx.theme().muted_foreground)
             })
             .refine_style(&self.style)
-            .when(!self.disabled, |this| {
-                this.when_some(self.on_click, |this, on_click| {
-                    this.cursor_pointer().on_click(move |event, window, cx| {
-                        on_click(event, window, cx);
+            .when(clickable, |this| {
+                let on_click = self.on_click;
+                let on_select = self.on_select;
+                this.cursor_pointer()
+                    .hover(|this| this.text_color(hover_color))
+                    .on_click(move |event, window, cx| {
+                        if let Some(on_click) = &on_click {
+                            on_click(event, window, cx);
+                        }
+                        if let Some(on_select) = &on_select {
+                            on_select(&ix, window, cx);
+                        }
                     })
-                })
             })
     }
 }
@@ -86,26 +136,201 @@ impl RenderOnce for BreadcrumbItem {
 impl Breadcrumb {
     pub fn new() -> Self {
         Self {
+            id: "breadcrumb".into(),
             items: Vec::new(),
             style: StyleRefinement::default(),
+            separator: BreadcrumbSeparator::default(),
+            max_visible_items: None,
+            on_click: None,
         }
     }
 
+    /// Set the id of the breadcrumb, default is `"breadcrumb"`.
+    ///
+    /// The measured widths and the ellipsis menu of the collapsed items are identified by
+    /// it, so set a unique id for each breadcrumb in the same view.
+    pub fn id(mut self, id: impl Into<ElementId>) -> Self {
+        self.id = id.into();
+        self
+    }
+
     /// Add an item to the breadcrumb.
     pub fn item(mut self, item: BreadcrumbItem) -> Self {
         self.items.push(item);
         self
     }
+
+    /// Set the separator between the items, default is [`BreadcrumbSeparator::Chevron`].
+    pub fn separator(mut self, separator: impl Into<BreadcrumbSeparator>) -> Self {
+        self.separator = separator.into();
+        self
+    }
+
+    /// Set the max number of visible items, default: None (no limit).
+    ///
+    /// The middle items are collapsed into an ellipsis menu when the items do not fit the
+    /// available width, this also collapses them when there are more items than the count.
+    /// The first and last items are always visible.
+    pub fn max_visible_items(mut self, max_visible_items: usize) -> Self {
+        self.max_visible_items = Some(max_visible_items);
+        self
+    }
+
+    /// Set the click handler of the items, the argument is the index of the clicked item.
+    ///
+    /// The last item is the current page, so it is not clickable.
+    pub fn on_click(mut self, on_click: impl Fn(&usize, &mut Window, &mut App) + 'static) -> Self {
+        self.on_click = Some(Rc::new(on_click));
+        self
+    }
+}
+
+/// Returns the range of the items to collapse by the max count, the first and last items are
+/// always visible.
+fn collapsed_range(items_count: usize, max_visible_items: Option<usize>) -> Option<Range<usize>> {
+    let max_visible_items = max_visible_items?.max(2);
+    if items_count <= max_visible_items {
+        return None;
+    }
+
+    // Keep the first item and the last `max_visible_items - 1` items.
+    Some(1..items_count - (max_visible_items - 1))
+}
+
+/// The measured widths to collapse the items to fit, see [`fit_collapsed_range`].
+#[derive(Default)]
+struct BreadcrumbState {
+    /// The available width of the breadcrumb.
+    width: Option<Pixels>,
+    /// The widths of the items by id, measured while they are visible.
+    item_widths: HashMap<ElementId, Pixels>,
+    separator_width: Option<Pixels>,
+    ellipsis_width: Option<Pixels>,
+}
+
+/// Returns the range of the items to collapse to fit the `available` width, at least the
+/// `max_range` of the max count. The `joint` is the width between two items, with the
+/// separator and the gaps.
+///
+/// The items are shown as is until the widths of the candidate items are measured.
+fn fit_collapsed_range(
+    widths: &[Option<Pixels>],
+    max_range: Option<Range<usize>>,
+    available: Pixels,
+    joint: Pixels,
+    ellipsis: Pixels,
+) -> Option<Range<usize>> {
+    let items_count = widths.len();
+    if items_count <= 2 {
+        return max_range;
+    }
+
+    // The first item and the items after the max range are the candidates to show.
+    let first_candidate = max_range.as_ref().map_or(1, |range| range.end);
+    let Some(candidates) = std::iter::once(widths[0])
+        .chain(widths[first_candidate..].iter().copied())
+        .collect::<Option<Vec<_>>>()
+    else {
+        return max_range;
+    };
+
+    if max_range.is_none() {
+        let total = candidates
+            .iter()
+            .fold(px(0.), |total, width| total + *width)
+            + joint * (items_count - 1) as f32;
+        if total <= available {
+            return None;
+        }
+    }
+
+    // Keep the first item, the ellipsis and as many of the last items as fit.
+    let mut used = candidates[0] + joint + ellipsis + joint + candidates[candidates.len() - 1];
+    let mut keep = 1;
+    let max_keep = (items_count - first_candidate).min(items_count - 2);
+    while keep < max_keep {
+        let next = used + candidates[candidates.len() - 1 - keep] + joint;
+        if next > available {
+            break;
+        }
+        used = next;
+        keep += 1;
+    }
+
+    Some(1..items_count - keep)
+}
+
+/// Returns an element to measure the width of its parent and store it by `update`, the
+/// breadcrumb is rendered again if the width is changed.
+fn measure_width(
+    state: &Entity<BreadcrumbState>,
+    update: impl Fn(&mut BreadcrumbState, Pixels) -> bool + 'static,
+) -> impl IntoElement {
+    let state = state.clone();
+    canvas(
+        move |bounds, _, cx| {
+            state.update(cx, |state, cx| {
+                if update(state, bounds.size.width) {
+                    cx.notify();
+                }
+            })
+        },
+        |_, _, _, _| {},
+    )
+    .absolute()
+    .size_full()
+}
+
+/// Sets the width to the slot, returns true if it is changed.
+fn set_width(slot: &mut Option<Pixels>, width: Pixels) -> bool {
+    slot.replace(width) != Some(width)
+}
+
+/// The separator between the breadcrumb items.
+#[derive(IntoElement, Clone, Default)]
+pub enum BreadcrumbSeparator {
+    /// The `>` chevron icon.
+    #[default]
+    Chevron,
+    /// The `/` slash text.
+    Slash,
+    /// A custom icon.
+    Icon(Icon),
+    /// A custom text.
+    Text(SharedString),
+}
+
+impl From<IconName> for BreadcrumbSeparator {
+    fn from(icon: IconName) -> Self {
+        Self::Icon(icon.into())
+    }
+}
+
+impl From<Icon> for BreadcrumbSeparator {
+    fn from(icon: Icon) -> Self {
+        Self::Icon(icon)
+    }
+}
+
+impl From<&'static str> for BreadcrumbSeparator {
+    fn from(text: &'static str) -> Self {
+        Self::Text(text.into())
+    }
 }
 
-#[derive(IntoElement)]
-struct BreadcrumbSeparator;
 impl RenderOnce for BreadcrumbSeparator {
     fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
-        Icon::new(IconName::ChevronRight)
-            .text_color(cx.theme().muted_foreground)
-            .size_3p5()
-            .into_any_element()
+        let color = cx.theme().muted_foreground;
+
+        match self {
+            Self::Chevron => Icon::new(IconName::ChevronRight)
+                .text_color(color)
+                .size_3p5()
+                .into_any_element(),
+            Self::Icon(icon) => icon.text_color(color).size_3p5().into_any_element(),
+            Self::Slash => div().text_color(color).child("/").into_any_element(),
+            Self::Text(text) => div().text_color(color).child(text).into_any_element(),
+        }
     }
 }
 
@@ -116,24 +341,191 @@ impl Styled for Breadcrumb {
 }
 
 impl RenderOnce for Breadcrumb {
-    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
         let items_count = self.items.len();
+        let state = window.use_keyed_state(self.id.clone(), cx, |_, _| BreadcrumbState::default());
+        state.update(cx, |state, _| {
+            let ids = self.items.iter().map(|item| &item.id).collect::<Vec<_>>();
+            state.item_widths.retain(|id, _| ids.contains(&id));
+        });
+
+        let max_range = collapsed_range(items_count, self.max_visible_items);
+        let collapsed_range = {
+            let state = state.read(cx);
+            match (state.width, state.separator_width) {
+                (Some(width), Some(separator_width)) => {
+                    let widths = self
+                        .items
+                        .iter()
+                        .map(|item| state.item_widths.get(&item.id).copied())
+                        .collect::<Vec<_>>();
+                    // The gap of `gap_1p5` on both sides of the separator.
+                    let joint = separator_width + window.rem_size() * 0.375 * 2.;
+                    let ellipsis_width = state.ellipsis_width.unwrap_or(ELLIPSIS_WIDTH);
+                    fit_collapsed_range(&widths, max_range, width, joint, ellipsis_width)
+                }
+                _ => max_range,
+            }
+        };
+        let ellipsis_id = ElementId::Name(format!("{}-ellipsis", self.id).into());
+        let on_click = self.on_click;
+
+        let separator = |separator: &BreadcrumbSeparator| {
+            div()
+                .relative()
+                .flex_shrink_0()
+                .child(separator.clone())
+                .child(measure_width(&state, |state, width| {
+                    set_width(&mut state.separator_width, width)
+                }))
+                .into_any_element()
+        };
 
         let mut children = vec![];
+        let mut collapsed_items = vec![];
         for (ix, item) in self.items.into_iter().enumerate() {
             let is_last = ix == items_count - 1;
 
-            children.push(item.is_last(is_last).into_any_element());
+            if let Some(range) = collapsed_range.as_ref().filter(|range| range.contains(&ix)) {
+                collapsed_items.push((ix, item));
+                if ix + 1 == range.end {
+                    children.push(
+                        div()
+                            .relative()
+                            .flex_shrink_0()
+                            .child(collapsed_menu(
+                                ellipsis_id.clone(),
+                                std::mem::take(&mut collapsed_items),
+                                on_click.clone(),
+                            ))
+                            .child(measure_width(&state, |state, width| {
+                                set_width(&mut state.ellipsis_width, width)
+                            }))
+                            .into_any_element(),
+                    );
+                    children.push(separator(&self.separator));
+                }
+                continue;
+            }
+
+            let id = item.id.clone();
+            children.push(
+                div()
+                    .relative()
+                    .flex_shrink_0()
+                    .whitespace_nowrap()
+                    .child(item.on_select(ix, on_click.clone()).is_last(is_last))
+                    .child(measure_width(&state, move |state, width| {
+                        state.item_widths.insert(id.clone(), width) != Some(width)
+                    }))
+                    .into_any_element(),
+            );
             if !is_last {
-                children.push(BreadcrumbSeparator.into_any_element());
+                children.push(separator(&self.separator));
             }
         }
 
         h_flex()
+            .relative()
+            .w_full()
+            .min_w_0()
+            .overflow_hidden()
             .gap_1p5()
             .text_sm()
             .text_color(cx.theme().muted_foreground)
             .refine_style(&self.style)
             .children(children)
+            .child(measure_width(&state, |state, width| {
+                set_width(&mut state.width, width)
+            }))
+    }
+}
+
+/// Render the collapsed items into an ellipsis dropdown menu, the disabled items are shown
+/// as the disabled menu items to keep the path.
+fn collapsed_menu(
+    id: ElementId,
+    items: Vec<(usize, BreadcrumbItem)>,
+    on_select: Option<OnSelect>,
+) -> impl IntoElement {
+    let items = Rc::new(
+        items
+            .into_iter()
+            .map(|(ix, item)| (ix, item.text, item.icon, item.on_click, item.disabled))
+            .collect::<Vec<_>>(),
+    );
+
+    Button::new(id)
+        .ghost()
+        .xsmall()
+        .icon(IconName::Ellipsis)
+        .popup_menu(move |mut menu, _, _| {
+            for (ix, text, icon, on_click, disabled) in items.iter() {
+                let ix = *ix;
+                let disabled = *disabled;
+                let on_click = on_click.clone();
+                let on_select = on_select.clone();
+                menu = menu.menu_with_handler_and_disabled(
+                    text.clone(),
+                    icon.clone(),
+                    move |window, cx| {
+                        if disabled {
+                            return;
+                        }
+                        if let Some(on_click) = &on_click {
+                            on_click(&ClickEvent::default(), window, cx);
+                        }
+                        if let Some(on_select) = &on_select {
+                            on_select(&ix, window, cx);
+                        }
+                    },
+                    disabled,
+                );
+            }
+            menu
+        })
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::px;
+
+    use super::{collapsed_range, fit_collapsed_range};
+
+    #[test]
+    fn test_collapsed_range() {
+        assert_eq!(collapsed_range(5, None), None);
+        assert_eq!(collapsed_range(3, Some(3)), None);
+        assert_eq!(collapsed_range(5, Some(3)), Some(1..3));
+        assert_eq!(collapsed_range(6, Some(4)), Some(1..3));
+        // At least keep the first and last items.
+        assert_eq!(collapsed_range(4, Some(0)), Some(1..3));
+        assert_eq!(collapsed_range(2, Some(1)), None);
+    }
+
+    #[test]
+    fn test_fit_collapsed_range() {
+        let widths = [Some(px(50.)); 5];
+        let fit = |widths: &[_], max_range, available| {
+            fit_collapsed_range(widths, max_range, px(available), px(10.), px(20.))
+        };
+
+        assert_eq!(fit(&widths, None, 300.), None);
+        assert_eq!(fit(&widths, None, 200.), Some(1..3));
+        // At least keep the first and last items.
+        assert_eq!(fit(&widths, None, 100.), Some(1..4));
+        assert_eq!(fit(&widths[..2], None, 10.), None);
+
+        // Shown as is until measured.
+        let mut unmeasured = widths;
+        unmeasured[2] = None;
+        assert_eq!(fit(&unmeasured, None, 100.), None);
+
+        // The max count is a cap, the collapsed items need not be measured.
+        let mut capped = widths;
+        capped[1] = None;
+        capped[2] = None;
+        assert_eq!(fit(&capped, collapsed_range(5, Some(3)), 1000.), Some(1..3));
+        assert_eq!(fit(&capped, collapsed_range(5, Some(3)), 150.), Some(1..4));
     }
 }
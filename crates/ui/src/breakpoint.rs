@@ -0,0 +1,179 @@
+use gpui::{px, App, Global, Pixels, Window};
+
+pub(crate) fn init(cx: &mut App) {
+    cx.set_global(Breakpoints::default());
+}
+
+/// The breakpoint of the window width, from the narrowest to the widest.
+///
+/// A breakpoint is active from its min width in the [`Breakpoints`], so compare them
+/// to adapt the layout, e.g.: `window.breakpoint(cx) < Breakpoint::Md` for a narrow window.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, PartialOrd, Ord, Hash)]
+pub enum Breakpoint {
+    /// Narrower than the `sm` width.
+    Xs,
+    Sm,
+    Md,
+    Lg,
+    Xl,
+}
+
+/// The min widths of the [`Breakpoint`]s, default is the same as Tailwind CSS.
+///
+/// Use [`Breakpoints::global_mut`] to configure them.
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub struct Breakpoints {
+    pub sm: Pixels,
+    pub md: Pixels,
+    pub lg: Pixels,
+    pub xl: Pixels,
+}
+
+impl Default for Breakpoints {
+    fn default() -> Self {
+        Self {
+            sm: px(640.),
+            md: px(768.),
+            lg: px(1024.),
+            xl: px(1280.),
+        }
+    }
+}
+
+impl Global for Breakpoints {}
+
+impl Breakpoints {
+    /// Returns the global breakpoints.
+    #[inline(always)]
+    pub fn global(cx: &App) -> &Self {
+        cx.global::<Self>()
+    }
+
+    /// Returns the global breakpoints mutable reference.
+    #[inline(always)]
+    pub fn global_mut(cx: &mut App) -> &mut Self {
+        cx.global_mut::<Self>()
+    }
+
+    /// Returns the breakpoint of the `width`.
+    pub fn breakpoint(&self, width: Pixels) -> Breakpoint {
+        if width >= self.xl {
+            Breakpoint::Xl
+        } else if width >= self.lg {
+            Breakpoint::Lg
+        } else if width >= self.md {
+            Breakpoint::Md
+        } else if width >= self.sm {
+            Breakpoint::Sm
+        } else {
+            Breakpoint::Xs
+        }
+    }
+}
+
+/// Extends the [`Window`] to get the [`Breakpoint`] of the window width.
+pub trait WindowBreakpointExt {
+    /// Returns the breakpoint of the window width.
+    ///
+    /// The window is rendered again on resize, so read it in the `render` to adapt the layout,
+    /// the cached views are not rendered again, see [`gpui::AnyView::cached`].
+    fn breakpoint(&self, cx: &App) -> Breakpoint;
+}
+
+impl WindowBreakpointExt for Window {
+    fn breakpoint(&self, cx: &App) -> Breakpoint {
+        Breakpoints::global(cx).breakpoint(self.viewport_size().width)
+    }
+}
+
+/// A value that depends on the [`Breakpoint`] of the window width, like the responsive
+/// styles of Tailwind CSS, the value of a breakpoint is used from it to the wider ones.
+///
+/// ```ignore
+/// let columns = Responsive::new(1).md(2).xl(4).resolve(window, cx);
+/// ```
+#[derive(Debug, Clone)]
+pub struct Responsive<T> {
+    base: T,
+    values: Vec<(Breakpoint, T)>,
+}
+
+impl<T> Responsive<T> {
+    /// Create a responsive value, the `base` is used if no breakpoint value matches.
+    pub fn new(base: T) -> Self {
+        Self {
+            base,
+            values: vec![],
+        }
+    }
+
+    /// Set the value from the `breakpoint`.
+    pub fn at(mut self, breakpoint: Breakpoint, value: T) -> Self {
+        self.values.retain(|(bp, _)| *bp != breakpoint);
+        self.values.push((breakpoint, value));
+        self
+    }
+
+    /// Set the value from the [`Breakpoint::Sm`].
+    pub fn sm(self, value: T) -> Self {
+        self.at(Breakpoint::Sm, value)
+    }
+
+    /// Set the value from the [`Breakpoint::Md`].
+    pub fn md(self, value: T) -> Self {
+        self.at(Breakpoint::Md, value)
+    }
+
+    /// Set the value from the [`Breakpoint::Lg`].
+    pub fn lg(self, value: T) -> Self {
+        self.at(Breakpoint::Lg, value)
+    }
+
+    /// Set the value from the [`Breakpoint::Xl`].
+    pub fn xl(self, value: T) -> Self {
+        self.at(Breakpoint::Xl, value)
+    }
+
+    /// Returns the value of the widest breakpoint that is not wider than the `breakpoint`.
+    pub fn get(&self, breakpoint: Breakpoint) -> &T {
+        self.values
+            .iter()
+            .filter(|(bp, _)| *bp <= breakpoint)
+            .max_by_key(|(bp, _)| *bp)
+            .map(|(_, value)| value)
+            .unwrap_or(&self.base)
+    }
+
+    /// Returns the value for the current window width.
+    pub fn resolve(&self, window: &Window, cx: &App) -> &T {
+        self.get(window.breakpoint(cx))
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::px;
+
+    use super::{Breakpoint, Breakpoints, Responsive};
+
+    #[test]
+    fn test_breakpoint() {
+        let breakpoints = Breakpoints::default();
+        assert_eq!(breakpoints.breakpoint(px(320.)), Breakpoint::Xs);
+        assert_eq!(breakpoints.breakpoint(px(640.)), Breakpoint::Sm);
+        assert_eq!(breakpoints.breakpoint(px(1000.)), Breakpoint::Md);
+        assert_eq!(breakpoints.breakpoint(px(1024.)), Breakpoint::Lg);
+        assert_eq!(breakpoints.breakpoint(px(1920.)), Breakpoint::Xl);
+        assert!(Breakpoint::Sm < Breakpoint::Md);
+    }
+
+    #[test]
+    fn test_responsive() {
+        let value = Responsive::new(1).xl(4).md(2).md(3);
+        assert_eq!(*value.get(Breakpoint::Xs), 1);
+        assert_eq!(*value.get(Breakpoint::Sm), 1);
+        assert_eq!(*value.get(Breakpoint::Md), 3);
+        assert_eq!(*value.get(Breakpoint::Lg), 3);
+        assert_eq!(*value.get(Breakpoint::Xl), 4);
+    }
+}
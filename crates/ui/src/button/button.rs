@@ -27,6 +27,35 @@ impl From<Pixels> for ButtonRounded {
     }
 }
 
+/// The style overrides of a [`Button`] instance, see [`Button::style_overrides`].
+///
+/// The `None` fields use the values of the [`ButtonVariant`] and the theme:
+///
+/// ```ignore
+/// Button::new("save").style_overrides(ButtonStyle {
+///     bg: Some(cx.theme().accent),
+///     radius: Some(px(0.)),
+///     ..Default::default()
+/// })
+/// ```
+#[derive(Debug, Default, Clone, Copy, PartialEq)]
+pub struct ButtonStyle {
+    /// The background of the button.
+    pub bg: Option<Hsla>,
+    /// The text color of the button.
+    pub fg: Option<Hsla>,
+    /// The border color of the button.
+    pub border: Option<Hsla>,
+    /// The background when hovered, default is the `bg` if set.
+    pub hover_bg: Option<Hsla>,
+    /// The background when pressed or selected, default is the `hover_bg` or `bg` if set.
+    pub active_bg: Option<Hsla>,
+    /// The radius of the button, this takes precedence over the [`ButtonRounded`].
+    pub radius: Option<Pixels>,
+    /// The padding of the button, default is by the size of the button.
+    pub padding: Option<Edges<Pixels>>,
+}
+
 #[derive(Clone, Copy, PartialEq, Eq)]
 pub struct ButtonCustomVariant {
     color: Hsla,
@@ -190,6 +219,7 @@ pub struct Button {
     pub(crate) selected: bool,
     variant: ButtonVariant,
     rounded: ButtonRounded,
+    style_overrides: ButtonStyle,
     outline: bool,
     border_corners: Corners<bool>,
     border_edges: Edges<bool>,
@@ -222,6 +252,7 @@ impl Button {
             selected: false,
             variant: ButtonVariant::default(),
             rounded: ButtonRounded::Medium,
+            style_overrides: ButtonStyle::default(),
             border_corners: Corners::all(true),
             border_edges: Edges::all(true),
             size: Size::Medium,
@@ -248,6 +279,13 @@ impl Button {
         self
     }
 
+    /// Set the style overrides of the Button, the `None` fields of the `style` use the
+    /// values of the variant.
+    pub fn style_overrides(mut self, style: ButtonStyle) -> Self {
+        self.style_overrides = style;
+        self
+    }
+
     /// Set the border corners side of the Button.
     pub(crate) fn border_corners(mut self, corners: impl Into<Corners<bool>>) -> Self {
         self.border_corners = corners.into();
@@ -378,7 +416,12 @@ impl InteractiveElement for Button {
 impl RenderOnce for Button {
     fn render(self, _window: &mut Window, cx: &mut App) -> impl IntoElement {
         let style: ButtonVariant = self.variant;
-        let normal_style = style.normal(self.outline, cx);
+        let overrides = self.style_overrides;
+        let active_bg = overrides.active_bg.or(overrides.hover_bg).or(overrides.bg);
+        let normal_style = style
+            .normal(self.outline, cx)
+            .merge(overrides.bg, &overrides);
+        let radius = overrides.radius.unwrap_or(cx.theme().radius);
         let icon_size = match self.size {
             Size::Size(v) => Size::Size(v * 0.75),
             _ => self.size,
@@ -414,12 +457,19 @@ impl RenderOnce for Button {
                     }
                 }
             })
+            .when_some(overrides.padding, |this, padding| {
+                this.pl(padding.left)
+                    .pr(padding.right)
+                    .pt(padding.top)
+                    .pb(padding.bottom)
+            })
             .when(
                 self.border_corners.top_left && self.border_corners.bottom_left,
                 |this| match self.rounded {
-                    ButtonRounded::Small => this.rounded_l(cx.theme().radius * 0.5),
-                    ButtonRounded::Medium => this.rounded_l(cx.theme().radius),
-                    ButtonRounded::Large => this.rounded_l(cx.theme().radius * 2.0),
+                    _ if overrides.radius.is_some() => this.rounded_l(radius),
+                    ButtonRounded::Small => this.rounded_l(radius * 0.5),
+                    ButtonRounded::Medium => this.rounded_l(radius),
+                    ButtonRounded::Large => this.rounded_l(radius * 2.0),
                     ButtonRounded::Size(px) => this.rounded_l(px),
                     ButtonRounded::None => this.rounded_none(),
                 },
@@ -427,9 +477,10 @@ impl RenderOnce for Button {
             .when(
                 self.border_corners.top_right && self.border_corners.bottom_right,
                 |this| match self.rounded {
-                    ButtonRounded::Small => this.rounded_r(cx.theme().radius * 0.5),
-                    ButtonRounded::Medium => this.rounded_r(cx.theme().radius),
-                    ButtonRounded::Large => this.rounded_r(cx.theme().radius * 2.0),
+                    _ if overrides.radius.is_some() => this.rounded_r(radius),
+                    ButtonRounded::Small => this.rounded_r(radius * 0.5),
+                    ButtonRounded::Medium => this.rounded_r(radius),
+                    ButtonRounded::Large => this.rounded_r(radius * 2.0),
                     ButtonRounded::Size(px) => this.rounded_r(px),
                     ButtonRounded::None => this.rounded_none(),
                 },
@@ -440,7 +491,9 @@ impl RenderOnce for Button {
             .when(self.border_edges.bottom, |this| this.border_b_1())
             .text_color(normal_style.fg)
             .when(self.selected, |this| {
-                let selected_style = style.selected(self.outline, cx);
+                let selected_style = style
+                    .selected(self.outline, cx)
+                    .merge(active_bg, &overrides);
                 this.bg(selected_style.bg)
                     .border_color(selected_style.border)
                     .text_color(selected_style.fg)
@@ -450,13 +503,16 @@ impl RenderOnce for Button {
                     .bg(normal_style.bg)
                     .when(normal_style.underline, |this| this.text_decoration_1())
                     .hover(|this| {
-                        let hover_style = style.hovered(self.outline, cx);
+                        let hover_style = style
+                            .hovered(self.outline, cx)
+                            .merge(overrides.hover_bg.or(overrides.bg), &overrides);
                         this.bg(hover_style.bg)
                             .border_color(hover_style.border)
-                            .text_color(crate::red_400())
+                            .text_color(hover_style.fg)
                     })
                     .active(|this| {
-                        let active_style = style.active(self.outline, cx);
+                        let active_style =
+                            style.active(self.outline, cx).merge(active_bg, &overrides);
                         this.bg(active_style.bg)
                             .border_color(active_style.border)
                             .text_color(active_style.fg)
@@ -541,6 +597,18 @@ struct ButtonVariantStyle {
     shadow: bool,
 }
 
+impl ButtonVariantStyle {
+    /// Merge the [`ButtonStyle`] overrides with the `bg` of the current state.
+    fn merge(self, bg: Option<Hsla>, overrides: &ButtonStyle) -> Self {
+        Self {
+            bg: bg.unwrap_or(self.bg),
+            fg: overrides.fg.unwrap_or(self.fg),
+            border: overrides.border.unwrap_or(self.border),
+            ..self
+        }
+    }
+}
+
 impl ButtonVariant {
     fn bg_color(&self, outline: bool, cx: &mut App) -> Hsla {
         if outline {
@@ -903,3 +971,74 @@ impl ButtonVariant {
         }
     }
 }
+
+#[cfg(test)]
+mod tests {
+    use gpui::{
+        px, size, InteractiveElement as _, ParentElement as _, Styled as _, TestAppContext,
+    };
+
+    use super::{Button, ButtonVariants as _};
+    use crate::{
+        h_flex,
+        test_support::{bounds, init_test, layout_snapshot, mount},
+        IconName, Sizable as _, ThemeMode,
+    };
+
+    #[gpui::test]
+    fn test_button_size(cx: &mut TestAppContext) {
+        init_test(cx);
+        let cx = mount(cx, ThemeMode::Light, |_, _| {
+            h_flex()
+                .gap_2()
+                .child(
+                    Button::new("medium")
+                        .label("OK")
+                        .debug_selector(|| "medium".into()),
+                )
+                .child(
+                    Button::new("small")
+                        .small()
+                        .label("OK")
+                        .debug_selector(|| "small".into()),
+                )
+                .child(
+                    Button::new("icon")
+                        .icon(IconName::Check)
+                        .debug_selector(|| "icon".into()),
+                )
+        });
+
+        assert_eq!(bounds(cx, "medium").size.height, px(32.));
+        assert_eq!(bounds(cx, "small").size.height, px(24.));
+        assert_eq!(bounds(cx, "icon").size, size(px(32.), px(32.)));
+    }
+
+    #[gpui::test]
+    fn test_button_layout_across_themes(cx: &mut TestAppContext) {
+        init_test(cx);
+
+        let snapshots = [ThemeMode::Light, ThemeMode::Dark].map(|mode| {
+            let cx = mount(cx, mode, |_, _| {
+                h_flex()
+                    .gap_2()
+                    .child(
+                        Button::new("primary")
+                            .primary()
+                            .label("Primary")
+                            .debug_selector(|| "primary".into()),
+                    )
+                    .child(
+                        Button::new("outline")
+                            .outline()
+                            .label("Outline")
+                            .debug_selector(|| "outline".into()),
+                    )
+            });
+            layout_snapshot(cx, &["primary", "outline"])
+        });
+
+        assert!(!snapshots[0].contains("none"));
+        assert_eq!(snapshots[0], snapshots[1]);
+    }
+}
@@ -0,0 +1,242 @@
+use std::rc::Rc;
+
+use gpui::{
+    div, prelude::FluentBuilder as _, px, relative, AnyElement, App, ClickEvent, ElementId,
+    InteractiveElement as _, IntoElement, ParentElement, Pixels, RenderOnce,
+    StatefulInteractiveElement as _, StyleRefinement, Styled, Window,
+};
+use smallvec::SmallVec;
+
+use crate::{
+    actions::Confirm, h_flex, keymap::ContextBindings, v_flex, ActiveTheme, Disableable,
+    StyledExt as _,
+};
+
+const CONTEXT: &str = "Card";
+
+pub(crate) fn init(cx: &mut App) {
+    ContextBindings::new(CONTEXT)
+        .bind("enter", Confirm { secondary: false })
+        .bind("space", Confirm { secondary: false })
+        .register(cx);
+}
+
+/// A container to group the related content, with the optional media, header and footer.
+///
+/// The body is the children of the card, it fills the rest of the height between the header
+/// and the footer, so the card can be sized by the parent, e.g.: in a [`crate::grid::Grid`].
+///
+/// Set [`Card::on_click`] to make it interactive with the hover and pressed states, it can be
+/// focused and clicked by pressing `Enter` or `Space`.
+#[derive(IntoElement)]
+pub struct Card {
+    id: ElementId,
+    style: StyleRefinement,
+    media: Option<AnyElement>,
+    title: Option<AnyElement>,
+    subtitle: Option<AnyElement>,
+    actions: SmallVec<[AnyElement; 2]>,
+    children: SmallVec<[AnyElement; 2]>,
+    footer: Option<AnyElement>,
+    padding: Pixels,
+    bordered: bool,
+    shadow: bool,
+    disabled: bool,
+    on_click: Option<Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>>,
+}
+
+impl Card {
+    pub fn new(id: impl Into<ElementId>) -> Self {
+        Self {
+            id: id.into(),
+            style: StyleRefinement::default(),
+            media: None,
+            title: None,
+            subtitle: None,
+            actions: SmallVec::new(),
+            children: SmallVec::new(),
+            footer: None,
+            padding: px(16.),
+            bordered: true,
+            shadow: true,
+            disabled: false,
+            on_click: None,
+        }
+    }
+
+    /// Set the media at the top of the card without the padding, e.g.: an image.
+    pub fn media(mut self, media: impl IntoElement) -> Self {
+        self.media = Some(media.into_any_element());
+        self
+    }
+
+    /// Set the title of the header.
+    pub fn title(mut self, title: impl IntoElement) -> Self {
+        self.title = Some(title.into_any_element());
+        self
+    }
+
+    /// Set the subtitle under the title of the header.
+    pub fn subtitle(mut self, subtitle: impl IntoElement) -> Self {
+        self.subtitle = Some(subtitle.into_any_element());
+        self
+    }
+
+    /// Add an action at the trailing of the header, e.g.: a ghost button with a menu.
+    pub fn action(mut self, action: impl IntoElement) -> Self {
+        self.actions.push(action.into_any_element());
+        self
+    }
+
+    /// Set the footer of the card, e.g.: the buttons.
+    pub fn footer(mut self, footer: impl IntoElement) -> Self {
+        self.footer = Some(footer.into_any_element());
+        self
+    }
+
+    /// Set the padding of the header, body and footer, default is 16px.
+    pub fn padding(mut self, padding: impl Into<Pixels>) -> Self {
+        self.padding = padding.into();
+        self
+    }
+
+    /// Set whether to show the border, default is true.
+    pub fn bordered(mut self, bordered: bool) -> Self {
+        self.bordered = bordered;
+        self
+    }
+
+    /// Set whether to show the shadow if the theme has the shadow, default is true.
+    pub fn shadow(mut self, shadow: bool) -> Self {
+        self.shadow = shadow;
+        self
+    }
+
+    /// Set the click handler to make the card interactive.
+    pub fn on_click(
+        mut self,
+        handler: impl Fn(&ClickEvent, &mut Window, &mut App) + 'static,
+    ) -> Self {
+        self.on_click = Some(Rc::new(handler));
+        self
+    }
+}
+
+impl Disableable for Card {
+    fn disabled(mut self, disabled: bool) -> Self {
+        self.disabled = disabled;
+        self
+    }
+}
+
+impl ParentElement for Card {
+    fn extend(&mut self, elements: impl IntoIterator<Item = AnyElement>) {
+        self.children.extend(elements);
+    }
+}
+
+impl Styled for Card {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl RenderOnce for Card {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let on_click = self.on_click.filter(|_| !self.disabled);
+        let focus_handle = window
+            .use_keyed_state(self.id.clone(), cx, |_, cx| cx.focus_handle())
+            .read(cx)
+            .clone();
+        let focused = on_click.is_some() && focus_handle.is_focused(window);
+        let has_header =
+            self.title.is_some() || self.subtitle.is_some() || !self.actions.is_empty();
+        let (hover_bg, active_bg) = (cx.theme().secondary_hover, cx.theme().secondary_active);
+
+        v_flex()
+            .id(self.id)
+            .overflow_hidden()
+            .bg(cx.theme().background)
+            .text_color(cx.theme().foreground)
+            .rounded(cx.theme().radius_lg)
+            .when(self.bordered, |this| {
+                this.border_1().border_color(cx.theme().border)
+            })
+            .when(self.shadow && cx.theme().shadow, |this| this.shadow_sm())
+            .when(self.disabled, |this| this.opacity(0.5))
+            .when_some(on_click, |this, on_click| {
+                this.key_context(CONTEXT)
+                    .track_focus(&focus_handle)
+                    .cursor_pointer()
+                    .hover(|this| this.bg(hover_bg))
+                    .active(|this| this.bg(active_bg))
+                    .when(focused, |this| this.focused_border(cx))
+                    .on_action({
+                        let on_click = on_click.clone();
+                        move |_: &Confirm, window, cx| {
+                            on_click(&ClickEvent::default(), window, cx);
+                        }
+                    })
+                    .on_click(move |event, window, cx| on_click(event, window, cx))
+            })
+            .refine_style(&self.style)
+            .when_some(self.media, |this, media| {
+                this.child(div().flex_shrink_0().overflow_hidden().child(media))
+            })
+            .child(
+                v_flex()
+                    .flex_1()
+                    .min_h_0()
+                    .p(self.padding)
+                    .gap_3()
+                    .when(has_header, |this| {
+                        this.child(
+                            h_flex()
+                                .flex_shrink_0()
+                                .items_start()
+                                .gap_3()
+                                .child(
+                                    v_flex()
+                                        .flex_1()
+                                        .min_w_0()
+                                        .gap_1()
+                                        .when_some(self.title, |this, title| {
+                                            this.child(
+                                                div()
+                                                    .font_semibold()
+                                                    .line_height(relative(1.25))
+                                                    .child(title),
+                                            )
+                                        })
+                                        .when_some(self.subtitle, |this, subtitle| {
+                                            this.child(
+                                                div()
+                                                    .text_sm()
+                                                    .text_color(cx.theme().muted_foreground)
+                                                    .child(subtitle),
+                                            )
+                                        }),
+                                )
+                                .when(!self.actions.is_empty(), |this| {
+                                    this.child(
+                                        h_flex().flex_shrink_0().gap_1().children(self.actions),
+                                    )
+                                }),
+                        )
+                    })
+                    .when(!self.children.is_empty(), |this| {
+                        this.child(v_flex().flex_1().min_h_0().gap_2().children(self.children))
+                    })
+                    .when_some(self.footer, |this, footer| {
+                        this.child(
+                            h_flex()
+                                .flex_shrink_0()
+                                .mt_auto()
+                                .items_center()
+                                .gap_2()
+                                .child(footer),
+                        )
+                    }),
+            )
+    }
+}
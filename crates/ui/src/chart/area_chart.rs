@@ -5,6 +5,7 @@ use gpui_component_macros::IntoPlot;
 use num_traits::{Num, ToPrimitive};
 
 use crate::{
+    number_format::NumberFormat,
     plot::{
         scale::{Scale, ScaleLinear, ScalePoint, Sealed},
         shape::Area,
@@ -27,6 +28,7 @@ where
     stroke_style: StrokeStyle,
     fill: Vec<Background>,
     tick_margin: usize,
+    y_axis_format: Option<NumberFormat>,
 }
 
 impl<T, X, Y> AreaChart<T, X, Y>
@@ -46,6 +48,7 @@ where
             tick_margin: 1,
             x: None,
             y: vec![],
+            y_axis_format: None,
         }
     }
 
@@ -78,6 +81,13 @@ where
         self.tick_margin = tick_margin;
         self
     }
+
+    /// Label the grid lines of the y-axis with the values formatted by the `format`,
+    /// default is None (no labels).
+    pub fn y_axis_format(mut self, format: NumberFormat) -> Self {
+        self.y_axis_format = Some(format);
+        self
+    }
 }
 
 impl<T, X, Y> Plot for AreaChart<T, X, Y>
@@ -132,15 +142,17 @@ where
             }
         });
 
-        Axis::new()
-            .x(height)
-            .x_label(x_label)
-            .stroke(cx.theme().border)
-            .paint(&bounds, window, cx);
+        let grid_ticks = (0..=3).map(|i| height * i as f32 / 4.0).collect::<Vec<_>>();
+        let mut axis = Axis::new().x(height).x_label(x_label);
+        if let Some(format) = self.y_axis_format.as_ref() {
+            let y_label = AxisText::numbers(&y, &grid_ticks, format, cx.theme().muted_foreground);
+            axis = axis.y(px(0.)).y_label(y_label);
+        }
+        axis.stroke(cx.theme().border).paint(&bounds, window, cx);
 
         // Draw grid
         Grid::new()
-            .y((0..=3).map(|i| height * i as f32 / 4.0).collect())
+            .y(grid_ticks)
             .stroke(cx.theme().border)
             .dash_array(&[px(4.), px(2.)])
             .paint(&bounds, window);
@@ -5,6 +5,7 @@ use gpui_component_macros::IntoPlot;
 use num_traits::{Num, ToPrimitive};
 
 use crate::{
+    number_format::{format_number, NumberFormat},
     plot::{
         label::Text,
         scale::{Scale, ScaleBand, ScaleLinear, Sealed},
@@ -27,6 +28,8 @@ where
     fill: Option<Rc<dyn Fn(&T) -> Hsla>>,
     tick_margin: usize,
     label: Option<Rc<dyn Fn(&T) -> SharedString>>,
+    label_format: Option<NumberFormat>,
+    y_axis_format: Option<NumberFormat>,
 }
 
 impl<T, X, Y> BarChart<T, X, Y>
@@ -45,6 +48,8 @@ where
             fill: None,
             tick_margin: 1,
             label: None,
+            label_format: None,
+            y_axis_format: None,
         }
     }
 
@@ -78,6 +83,19 @@ where
         self.label = Some(Rc::new(move |t| label(t).into()));
         self
     }
+
+    /// Label the bars with the y values formatted by the `format`, if no [`BarChart::label`].
+    pub fn label_format(mut self, format: NumberFormat) -> Self {
+        self.label_format = Some(format);
+        self
+    }
+
+    /// Label the grid lines of the y-axis with the values formatted by the `format`,
+    /// default is None (no labels).
+    pub fn y_axis_format(mut self, format: NumberFormat) -> Self {
+        self.y_axis_format = Some(format);
+        self
+    }
 }
 
 impl<T, X, Y> Plot for BarChart<T, X, Y>
@@ -125,19 +143,30 @@ where
             }
         });
 
-        Axis::new()
-            .x(height)
-            .x_label(x_label)
-            .stroke(cx.theme().border)
-            .paint(&bounds, window, cx);
+        let grid_ticks = (0..=3).map(|i| height * i as f32 / 4.0).collect::<Vec<_>>();
+        let mut axis = Axis::new().x(height).x_label(x_label);
+        if let Some(format) = self.y_axis_format.as_ref() {
+            let y_label = AxisText::numbers(&y, &grid_ticks, format, cx.theme().muted_foreground);
+            axis = axis.y(px(0.)).y_label(y_label);
+        }
+        axis.stroke(cx.theme().border).paint(&bounds, window, cx);
 
         // Draw grid
         Grid::new()
-            .y((0..=3).map(|i| height * i as f32 / 4.0).collect())
+            .y(grid_ticks)
             .stroke(cx.theme().border)
             .dash_array(&[px(4.), px(2.)])
             .paint(&bounds, window);
 
+        let label = self.label.clone().or_else(|| {
+            let format = self.label_format.clone()?;
+            let y_fn = y_fn.clone();
+            Some(
+                Rc::new(move |d: &T| format_number(y_fn(d).to_f64().unwrap_or_default(), &format))
+                    as Rc<dyn Fn(&T) -> SharedString>,
+            )
+        });
+
         // Draw bars
         let x_fn = x_fn.clone();
         let y_fn = y_fn.clone();
@@ -152,8 +181,7 @@ where
             .y1(move |d| y.tick(&y_fn(d)))
             .fill(move |d| fill.as_ref().map(|f| f(d)).unwrap_or(default_fill));
 
-        if let Some(label) = self.label.as_ref() {
-            let label = label.clone();
+        if let Some(label) = label {
             bar =
                 bar.label(move |d, p| Text::new(label(d), p, label_color).align(TextAlign::Center));
         }
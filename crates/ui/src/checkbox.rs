@@ -1,8 +1,8 @@
 use std::time::Duration;
 
 use crate::{
-    text::Text, v_flex, ActiveTheme, Disableable, IconName, Selectable, Sizable, Size,
-    StyledExt as _,
+    animation::AnimationSettings as _, text::Text, v_flex, ActiveTheme, Disableable, IconName,
+    Selectable, Sizable, Size, StyledExt as _,
 };
 use gpui::{
     div, prelude::FluentBuilder as _, px, relative, rems, svg, Animation, AnimationExt, AnyElement,
@@ -10,6 +10,46 @@ use gpui::{
     StatefulInteractiveElement, StyleRefinement, Styled, Window,
 };
 
+/// The state of a [`Checkbox`].
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Hash)]
+pub enum CheckState {
+    #[default]
+    Unchecked,
+    Checked,
+    /// Partially checked, e.g.: a "select all" checkbox when some of the children are checked.
+    Indeterminate,
+}
+
+impl CheckState {
+    /// Returns true if the state is [`CheckState::Checked`].
+    pub fn is_checked(&self) -> bool {
+        matches!(self, Self::Checked)
+    }
+
+    /// Returns true if the state is [`CheckState::Indeterminate`].
+    pub fn is_indeterminate(&self) -> bool {
+        matches!(self, Self::Indeterminate)
+    }
+
+    /// Returns the next state when clicked, the indeterminate state becomes checked.
+    pub fn toggle(&self) -> Self {
+        match self {
+            Self::Checked => Self::Unchecked,
+            Self::Unchecked | Self::Indeterminate => Self::Checked,
+        }
+    }
+}
+
+impl From<bool> for CheckState {
+    fn from(checked: bool) -> Self {
+        if checked {
+            Self::Checked
+        } else {
+            Self::Unchecked
+        }
+    }
+}
+
 /// A Checkbox element.
 #[derive(IntoElement)]
 pub struct Checkbox {
@@ -18,7 +58,7 @@ pub struct Checkbox {
     style: StyleRefinement,
     label: Option<Text>,
     children: Vec<AnyElement>,
-    checked: bool,
+    state: CheckState,
     disabled: bool,
     size: Size,
     on_click: Option<Box<dyn Fn(&bool, &mut Window, &mut App) + 'static>>,
@@ -32,7 +72,7 @@ impl Checkbox {
             style: StyleRefinement::default(),
             label: None,
             children: Vec::new(),
-            checked: false,
+            state: CheckState::Unchecked,
             disabled: false,
             size: Size::default(),
             on_click: None,
@@ -45,10 +85,19 @@ impl Checkbox {
     }
 
     pub fn checked(mut self, checked: bool) -> Self {
-        self.checked = checked;
+        self.state = checked.into();
+        self
+    }
+
+    /// Set the check state, use [`CheckState::Indeterminate`] to show a dash.
+    pub fn state(mut self, state: CheckState) -> Self {
+        self.state = state;
         self
     }
 
+    /// Set the click handler, the argument is the new checked state.
+    ///
+    /// When the state is [`CheckState::Indeterminate`], clicking it will be checked.
     pub fn on_click(mut self, handler: impl Fn(&bool, &mut Window, &mut App) + 'static) -> Self {
         self.on_click = Some(Box::new(handler));
         self
@@ -81,7 +130,7 @@ impl Selectable for Checkbox {
     }
 
     fn is_selected(&self) -> bool {
-        self.checked
+        self.state.is_checked()
     }
 }
 
@@ -101,12 +150,14 @@ impl Sizable for Checkbox {
 pub(crate) fn checkbox_check_icon(
     id: ElementId,
     size: Size,
-    checked: bool,
+    state: impl Into<CheckState>,
     disabled: bool,
     window: &mut Window,
     cx: &mut App,
 ) -> impl IntoElement {
-    let toggle_state = window.use_keyed_state(id, cx, |_, _| checked);
+    let state = state.into();
+    let checked = state != CheckState::Unchecked;
+    let toggle_state = window.use_keyed_state(id, cx, |_, _| state);
     let color = if disabled {
         cx.theme().primary_foreground.opacity(0.5)
     } else {
@@ -125,24 +176,25 @@ pub(crate) fn checkbox_check_icon(
             _ => this.size_3(),
         })
         .text_color(color)
-        .map(|this| match checked {
-            true => this.path(IconName::Check.path()),
-            _ => this,
+        .map(|this| match state {
+            CheckState::Checked => this.path(IconName::Check.path()),
+            CheckState::Indeterminate => this.path(IconName::Minus.path()),
+            CheckState::Unchecked => this,
         })
         .map(|this| {
-            if !disabled && checked != *toggle_state.read(cx) {
+            if !disabled && cx.animations_enabled() && state != *toggle_state.read(cx) {
                 let duration = Duration::from_secs_f64(0.25);
                 cx.spawn({
                     let toggle_state = toggle_state.clone();
                     async move |cx| {
                         cx.background_executor().timer(duration).await;
-                        _ = toggle_state.update(cx, |this, _| *this = checked);
+                        _ = toggle_state.update(cx, |this, _| *this = state);
                     }
                 })
                 .detach();
 
                 this.with_animation(
-                    ElementId::NamedInteger("toggle".into(), checked as u64),
+                    ElementId::NamedInteger("toggle".into(), state as u64),
                     Animation::new(Duration::from_secs_f64(0.25)),
                     move |this, delta| {
                         this.opacity(if checked { 1.0 * delta } else { 1.0 - delta })
@@ -157,7 +209,8 @@ pub(crate) fn checkbox_check_icon(
 
 impl RenderOnce for Checkbox {
     fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
-        let checked = self.checked;
+        let state = self.state;
+        let checked = state != CheckState::Unchecked;
         let border_color = if checked {
             cx.theme().primary
         } else {
@@ -204,14 +257,14 @@ impl RenderOnce for Checkbox {
                         .border_color(color)
                         .rounded(radius)
                         .when(cx.theme().shadow && !self.disabled, |this| this.shadow_xs())
-                        .map(|this| match self.checked {
+                        .map(|this| match checked {
                             false => this.bg(cx.theme().background),
                             _ => this.bg(color),
                         })
                         .child(checkbox_check_icon(
                             self.id,
                             self.size,
-                            checked,
+                            state,
                             self.disabled,
                             window,
                             cx,
@@ -247,7 +300,7 @@ impl RenderOnce for Checkbox {
                     |this, on_click| {
                         this.on_click(move |_, window, cx| {
                             cx.stop_propagation();
-                            let checked = !self.checked;
+                            let checked = state.toggle().is_checked();
                             on_click(&checked, window, cx);
                         })
                     },
@@ -0,0 +1,324 @@
+use std::{ops::Range, rc::Rc, sync::Arc};
+
+use gpui::{
+    div, prelude::FluentBuilder as _, rems, App, ElementId, HighlightStyle,
+    InteractiveElement as _, IntoElement, Length, ParentElement, RenderOnce, ScrollHandle,
+    SharedString, StatefulInteractiveElement as _, StyleRefinement, Styled, StyledText, Window,
+};
+use ropey::Rope;
+
+use crate::{
+    clipboard::Clipboard,
+    h_flex,
+    highlighter::{HighlightTheme, SyntaxHighlighter},
+    scroll::{Scrollbar, ScrollbarState},
+    v_flex, ActiveTheme as _, StyledExt as _,
+};
+
+/// A line of the [`CodeBlock`] with the highlights in the line.
+#[derive(Debug, Clone, PartialEq)]
+pub(crate) struct CodeLine {
+    pub(crate) text: SharedString,
+    pub(crate) highlights: Vec<(Range<usize>, HighlightStyle)>,
+}
+
+/// Highlight the `code` by the `language`, and split it into lines.
+pub(crate) fn highlight_code(
+    code: &str,
+    language: &str,
+    theme: &HighlightTheme,
+    cx: &App,
+) -> Vec<CodeLine> {
+    let mut highlighter = SyntaxHighlighter::new(language, cx);
+    highlighter.update(None, &Rope::from_str(code), cx);
+    let styles = highlighter.styles(&(0..code.len()), theme);
+
+    split_lines(code, &styles)
+}
+
+/// Split the `code` into lines, and the `styles` of the code into the styles of each line.
+///
+/// The trailing newline of the code is not shown as an empty line.
+fn split_lines(code: &str, styles: &[(Range<usize>, HighlightStyle)]) -> Vec<CodeLine> {
+    let mut offset = 0;
+    let mut styles = styles.iter().peekable();
+    let code = code.strip_suffix('\n').unwrap_or(code);
+
+    code.split('\n')
+        .map(|raw_line| {
+            let line = raw_line.strip_suffix('\r').unwrap_or(raw_line);
+            let range = offset..offset + line.len();
+            offset += raw_line.len() + 1;
+
+            let mut highlights = vec![];
+            while let Some((style_range, style)) = styles.peek() {
+                if style_range.start >= range.end {
+                    break;
+                }
+                let start = style_range.start.max(range.start);
+                let end = style_range.end.min(range.end);
+                if start < end {
+                    highlights.push((start - range.start..end - range.start, *style));
+                }
+                // Keep the style that continues to the next line, e.g.: a block comment.
+                if style_range.end > range.end {
+                    break;
+                }
+                styles.next();
+            }
+
+            CodeLine {
+                text: line.to_string().into(),
+                highlights,
+            }
+        })
+        .collect()
+}
+
+struct CodeBlockState {
+    code: SharedString,
+    language: SharedString,
+    theme: Arc<HighlightTheme>,
+    lines: Rc<Vec<CodeLine>>,
+    scroll_handle: ScrollHandle,
+    scroll_state: ScrollbarState,
+}
+
+impl CodeBlockState {
+    /// Highlight the code again if the code, language or theme is changed.
+    fn highlight(&mut self, code: &SharedString, language: &SharedString, cx: &App) {
+        let theme = cx.theme().highlight_theme.clone();
+        if !self.lines.is_empty()
+            && self.code == *code
+            && self.language == *language
+            && Arc::ptr_eq(&self.theme, &theme)
+        {
+            return;
+        }
+
+        self.lines = Rc::new(highlight_code(code, language, &theme, cx));
+        self.code = code.clone();
+        self.language = language.clone();
+        self.theme = theme;
+    }
+}
+
+/// A read-only code block with the syntax highlighting, a language label and a copy button.
+///
+/// The code is highlighted by the language registered in the
+/// [`crate::highlighter::LanguageRegistry`], the unknown language is shown as plain text.
+///
+/// ```ignore
+/// CodeBlock::new("example", include_str!("main.go"), "go")
+///     .line_numbers(true)
+///     .highlight_lines([3, 4])
+/// ```
+#[derive(IntoElement)]
+pub struct CodeBlock {
+    id: ElementId,
+    style: StyleRefinement,
+    code: SharedString,
+    language: SharedString,
+    line_numbers: bool,
+    highlighted_lines: Vec<usize>,
+    soft_wrap: bool,
+    max_height: Length,
+}
+
+impl CodeBlock {
+    pub fn new(
+        id: impl Into<ElementId>,
+        code: impl Into<SharedString>,
+        language: impl Into<SharedString>,
+    ) -> Self {
+        Self {
+            id: id.into(),
+            style: StyleRefinement::default(),
+            code: code.into(),
+            language: language.into(),
+            line_numbers: false,
+            highlighted_lines: vec![],
+            soft_wrap: false,
+            max_height: rems(30.).into(),
+        }
+    }
+
+    /// Set true to show the line numbers, default is false.
+    pub fn line_numbers(mut self, line_numbers: bool) -> Self {
+        self.line_numbers = line_numbers;
+        self
+    }
+
+    /// Set the (1-based) lines to highlight, e.g.: the changed lines of a diff.
+    pub fn highlight_lines(mut self, lines: impl IntoIterator<Item = usize>) -> Self {
+        self.highlighted_lines = lines.into_iter().collect();
+        self
+    }
+
+    /// Set true to wrap the long lines, otherwise scroll horizontally, default is false.
+    pub fn soft_wrap(mut self, soft_wrap: bool) -> Self {
+        self.soft_wrap = soft_wrap;
+        self
+    }
+
+    /// Set the max height of the code, the longer code is scrolled, default is 30rem.
+    pub fn max_height(mut self, max_height: impl Into<Length>) -> Self {
+        self.max_height = max_height.into();
+        self
+    }
+}
+
+impl Styled for CodeBlock {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl RenderOnce for CodeBlock {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = window.use_keyed_state(self.id.clone(), cx, |_, cx| CodeBlockState {
+            code: SharedString::default(),
+            language: SharedString::default(),
+            theme: cx.theme().highlight_theme.clone(),
+            lines: Rc::new(vec![]),
+            scroll_handle: ScrollHandle::default(),
+            scroll_state: ScrollbarState::default(),
+        });
+        state.update(cx, |state, cx| {
+            state.highlight(&self.code, &self.language, cx)
+        });
+
+        let state = state.read(cx);
+        let lines = state.lines.clone();
+        let scroll_handle = state.scroll_handle.clone();
+        let scroll_state = state.scroll_state.clone();
+        let number_width = rems(0.6 * lines.len().to_string().len() as f32);
+        let highlight_bg = cx.theme().primary.alpha(0.12);
+        let code = self.code.clone();
+
+        v_flex()
+            .id(self.id.clone())
+            .overflow_hidden()
+            .rounded(cx.theme().radius)
+            .border_1()
+            .border_color(cx.theme().border)
+            .bg(cx.theme().accent)
+            .text_sm()
+            .refine_style(&self.style)
+            .child(
+                h_flex()
+                    .justify_between()
+                    .pl_3()
+                    .pr_1()
+                    .py_0p5()
+                    .border_b_1()
+                    .border_color(cx.theme().border)
+                    .text_xs()
+                    .text_color(cx.theme().muted_foreground)
+                    .child(self.language.clone())
+                    .child(Clipboard::new("copy").value(code)),
+            )
+            .child(
+                div()
+                    .relative()
+                    .child(
+                        div()
+                            .id("lines")
+                            .max_h(self.max_height)
+                            .map(|this| {
+                                if self.soft_wrap {
+                                    this.overflow_y_scroll()
+                                } else {
+                                    this.overflow_scroll()
+                                }
+                            })
+                            .track_scroll(&scroll_handle)
+                            .py_2()
+                            .font_family("Menlo, Monaco, Consolas, monospace")
+                            .child(v_flex().children(lines.iter().enumerate().map(
+                                |(ix, line)| {
+                                    let highlighted = self.highlighted_lines.contains(&(ix + 1));
+
+                                    h_flex()
+                                        .items_start()
+                                        .px_3()
+                                        .gap_3()
+                                        .when(highlighted, |this| this.bg(highlight_bg))
+                                        .when(self.line_numbers, |this| {
+                                            this.child(
+                                                div()
+                                                    .flex_none()
+                                                    .min_w(number_width)
+                                                    .text_right()
+                                                    .text_color(cx.theme().muted_foreground)
+                                                    .child((ix + 1).to_string()),
+                                            )
+                                        })
+                                        .child(
+                                            div()
+                                                .when(!self.soft_wrap, |this| {
+                                                    this.flex_none().whitespace_nowrap()
+                                                })
+                                                .when(self.soft_wrap, |this| this.flex_1())
+                                                // Keep the height of the empty line.
+                                                .min_h(rems(1.25))
+                                                .child(
+                                                    StyledText::new(line.text.clone())
+                                                        .with_highlights(line.highlights.clone()),
+                                                ),
+                                        )
+                                },
+                            ))),
+                    )
+                    .child(
+                        div()
+                            .absolute()
+                            .top_0()
+                            .left_0()
+                            .right_0()
+                            .bottom_0()
+                            .child(if self.soft_wrap {
+                                Scrollbar::vertical(&scroll_state, &scroll_handle)
+                            } else {
+                                Scrollbar::both(&scroll_state, &scroll_handle)
+                            }),
+                    ),
+            )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::HighlightStyle;
+
+    use super::split_lines;
+
+    #[test]
+    fn test_split_lines() {
+        let red = HighlightStyle {
+            color: Some(gpui::red()),
+            ..Default::default()
+        };
+        let code = "let a;\r\n/* b\nc */\n";
+        let styles = vec![(0..3, red), (3..6, HighlightStyle::default()), (8..17, red)];
+
+        let lines = split_lines(code, &styles);
+        assert_eq!(lines.len(), 3);
+        assert_eq!(lines[0].text.as_ref(), "let a;");
+        assert_eq!(
+            lines[0].highlights,
+            vec![(0..3, red), (3..6, HighlightStyle::default())]
+        );
+        // The block comment continues to the next line.
+        assert_eq!(lines[1].text.as_ref(), "/* b");
+        assert_eq!(lines[1].highlights, vec![(0..4, red)]);
+        assert_eq!(lines[2].text.as_ref(), "c */");
+        assert_eq!(lines[2].highlights, vec![(0..4, red)]);
+
+        let lines = split_lines("a\n\nb", &[(0..4, red)]);
+        assert_eq!(lines.len(), 3);
+        assert_eq!(lines[1].text.as_ref(), "");
+        assert_eq!(lines[1].highlights, vec![]);
+        assert_eq!(lines[2].highlights, vec![(0..1, red)]);
+    }
+}
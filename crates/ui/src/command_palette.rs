@@ -0,0 +1,560 @@
+use std::rc::Rc;
+
+use gpui::{
+    actions, div, prelude::FluentBuilder as _, px, App, AppContext as _, Context, Entity,
+    EventEmitter, FocusHandle, Focusable, IntoElement, KeyBinding, Keystroke, ParentElement as _,
+    Render, SharedString, Styled as _, Subscription, Task, Window,
+};
+use rust_i18n::t;
+
+use crate::{
+    h_flex,
+    list::{List, ListDelegate, ListEvent, ListItem},
+    v_flex, ActiveTheme as _, ContextModal as _, Icon, IndexPath, Kbd,
+};
+
+actions!(command_palette, [ToggleCommandPalette]);
+
+/// The max number of the recently used commands to show, when the query is empty.
+const MAX_RECENT: usize = 5;
+
+pub fn init(cx: &mut App) {
+    cx.bind_keys([
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-shift-p", ToggleCommandPalette, None),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-shift-p", ToggleCommandPalette, None),
+    ]);
+}
+
+/// A command to register in the [`CommandPalette`].
+#[derive(Clone)]
+pub struct Command {
+    id: SharedString,
+    label: SharedString,
+    category: Option<SharedString>,
+    keybinding: Option<Keystroke>,
+    icon: Option<Icon>,
+    handler: Option<Rc<dyn Fn(&mut Window, &mut App)>>,
+}
+
+impl Command {
+    pub fn new(id: impl Into<SharedString>, label: impl Into<SharedString>) -> Self {
+        Self {
+            id: id.into(),
+            label: label.into(),
+            category: None,
+            keybinding: None,
+            icon: None,
+            handler: None,
+        }
+    }
+
+    /// Set the category of the command, the commands are grouped by category when the query is empty.
+    pub fn category(mut self, category: impl Into<SharedString>) -> Self {
+        self.category = Some(category.into());
+        self
+    }
+
+    /// Set the keybinding hint of the command, e.g.: `cmd-shift-s`.
+    ///
+    /// This is only for display, the keybinding is not registered.
+    pub fn keybinding(mut self, keystroke: &str) -> Self {
+        self.keybinding = Keystroke::parse(keystroke).ok();
+        self
+    }
+
+    /// Set the icon of the command.
+    pub fn icon(mut self, icon: impl Into<Icon>) -> Self {
+        self.icon = Some(icon.into());
+        self
+    }
+
+    /// Set the action to run when the command is executed.
+    pub fn on_action(mut self, handler: impl Fn(&mut Window, &mut App) + 'static) -> Self {
+        self.handler = Some(Rc::new(handler));
+        self
+    }
+
+    /// Returns the id of the command.
+    pub fn id(&self) -> &SharedString {
+        &self.id
+    }
+
+    /// Returns the label of the command.
+    pub fn label(&self) -> &SharedString {
+        &self.label
+    }
+
+    /// The text to match the query, includes the category like `File: Save`.
+    fn search_text(&self) -> String {
+        match &self.category {
+            Some(category) => format!("{}: {}", category, self.label),
+            None => self.label.to_string(),
+        }
+    }
+}
+
+/// A group of commands, selected by the prefix of the query.
+struct CommandProvider {
+    prefix: SharedString,
+    commands: Vec<Command>,
+}
+
+/// A section of the matched commands, the items are the indices of the provider commands.
+struct CommandSection {
+    title: Option<SharedString>,
+    /// Show the category before the label, for the sections not grouped by category.
+    show_category: bool,
+    items: Vec<usize>,
+}
+
+pub enum CommandPaletteEvent {
+    /// A command is executed, with the command id.
+    Execute(SharedString),
+}
+
+struct CommandListDelegate {
+    providers: Vec<CommandProvider>,
+    /// The recently used command ids, the most recent first.
+    recent: Vec<SharedString>,
+    /// The index of the current provider in `providers`.
+    provider_ix: Option<usize>,
+    sections: Vec<CommandSection>,
+    query: String,
+}
+
+impl CommandListDelegate {
+    fn new() -> Self {
+        Self {
+            providers: vec![],
+            recent: vec![],
+            provider_ix: None,
+            sections: vec![],
+            query: String::new(),
+        }
+    }
+
+    /// Returns the provider index for the query, and the query without the prefix.
+    ///
+    /// The longest matched prefix is used, fallback to the provider without prefix.
+    fn provider_for_query<'a>(&self, query: &'a str) -> Option<(usize, &'a str)> {
+        self.providers
+            .iter()
+            .enumerate()
+            .filter(|(_, provider)| query.starts_with(provider.prefix.as_str()))
+            .max_by_key(|(_, provider)| provider.prefix.len())
+            .map(|(ix, provider)| (ix, query[provider.prefix.len()..].trim()))
+    }
+
+    fn update_matches(&mut self) {
+        self.sections.clear();
+        let query = self.query.clone();
+        let Some((provider_ix, query)) = self.provider_for_query(&query) else {
+            self.provider_ix = None;
+            return;
+        };
+        self.provider_ix = Some(provider_ix);
+
+        let commands = &self.providers[provider_ix].commands;
+        let recent_rank = |command: &Command| {
+            self.recent
+                .iter()
+                .position(|id| id == &command.id)
+                .unwrap_or(usize::MAX)
+        };
+
+        if !query.is_empty() {
+            let mut matches = commands
+                .iter()
+                .enumerate()
+                .filter_map(|(ix, command)| {
+                    fuzzy_score(query, &command.search_text())
+                        .map(|score| (ix, score, recent_rank(command)))
+                })
+                .collect::<Vec<_>>();
+            // Higher score first, then the recently used, then the registration order.
+            matches.sort_by(|a, b| b.1.cmp(&a.1).then(a.2.cmp(&b.2)).then(a.0.cmp(&b.0)));
+
+            if !matches.is_empty() {
+                self.sections.push(CommandSection {
+                    title: None,
+                    show_category: true,
+                    items: matches.into_iter().map(|(ix, _, _)| ix).collect(),
+                });
+            }
+            return;
+        }
+
+        let mut recent_items = commands
+            .iter()
+            .enumerate()
+            .filter(|(_, command)| recent_rank(command) < MAX_RECENT)
+            .map(|(ix, command)| (ix, recent_rank(command)))
+            .collect::<Vec<_>>();
+        recent_items.sort_by_key(|(_, rank)| *rank);
+        if !recent_items.is_empty() {
+            self.sections.push(CommandSection {
+                title: Some(t!("CommandPalette.recent").into()),
+                show_category: true,
+                items: recent_items.iter().map(|(ix, _)| *ix).collect(),
+            });
+        }
+
+        // Group by category in the registration order.
+        let mut category_sections: Vec<CommandSection> = vec![];
+        for (ix, command) in commands.iter().enumerate() {
+            if recent_items.iter().any(|(recent_ix, _)| *recent_ix == ix) {
+                continue;
+            }
+
+            let title = command
+                .category
+                .clone()
+                .unwrap_or_else(|| t!("CommandPalette.other").into());
+            match category_sections
+                .iter_mut()
+                .find(|section| section.title.as_ref() == Some(&title))
+            {
+                Some(section) => section.items.push(ix),
+                None => category_sections.push(CommandSection {
+                    title: Some(title),
+                    show_category: false,
+                    items: vec![ix],
+                }),
+            }
+        }
+        self.sections.extend(category_sections);
+    }
+
+    fn command(&self, ix: IndexPath) -> Option<&Command> {
+        let provider = self.providers.get(self.provider_ix?)?;
+        let command_ix = *self.sections.get(ix.section)?.items.get(ix.row)?;
+        provider.commands.get(command_ix)
+    }
+
+    fn push_recent(&mut self, id: SharedString) {
+        self.recent.retain(|recent_id| recent_id != &id);
+        self.recent.insert(0, id);
+        self.recent.truncate(MAX_RECENT);
+        self.update_matches();
+    }
+}
+
+impl ListDelegate for CommandListDelegate {
+    type Item = ListItem;
+
+    fn sections_count(&self, _: &App) -> usize {
+        self.sections.len()
+    }
+
+    fn items_count(&self, section: usize, _: &App) -> usize {
+        self.sections
+            .get(section)
+            .map_or(0, |section| section.items.len())
+    }
+
+    fn perform_search(
+        &mut self,
+        query: &str,
+        _: &mut Window,
+        _: &mut Context<List<Self>>,
+    ) -> Task<()> {
+        self.query = query.to_string();
+        self.update_matches();
+        Task::ready(())
+    }
+
+    fn render_section_header(
+        &self,
+        section: usize,
+        _: &mut Window,
+        cx: &mut Context<List<Self>>,
+    ) -> Option<impl IntoElement> {
+        let title = self.sections.get(section)?.title.clone()?;
+
+        Some(
+            div()
+                .pt_2()
+                .pb_1()
+                .px_3()
+                .text_xs()
+                .text_color(cx.theme().muted_foreground)
+                .child(title),
+        )
+    }
+
+    fn render_item(
+        &self,
+        ix: IndexPath,
+        _: &mut Window,
+        cx: &mut Context<List<Self>>,
+    ) -> Option<Self::Item> {
+        let command = self.command(ix)?;
+        let show_category = self.sections.get(ix.section)?.show_category;
+
+        Some(
+            ListItem::new(ix).px_3().py_1p5().child(
+                h_flex()
+                    .w_full()
+                    .gap_2()
+                    .when_some(command.icon.clone(), |this, icon| {
+                        this.child(icon.text_color(cx.theme().muted_foreground))
+                    })
+                    .child(
+                        h_flex()
+                            .flex_1()
+                            .gap_1()
+                            .overflow_hidden()
+                            .whitespace_nowrap()
+                            .when(show_category, |this| {
+                                this.when_some(command.category.clone(), |this, category| {
+                                    this.child(
+                                        div()
+                                            .text_color(cx.theme().muted_foreground)
+                                            .child(format!("{}:", category)),
+                                    )
+                                })
+                            })
+                            .child(command.label.clone()),
+                    )
+                    .when_some(command.keybinding.clone(), |this, keystroke| {
+                        this.child(Kbd::new(keystroke))
+                    }),
+            ),
+        )
+    }
+}
+
+/// A command palette to search and execute the registered commands by keyboard.
+///
+/// Use [`CommandPalette::toggle`] to open it in a modal, it is bound to `cmd-shift-p` (`ctrl-shift-p`)
+/// by the [`ToggleCommandPalette`] action, the app should handle the action to call it.
+///
+/// The commands without prefix are searched by default, use [`CommandPalette::provider`]
+/// to register the commands that are searched when the query starts with the prefix, e.g.: `>`.
+pub struct CommandPalette {
+    list: Entity<List<CommandListDelegate>>,
+    open: bool,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl EventEmitter<CommandPaletteEvent> for CommandPalette {}
+
+impl CommandPalette {
+    pub fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let list = cx.new(|cx| List::new(CommandListDelegate::new(), window, cx).max_h(px(360.)));
+        if let Some(input) = list.read(cx).query_input().cloned() {
+            input.update(cx, |input, cx| {
+                input.set_placeholder(t!("CommandPalette.placeholder"), window, cx)
+            });
+        }
+
+        let _subscriptions = vec![cx.subscribe_in(&list, window, Self::on_list_event)];
+
+        Self {
+            list,
+            open: false,
+            _subscriptions,
+        }
+    }
+
+    /// Set the commands searched without prefix.
+    pub fn commands(
+        &mut self,
+        commands: impl IntoIterator<Item = Command>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.provider("", commands, window, cx);
+    }
+
+    /// Set the commands searched when the query starts with the `prefix`,
+    /// the prefix is removed from the query to match the commands.
+    ///
+    /// Call again with the same prefix to replace the commands.
+    pub fn provider(
+        &mut self,
+        prefix: impl Into<SharedString>,
+        commands: impl IntoIterator<Item = Command>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let prefix = prefix.into();
+        let commands = commands.into_iter().collect::<Vec<_>>();
+        self.list.update(cx, |list, cx| {
+            let delegate = list.delegate_mut();
+            match delegate
+                .providers
+                .iter_mut()
+                .find(|provider| provider.prefix == prefix)
+            {
+                Some(provider) => provider.commands = commands,
+                None => delegate
+                    .providers
+                    .push(CommandProvider { prefix, commands }),
+            }
+            delegate.update_matches();
+            cx.notify();
+        });
+    }
+
+    /// Returns the recently used command ids, the most recent first.
+    pub fn recent(&self, cx: &App) -> Vec<SharedString> {
+        self.list.read(cx).delegate().recent.clone()
+    }
+
+    /// Open the command palette in a modal, or close it if it is opened.
+    ///
+    /// The query is set to the `prefix` when opening, e.g.: `>` to open with the commands of the provider.
+    pub fn toggle(
+        palette: &Entity<Self>,
+        prefix: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut App,
+    ) {
+        if palette.read(cx).open {
+            palette.update(cx, |this, _| this.open = false);
+            window.close_modal(cx);
+            return;
+        }
+
+        let prefix = prefix.into();
+        window.open_modal(cx, {
+            let palette = palette.clone();
+            move |modal, _, _| {
+                let palette = palette.clone();
+                modal
+                    .show_close(false)
+                    .width(px(600.))
+                    .margin_top(px(80.))
+                    .p_0()
+                    .on_close(move |_, _, cx| {
+                        palette.update(cx, |this, _| this.open = false);
+                    })
+                    .child(palette.clone())
+            }
+        });
+
+        palette.update(cx, |this, cx| {
+            this.open = true;
+            let list = this.list.clone();
+            list.update(cx, |list, cx| {
+                if let Some(input) = list.query_input().cloned() {
+                    input.update(cx, |input, cx| input.set_value(prefix, window, cx));
+                }
+                list.focus(window, cx);
+            });
+        });
+    }
+
+    fn on_list_event(
+        &mut self,
+        _: &Entity<List<CommandListDelegate>>,
+        event: &ListEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let ListEvent::Confirm(ix) = event else {
+            return;
+        };
+        let Some(command) = self.list.read(cx).delegate().command(*ix).cloned() else {
+            return;
+        };
+
+        self.list.update(cx, |list, _| {
+            list.delegate_mut().push_recent(command.id.clone());
+        });
+        if self.open {
+            self.open = false;
+            window.close_modal(cx);
+        }
+
+        if let Some(handler) = command.handler.clone() {
+            cx.defer_in(window, move |_, window, cx| handler(window, cx));
+        }
+        cx.emit(CommandPaletteEvent::Execute(command.id));
+    }
+}
+
+impl Focusable for CommandPalette {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.list.focus_handle(cx)
+    }
+}
+
+impl Render for CommandPalette {
+    fn render(&mut self, _: &mut Window, _: &mut Context<Self>) -> impl IntoElement {
+        v_flex().w_full().child(self.list.clone())
+    }
+}
+
+/// Fuzzy match the `query` in the `text` case-insensitively, returns the score
+/// if all the chars of the query are found in order, higher is better.
+///
+/// The consecutive matches and the matches at the start of words get a bonus.
+pub(crate) fn fuzzy_score(query: &str, text: &str) -> Option<usize> {
+    let mut query_chars = query
+        .chars()
+        .filter(|c| !c.is_whitespace())
+        .flat_map(char::to_lowercase)
+        .peekable();
+    let mut score = 0;
+    let mut prev_matched = false;
+    let mut prev_char: Option<char> = None;
+
+    for c in text.chars() {
+        let Some(&q) = query_chars.peek() else {
+            break;
+        };
+
+        if c.to_lowercase().eq(std::iter::once(q)) {
+            score += 1;
+            if prev_matched {
+                score += 4;
+            }
+            let word_start = match prev_char {
+                None => true,
+                Some(prev) => !prev.is_alphanumeric() || (prev.is_lowercase() && c.is_uppercase()),
+            };
+            if word_start {
+                score += 3;
+            }
+            query_chars.next();
+            prev_matched = true;
+        } else {
+            prev_matched = false;
+        }
+        prev_char = Some(c);
+    }
+
+    if query_chars.peek().is_some() {
+        return None;
+    }
+
+    Some(score)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::fuzzy_score;
+
+    #[test]
+    fn test_fuzzy_score() {
+        assert_eq!(fuzzy_score("", "Save File"), Some(0));
+        assert_eq!(fuzzy_score("xyz", "Save File"), None);
+        assert_eq!(fuzzy_score("fs", "Save File"), None);
+        assert!(fuzzy_score("SAVE", "Save File").is_some());
+        assert!(fuzzy_score("sf", "Save File").is_some());
+        assert!(fuzzy_score("save fi", "Save File").is_some());
+
+        // Consecutive matches are better than scattered.
+        assert!(
+            fuzzy_score("save", "Save File") > fuzzy_score("save", "Show All Versions Everywhere")
+        );
+        // Word start matches are better.
+        assert!(fuzzy_score("of", "Open File") > fuzzy_score("of", "Profile"));
+        // Camel case is a word start.
+        assert!(fuzzy_score("gs", "gitStatus") > fuzzy_score("gs", "gigs"));
+    }
+}
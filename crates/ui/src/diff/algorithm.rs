@@ -0,0 +1,351 @@
+use std::ops::Range;
+
+/// The min number of the unchanged lines to collapse, collapse a single line is useless.
+const MIN_COLLAPSED_LINES: usize = 2;
+
+/// An edit of the [`diff`], with the zero based indexes of the items.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub(crate) enum Edit {
+    Equal(usize, usize),
+    Delete(usize),
+    Insert(usize),
+}
+
+/// Returns the shortest edits to change the `old` items to the `new` items,
+/// by the Myers' algorithm.
+///
+/// The common prefix and suffix are skipped first, they are the most of the lines in a diff.
+pub(crate) fn diff<T: PartialEq>(old: &[T], new: &[T]) -> Vec<Edit> {
+    let prefix = old.iter().zip(new).take_while(|(a, b)| a == b).count();
+    let suffix = old[prefix..]
+        .iter()
+        .rev()
+        .zip(new[prefix..].iter().rev())
+        .take_while(|(a, b)| a == b)
+        .count();
+
+    let mut edits = (0..prefix)
+        .map(|ix| Edit::Equal(ix, ix))
+        .collect::<Vec<_>>();
+    myers(
+        &old[prefix..old.len() - suffix],
+        &new[prefix..new.len() - suffix],
+        prefix,
+        &mut edits,
+    );
+    edits.extend(
+        (0..suffix).map(|ix| Edit::Equal(old.len() - suffix + ix, new.len() - suffix + ix)),
+    );
+    edits
+}
+
+fn myers<T: PartialEq>(old: &[T], new: &[T], offset: usize, edits: &mut Vec<Edit>) {
+    let (n, m) = (old.len() as isize, new.len() as isize);
+    let max = n + m;
+    if max == 0 {
+        return;
+    }
+
+    // The furthest x of each diagonal k, at `v[k + max + 1]`.
+    let mut v = vec![0isize; 2 * max as usize + 3];
+    let ix = |k: isize| (k + max + 1) as usize;
+    // The `v` before each step d, only keep the diagonals -d-1..=d+1 used by the backtracking.
+    let mut trace: Vec<Vec<isize>> = vec![];
+
+    'outer: for d in 0..=max {
+        trace.push(v[ix(-d - 1)..=ix(d + 1)].to_vec());
+        for k in (-d..=d).step_by(2) {
+            let mut x = if k == -d || (k != d && v[ix(k - 1)] < v[ix(k + 1)]) {
+                v[ix(k + 1)]
+            } else {
+                v[ix(k - 1)] + 1
+            };
+            let mut y = x - k;
+            while x < n && y < m && old[x as usize] == new[y as usize] {
+                x += 1;
+                y += 1;
+            }
+            v[ix(k)] = x;
+            if x >= n && y >= m {
+                break 'outer;
+            }
+        }
+    }
+
+    let (mut x, mut y) = (n, m);
+    let mut result = vec![];
+    for (d, v) in trace.iter().enumerate().rev() {
+        let d = d as isize;
+        let at = |k: isize| v[(k + d + 1) as usize];
+        let k = x - y;
+        let prev_k = if k == -d || (k != d && at(k - 1) < at(k + 1)) {
+            k + 1
+        } else {
+            k - 1
+        };
+        let prev_x = at(prev_k);
+        let prev_y = prev_x - prev_k;
+
+        while x > prev_x && y > prev_y {
+            x -= 1;
+            y -= 1;
+            result.push(Edit::Equal(x as usize + offset, y as usize + offset));
+        }
+        if d > 0 {
+            if x == prev_x {
+                result.push(Edit::Insert(prev_y as usize + offset));
+            } else {
+                result.push(Edit::Delete(prev_x as usize + offset));
+            }
+        }
+        (x, y) = (prev_x, prev_y);
+    }
+
+    edits.extend(result.into_iter().rev());
+}
+
+/// The kind of a [`DiffRow`].
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub(crate) enum DiffKind {
+    Unchanged,
+    Added,
+    Removed,
+    /// The removed line is replaced by the added line.
+    Modified,
+}
+
+/// A row of the side by side diff, with the zero based line index of each side.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub(crate) struct DiffRow {
+    pub(crate) kind: DiffKind,
+    pub(crate) old: Option<usize>,
+    pub(crate) new: Option<usize>,
+}
+
+/// Returns the rows of the line diff, the removed and added lines of a change
+/// are paired as the modified lines.
+pub(crate) fn diff_rows(old: &[&str], new: &[&str]) -> Vec<DiffRow> {
+    let mut rows = vec![];
+    let mut removed = vec![];
+    let mut added = vec![];
+
+    let flush = |rows: &mut Vec<DiffRow>, removed: &mut Vec<usize>, added: &mut Vec<usize>| {
+        for ix in 0..removed.len().max(added.len()) {
+            let (old, new) = (removed.get(ix).copied(), added.get(ix).copied());
+            let kind = match (old, new) {
+                (Some(_), Some(_)) => DiffKind::Modified,
+                (Some(_), None) => DiffKind::Removed,
+                _ => DiffKind::Added,
+            };
+            rows.push(DiffRow { kind, old, new });
+        }
+        removed.clear();
+        added.clear();
+    };
+
+    for edit in diff(old, new) {
+        match edit {
+            Edit::Equal(old, new) => {
+                flush(&mut rows, &mut removed, &mut added);
+                rows.push(DiffRow {
+                    kind: DiffKind::Unchanged,
+                    old: Some(old),
+                    new: Some(new),
+                });
+            }
+            Edit::Delete(old) => removed.push(old),
+            Edit::Insert(new) => added.push(new),
+        }
+    }
+    flush(&mut rows, &mut removed, &mut added);
+
+    rows
+}
+
+/// Split the line into the words, the whitespaces and the other chars, returns the byte ranges.
+fn tokenize(line: &str) -> Vec<Range<usize>> {
+    let mut tokens: Vec<Range<usize>> = vec![];
+    let mut last_kind = None;
+    for (ix, c) in line.char_indices() {
+        let kind = if c.is_alphanumeric() || c == '_' {
+            Some(0)
+        } else if c.is_whitespace() {
+            Some(1)
+        } else {
+            None
+        };
+
+        match tokens.last_mut() {
+            Some(last) if kind.is_some() && kind == last_kind => last.end = ix + c.len_utf8(),
+            _ => tokens.push(ix..ix + c.len_utf8()),
+        }
+        last_kind = kind;
+    }
+    tokens
+}
+
+/// Returns the byte ranges of the changed words in the `old` and `new` line.
+pub(crate) fn word_ranges(old: &str, new: &str) -> (Vec<Range<usize>>, Vec<Range<usize>>) {
+    let old_tokens = tokenize(old);
+    let new_tokens = tokenize(new);
+    let old_words = old_tokens
+        .iter()
+        .map(|r| &old[r.clone()])
+        .collect::<Vec<_>>();
+    let new_words = new_tokens
+        .iter()
+        .map(|r| &new[r.clone()])
+        .collect::<Vec<_>>();
+
+    let push = |ranges: &mut Vec<Range<usize>>, range: &Range<usize>| match ranges.last_mut() {
+        Some(last) if last.end == range.start => last.end = range.end,
+        _ => ranges.push(range.clone()),
+    };
+
+    let (mut old_ranges, mut new_ranges) = (vec![], vec![]);
+    for edit in diff(&old_words, &new_words) {
+        match edit {
+            Edit::Delete(ix) => push(&mut old_ranges, &old_tokens[ix]),
+            Edit::Insert(ix) => push(&mut new_ranges, &new_tokens[ix]),
+            Edit::Equal(..) => {}
+        }
+    }
+    (old_ranges, new_ranges)
+}
+
+/// Returns the ranges of the unchanged rows to collapse,
+/// keep the `context` rows around the changes.
+pub(crate) fn collapsed_ranges(rows: &[DiffRow], context: usize) -> Vec<Range<usize>> {
+    let mut ranges = vec![];
+    let mut ix = 0;
+    while ix < rows.len() {
+        if rows[ix].kind != DiffKind::Unchanged {
+            ix += 1;
+            continue;
+        }
+
+        let start = ix;
+        while ix < rows.len() && rows[ix].kind == DiffKind::Unchanged {
+            ix += 1;
+        }
+        let end = ix;
+
+        // No context is needed at the start and the end of the text.
+        let hidden_start = if start == 0 { 0 } else { start + context };
+        let hidden_end = if end == rows.len() {
+            end
+        } else {
+            end.saturating_sub(context)
+        };
+        if hidden_end >= hidden_start + MIN_COLLAPSED_LINES {
+            ranges.push(hidden_start..hidden_end);
+        }
+    }
+    ranges
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    /// Apply the edits to the `old` to check them.
+    fn apply(old: &[&str], new: &[&str], edits: &[Edit]) -> Vec<String> {
+        let mut result = vec![];
+        for edit in edits {
+            match *edit {
+                Edit::Equal(o, n) => {
+                    assert_eq!(old[o], new[n]);
+                    result.push(old[o].to_string());
+                }
+                Edit::Insert(n) => result.push(new[n].to_string()),
+                Edit::Delete(_) => {}
+            }
+        }
+        result
+    }
+
+    #[test]
+    fn test_diff() {
+        let cases: [(&[&str], &[&str], usize); 6] = [
+            (&[], &[], 0),
+            (&["a", "b"], &["a", "b"], 0),
+            (&[], &["a", "b"], 2),
+            (&["a", "b"], &[], 2),
+            (
+                &["a", "b", "c", "a", "b", "b", "a"],
+                &["c", "b", "a", "b", "a", "c"],
+                5,
+            ),
+            (&["x", "a", "b", "y"], &["x", "c", "b", "y", "z"], 3),
+        ];
+
+        for (old, new, changes) in cases {
+            let edits = diff(old, new);
+            assert_eq!(apply(old, new, &edits), new);
+            let count = edits
+                .iter()
+                .filter(|edit| !matches!(edit, Edit::Equal(..)))
+                .count();
+            assert_eq!(count, changes, "{:?} -> {:?}", old, new);
+        }
+    }
+
+    #[test]
+    fn test_diff_rows() {
+        let old = ["a", "b", "c", "d"];
+        let new = ["a", "B", "c", "e", "f"];
+        let rows = diff_rows(&old, &new);
+        let kinds = rows
+            .iter()
+            .map(|row| (row.kind, row.old, row.new))
+            .collect::<Vec<_>>();
+
+        assert_eq!(
+            kinds,
+            vec![
+                (DiffKind::Unchanged, Some(0), Some(0)),
+                (DiffKind::Modified, Some(1), Some(1)),
+                (DiffKind::Unchanged, Some(2), Some(2)),
+                (DiffKind::Modified, Some(3), Some(3)),
+                (DiffKind::Added, None, Some(4)),
+            ]
+        );
+    }
+
+    #[test]
+    fn test_word_ranges() {
+        assert_eq!(
+            tokenize("let a = 1;"),
+            vec![0..3, 3..4, 4..5, 5..6, 6..7, 7..8, 8..9, 9..10]
+        );
+        assert_eq!(tokenize("foo_bar(x)"), vec![0..7, 7..8, 8..9, 9..10]);
+
+        let (old, new) = word_ranges("let name = 1;", "let title = 10;");
+        assert_eq!(old, vec![4..8, 11..12]);
+        assert_eq!(new, vec![4..9, 12..14]);
+
+        let (old, new) = word_ranges("a b", "a b c");
+        assert_eq!(old, vec![]);
+        assert_eq!(new, vec![3..5]);
+    }
+
+    #[test]
+    fn test_collapsed_ranges() {
+        let row = |kind| DiffRow {
+            kind,
+            old: None,
+            new: None,
+        };
+        let mut rows = vec![row(DiffKind::Unchanged); 10];
+        rows[5] = row(DiffKind::Added);
+        rows.extend(vec![row(DiffKind::Unchanged); 3]);
+        rows.push(row(DiffKind::Removed));
+        rows.extend(vec![row(DiffKind::Unchanged); 8]);
+
+        // Rows: 0..5 unchanged, 5 added, 6..13 unchanged, 13 removed, 14..22 unchanged.
+        assert_eq!(collapsed_ranges(&rows, 2), vec![0..3, 8..11, 16..22]);
+        assert_eq!(collapsed_ranges(&rows, 3), vec![0..2, 17..22]);
+        assert_eq!(collapsed_ranges(&rows[..5], 3), vec![0..5]);
+        assert_eq!(collapsed_ranges(&rows[5..6], 3), vec![]);
+    }
+}
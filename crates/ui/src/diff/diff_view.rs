@@ -0,0 +1,506 @@
+use std::{
+    collections::{HashMap, HashSet},
+    ops::Range,
+    rc::Rc,
+    sync::Arc,
+};
+
+use gpui::{
+    div, prelude::FluentBuilder as _, px, rems, uniform_list, App, Context, HighlightStyle, Hsla,
+    InteractiveElement as _, IntoElement, ParentElement, Render, SharedString,
+    StatefulInteractiveElement as _, Styled, StyledText, UniformListScrollHandle, Window,
+};
+use rust_i18n::t;
+
+use crate::{
+    code_block::{highlight_code, CodeLine},
+    h_flex,
+    highlighter::HighlightTheme,
+    scroll::{Scrollbar, ScrollbarState},
+    v_flex, ActiveTheme as _, Icon, IconName, Sizable as _,
+};
+
+use super::algorithm::{collapsed_ranges, diff_rows, word_ranges, DiffKind, DiffRow};
+
+/// The layout mode of the [`DiffView`].
+#[derive(Debug, Default, Clone, Copy, PartialEq, Eq)]
+pub enum DiffMode {
+    /// The old text on the left and the new text on the right.
+    #[default]
+    SideBySide,
+    /// The removed lines above the added lines in a single column.
+    Inline,
+}
+
+/// An item of the list to render.
+#[derive(Debug, Clone, PartialEq)]
+enum DiffItem {
+    /// The row index of the `rows`, with the kind to render.
+    ///
+    /// In the inline mode, a modified row is rendered as a removed and an added line.
+    Row(usize, DiffKind),
+    /// The collapsed unchanged rows, click to expand.
+    Collapsed(Range<usize>),
+}
+
+/// Returns the items of the `rows` to render, the unchanged rows not in the `expanded`
+/// are collapsed.
+fn build_items(
+    rows: &[DiffRow],
+    mode: DiffMode,
+    context_lines: usize,
+    expanded: &HashSet<usize>,
+) -> Vec<DiffItem> {
+    let mut collapsed = collapsed_ranges(rows, context_lines)
+        .into_iter()
+        .filter(|range| !expanded.contains(&range.start))
+        .peekable();
+
+    let mut items = vec![];
+    let mut ix = 0;
+    while ix < rows.len() {
+        if let Some(range) = collapsed.next_if(|range| range.start == ix) {
+            ix = range.end;
+            items.push(DiffItem::Collapsed(range));
+            continue;
+        }
+
+        let row = rows[ix];
+        if mode == DiffMode::SideBySide || row.kind == DiffKind::Unchanged {
+            items.push(DiffItem::Row(ix, row.kind));
+            ix += 1;
+            continue;
+        }
+
+        // Show all the removed lines of the change before the added lines.
+        let start = ix;
+        while ix < rows.len() && rows[ix].kind != DiffKind::Unchanged {
+            ix += 1;
+        }
+        for row_ix in start..ix {
+            if rows[row_ix].old.is_some() {
+                items.push(DiffItem::Row(row_ix, DiffKind::Removed));
+            }
+        }
+        for row_ix in start..ix {
+            if rows[row_ix].new.is_some() {
+                items.push(DiffItem::Row(row_ix, DiffKind::Added));
+            }
+        }
+    }
+
+    items
+}
+
+/// A view to show the diff of two texts, with the syntax highlighting.
+///
+/// The large unchanged regions are collapsed, only the context lines around
+/// the changes are shown.
+///
+/// ```ignore
+/// let diff = cx.new(|cx| {
+///     DiffView::new(old_text, new_text, "rust", window, cx)
+///         .mode(DiffMode::Inline)
+///         .context_lines(5)
+/// });
+/// ```
+pub struct DiffView {
+    old: SharedString,
+    new: SharedString,
+    language: SharedString,
+    mode: DiffMode,
+    word_diff: bool,
+    context_lines: usize,
+
+    theme: Arc<HighlightTheme>,
+    old_lines: Rc<Vec<CodeLine>>,
+    new_lines: Rc<Vec<CodeLine>>,
+    rows: Rc<Vec<DiffRow>>,
+    /// The changed word ranges of the old and the new line, by the index of the modified row.
+    words: HashMap<usize, (Vec<Range<usize>>, Vec<Range<usize>>)>,
+    /// The start row of the expanded collapsed ranges.
+    expanded: HashSet<usize>,
+    items: Rc<Vec<DiffItem>>,
+
+    scroll_handle: UniformListScrollHandle,
+    scroll_state: ScrollbarState,
+}
+
+impl DiffView {
+    pub fn new(
+        old: impl Into<SharedString>,
+        new: impl Into<SharedString>,
+        language: impl Into<SharedString>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> Self {
+        let mut this = Self {
+            old: old.into(),
+            new: new.into(),
+            language: language.into(),
+            mode: DiffMode::default(),
+            word_diff: true,
+            context_lines: 3,
+            theme: cx.theme().highlight_theme.clone(),
+            old_lines: Rc::new(vec![]),
+            new_lines: Rc::new(vec![]),
+            rows: Rc::new(vec![]),
+            words: HashMap::new(),
+            expanded: HashSet::new(),
+            items: Rc::new(vec![]),
+            scroll_handle: UniformListScrollHandle::new(),
+            scroll_state: ScrollbarState::default(),
+        };
+        this.update_diff(cx);
+        this
+    }
+
+    /// Set the layout mode, default is [`DiffMode::SideBySide`].
+    pub fn mode(mut self, mode: DiffMode) -> Self {
+        self.mode = mode;
+        self.update_items();
+        self
+    }
+
+    /// Set true to highlight the changed words in the modified lines, default is true.
+    pub fn word_diff(mut self, word_diff: bool) -> Self {
+        self.word_diff = word_diff;
+        self.update_words();
+        self
+    }
+
+    /// Set the number of the unchanged lines to show around the changes, default is 3.
+    pub fn context_lines(mut self, context_lines: usize) -> Self {
+        self.context_lines = context_lines;
+        self.expanded.clear();
+        self.update_items();
+        self
+    }
+
+    pub fn set_mode(&mut self, mode: DiffMode, cx: &mut Context<Self>) {
+        self.mode = mode;
+        self.update_items();
+        cx.notify();
+    }
+
+    /// Set the texts to diff, the expanded regions are collapsed again.
+    pub fn set_texts(
+        &mut self,
+        old: impl Into<SharedString>,
+        new: impl Into<SharedString>,
+        cx: &mut Context<Self>,
+    ) {
+        self.old = old.into();
+        self.new = new.into();
+        self.update_diff(cx);
+        cx.notify();
+    }
+
+    /// Returns the number of the added and removed lines.
+    pub fn stats(&self) -> (usize, usize) {
+        self.rows.iter().fold((0, 0), |(added, removed), row| {
+            (
+                added + (row.kind != DiffKind::Unchanged && row.new.is_some()) as usize,
+                removed + (row.kind != DiffKind::Unchanged && row.old.is_some()) as usize,
+            )
+        })
+    }
+
+    fn update_diff(&mut self, cx: &App) {
+        self.theme = cx.theme().highlight_theme.clone();
+        self.old_lines = Rc::new(highlight_code(&self.old, &self.language, &self.theme, cx));
+        self.new_lines = Rc::new(highlight_code(&self.new, &self.language, &self.theme, cx));
+
+        let old = self
+            .old_lines
+            .iter()
+            .map(|line| line.text.as_ref())
+            .collect::<Vec<_>>();
+        let new = self
+            .new_lines
+            .iter()
+            .map(|line| line.text.as_ref())
+            .collect::<Vec<_>>();
+        self.rows = Rc::new(diff_rows(&old, &new));
+        self.expanded.clear();
+        self.update_words();
+        self.update_items();
+    }
+
+    /// Highlight the lines again if the theme is changed.
+    fn update_theme(&mut self, cx: &App) {
+        let theme = cx.theme().highlight_theme.clone();
+        if Arc::ptr_eq(&self.theme, &theme) {
+            return;
+        }
+
+        self.old_lines = Rc::new(highlight_code(&self.old, &self.language, &theme, cx));
+        self.new_lines = Rc::new(highlight_code(&self.new, &self.language, &theme, cx));
+        self.theme = theme;
+    }
+
+    fn update_words(&mut self) {
+        self.words.clear();
+        if !self.word_diff {
+            return;
+        }
+
+        for (ix, row) in self.rows.iter().enumerate() {
+            let (Some(old), Some(new)) = (row.old, row.new) else {
+                continue;
+            };
+            if row.kind == DiffKind::Modified {
+                let ranges = word_ranges(&self.old_lines[old].text, &self.new_lines[new].text);
+                self.words.insert(ix, ranges);
+            }
+        }
+    }
+
+    fn update_items(&mut self) {
+        self.items = Rc::new(build_items(
+            &self.rows,
+            self.mode,
+            self.context_lines,
+            &self.expanded,
+        ));
+    }
+
+    fn expand(&mut self, start: usize, cx: &mut Context<Self>) {
+        self.expanded.insert(start);
+        self.update_items();
+        cx.notify();
+    }
+
+    /// Returns the text of the line with the syntax and changed words highlights.
+    fn render_text(&self, row_ix: usize, old: bool, bg: Hsla) -> impl IntoElement {
+        let row = self.rows[row_ix];
+        let (line, words) = if old {
+            (
+                row.old.map(|ix| &self.old_lines[ix]),
+                self.words.get(&row_ix).map(|w| &w.0),
+            )
+        } else {
+            (
+                row.new.map(|ix| &self.new_lines[ix]),
+                self.words.get(&row_ix).map(|w| &w.1),
+            )
+        };
+
+        div()
+            .flex_1()
+            .min_w_0()
+            .overflow_hidden()
+            .whitespace_nowrap()
+            .when_some(line, |this, line| {
+                let mut highlights = line.highlights.clone();
+                if let Some(words) = words {
+                    let style = HighlightStyle {
+                        background_color: Some(bg),
+                        ..Default::default()
+                    };
+                    let words = words.iter().map(|range| (range.clone(), style));
+                    highlights = gpui::combine_highlights(highlights, words).collect();
+                }
+
+                this.child(StyledText::new(line.text.clone()).with_highlights(highlights))
+            })
+    }
+
+    fn render_gutter(&self, number: Option<usize>, cx: &App) -> impl IntoElement {
+        let width = self
+            .old_lines
+            .len()
+            .max(self.new_lines.len())
+            .to_string()
+            .len();
+
+        div()
+            .flex_none()
+            .min_w(rems(0.6 * width as f32))
+            .text_right()
+            .text_color(cx.theme().muted_foreground)
+            .when_some(number, |this, ix| this.child((ix + 1).to_string()))
+    }
+
+    fn render_sign(&self, kind: DiffKind, cx: &App) -> impl IntoElement {
+        let (sign, color) = match kind {
+            DiffKind::Added => ("+", cx.theme().success),
+            DiffKind::Removed => ("-", cx.theme().danger),
+            _ => (" ", cx.theme().muted_foreground),
+        };
+
+        div().flex_none().w_3().text_color(color).child(sign)
+    }
+
+    /// Render a half of the side by side row, the `old` or the new line.
+    fn render_side(&self, row_ix: usize, old: bool, cx: &App) -> impl IntoElement {
+        let row = self.rows[row_ix];
+        let (number, kind, color) = if old {
+            (row.old, DiffKind::Removed, cx.theme().danger)
+        } else {
+            (row.new, DiffKind::Added, cx.theme().success)
+        };
+        let changed = row.kind != DiffKind::Unchanged;
+
+        h_flex()
+            .flex_1()
+            .min_w_0()
+            .h_full()
+            .px_2()
+            .gap_2()
+            .when(changed && number.is_some(), |this| {
+                this.bg(color.alpha(0.12))
+            })
+            .when(number.is_none(), |this| {
+                this.bg(cx.theme().muted.alpha(0.5))
+            })
+            .child(self.render_gutter(number, cx))
+            .when_some(number, |this, _| {
+                this.child(self.render_sign(if changed { kind } else { row.kind }, cx))
+            })
+            .child(self.render_text(row_ix, old, color.alpha(0.3)))
+    }
+
+    fn render_item(&self, ix: usize, cx: &mut Context<Self>) -> impl IntoElement {
+        let item = self.items[ix].clone();
+
+        h_flex()
+            .id(ix)
+            .w_full()
+            .h(rems(1.25))
+            .map(|this| match item {
+                DiffItem::Collapsed(range) => {
+                    let start = range.start;
+                    this.px_2()
+                        .gap_2()
+                        .bg(cx.theme().accent)
+                        .text_color(cx.theme().muted_foreground)
+                        .cursor_pointer()
+                        .hover(|this| this.text_color(cx.theme().foreground))
+                        .child(Icon::new(IconName::ChevronsUpDown).xsmall())
+                        .child(t!("DiffView.unchanged_lines", count = range.len()).to_string())
+                        .on_click(cx.listener(move |this, _, _, cx| this.expand(start, cx)))
+                }
+                DiffItem::Row(row_ix, kind) => match self.mode {
+                    DiffMode::SideBySide => this
+                        .child(self.render_side(row_ix, true, cx))
+                        .child(div().flex_none().w(px(1.)).h_full().bg(cx.theme().border))
+                        .child(self.render_side(row_ix, false, cx)),
+                    DiffMode::Inline => {
+                        let row = self.rows[row_ix];
+                        let old = kind != DiffKind::Added;
+                        let (old_number, new_number) = match kind {
+                            DiffKind::Added => (None, row.new),
+                            DiffKind::Removed => (row.old, None),
+                            _ => (row.old, row.new),
+                        };
+                        let color = if old {
+                            cx.theme().danger
+                        } else {
+                            cx.theme().success
+                        };
+
+                        this.px_2()
+                            .gap_2()
+                            .when(kind != DiffKind::Unchanged, |this| {
+                                this.bg(color.alpha(0.12))
+                            })
+                            .child(self.render_gutter(old_number, cx))
+                            .child(self.render_gutter(new_number, cx))
+                            .child(self.render_sign(kind, cx))
+                            .child(self.render_text(row_ix, old, color.alpha(0.3)))
+                    }
+                },
+            })
+    }
+}
+
+impl Render for DiffView {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        self.update_theme(cx);
+
+        // Both sides are in the same row, so they are always scrolled together.
+        v_flex()
+            .id("diff-view")
+            .relative()
+            .size_full()
+            .overflow_hidden()
+            .rounded(cx.theme().radius)
+            .border_1()
+            .border_color(cx.theme().border)
+            .bg(cx.theme().background)
+            .text_sm()
+            .font_family("Menlo, Monaco, Consolas, monospace")
+            .child(
+                uniform_list(
+                    "diff-rows",
+                    self.items.len(),
+                    cx.processor(|this, visible_range: Range<usize>, _, cx| {
+                        visible_range
+                            .map(|ix| this.render_item(ix, cx))
+                            .collect::<Vec<_>>()
+                    }),
+                )
+                .track_scroll(self.scroll_handle.clone())
+                .size_full(),
+            )
+            .child(
+                div()
+                    .absolute()
+                    .top_0()
+                    .left_0()
+                    .right_0()
+                    .bottom_0()
+                    .child(Scrollbar::uniform_scroll(
+                        &self.scroll_state,
+                        &self.scroll_handle,
+                    )),
+            )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use std::collections::HashSet;
+
+    use super::{build_items, DiffItem, DiffMode};
+    use crate::diff::algorithm::{diff_rows, DiffKind};
+
+    #[test]
+    fn test_build_items() {
+        let old = ["a", "b", "c", "d", "e", "f", "g"];
+        let new = ["a", "b", "c", "d", "E", "F", "x", "g"];
+        let rows = diff_rows(&old, &new);
+        let mut expanded = HashSet::new();
+
+        assert_eq!(
+            build_items(&rows, DiffMode::SideBySide, 1, &expanded),
+            vec![
+                DiffItem::Collapsed(0..3),
+                DiffItem::Row(3, DiffKind::Unchanged),
+                DiffItem::Row(4, DiffKind::Modified),
+                DiffItem::Row(5, DiffKind::Modified),
+                DiffItem::Row(6, DiffKind::Added),
+                DiffItem::Row(7, DiffKind::Unchanged),
+            ]
+        );
+        assert_eq!(
+            build_items(&rows, DiffMode::Inline, 1, &expanded),
+            vec![
+                DiffItem::Collapsed(0..3),
+                DiffItem::Row(3, DiffKind::Unchanged),
+                DiffItem::Row(4, DiffKind::Removed),
+                DiffItem::Row(5, DiffKind::Removed),
+                DiffItem::Row(4, DiffKind::Added),
+                DiffItem::Row(5, DiffKind::Added),
+                DiffItem::Row(6, DiffKind::Added),
+                DiffItem::Row(7, DiffKind::Unchanged),
+            ]
+        );
+
+        expanded.insert(0);
+        let items = build_items(&rows, DiffMode::SideBySide, 1, &expanded);
+        assert_eq!(items.len(), 8);
+        assert_eq!(items[0], DiffItem::Row(0, DiffKind::Unchanged));
+    }
+}
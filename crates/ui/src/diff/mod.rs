@@ -0,0 +1,4 @@
+mod algorithm;
+mod diff_view;
+
+pub use diff_view::*;
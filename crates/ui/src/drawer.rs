@@ -3,14 +3,16 @@ use std::{rc::Rc, time::Duration};
 use gpui::{
     anchored, div, point, prelude::FluentBuilder as _, px, Animation, AnimationExt as _,
     AnyElement, App, Axis, ClickEvent, DefiniteLength, DismissEvent, Div, EventEmitter,
-    FocusHandle, InteractiveElement as _, IntoElement, KeyBinding, MouseButton, ParentElement,
-    Pixels, RenderOnce, Styled, Window,
+    FocusHandle, InteractiveElement as _, IntoElement, MouseButton, ParentElement, Pixels,
+    RenderOnce, Styled, Window,
 };
 
 use crate::{
     actions::Cancel,
+    animation::AnimationSettings as _,
     button::{Button, ButtonVariants as _},
     h_flex,
+    keymap::ContextBindings,
     modal::overlay_color,
     root::ContextModal as _,
     title_bar::TITLE_BAR_HEIGHT,
@@ -19,7 +21,9 @@ use crate::{
 
 const CONTEXT: &str = "Drawer";
 pub fn init(cx: &mut App) {
-    cx.bind_keys([KeyBinding::new("escape", Cancel, Some(CONTEXT))])
+    ContextBindings::new(CONTEXT)
+        .bind("escape", Cancel)
+        .register(cx);
 }
 
 #[derive(IntoElement)]
@@ -160,9 +164,8 @@ impl RenderOnce for Drawer {
                             .track_focus(&self.focus_handle)
                             .on_action({
                                 let on_close = self.on_close.clone();
+                                // Stop the Escape here, do not close the modal under the drawer.
                                 move |_: &Cancel, window, cx| {
-                                    cx.propagate();
-
                                     on_close(&ClickEvent::default(), window, cx);
                                     window.close_drawer(cx);
                                 }
@@ -227,19 +230,26 @@ impl RenderOnce for Drawer {
                                         .child(footer),
                                 )
                             })
-                            .with_animation(
-                                "slide",
-                                Animation::new(Duration::from_secs_f64(0.15)),
-                                move |this, delta| {
-                                    let y = px(-100.) + delta * px(100.);
-                                    this.map(|this| match placement {
-                                        Placement::Top => this.top(y),
-                                        Placement::Right => this.right(y),
-                                        Placement::Bottom => this.bottom(y),
-                                        Placement::Left => this.left(y),
-                                    })
-                                },
-                            ),
+                            .map(|this| {
+                                if !cx.animations_enabled() {
+                                    return this.into_any_element();
+                                }
+
+                                this.with_animation(
+                                    "slide",
+                                    Animation::new(Duration::from_secs_f64(0.15)),
+                                    move |this, delta| {
+                                        let y = px(-100.) + delta * px(100.);
+                                        this.map(|this| match placement {
+                                            Placement::Top => this.top(y),
+                                            Placement::Right => this.right(y),
+                                            Placement::Bottom => this.bottom(y),
+                                            Placement::Left => this.left(y),
+                                        })
+                                    },
+                                )
+                                .into_any_element()
+                            }),
                     ),
             )
     }
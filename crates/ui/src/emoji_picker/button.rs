@@ -0,0 +1,122 @@
+use std::rc::Rc;
+
+use gpui::{
+    App, AppContext as _, Corner, ElementId, Entity, Focusable as _, IntoElement, RenderOnce,
+    SharedString, Window,
+};
+
+use crate::{
+    button::{Button, ButtonVariants as _},
+    input::InputState,
+    popover::Popover,
+    Disableable, IconName, Sizable, Size,
+};
+
+use super::{EmojiPicker, EmojiPickerEvent};
+
+/// A button to open the [`EmojiPicker`] in a popover, the picked emoji replaces the selection
+/// of the [`EmojiPickerButton::input`], and is passed to the [`EmojiPickerButton::on_pick`].
+///
+/// ```ignore
+/// h_flex()
+///     .child(TextInput::new(&input))
+///     .child(EmojiPickerButton::new("emoji").input(&input))
+/// ```
+#[derive(IntoElement)]
+pub struct EmojiPickerButton {
+    id: ElementId,
+    anchor: Corner,
+    size: Size,
+    disabled: bool,
+    input: Option<Entity<InputState>>,
+    on_pick: Option<Rc<dyn Fn(&SharedString, &mut Window, &mut App)>>,
+}
+
+impl EmojiPickerButton {
+    pub fn new(id: impl Into<ElementId>) -> Self {
+        Self {
+            id: id.into(),
+            anchor: Corner::TopRight,
+            size: Size::default(),
+            disabled: false,
+            input: None,
+            on_pick: None,
+        }
+    }
+
+    /// Set the anchor corner of the popover, default is [`Corner::TopRight`].
+    pub fn anchor(mut self, anchor: Corner) -> Self {
+        self.anchor = anchor;
+        self
+    }
+
+    /// Set the input to insert the picked emoji at its cursor, and focus it after picking.
+    pub fn input(mut self, input: &Entity<InputState>) -> Self {
+        self.input = Some(input.clone());
+        self
+    }
+
+    /// Set the handler to call with the picked emoji, after it is inserted into the input.
+    pub fn on_pick(
+        mut self,
+        handler: impl Fn(&SharedString, &mut Window, &mut App) + 'static,
+    ) -> Self {
+        self.on_pick = Some(Rc::new(handler));
+        self
+    }
+}
+
+impl Sizable for EmojiPickerButton {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl Disableable for EmojiPickerButton {
+    fn disabled(mut self, disabled: bool) -> Self {
+        self.disabled = disabled;
+        self
+    }
+}
+
+impl RenderOnce for EmojiPickerButton {
+    fn render(self, _: &mut Window, _: &mut App) -> impl IntoElement {
+        let trigger = Button::new(self.id.clone())
+            .ghost()
+            .icon(IconName::Smile)
+            .with_size(self.size)
+            .disabled(self.disabled);
+        let input = self.input;
+        let on_pick = self.on_pick;
+
+        Popover::new(self.id)
+            .anchor(self.anchor)
+            .trigger(trigger)
+            .content(move |window, cx| {
+                let picker = cx.new(|cx| EmojiPicker::new(window, cx));
+                let input = input.clone();
+                let on_pick = on_pick.clone();
+                window
+                    .subscribe(
+                        &picker,
+                        cx,
+                        move |_, event: &EmojiPickerEvent, window, cx| match event {
+                            EmojiPickerEvent::Pick(emoji) => {
+                                if let Some(input) = &input {
+                                    input.update(cx, |input, cx| {
+                                        input.replace(emoji.clone(), window, cx);
+                                    });
+                                    window.focus(&input.focus_handle(cx));
+                                }
+                                if let Some(on_pick) = &on_pick {
+                                    on_pick(emoji, window, cx);
+                                }
+                            }
+                        },
+                    )
+                    .detach();
+                picker
+            })
+    }
+}
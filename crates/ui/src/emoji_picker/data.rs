@@ -0,0 +1,271 @@
+use std::sync::LazyLock;
+
+use gpui::SharedString;
+use rust_i18n::t;
+
+/// The emoji data, parsed once on the first use.
+static EMOJIS: LazyLock<Vec<Emoji>> = LazyLock::new(|| parse(include_str!("./emoji.txt")));
+
+/// The font of the emoji glyphs, the color emoji font of the platform.
+pub const EMOJI_FONT_FAMILY: &str = if cfg!(target_os = "macos") {
+    "Apple Color Emoji"
+} else if cfg!(target_os = "windows") {
+    "Segoe UI Emoji"
+} else {
+    "Noto Color Emoji"
+};
+
+/// The category of the emoji, in the order of the tabs.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash)]
+pub enum EmojiCategory {
+    /// The recently used emoji, see [`super::EmojiPickerSettings`].
+    Recent,
+    Smileys,
+    People,
+    Animals,
+    Food,
+    Travel,
+    Activities,
+    Objects,
+    Symbols,
+    Flags,
+}
+
+impl EmojiCategory {
+    pub fn all() -> [Self; 10] {
+        [
+            Self::Recent,
+            Self::Smileys,
+            Self::People,
+            Self::Animals,
+            Self::Food,
+            Self::Travel,
+            Self::Activities,
+            Self::Objects,
+            Self::Symbols,
+            Self::Flags,
+        ]
+    }
+
+    /// Returns the category of the `[key]` line in the data.
+    fn from_key(key: &str) -> Option<Self> {
+        Some(match key {
+            "smileys" => Self::Smileys,
+            "people" => Self::People,
+            "animals" => Self::Animals,
+            "food" => Self::Food,
+            "travel" => Self::Travel,
+            "activities" => Self::Activities,
+            "objects" => Self::Objects,
+            "symbols" => Self::Symbols,
+            "flags" => Self::Flags,
+            _ => return None,
+        })
+    }
+
+    /// Returns the emoji to show on the tab of the category.
+    pub fn icon(&self) -> &'static str {
+        match self {
+            Self::Recent => "🕘",
+            Self::Smileys => "😀",
+            Self::People => "👋",
+            Self::Animals => "🐶",
+            Self::Food => "🍔",
+            Self::Travel => "🚗",
+            Self::Activities => "⚽",
+            Self::Objects => "💡",
+            Self::Symbols => "❤️",
+            Self::Flags => "🏁",
+        }
+    }
+
+    pub fn label(&self) -> SharedString {
+        match self {
+            Self::Recent => t!("EmojiPicker.recent"),
+            Self::Smileys => t!("EmojiPicker.smileys"),
+            Self::People => t!("EmojiPicker.people"),
+            Self::Animals => t!("EmojiPicker.animals"),
+            Self::Food => t!("EmojiPicker.food"),
+            Self::Travel => t!("EmojiPicker.travel"),
+            Self::Activities => t!("EmojiPicker.activities"),
+            Self::Objects => t!("EmojiPicker.objects"),
+            Self::Symbols => t!("EmojiPicker.symbols"),
+            Self::Flags => t!("EmojiPicker.flags"),
+        }
+        .into()
+    }
+}
+
+/// The skin tone of the emoji, the Fitzpatrick modifiers of Unicode.
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Hash)]
+pub enum SkinTone {
+    /// The yellow emoji without the modifier.
+    #[default]
+    Default,
+    Light,
+    MediumLight,
+    Medium,
+    MediumDark,
+    Dark,
+}
+
+impl SkinTone {
+    pub fn all() -> [Self; 6] {
+        [
+            Self::Default,
+            Self::Light,
+            Self::MediumLight,
+            Self::Medium,
+            Self::MediumDark,
+            Self::Dark,
+        ]
+    }
+
+    /// Returns the modifier character, None for [`SkinTone::Default`].
+    pub fn modifier(&self) -> Option<char> {
+        match self {
+            Self::Default => None,
+            Self::Light => Some('\u{1F3FB}'),
+            Self::MediumLight => Some('\u{1F3FC}'),
+            Self::Medium => Some('\u{1F3FD}'),
+            Self::MediumDark => Some('\u{1F3FE}'),
+            Self::Dark => Some('\u{1F3FF}'),
+        }
+    }
+}
+
+/// An emoji of the picker.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub struct Emoji {
+    /// The emoji without the skin tone.
+    pub emoji: &'static str,
+    /// The CLDR short name, e.g.: `grinning face`.
+    pub name: &'static str,
+    pub category: EmojiCategory,
+    /// Whether the emoji supports the skin tones.
+    pub skin_tones: bool,
+}
+
+impl Emoji {
+    /// Returns all the emoji, in the category order.
+    pub fn all() -> &'static [Emoji] {
+        &EMOJIS
+    }
+
+    /// Returns the emoji with the `skin_tone` if it supports the skin tones.
+    ///
+    /// The modifier follows the first character, in place of its variation selector.
+    pub fn with_skin_tone(&self, skin_tone: SkinTone) -> SharedString {
+        let Some(modifier) = skin_tone.modifier().filter(|_| self.skin_tones) else {
+            return self.emoji.into();
+        };
+
+        let mut chars = self.emoji.chars();
+        let mut emoji = String::with_capacity(self.emoji.len() + 4);
+        emoji.extend(chars.next());
+        emoji.push(modifier);
+        let rest = chars.as_str();
+        emoji.push_str(rest.strip_prefix('\u{FE0F}').unwrap_or(rest));
+        emoji.into()
+    }
+}
+
+fn parse(data: &'static str) -> Vec<Emoji> {
+    let mut category = EmojiCategory::Smileys;
+    data.lines()
+        .filter(|line| !line.is_empty() && !line.starts_with("# "))
+        .filter_map(|line| {
+            if let Some(key) = line.strip_prefix('[').and_then(|l| l.strip_suffix(']')) {
+                category = EmojiCategory::from_key(key).unwrap_or(category);
+                return None;
+            }
+
+            let mut parts = line.splitn(3, ';');
+            let emoji = parts.next()?;
+            let name = parts.next()?;
+            Some(Emoji {
+                emoji,
+                name,
+                category,
+                skin_tones: parts.next() == Some("t"),
+            })
+        })
+        .collect()
+}
+
+/// Returns the indices in [`Emoji::all`] of the emoji that the name contains all words
+/// of the `query`, the names start with the query come first.
+pub(super) fn search(emojis: &[Emoji], query: &str) -> Vec<usize> {
+    let query = query.trim().to_lowercase();
+    let words = query.split_whitespace().collect::<Vec<_>>();
+    if words.is_empty() {
+        return vec![];
+    }
+
+    let mut matches = emojis
+        .iter()
+        .enumerate()
+        .filter(|(_, emoji)| words.iter().all(|word| emoji.name.contains(word)))
+        .map(|(ix, emoji)| (ix, !emoji.name.starts_with(&query)))
+        .collect::<Vec<_>>();
+    matches.sort_by_key(|(_, prefixed)| *prefixed);
+    matches.into_iter().map(|(ix, _)| ix).collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{search, Emoji, EmojiCategory, SkinTone};
+
+    #[test]
+    fn test_parse() {
+        let emojis = Emoji::all();
+        assert!(emojis.len() > 1800);
+        assert_eq!(emojis[0].emoji, "😀");
+        assert_eq!(emojis[0].name, "grinning face");
+        assert_eq!(emojis[0].category, EmojiCategory::Smileys);
+        assert!(emojis
+            .iter()
+            .all(|emoji| emoji.category != EmojiCategory::Recent));
+
+        let keycap = emojis.iter().find(|e| e.name == "keycap: #").unwrap();
+        assert_eq!(keycap.emoji, "#\u{FE0F}\u{20E3}");
+        assert_eq!(keycap.category, EmojiCategory::Symbols);
+        assert_eq!(emojis.last().unwrap().category, EmojiCategory::Flags);
+    }
+
+    #[test]
+    fn test_with_skin_tone() {
+        let find = |name: &str| *Emoji::all().iter().find(|e| e.name == name).unwrap();
+
+        let wave = find("waving hand");
+        assert!(wave.skin_tones);
+        assert_eq!(wave.with_skin_tone(SkinTone::Default).as_ref(), "👋");
+        assert_eq!(wave.with_skin_tone(SkinTone::Medium).as_ref(), "👋🏽");
+
+        // The variation selector is replaced by the modifier.
+        let hand = find("hand with fingers splayed");
+        assert_eq!(hand.emoji, "🖐\u{FE0F}");
+        assert_eq!(hand.with_skin_tone(SkinTone::Dark).as_ref(), "🖐🏿");
+
+        let smile = find("grinning face");
+        assert!(!smile.skin_tones);
+        assert_eq!(smile.with_skin_tone(SkinTone::Light).as_ref(), "😀");
+    }
+
+    #[test]
+    fn test_search() {
+        let emojis = Emoji::all();
+        assert!(search(emojis, " ").is_empty());
+
+        let results = search(emojis, "Cat");
+        assert!(results.len() > 5);
+        assert_eq!(emojis[results[0]].name.split(' ').next(), Some("cat"));
+        assert!(results.iter().all(|ix| emojis[*ix].name.contains("cat")));
+
+        let results = search(emojis, "face heart");
+        assert!(results
+            .iter()
+            .all(|ix| emojis[*ix].name.contains("face") && emojis[*ix].name.contains("heart")));
+        assert!(!results.is_empty());
+    }
+}
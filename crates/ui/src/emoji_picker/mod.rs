@@ -0,0 +1,11 @@
+mod button;
+mod data;
+mod picker;
+
+pub use button::*;
+pub use data::*;
+pub use picker::*;
+
+pub(crate) fn init(cx: &mut gpui::App) {
+    picker::init(cx);
+}
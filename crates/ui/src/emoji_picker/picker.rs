@@ -0,0 +1,435 @@
+use gpui::{
+    div, prelude::FluentBuilder as _, px, App, AppContext as _, Context, DismissEvent, Entity,
+    EventEmitter, FocusHandle, Focusable, Global, InteractiveElement as _, IntoElement,
+    ParentElement as _, Render, SharedString, Styled as _, Subscription, Window,
+};
+use rust_i18n::t;
+
+use crate::{
+    button::{Button, ButtonVariants as _},
+    grid::{Grid, GridDelegate, GridEvent, SelectDown, SelectUp},
+    h_flex,
+    input::{InputEvent, InputState, TextInput},
+    keymap::ContextBindings,
+    tab::{Tab, TabBar},
+    v_flex, ActiveTheme as _, Icon, IconName, Selectable as _, Sizable as _, StyledExt as _,
+};
+
+use super::{data::search, Emoji, EmojiCategory, SkinTone, EMOJI_FONT_FAMILY};
+
+const CONTEXT: &str = "EmojiPicker";
+/// The number of the emoji in a row.
+const COLUMNS: usize = 9;
+/// The max number of the recently used emoji.
+const MAX_RECENT: usize = 27;
+
+pub(crate) fn init(cx: &mut App) {
+    cx.set_global(EmojiPickerSettings::default());
+
+    // Move the selection while typing in the search input, `left` and `right` move the cursor.
+    ContextBindings::new(CONTEXT)
+        .bind("up", SelectUp)
+        .bind("down", SelectDown)
+        .register(cx);
+}
+
+/// The settings of the [`EmojiPicker`] shared by all the pickers, the picker of a popover
+/// is created every time it is opened.
+#[derive(Default)]
+pub struct EmojiPickerSettings {
+    /// The skin tone to apply to the emoji that support it.
+    pub skin_tone: SkinTone,
+    /// The indices in [`Emoji::all`] of the recently used emoji, the most recent first.
+    recent: Vec<usize>,
+}
+
+impl Global for EmojiPickerSettings {}
+
+impl EmojiPickerSettings {
+    pub fn global(cx: &App) -> &Self {
+        cx.global::<Self>()
+    }
+
+    pub fn global_mut(cx: &mut App) -> &mut Self {
+        cx.global_mut::<Self>()
+    }
+
+    /// Returns the recently used emoji, the most recent first.
+    pub fn recent(&self) -> impl Iterator<Item = &'static Emoji> + '_ {
+        self.recent.iter().map(|ix| &Emoji::all()[*ix])
+    }
+
+    pub fn clear_recent(&mut self) {
+        self.recent.clear();
+    }
+
+    fn push_recent(&mut self, ix: usize) {
+        self.recent.retain(|recent_ix| *recent_ix != ix);
+        self.recent.insert(0, ix);
+        self.recent.truncate(MAX_RECENT);
+    }
+}
+
+#[derive(Clone)]
+pub enum EmojiPickerEvent {
+    /// An emoji is picked, with the skin tone applied.
+    Pick(SharedString),
+}
+
+struct EmojiGridDelegate {
+    /// The indices in [`Emoji::all`].
+    items: Vec<usize>,
+    skin_tone: SkinTone,
+}
+
+impl GridDelegate for EmojiGridDelegate {
+    fn items_count(&self, _: &App) -> usize {
+        self.items.len()
+    }
+
+    fn render_item(
+        &self,
+        ix: usize,
+        _: &mut Window,
+        cx: &mut Context<Grid<Self>>,
+    ) -> Option<impl IntoElement> {
+        let emoji = Emoji::all().get(*self.items.get(ix)?)?;
+
+        Some(
+            div()
+                .size_full()
+                .flex()
+                .items_center()
+                .justify_center()
+                .rounded(cx.theme().radius)
+                .cursor_pointer()
+                .hover(|this| this.bg(cx.theme().accent))
+                .font_family(EMOJI_FONT_FAMILY)
+                .text_xl()
+                .child(emoji.with_skin_tone(self.skin_tone)),
+        )
+    }
+
+    fn render_empty(&self, _: &mut Window, cx: &mut Context<Grid<Self>>) -> impl IntoElement {
+        v_flex()
+            .size_full()
+            .justify_center()
+            .items_center()
+            .text_sm()
+            .text_color(cx.theme().muted_foreground)
+            .child(SharedString::from(t!("EmojiPicker.empty")))
+    }
+}
+
+/// A searchable emoji picker with the category tabs, the skin tones and the recently used
+/// emoji, it emits [`EmojiPickerEvent::Pick`] and then [`DismissEvent`] to close the popover.
+///
+/// Use [`super::EmojiPickerButton`] to open it in a popover and insert into an input.
+///
+/// The emoji are filtered by the search, or by the selected category, press `up` and `down`
+/// in the search input to move the selection, and `enter` to pick.
+pub struct EmojiPicker {
+    query_input: Entity<InputState>,
+    grid: Entity<Grid<EmojiGridDelegate>>,
+    category: EmojiCategory,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl EmojiPicker {
+    pub fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let query_input = cx.new(|cx| {
+            InputState::new(window, cx).placeholder(t!("EmojiPicker.search_placeholder"))
+        });
+
+        let settings = EmojiPickerSettings::global(cx);
+        let category = if settings.recent.is_empty() {
+            EmojiCategory::Smileys
+        } else {
+            EmojiCategory::Recent
+        };
+        let delegate = EmojiGridDelegate {
+            items: vec![],
+            skin_tone: settings.skin_tone,
+        };
+        let grid = cx.new(|cx| {
+            Grid::new(delegate, window, cx)
+                .columns(COLUMNS)
+                .gap(px(2.))
+                .scrollbar_visible(false)
+        });
+
+        let _subscriptions = vec![
+            cx.subscribe_in(&query_input, window, Self::on_query_input_event),
+            cx.subscribe_in(&grid, window, Self::on_grid_event),
+        ];
+
+        let mut this = Self {
+            query_input,
+            grid,
+            category,
+            _subscriptions,
+        };
+        this.update_items(window, cx);
+        this
+    }
+
+    /// Returns the selected category, it's ignored while searching.
+    pub fn category(&self) -> EmojiCategory {
+        self.category
+    }
+
+    /// Select the `category` and clear the search.
+    pub fn set_category(
+        &mut self,
+        category: EmojiCategory,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.category = category;
+        if !self.query_input.read(cx).value().is_empty() {
+            self.query_input
+                .update(cx, |input, cx| input.set_value("", window, cx));
+        }
+        self.update_items(window, cx);
+    }
+
+    /// Set the skin tone of the picker, and remember it for the later pickers.
+    pub fn set_skin_tone(&mut self, skin_tone: SkinTone, cx: &mut Context<Self>) {
+        EmojiPickerSettings::global_mut(cx).skin_tone = skin_tone;
+        self.grid.update(cx, |grid, cx| {
+            grid.delegate_mut().skin_tone = skin_tone;
+            cx.notify();
+        });
+        cx.notify();
+    }
+
+    fn is_searching(&self, cx: &App) -> bool {
+        !self.query_input.read(cx).value().trim().is_empty()
+    }
+
+    fn update_items(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let emojis = Emoji::all();
+        let items = if self.is_searching(cx) {
+            search(emojis, &self.query_input.read(cx).value())
+        } else if self.category == EmojiCategory::Recent {
+            EmojiPickerSettings::global(cx).recent.clone()
+        } else {
+            emojis
+                .iter()
+                .enumerate()
+                .filter(|(_, emoji)| emoji.category == self.category)
+                .map(|(ix, _)| ix)
+                .collect()
+        };
+
+        // Select the first result of the search, to pick it by pressing Enter.
+        let selected_index = (self.is_searching(cx) && !items.is_empty()).then_some(0);
+        self.grid.update(cx, |grid, cx| {
+            grid.delegate_mut().items = items;
+            grid.set_selected_index(selected_index, window, cx);
+            grid.scroll_to_item(0, cx);
+        });
+        cx.notify();
+    }
+
+    /// Returns the emoji of the item `ix` of the grid.
+    fn emoji_at(&self, ix: usize, cx: &App) -> Option<(usize, &'static Emoji)> {
+        let emoji_ix = *self.grid.read(cx).delegate().items.get(ix)?;
+        Some((emoji_ix, &Emoji::all()[emoji_ix]))
+    }
+
+    fn pick(&mut self, ix: usize, cx: &mut Context<Self>) {
+        let Some((emoji_ix, emoji)) = self.emoji_at(ix, cx) else {
+            return;
+        };
+
+        let settings = EmojiPickerSettings::global_mut(cx);
+        settings.push_recent(emoji_ix);
+        let emoji = emoji.with_skin_tone(settings.skin_tone);
+        cx.emit(EmojiPickerEvent::Pick(emoji));
+        cx.emit(DismissEvent);
+    }
+
+    fn move_selection(&mut self, delta: isize, window: &mut Window, cx: &mut Context<Self>) {
+        self.grid.update(cx, |grid, cx| {
+            let count = grid.delegate().items.len();
+            if count == 0 {
+                return;
+            }
+
+            let ix = match grid.selected_index() {
+                Some(ix) => (ix as isize + delta).clamp(0, count as isize - 1) as usize,
+                None => 0,
+            };
+            grid.set_selected_index(Some(ix), window, cx);
+        });
+        cx.notify();
+    }
+
+    fn on_query_input_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::Change(_) => self.update_items(window, cx),
+            InputEvent::PressEnter { .. } => {
+                if let Some(ix) = self.grid.read(cx).selected_index() {
+                    self.pick(ix, cx);
+                }
+            }
+            _ => {}
+        }
+    }
+
+    fn on_grid_event(
+        &mut self,
+        _: &Entity<Grid<EmojiGridDelegate>>,
+        event: &GridEvent,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            GridEvent::Select(_) => cx.notify(),
+            GridEvent::Confirm(ix) => self.pick(*ix, cx),
+            GridEvent::Cancel => {}
+        }
+    }
+
+    fn on_action_select_up(&mut self, _: &SelectUp, window: &mut Window, cx: &mut Context<Self>) {
+        self.move_selection(-(COLUMNS as isize), window, cx);
+    }
+
+    fn on_action_select_down(
+        &mut self,
+        _: &SelectDown,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.move_selection(COLUMNS as isize, window, cx);
+    }
+
+    fn render_footer(&self, cx: &mut Context<Self>) -> impl IntoElement {
+        let selected = self
+            .grid
+            .read(cx)
+            .selected_index()
+            .and_then(|ix| self.emoji_at(ix, cx));
+        let skin_tone = EmojiPickerSettings::global(cx).skin_tone;
+        let hand = Emoji::all()
+            .iter()
+            .find(|emoji| emoji.name == "raised hand");
+
+        h_flex()
+            .h_8()
+            .gap_2()
+            .justify_between()
+            .child(
+                h_flex()
+                    .flex_1()
+                    .min_w_0()
+                    .gap_2()
+                    .when_some(selected, |this, (_, emoji)| {
+                        this.child(
+                            div()
+                                .font_family(EMOJI_FONT_FAMILY)
+                                .text_2xl()
+                                .child(emoji.with_skin_tone(skin_tone)),
+                        )
+                        .child(
+                            div()
+                                .text_xs()
+                                .text_color(cx.theme().muted_foreground)
+                                .truncate()
+                                .child(emoji.name),
+                        )
+                    }),
+            )
+            .child(
+                h_flex()
+                    .flex_shrink_0()
+                    .children(SkinTone::all().into_iter().enumerate().map(|(ix, tone)| {
+                        let swatch = hand.map_or("✋".into(), |hand| hand.with_skin_tone(tone));
+                        Button::new(("skin-tone", ix))
+                            .ghost()
+                            .xsmall()
+                            .font_family(EMOJI_FONT_FAMILY)
+                            .label(swatch)
+                            .selected(tone == skin_tone)
+                            .on_click(cx.listener(move |this, _, _, cx| {
+                                this.set_skin_tone(tone, cx);
+                            }))
+                    })),
+            )
+    }
+}
+
+impl EventEmitter<EmojiPickerEvent> for EmojiPicker {}
+impl EventEmitter<DismissEvent> for EmojiPicker {}
+
+impl Focusable for EmojiPicker {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.query_input.focus_handle(cx)
+    }
+}
+
+impl Render for EmojiPicker {
+    fn render(&mut self, _: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let searching = self.is_searching(cx);
+        let categories = EmojiCategory::all()
+            .into_iter()
+            .filter(|category| {
+                *category != EmojiCategory::Recent
+                    || !EmojiPickerSettings::global(cx).recent.is_empty()
+            })
+            .collect::<Vec<_>>();
+        let selected_ix = categories
+            .iter()
+            .position(|category| *category == self.category)
+            .unwrap_or_default();
+
+        v_flex()
+            .key_context(CONTEXT)
+            .w(px(COLUMNS as f32 * 36.))
+            .gap_2()
+            .on_action(cx.listener(Self::on_action_select_up))
+            .on_action(cx.listener(Self::on_action_select_down))
+            .child(
+                TextInput::new(&self.query_input)
+                    .small()
+                    .prefix(Icon::new(IconName::Search).text_color(cx.theme().muted_foreground))
+                    .cleanable(),
+            )
+            .child(
+                TabBar::new("emoji-categories")
+                    .underline()
+                    .small()
+                    .when(!searching, |this| this.selected_index(selected_ix))
+                    .children(
+                        categories.iter().map(|category| {
+                            Tab::new(category.icon()).font_family(EMOJI_FONT_FAMILY)
+                        }),
+                    )
+                    .on_click(cx.listener(move |this, ix: &usize, window, cx| {
+                        if let Some(category) = categories.get(*ix) {
+                            this.set_category(*category, window, cx);
+                        }
+                    })),
+            )
+            .child(
+                div()
+                    .text_xs()
+                    .font_semibold()
+                    .text_color(cx.theme().muted_foreground)
+                    .child(if searching {
+                        SharedString::from(t!("EmojiPicker.search_results"))
+                    } else {
+                        self.category.label()
+                    }),
+            )
+            .child(div().h(px(5. * 36.)).child(self.grid.clone()))
+            .child(self.render_footer(cx))
+    }
+}
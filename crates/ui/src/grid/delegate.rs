@@ -0,0 +1,105 @@
+use gpui::{
+    div, App, Context, IntoElement, ParentElement as _, Pixels, SharedString, Styled as _, Window,
+};
+use rust_i18n::t;
+
+use crate::{
+    grid::Grid, indicator::Indicator, v_flex, ActiveTheme as _, Icon, IconName, Sizable as _,
+};
+
+/// A delegate for the [`Grid`].
+#[allow(unused)]
+pub trait GridDelegate: Sized + 'static {
+    /// Return the number of items in the grid.
+    fn items_count(&self, cx: &App) -> usize;
+
+    /// Render the item at the given index, the item is sized to fill the cell.
+    ///
+    /// Return None will render an empty cell.
+    fn render_item(
+        &self,
+        ix: usize,
+        window: &mut Window,
+        cx: &mut Context<Grid<Self>>,
+    ) -> Option<impl IntoElement>;
+
+    /// Return the height of the item at the given index for the column `width`.
+    ///
+    /// This is called for every item to lay out the grid before rendering, so keep it cheap.
+    /// The items in a row have the height of the tallest one, unless [`Grid::masonry`].
+    ///
+    /// Default is the `width`, to render the square cells.
+    fn item_height(&self, ix: usize, width: Pixels, cx: &App) -> Pixels {
+        width
+    }
+
+    /// Return a Element to show when grid is empty.
+    fn render_empty(&self, window: &mut Window, cx: &mut Context<Grid<Self>>) -> impl IntoElement {
+        v_flex()
+            .size_full()
+            .justify_center()
+            .items_center()
+            .gap_2()
+            .text_color(cx.theme().muted_foreground.opacity(0.6))
+            .child(Icon::new(IconName::Inbox).size_12())
+            .child(div().text_sm().child(SharedString::from(t!("List.empty"))))
+    }
+
+    /// Returns the loading state to show the loading view.
+    fn loading(&self, cx: &App) -> bool {
+        false
+    }
+
+    /// Returns a Element to show when loading, default is a spinner.
+    fn render_loading(
+        &self,
+        window: &mut Window,
+        cx: &mut Context<Grid<Self>>,
+    ) -> impl IntoElement {
+        v_flex()
+            .size_full()
+            .justify_center()
+            .items_center()
+            .child(Indicator::new().large().color(cx.theme().muted_foreground))
+    }
+
+    /// Set the selected index, just store the ix, don't confirm.
+    fn set_selected_index(
+        &mut self,
+        ix: Option<usize>,
+        window: &mut Window,
+        cx: &mut Context<Grid<Self>>,
+    ) {
+    }
+
+    /// Set the confirm and give the selected index,
+    /// this is means user have clicked the item or pressed Enter.
+    ///
+    /// This will always to `set_selected_index` before confirm.
+    fn confirm(&mut self, secondary: bool, window: &mut Window, cx: &mut Context<Grid<Self>>) {}
+
+    /// Cancel the selection, e.g.: Pressed ESC.
+    fn cancel(&mut self, window: &mut Window, cx: &mut Context<Grid<Self>>) {}
+
+    /// Return true to enable load more data when scrolling to the bottom.
+    ///
+    /// Default: true
+    fn is_eof(&self, cx: &App) -> bool {
+        true
+    }
+
+    /// Returns a threshold value (n items), when scrolling to the bottom,
+    /// the remaining number of items triggers `load_more`.
+    ///
+    /// Default: 20 items
+    fn load_more_threshold(&self) -> usize {
+        20
+    }
+
+    /// Load more data when the grid is scrolled to the bottom.
+    ///
+    /// This is always called when the grid is near the bottom,
+    /// so you must check if there is more data to load or lock
+    /// the loading state.
+    fn load_more(&mut self, window: &mut Window, cx: &mut Context<Grid<Self>>) {}
+}
@@ -0,0 +1,499 @@
+use gpui::{
+    actions, canvas, div, prelude::FluentBuilder, px, App, Bounds, Context, EventEmitter,
+    FocusHandle, Focusable, InteractiveElement, IntoElement, KeyBinding, MouseButton,
+    MouseDownEvent, ParentElement, Pixels, Render, ScrollHandle, StatefulInteractiveElement,
+    Styled, Task, Window,
+};
+
+use crate::{
+    actions::{Cancel, Confirm},
+    grid::{
+        layout::{columns_for_width, GridDirection, GridLayout},
+        GridDelegate,
+    },
+    scroll::{Scrollbar, ScrollbarState},
+    ActiveTheme,
+};
+
+const CONTEXT: &str = "Grid";
+
+actions!(grid, [SelectUp, SelectDown, SelectLeft, SelectRight]);
+
+pub fn init(cx: &mut App) {
+    let context: Option<&str> = Some(CONTEXT);
+    cx.bind_keys([
+        KeyBinding::new("escape", Cancel, context),
+        KeyBinding::new("enter", Confirm { secondary: false }, context),
+        KeyBinding::new("secondary-enter", Confirm { secondary: true }, context),
+        KeyBinding::new("up", SelectUp, context),
+        KeyBinding::new("down", SelectDown, context),
+        KeyBinding::new("left", SelectLeft, context),
+        KeyBinding::new("right", SelectRight, context),
+    ]);
+}
+
+#[derive(Clone)]
+pub enum GridEvent {
+    /// Move to select item.
+    Select(usize),
+    /// Click on item or pressed Enter.
+    Confirm(usize),
+    /// Pressed ESC to deselect the item.
+    Cancel,
+}
+
+#[derive(Debug, Clone, Copy)]
+enum GridColumns {
+    Fixed(usize),
+    /// The number of columns is by the width of the grid.
+    Responsive {
+        min_width: Pixels,
+    },
+}
+
+/// A virtualized grid to arrange the items of the [`GridDelegate`] into columns,
+/// only the visible cells are rendered.
+///
+/// Use [`Grid::masonry`] for the variable height items, e.g.: a waterfall of images.
+pub struct Grid<D: GridDelegate> {
+    focus_handle: FocusHandle,
+    delegate: D,
+    columns: GridColumns,
+    gap: Pixels,
+    masonry: bool,
+    selectable: bool,
+    scrollbar_visible: bool,
+    scroll_handle: ScrollHandle,
+    scroll_state: ScrollbarState,
+    layout: GridLayout,
+    /// The bounds of the grid, to lay out the items by the width.
+    bounds: Bounds<Pixels>,
+    selected_index: Option<usize>,
+    deferred_scroll_to_index: Option<usize>,
+    _load_more_task: Task<()>,
+}
+
+impl<D> Grid<D>
+where
+    D: GridDelegate,
+{
+    pub fn new(delegate: D, _: &mut Window, cx: &mut Context<Self>) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+            delegate,
+            columns: GridColumns::Fixed(3),
+            gap: px(8.),
+            masonry: false,
+            selectable: true,
+            scrollbar_visible: true,
+            scroll_handle: ScrollHandle::default(),
+            scroll_state: ScrollbarState::default(),
+            layout: GridLayout::default(),
+            bounds: Bounds::default(),
+            selected_index: None,
+            deferred_scroll_to_index: None,
+            _load_more_task: Task::ready(()),
+        }
+    }
+
+    /// Set a fixed number of columns, default is 3.
+    pub fn columns(mut self, columns: usize) -> Self {
+        self.columns = GridColumns::Fixed(columns.max(1));
+        self
+    }
+
+    /// Set the columns to fit the width of the grid, each column is at least `min_width`.
+    pub fn min_column_width(mut self, min_width: impl Into<Pixels>) -> Self {
+        self.columns = GridColumns::Responsive {
+            min_width: min_width.into(),
+        };
+        self
+    }
+
+    /// Set the gap between the cells, default is 8px.
+    pub fn gap(mut self, gap: impl Into<Pixels>) -> Self {
+        self.gap = gap.into();
+        self
+    }
+
+    /// Set true to place each item in the shortest column to balance the column heights,
+    /// for the items with variable heights, see [`GridDelegate::item_height`].
+    ///
+    /// Default is false, the items are placed in rows.
+    pub fn masonry(mut self, masonry: bool) -> Self {
+        self.masonry = masonry;
+        self
+    }
+
+    /// Sets whether the grid is selectable, default is true.
+    pub fn selectable(mut self, selectable: bool) -> Self {
+        self.selectable = selectable;
+        self
+    }
+
+    /// Set the visibility of the scrollbar, default is true.
+    pub fn scrollbar_visible(mut self, visible: bool) -> Self {
+        self.scrollbar_visible = visible;
+        self
+    }
+
+    /// Set a fixed number of columns, see [`Grid::columns`].
+    pub fn set_columns(&mut self, columns: usize, cx: &mut Context<Self>) {
+        self.columns = GridColumns::Fixed(columns.max(1));
+        cx.notify();
+    }
+
+    /// Set the minimum column width to fit the width, see [`Grid::min_column_width`].
+    pub fn set_min_column_width(&mut self, min_width: impl Into<Pixels>, cx: &mut Context<Self>) {
+        self.columns = GridColumns::Responsive {
+            min_width: min_width.into(),
+        };
+        cx.notify();
+    }
+
+    /// Set the masonry layout, see [`Grid::masonry`].
+    pub fn set_masonry(&mut self, masonry: bool, cx: &mut Context<Self>) {
+        self.masonry = masonry;
+        cx.notify();
+    }
+
+    pub fn delegate(&self) -> &D {
+        &self.delegate
+    }
+
+    pub fn delegate_mut(&mut self) -> &mut D {
+        &mut self.delegate
+    }
+
+    pub fn focus(&mut self, window: &mut Window, _: &mut App) {
+        window.focus(&self.focus_handle);
+    }
+
+    pub fn selected_index(&self) -> Option<usize> {
+        self.selected_index
+    }
+
+    pub fn set_selected_index(
+        &mut self,
+        ix: Option<usize>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.selected_index = ix;
+        self.delegate.set_selected_index(ix, window, cx);
+        self.deferred_scroll_to_index = ix;
+        cx.notify();
+    }
+
+    /// Returns the number of columns in the last layout.
+    pub fn columns_count(&self) -> usize {
+        self.layout.columns
+    }
+
+    /// Scroll to the item at the given index, on the next layout.
+    pub fn scroll_to_item(&mut self, ix: usize, cx: &mut Context<Self>) {
+        self.deferred_scroll_to_index = Some(ix);
+        cx.notify();
+    }
+
+    fn prepare_layout(&mut self, cx: &mut Context<Self>) {
+        let width = self.bounds.size.width;
+        let columns = match self.columns {
+            GridColumns::Fixed(columns) => columns,
+            GridColumns::Responsive { min_width } => columns_for_width(width, min_width, self.gap),
+        };
+
+        let delegate = &self.delegate;
+        self.layout = GridLayout::new(
+            delegate.items_count(cx),
+            columns,
+            width,
+            self.gap,
+            self.masonry,
+            |ix, width| delegate.item_height(ix, width, cx),
+        );
+    }
+
+    /// Scroll the least to show the item at the given index.
+    fn scroll_to_cell(&self, ix: usize) {
+        let Some(cell) = self.layout.cells.get(ix) else {
+            return;
+        };
+
+        let mut offset = self.scroll_handle.offset();
+        let top = -offset.y;
+        let height = self.bounds.size.height;
+        if cell.bounds.top() < top {
+            offset.y = -cell.bounds.top();
+        } else if cell.bounds.bottom() > top + height {
+            offset.y = -(cell.bounds.bottom() - height);
+        }
+        self.scroll_handle.set_offset(offset);
+    }
+
+    /// Dispatch delegate's `load_more` method when the
+    /// visible range is near the end.
+    fn load_more_if_need(
+        &mut self,
+        items_count: usize,
+        visible_end: usize,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let threshold = self.delegate.load_more_threshold();
+        if visible_end >= items_count.saturating_sub(threshold) {
+            if !self.delegate.is_eof(cx) {
+                return;
+            }
+
+            self._load_more_task = cx.spawn_in(window, async move |view, cx| {
+                _ = view.update_in(cx, |view, window, cx| {
+                    view.delegate.load_more(window, cx);
+                });
+            });
+        }
+    }
+
+    fn select_item(&mut self, ix: usize, window: &mut Window, cx: &mut Context<Self>) {
+        self.selected_index = Some(ix);
+        self.delegate.set_selected_index(Some(ix), window, cx);
+        self.deferred_scroll_to_index = Some(ix);
+        cx.emit(GridEvent::Select(ix));
+        cx.notify();
+    }
+
+    fn move_selection(
+        &mut self,
+        direction: GridDirection,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if self.layout.cells.is_empty() {
+            return;
+        }
+
+        let ix = match self.selected_index {
+            Some(ix) => match self.layout.neighbor(ix, direction) {
+                Some(ix) => ix,
+                None => return,
+            },
+            None => 0,
+        };
+        self.select_item(ix, window, cx);
+    }
+
+    fn on_action_select_up(&mut self, _: &SelectUp, window: &mut Window, cx: &mut Context<Self>) {
+        self.move_selection(GridDirection::Up, window, cx);
+    }
+
+    fn on_action_select_down(
+        &mut self,
+        _: &SelectDown,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.move_selection(GridDirection::Down, window, cx);
+    }
+
+    fn on_action_select_left(
+        &mut self,
+        _: &SelectLeft,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.move_selection(GridDirection::Left, window, cx);
+    }
+
+    fn on_action_select_right(
+        &mut self,
+        _: &SelectRight,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.move_selection(GridDirection::Right, window, cx);
+    }
+
+    fn on_action_cancel(&mut self, _: &Cancel, window: &mut Window, cx: &mut Context<Self>) {
+        cx.propagate();
+        self.selected_index = None;
+        self.delegate.set_selected_index(None, window, cx);
+        self.delegate.cancel(window, cx);
+        cx.emit(GridEvent::Cancel);
+        cx.notify();
+    }
+
+    fn on_action_confirm(
+        &mut self,
+        confirm: &Confirm,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let Some(ix) = self.selected_index else {
+            return;
+        };
+
+        self.delegate.set_selected_index(Some(ix), window, cx);
+        self.delegate.confirm(confirm.secondary, window, cx);
+        cx.emit(GridEvent::Confirm(ix));
+        cx.notify();
+    }
+
+    fn render_cell(
+        &self,
+        ix: usize,
+        bounds: Bounds<Pixels>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> impl IntoElement {
+        let selected = self.selected_index == Some(ix);
+
+        div()
+            .id(ix)
+            .absolute()
+            .left(bounds.origin.x)
+            .top(bounds.origin.y)
+            .w(bounds.size.width)
+            .h(bounds.size.height)
+            .children(self.delegate.render_item(ix, window, cx))
+            .when(selected, |this| {
+                this.child(
+                    div()
+                        .absolute()
+                        .top_0()
+                        .left_0()
+                        .size_full()
+                        .border_2()
+                        .border_color(cx.theme().ring)
+                        .rounded(cx.theme().radius),
+                )
+            })
+            .when(self.selectable, |this| {
+                this.on_mouse_down(
+                    MouseButton::Left,
+                    cx.listener(move |this, ev: &MouseDownEvent, window, cx| {
+                        window.focus(&this.focus_handle);
+                        this.select_item(ix, window, cx);
+                        this.on_action_confirm(
+                            &Confirm {
+                                secondary: ev.modifiers.secondary(),
+                            },
+                            window,
+                            cx,
+                        );
+                    }),
+                )
+            })
+    }
+
+    fn render_items(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let top = -self.scroll_handle.offset().y;
+        let bottom = top + self.bounds.size.height;
+        let visible_range = self.layout.visible_range(top, bottom);
+        self.load_more_if_need(self.layout.cells.len(), visible_range.end, window, cx);
+
+        let cells = visible_range
+            .filter_map(|ix| {
+                let bounds = self.layout.cells[ix].bounds;
+                (bounds.bottom() > top && bounds.top() < bottom)
+                    .then(|| self.render_cell(ix, bounds, window, cx))
+            })
+            .collect::<Vec<_>>();
+
+        div()
+            .id("grid-items")
+            .size_full()
+            .overflow_y_scroll()
+            .track_scroll(&self.scroll_handle)
+            .child(
+                div()
+                    .relative()
+                    .w_full()
+                    .h(self.layout.content_height)
+                    .children(cells),
+            )
+    }
+}
+
+impl<D> Focusable for Grid<D>
+where
+    D: GridDelegate,
+{
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+impl<D> EventEmitter<GridEvent> for Grid<D> where D: GridDelegate {}
+
+impl<D> Render for Grid<D>
+where
+    D: GridDelegate,
+{
+    fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let view = cx.entity().clone();
+        let loading = self.delegate.loading(cx);
+        if !loading {
+            self.prepare_layout(cx);
+            if let Some(ix) = self.deferred_scroll_to_index.take() {
+                self.scroll_to_cell(ix);
+            }
+        }
+        let is_empty = self.layout.cells.is_empty();
+
+        div()
+            .key_context(CONTEXT)
+            .id("grid")
+            .track_focus(&self.focus_handle)
+            .size_full()
+            .relative()
+            .overflow_hidden()
+            // To save the bounds of the grid, and lay out again if the size is changed.
+            .child(
+                canvas(
+                    move |bounds, _, cx| {
+                        view.update(cx, |r, cx| {
+                            if r.bounds.size != bounds.size {
+                                cx.notify();
+                            }
+                            r.bounds = bounds;
+                        })
+                    },
+                    |_, _, _, _| {},
+                )
+                .absolute()
+                .size_full(),
+            )
+            .when(loading, |this| {
+                this.child(self.delegate.render_loading(window, cx))
+            })
+            .when(!loading, |this| {
+                this.on_action(cx.listener(Self::on_action_cancel))
+                    .on_action(cx.listener(Self::on_action_confirm))
+                    .on_action(cx.listener(Self::on_action_select_up))
+                    .on_action(cx.listener(Self::on_action_select_down))
+                    .on_action(cx.listener(Self::on_action_select_left))
+                    .on_action(cx.listener(Self::on_action_select_right))
+                    .map(|this| {
+                        if is_empty {
+                            this.child(self.delegate.render_empty(window, cx))
+                        } else {
+                            this.child(self.render_items(window, cx))
+                        }
+                    })
+                    .when(self.scrollbar_visible && !is_empty, |this| {
+                        this.child(
+                            div()
+                                .absolute()
+                                .top_0()
+                                .left_0()
+                                .right_0()
+                                .bottom_0()
+                                .child(Scrollbar::vertical(
+                                    &self.scroll_state,
+                                    &self.scroll_handle,
+                                )),
+                        )
+                    })
+            })
+    }
+}
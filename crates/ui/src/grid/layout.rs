@@ -0,0 +1,274 @@
+use std::ops::Range;
+
+use gpui::{point, px, size, Bounds, Pixels};
+
+/// The direction to move the selection in the [`super::Grid`].
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub(crate) enum GridDirection {
+    Up,
+    Down,
+    Left,
+    Right,
+}
+
+/// Returns the number of columns to fit in the `width`, each column is at least `min_width`.
+pub(crate) fn columns_for_width(width: Pixels, min_width: Pixels, gap: Pixels) -> usize {
+    if min_width <= px(0.) {
+        return 1;
+    }
+
+    (((width + gap) / (min_width + gap)).floor() as usize).max(1)
+}
+
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub(crate) struct GridCell {
+    pub(crate) bounds: Bounds<Pixels>,
+    pub(crate) column: usize,
+}
+
+/// The positions of the items in the [`super::Grid`].
+///
+/// The items are placed in rows, or in the shortest column for the masonry layout,
+/// so the top of the cells never decrease by the index in both layouts.
+#[derive(Debug, Clone, Default)]
+pub(crate) struct GridLayout {
+    pub(crate) cells: Vec<GridCell>,
+    pub(crate) columns: usize,
+    pub(crate) content_height: Pixels,
+    masonry: bool,
+    max_item_height: Pixels,
+}
+
+impl GridLayout {
+    /// Lay out `count` items in `columns` to fit the `width`, the `item_height` is called
+    /// with the index and the column width.
+    pub(crate) fn new(
+        count: usize,
+        columns: usize,
+        width: Pixels,
+        gap: Pixels,
+        masonry: bool,
+        mut item_height: impl FnMut(usize, Pixels) -> Pixels,
+    ) -> Self {
+        let columns = columns.max(1);
+        let column_width = ((width - gap * (columns - 1) as f32) / columns as f32).max(px(0.));
+        let mut column_heights = vec![px(0.); columns];
+        let mut cells = Vec::with_capacity(count);
+        let mut max_item_height = px(0.);
+        let mut row_top = px(0.);
+        let mut row_height = px(0.);
+
+        for ix in 0..count {
+            let height = item_height(ix, column_width).max(px(0.));
+            max_item_height = max_item_height.max(height);
+
+            let (column, top) = if masonry {
+                // Place in the shortest column, the left one first.
+                let column = (0..columns)
+                    .min_by(|a, b| column_heights[*a].partial_cmp(&column_heights[*b]).unwrap())
+                    .unwrap_or(0);
+                let top = column_heights[column];
+                column_heights[column] = top + height + gap;
+                (column, top)
+            } else {
+                let column = ix % columns;
+                if column == 0 && ix > 0 {
+                    row_top = row_top + row_height + gap;
+                    row_height = px(0.);
+                }
+                row_height = row_height.max(height);
+                (column, row_top)
+            };
+
+            let left = (column_width + gap) * column as f32;
+            cells.push(GridCell {
+                bounds: Bounds::new(point(left, top), size(column_width, height)),
+                column,
+            });
+        }
+
+        let content_height = if count == 0 {
+            px(0.)
+        } else if masonry {
+            column_heights.into_iter().fold(px(0.), Pixels::max) - gap
+        } else {
+            row_top + row_height
+        };
+
+        Self {
+            cells,
+            columns,
+            content_height,
+            masonry,
+            max_item_height,
+        }
+    }
+
+    /// Returns the range of the items may be visible between the `top` and `bottom`,
+    /// the items in the range should still be checked by the bounds for the masonry layout.
+    pub(crate) fn visible_range(&self, top: Pixels, bottom: Pixels) -> Range<usize> {
+        let max_item_height = self.max_item_height;
+        let start = self
+            .cells
+            .partition_point(|cell| cell.bounds.origin.y + max_item_height <= top);
+        let end = self
+            .cells
+            .partition_point(|cell| cell.bounds.origin.y < bottom);
+
+        start..end.max(start)
+    }
+
+    /// Returns the index of the item next to the `ix` in the `direction`.
+    pub(crate) fn neighbor(&self, ix: usize, direction: GridDirection) -> Option<usize> {
+        let cell = self.cells.get(ix)?;
+        let len = self.cells.len();
+
+        if !self.masonry {
+            let columns = self.columns;
+            return match direction {
+                GridDirection::Left => ix.checked_sub(1),
+                GridDirection::Right => (ix + 1 < len).then_some(ix + 1),
+                GridDirection::Up => ix.checked_sub(columns),
+                // Move to the last item if the next row is shorter.
+                GridDirection::Down => {
+                    Some((ix + columns).min(len - 1)).filter(|next| next / columns > ix / columns)
+                }
+            };
+        }
+
+        match direction {
+            GridDirection::Up => (0..ix)
+                .rev()
+                .find(|ix| self.cells[*ix].column == cell.column),
+            GridDirection::Down => (ix + 1..len).find(|ix| self.cells[*ix].column == cell.column),
+            GridDirection::Left | GridDirection::Right => {
+                let column = if direction == GridDirection::Left {
+                    cell.column.checked_sub(1)?
+                } else {
+                    cell.column + 1
+                };
+                let center = cell.bounds.center().y;
+
+                // The nearest item by the vertical center in the next column.
+                (0..len)
+                    .filter(|ix| self.cells[*ix].column == column)
+                    .min_by(|a, b| {
+                        let a = (self.cells[*a].bounds.center().y - center).abs();
+                        let b = (self.cells[*b].bounds.center().y - center).abs();
+                        a.partial_cmp(&b).unwrap()
+                    })
+            }
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::px;
+
+    use super::*;
+
+    #[test]
+    fn test_columns_for_width() {
+        assert_eq!(columns_for_width(px(400.), px(100.), px(0.)), 4);
+        assert_eq!(columns_for_width(px(400.), px(100.), px(10.)), 3);
+        assert_eq!(columns_for_width(px(430.), px(100.), px(10.)), 4);
+        assert_eq!(columns_for_width(px(50.), px(100.), px(10.)), 1);
+        assert_eq!(columns_for_width(px(400.), px(0.), px(10.)), 1);
+    }
+
+    #[test]
+    fn test_grid_layout() {
+        let layout = GridLayout::new(5, 2, px(210.), px(10.), false, |ix, _| {
+            px(if ix == 1 { 80. } else { 50. })
+        });
+        let origins = layout
+            .cells
+            .iter()
+            .map(|cell| (cell.bounds.origin.x, cell.bounds.origin.y, cell.column))
+            .collect::<Vec<_>>();
+
+        assert_eq!(
+            origins,
+            vec![
+                (px(0.), px(0.), 0),
+                (px(110.), px(0.), 1),
+                (px(0.), px(90.), 0),
+                (px(110.), px(90.), 1),
+                (px(0.), px(150.), 0),
+            ]
+        );
+        assert_eq!(layout.cells[0].bounds.size.width, px(100.));
+        assert_eq!(layout.content_height, px(200.));
+        assert_eq!(
+            GridLayout::new(0, 2, px(210.), px(10.), false, |_, _| px(50.)).content_height,
+            px(0.)
+        );
+    }
+
+    #[test]
+    fn test_masonry_layout() {
+        let heights = [100., 50., 30., 40., 10.];
+        let layout = GridLayout::new(5, 2, px(210.), px(10.), true, |ix, _| px(heights[ix]));
+        let origins = layout
+            .cells
+            .iter()
+            .map(|cell| (cell.column, cell.bounds.origin.y))
+            .collect::<Vec<_>>();
+
+        assert_eq!(
+            origins,
+            vec![
+                (0, px(0.)),
+                (1, px(0.)),
+                (1, px(60.)),
+                (1, px(100.)),
+                (0, px(110.)),
+            ]
+        );
+        assert_eq!(layout.content_height, px(140.));
+    }
+
+    #[test]
+    fn test_visible_range() {
+        let layout = GridLayout::new(10, 2, px(210.), px(10.), false, |_, _| px(50.));
+        // Rows at 0, 60, 120, 180, 240.
+        assert_eq!(layout.visible_range(px(0.), px(100.)), 0..4);
+        assert_eq!(layout.visible_range(px(70.), px(150.)), 2..6);
+        assert_eq!(layout.visible_range(px(300.), px(400.)), 10..10);
+
+        let heights = [200., 20., 20., 20., 20., 20.];
+        let layout = GridLayout::new(6, 2, px(210.), px(10.), true, |ix, _| px(heights[ix]));
+        // The first tall item is still visible.
+        assert_eq!(layout.visible_range(px(150.), px(160.)), 0..6);
+    }
+
+    #[test]
+    fn test_grid_neighbor() {
+        let layout = GridLayout::new(7, 3, px(320.), px(10.), false, |_, _| px(50.));
+        assert_eq!(layout.neighbor(0, GridDirection::Left), None);
+        assert_eq!(layout.neighbor(2, GridDirection::Right), Some(3));
+        assert_eq!(layout.neighbor(6, GridDirection::Right), None);
+        assert_eq!(layout.neighbor(1, GridDirection::Up), None);
+        assert_eq!(layout.neighbor(4, GridDirection::Up), Some(1));
+        assert_eq!(layout.neighbor(1, GridDirection::Down), Some(4));
+        assert_eq!(layout.neighbor(5, GridDirection::Down), Some(6));
+        assert_eq!(layout.neighbor(6, GridDirection::Down), None);
+        assert_eq!(layout.neighbor(7, GridDirection::Down), None);
+    }
+
+    #[test]
+    fn test_masonry_neighbor() {
+        let heights = [60., 20., 20., 20., 50.];
+        // Column 0: 0, 4; column 1: 1, 2, 3.
+        let layout = GridLayout::new(5, 2, px(210.), px(10.), true, |ix, _| px(heights[ix]));
+        assert_eq!(layout.neighbor(0, GridDirection::Down), Some(4));
+        assert_eq!(layout.neighbor(4, GridDirection::Up), Some(0));
+        assert_eq!(layout.neighbor(2, GridDirection::Up), Some(1));
+        assert_eq!(layout.neighbor(3, GridDirection::Down), None);
+        assert_eq!(layout.neighbor(0, GridDirection::Right), Some(2));
+        assert_eq!(layout.neighbor(3, GridDirection::Left), Some(4));
+        assert_eq!(layout.neighbor(4, GridDirection::Right), Some(3));
+        assert_eq!(layout.neighbor(1, GridDirection::Right), None);
+    }
+}
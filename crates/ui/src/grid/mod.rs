@@ -0,0 +1,6 @@
+mod delegate;
+mod grid;
+mod layout;
+
+pub use delegate::*;
+pub use grid::*;
@@ -286,6 +286,15 @@ impl SyntaxHighlighter {
         self.text.len_bytes() == 0
     }
 
+    /// Returns the syntax tree of the text, `None` if not parsed or the language is plain text.
+    pub(crate) fn tree(&self) -> Option<&Tree> {
+        if self.language.as_ref() == "text" {
+            return None;
+        }
+
+        self.old_tree.as_ref()
+    }
+
     /// Highlight the given text, returning a map from byte ranges to highlight captures.
     /// Uses incremental parsing, detects changed ranges, and caches unchanged results.
     pub fn update(&mut self, edit: Option<InputEdit>, text: &Rope, cx: &App) {
@@ -14,6 +14,7 @@ pub enum IconName {
     ArrowUp,
     Asterisk,
     Bell,
+    Bold,
     BookOpen,
     Bot,
     Building2,
@@ -24,6 +25,8 @@ pub enum IconName {
     ChevronLeft,
     ChevronRight,
     ChevronUp,
+    ChevronsLeft,
+    ChevronsRight,
     ChevronsUpDown,
     CircleCheck,
     CircleUser,
@@ -49,7 +52,9 @@ pub enum IconName {
     Inbox,
     Info,
     Inspector,
+    Italic,
     LayoutDashboard,
+    Link,
     Loader,
     LoaderCircle,
     Map,
@@ -72,6 +77,7 @@ pub enum IconName {
     Search,
     Settings,
     Settings2,
+    Smile,
     SortAscending,
     SortDescending,
     SquareTerminal,
@@ -81,6 +87,7 @@ pub enum IconName {
     ThumbsDown,
     ThumbsUp,
     TriangleAlert,
+    Underline,
     User,
     WindowClose,
     WindowMaximize,
@@ -98,6 +105,7 @@ impl IconName {
             Self::ArrowUp => "icons/arrow-up.svg",
             Self::Asterisk => "icons/asterisk.svg",
             Self::Bell => "icons/bell.svg",
+            Self::Bold => "icons/bold.svg",
             Self::BookOpen => "icons/book-open.svg",
             Self::Bot => "icons/bot.svg",
             Self::Building2 => "icons/building-2.svg",
@@ -107,6 +115,8 @@ impl IconName {
             Self::ChevronDown => "icons/chevron-down.svg",
             Self::ChevronLeft => "icons/chevron-left.svg",
             Self::ChevronRight => "icons/chevron-right.svg",
+            Self::ChevronsLeft => "icons/chevrons-left.svg",
+            Self::ChevronsRight => "icons/chevrons-right.svg",
             Self::ChevronsUpDown => "icons/chevrons-up-down.svg",
             Self::ChevronUp => "icons/chevron-up.svg",
             Self::CircleCheck => "icons/circle-check.svg",
@@ -133,7 +143,9 @@ impl IconName {
             Self::Inbox => "icons/inbox.svg",
             Self::Info => "icons/info.svg",
             Self::Inspector => "icons/inspector.svg",
+            Self::Italic => "icons/italic.svg",
             Self::LayoutDashboard => "icons/layout-dashboard.svg",
+            Self::Link => "icons/link.svg",
             Self::Loader => "icons/loader.svg",
             Self::LoaderCircle => "icons/loader-circle.svg",
             Self::Map => "icons/map.svg",
@@ -156,6 +168,7 @@ impl IconName {
             Self::Search => "icons/search.svg",
             Self::Settings => "icons/settings.svg",
             Self::Settings2 => "icons/settings-2.svg",
+            Self::Smile => "icons/smile.svg",
             Self::SortAscending => "icons/sort-ascending.svg",
             Self::SortDescending => "icons/sort-descending.svg",
             Self::SquareTerminal => "icons/square-terminal.svg",
@@ -165,6 +178,7 @@ impl IconName {
             Self::ThumbsDown => "icons/thumbs-down.svg",
             Self::ThumbsUp => "icons/thumbs-up.svg",
             Self::TriangleAlert => "icons/triangle-alert.svg",
+            Self::Underline => "icons/underline.svg",
             Self::User => "icons/user.svg",
             Self::WindowClose => "icons/window-close.svg",
             Self::WindowMaximize => "icons/window-maximize.svg",
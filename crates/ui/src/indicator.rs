@@ -1,11 +1,9 @@
 use std::time::Duration;
 
-use crate::{Icon, IconName, Sizable, Size};
-use gpui::{
-    div, ease_in_out, percentage, prelude::FluentBuilder as _, Animation, AnimationExt as _, App,
-    Hsla, IntoElement, ParentElement, RenderOnce, Styled as _, Transformation, Window,
-};
+use crate::{spinner::Spinner, Icon, IconName, Sizable, Size};
+use gpui::{prelude::FluentBuilder as _, App, Hsla, IntoElement, RenderOnce, Window};
 
+/// A spinning icon to indicate loading, see [`Spinner`] for the label and more styles.
 #[derive(IntoElement)]
 pub struct Indicator {
     size: Size,
@@ -44,17 +42,10 @@ impl Sizable for Indicator {
 
 impl RenderOnce for Indicator {
     fn render(self, _window: &mut Window, _cx: &mut App) -> impl IntoElement {
-        div()
-            .child(
-                self.icon
-                    .with_size(self.size)
-                    .when_some(self.color, |this, color| this.text_color(color))
-                    .with_animation(
-                        "circle",
-                        Animation::new(self.speed).repeat().with_easing(ease_in_out),
-                        |this, delta| this.transform(Transformation::rotate(percentage(delta))),
-                    ),
-            )
-            .into_element()
+        Spinner::new()
+            .icon(self.icon)
+            .speed(self.speed)
+            .with_size(self.size)
+            .when_some(self.color, |this, color| this.color(color))
     }
 }
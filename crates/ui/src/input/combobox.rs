@@ -0,0 +1,573 @@
+use std::ops::Range;
+
+use gpui::{
+    actions, anchored, canvas, deferred, div, prelude::FluentBuilder as _, px, uniform_list, App,
+    AppContext as _, Bounds, Context, Corner, ElementId, Empty, Entity, EventEmitter, FocusHandle,
+    Focusable, InteractiveElement as _, IntoElement, KeyBinding, MouseButton, MouseDownEvent,
+    ParentElement, Pixels, Render, RenderOnce, ScrollStrategy, SharedString, StyleRefinement,
+    Styled, Subscription, UniformListScrollHandle, Window,
+};
+
+use crate::{
+    actions::{Cancel, Confirm, SelectNext, SelectPrev},
+    h_flex, v_flex, ActiveTheme, Sizable, Size, StyleSized as _, StyledExt as _,
+};
+
+use super::{InputEvent, InputState, TextInput};
+
+actions!(combobox, [AcceptSuggestion]);
+
+const CONTEXT: &str = "Combobox";
+const MAX_MENU_HEIGHT: Pixels = px(240.);
+const MENU_PADDING: Pixels = px(4.);
+const MENU_GAP: Pixels = px(6.);
+
+pub fn init(cx: &mut App) {
+    cx.bind_keys([
+        KeyBinding::new("up", SelectPrev, Some(CONTEXT)),
+        KeyBinding::new("down", SelectNext, Some(CONTEXT)),
+        KeyBinding::new("enter", Confirm { secondary: false }, Some(CONTEXT)),
+        KeyBinding::new("tab", AcceptSuggestion, Some(CONTEXT)),
+        KeyBinding::new("escape", Cancel, Some(CONTEXT)),
+    ])
+}
+
+/// Events emitted by the [`ComboboxState`].
+#[derive(Clone)]
+pub enum ComboboxEvent {
+    /// The text has been changed by the user, update the suggestions for the new query.
+    QueryChange(SharedString),
+    /// A suggestion has been accepted by Enter, Tab or click.
+    Select(SharedString),
+}
+
+/// State of the [`Combobox`].
+///
+/// The suggestions are supplied by the caller, subscribe the [`ComboboxEvent::QueryChange`]
+/// to filter them and then call [`ComboboxState::set_suggestions`].
+pub struct ComboboxState {
+    input: Entity<InputState>,
+    suggestions: Vec<SharedString>,
+    selected_index: Option<usize>,
+    /// The last known text, to ignore the change event of setting the value by code.
+    query: SharedString,
+    /// The last accepted suggestion.
+    selected_value: Option<SharedString>,
+    strict: bool,
+    open: bool,
+    /// Store the bounds of the input
+    bounds: Bounds<Pixels>,
+    scroll_handle: UniformListScrollHandle,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl ComboboxState {
+    pub fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let input = cx.new(|cx| InputState::new(window, cx));
+        let _subscriptions = vec![cx.subscribe_in(&input, window, Self::on_input_event)];
+
+        Self {
+            input,
+            suggestions: Vec::new(),
+            selected_index: None,
+            query: SharedString::default(),
+            selected_value: None,
+            strict: false,
+            open: false,
+            bounds: Bounds::default(),
+            scroll_handle: UniformListScrollHandle::new(),
+            _subscriptions,
+        }
+    }
+
+    /// Set true to only allow the values from the suggestions, default: false
+    ///
+    /// When the menu is closed, the text will be restored to the last selected suggestion,
+    /// unless it equals to one of the suggestions.
+    pub fn strict(mut self, strict: bool) -> Self {
+        self.strict = strict;
+        self
+    }
+
+    /// Set the placeholder of the input.
+    pub fn placeholder(
+        self,
+        placeholder: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> Self {
+        self.input.update(cx, |input, cx| {
+            input.set_placeholder(placeholder, window, cx);
+        });
+        self
+    }
+
+    /// Returns the [`InputState`] of the text field.
+    pub fn input(&self) -> &Entity<InputState> {
+        &self.input
+    }
+
+    /// Returns the current text of the input.
+    pub fn value(&self, cx: &App) -> SharedString {
+        self.input.read(cx).value()
+    }
+
+    /// Returns the last accepted suggestion.
+    pub fn selected_value(&self) -> Option<&SharedString> {
+        self.selected_value.as_ref()
+    }
+
+    /// Set the text of the input, this will not emit [`ComboboxEvent::QueryChange`].
+    pub fn set_value(
+        &mut self,
+        value: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let value: SharedString = value.into();
+        self.query = value.clone();
+        self.selected_value = (!value.is_empty()).then(|| value.clone());
+        self.input.update(cx, |input, cx| {
+            input.set_value(value, window, cx);
+        });
+    }
+
+    /// Set the suggestions to show in the menu.
+    ///
+    /// In strict mode the first suggestion will be highlighted,
+    /// otherwise nothing is highlighted until pressing up or down.
+    pub fn set_suggestions(
+        &mut self,
+        suggestions: impl Into<Vec<SharedString>>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.suggestions = suggestions.into();
+        self.selected_index = (self.strict && !self.suggestions.is_empty()).then_some(0);
+        self.scroll_handle.scroll_to_item(0, ScrollStrategy::Top);
+        cx.notify();
+    }
+
+    pub fn focus(&self, window: &mut Window, cx: &mut App) {
+        self.input.focus_handle(cx).focus(window);
+    }
+
+    fn on_input_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::Change(text) => {
+                if *text == self.query {
+                    return;
+                }
+
+                self.query = text.clone();
+                self.selected_index = None;
+                self.open = true;
+                cx.emit(ComboboxEvent::QueryChange(text.clone()));
+                cx.notify();
+            }
+            InputEvent::Blur => self.close(window, cx),
+            _ => {}
+        }
+    }
+
+    fn select(&mut self, value: SharedString, window: &mut Window, cx: &mut Context<Self>) {
+        self.set_value(value.clone(), window, cx);
+        self.open = false;
+        cx.emit(ComboboxEvent::Select(value));
+        cx.notify();
+    }
+
+    fn close(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.open {
+            return;
+        }
+
+        self.open = false;
+        if self.strict {
+            self.restore(window, cx);
+        }
+        cx.notify();
+    }
+
+    /// Restore the input to the last selected value, if the text is not one of the suggestions.
+    fn restore(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let value = self.value(cx);
+        if self.selected_value.as_ref() == Some(&value) {
+            return;
+        }
+
+        if let Some(item) = self
+            .suggestions
+            .iter()
+            .find(|item| item.to_lowercase() == value.to_lowercase())
+            .cloned()
+        {
+            self.select(item, window, cx);
+        } else {
+            let value = self.selected_value.clone().unwrap_or_default();
+            self.set_value(value, window, cx);
+        }
+    }
+
+    fn selected_item(&self) -> Option<SharedString> {
+        self.selected_index
+            .and_then(|ix| self.suggestions.get(ix))
+            .cloned()
+    }
+
+    fn move_selection(&mut self, delta: isize, cx: &mut Context<Self>) {
+        self.selected_index = step_index(self.selected_index, delta, self.suggestions.len());
+        if let Some(ix) = self.selected_index {
+            self.scroll_handle.scroll_to_item(ix, ScrollStrategy::Top);
+        }
+        cx.notify();
+    }
+
+    fn on_action_select_prev(&mut self, _: &SelectPrev, _: &mut Window, cx: &mut Context<Self>) {
+        if !self.open {
+            cx.propagate();
+            return;
+        }
+
+        self.move_selection(-1, cx);
+    }
+
+    fn on_action_select_next(&mut self, _: &SelectNext, _: &mut Window, cx: &mut Context<Self>) {
+        if !self.open {
+            self.open = true;
+            cx.notify();
+            return;
+        }
+
+        self.move_selection(1, cx);
+    }
+
+    fn on_action_confirm(&mut self, _: &Confirm, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.open {
+            cx.propagate();
+            return;
+        }
+
+        match self.selected_item() {
+            Some(item) => self.select(item, window, cx),
+            None => {
+                // Keep the free text, and let the parent to handle the Enter, e.g.: submit form.
+                self.close(window, cx);
+                cx.propagate();
+            }
+        }
+    }
+
+    fn on_action_accept(
+        &mut self,
+        _: &AcceptSuggestion,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match self.selected_item().filter(|_| self.open) {
+            Some(item) => self.select(item, window, cx),
+            // Move focus to the next field.
+            None => cx.propagate(),
+        }
+    }
+
+    fn on_action_cancel(&mut self, _: &Cancel, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.open {
+            cx.propagate();
+            return;
+        }
+
+        self.close(window, cx);
+    }
+
+    fn on_mouse_down_out(
+        &mut self,
+        event: &MouseDownEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        // Keep the menu open when clicking on the input to move the cursor.
+        if self.bounds.contains(&event.position) {
+            return;
+        }
+
+        self.close(window, cx);
+    }
+}
+
+/// Returns the index after moving `delta` rows from `selected`, wrapping around at the ends.
+fn step_index(selected: Option<usize>, delta: isize, count: usize) -> Option<usize> {
+    if count == 0 {
+        return None;
+    }
+
+    Some(match selected {
+        Some(ix) => (ix as isize + delta).rem_euclid(count as isize) as usize,
+        None if delta < 0 => count - 1,
+        None => 0,
+    })
+}
+
+/// Returns true if the menu should be placed above the input,
+/// that is when there is no enough room below and more room above.
+fn place_above(input_bounds: Bounds<Pixels>, menu_height: Pixels, viewport_height: Pixels) -> bool {
+    let below = viewport_height - input_bounds.bottom();
+    let above = input_bounds.top();
+
+    below < menu_height && above > below
+}
+
+impl EventEmitter<ComboboxEvent> for ComboboxState {}
+impl Focusable for ComboboxState {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.input.focus_handle(cx)
+    }
+}
+
+impl Render for ComboboxState {
+    fn render(&mut self, _: &mut Window, _: &mut Context<Self>) -> impl IntoElement {
+        Empty
+    }
+}
+
+/// A text input with a dropdown menu of suggestions, the text is still freely editable.
+///
+/// Unlike [`Dropdown`](crate::dropdown::Dropdown), the suggestions are not filtered by
+/// the component, update them on [`ComboboxEvent::QueryChange`].
+/// The menu is virtualized and will be placed above the input if there is no room below.
+#[derive(IntoElement)]
+pub struct Combobox {
+    id: ElementId,
+    style: StyleRefinement,
+    state: Entity<ComboboxState>,
+    size: Size,
+    cleanable: bool,
+    disabled: bool,
+}
+
+impl Combobox {
+    pub fn new(state: &Entity<ComboboxState>) -> Self {
+        Self {
+            id: ("combobox", state.entity_id()).into(),
+            style: StyleRefinement::default(),
+            state: state.clone(),
+            size: Size::default(),
+            cleanable: false,
+            disabled: false,
+        }
+    }
+
+    /// Set true to show the clear button when the input field is not empty.
+    pub fn cleanable(mut self) -> Self {
+        self.cleanable = true;
+        self
+    }
+
+    /// Set the disable state for the combobox.
+    pub fn disabled(mut self, disabled: bool) -> Self {
+        self.disabled = disabled;
+        self
+    }
+}
+
+impl Sizable for Combobox {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl Styled for Combobox {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl Focusable for Combobox {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.state.focus_handle(cx)
+    }
+}
+
+fn item_height(size: Size) -> Pixels {
+    match size {
+        Size::XSmall | Size::Small => px(26.),
+        Size::Large => px(36.),
+        _ => px(30.),
+    }
+}
+
+impl RenderOnce for Combobox {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = self.state.read(cx);
+        let bounds = state.bounds;
+        let items_count = state.suggestions.len();
+        let show_menu = state.open && items_count > 0 && !self.disabled;
+        let item_height = item_height(self.size);
+        let list_height = (item_height * items_count as f32).min(MAX_MENU_HEIGHT);
+        let above = place_above(
+            bounds,
+            list_height + MENU_PADDING * 2. + MENU_GAP,
+            window.viewport_size().height,
+        );
+        let popup_radius = cx.theme().radius.min(px(8.));
+
+        div()
+            .id(self.id)
+            .key_context(CONTEXT)
+            .on_action(window.listener_for(&self.state, ComboboxState::on_action_select_prev))
+            .on_action(window.listener_for(&self.state, ComboboxState::on_action_select_next))
+            .on_action(window.listener_for(&self.state, ComboboxState::on_action_confirm))
+            .on_action(window.listener_for(&self.state, ComboboxState::on_action_accept))
+            .on_action(window.listener_for(&self.state, ComboboxState::on_action_cancel))
+            .relative()
+            .w_full()
+            .refine_style(&self.style)
+            .child(
+                TextInput::new(&state.input)
+                    .with_size(self.size)
+                    .disabled(self.disabled)
+                    .when(self.cleanable, |this| this.cleanable()),
+            )
+            .child(
+                canvas(
+                    {
+                        let state = self.state.clone();
+                        move |bounds, _, cx| state.update(cx, |r, _| r.bounds = bounds)
+                    },
+                    |_, _, _, _| {},
+                )
+                .absolute()
+                .size_full(),
+            )
+            .when(show_menu, |this| {
+                let (anchor, position) = if above {
+                    (Corner::BottomLeft, bounds.origin)
+                } else {
+                    (Corner::TopLeft, bounds.bottom_left())
+                };
+                let selected_index = state.selected_index;
+
+                this.child(
+                    deferred(
+                        anchored()
+                            .snap_to_window_with_margin(px(8.))
+                            .anchor(anchor)
+                            .position(position)
+                            .child(
+                                v_flex()
+                                    .occlude()
+                                    .w(bounds.size.width)
+                                    .map(|this| if above { this.mb_1p5() } else { this.mt_1p5() })
+                                    .p(MENU_PADDING)
+                                    .bg(cx.theme().background)
+                                    .border_1()
+                                    .border_color(cx.theme().border)
+                                    .rounded(popup_radius)
+                                    .shadow_md()
+                                    .child(
+                                        uniform_list("suggestions", items_count, {
+                                            let state = self.state.clone();
+                                            let size = self.size;
+                                            move |visible_range: Range<usize>, _, cx: &mut App| {
+                                                let suggestions =
+                                                    state.read(cx).suggestions.clone();
+                                                visible_range
+                                                    .filter_map(|ix| {
+                                                        let item = suggestions.get(ix)?.clone();
+                                                        Some(render_item(
+                                                            &state,
+                                                            ix,
+                                                            item,
+                                                            selected_index == Some(ix),
+                                                            size,
+                                                            cx,
+                                                        ))
+                                                    })
+                                                    .collect::<Vec<_>>()
+                                            }
+                                        })
+                                        .track_scroll(state.scroll_handle.clone())
+                                        .h(list_height),
+                                    )
+                                    .on_mouse_down_out(window.listener_for(
+                                        &self.state,
+                                        ComboboxState::on_mouse_down_out,
+                                    )),
+                            ),
+                    )
+                    .with_priority(1),
+                )
+            })
+    }
+}
+
+fn render_item(
+    state: &Entity<ComboboxState>,
+    ix: usize,
+    item: SharedString,
+    selected: bool,
+    size: Size,
+    cx: &App,
+) -> impl IntoElement {
+    let state = state.clone();
+
+    h_flex()
+        .id(ix)
+        .flex_shrink_0()
+        .h(item_height(size))
+        .px_2()
+        .rounded(cx.theme().radius)
+        .text_color(cx.theme().foreground)
+        .input_text_size(size)
+        .whitespace_nowrap()
+        .overflow_hidden()
+        .when(!selected, |this| {
+            this.hover(|this| this.bg(cx.theme().accent.alpha(0.7)))
+        })
+        .when(selected, |this| this.bg(cx.theme().accent))
+        .child(item.clone())
+        .on_mouse_down(MouseButton::Left, move |_, window, cx| {
+            cx.stop_propagation();
+            state.update(cx, |state, cx| {
+                state.select(item.clone(), window, cx);
+                state.focus(window, cx);
+            });
+        })
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::{point, px, size, Bounds};
+
+    use super::{place_above, step_index};
+
+    #[test]
+    fn test_step_index() {
+        assert_eq!(step_index(None, 1, 0), None);
+        assert_eq!(step_index(Some(2), -1, 0), None);
+        assert_eq!(step_index(None, 1, 3), Some(0));
+        assert_eq!(step_index(None, -1, 3), Some(2));
+        assert_eq!(step_index(Some(0), 1, 3), Some(1));
+        assert_eq!(step_index(Some(2), 1, 3), Some(0));
+        assert_eq!(step_index(Some(0), -1, 3), Some(2));
+    }
+
+    #[test]
+    fn test_place_above() {
+        let input = |top: f32| Bounds::new(point(px(10.), px(top)), size(px(200.), px(30.)));
+
+        // Enough room below.
+        assert!(!place_above(input(100.), px(200.), px(600.)));
+        // No room below, more room above.
+        assert!(place_above(input(500.), px(200.), px(600.)));
+        // No room at both side, but more room below.
+        assert!(!place_above(input(100.), px(500.), px(600.)));
+    }
+}
@@ -0,0 +1,437 @@
+use std::{ops::Range, rc::Rc};
+
+use gpui::{
+    anchored, deferred, div, point, prelude::FluentBuilder as _, px, App, Context,
+    InteractiveElement as _, IntoElement, MouseButton, ParentElement as _, SharedString,
+    Styled as _, Window,
+};
+
+use crate::{h_flex, v_flex, ActiveTheme as _, Icon, IconName, Sizable as _};
+
+use super::{InputState, LineColumn};
+
+/// The max number of items displayed in the completion menu, the others are scrolled by
+/// the keyboard navigation.
+const MAX_VISIBLE_ITEMS: usize = 8;
+
+/// The kind of a [`CompletionItem`], to display the icon in the completion menu.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Default)]
+pub enum CompletionKind {
+    #[default]
+    Text,
+    Keyword,
+    Function,
+    Variable,
+    Field,
+    Module,
+    Snippet,
+}
+
+impl CompletionKind {
+    fn icon(&self) -> IconName {
+        match self {
+            Self::Text => IconName::ALargeSmall,
+            Self::Keyword => IconName::Asterisk,
+            Self::Function => IconName::SquareTerminal,
+            Self::Variable => IconName::Frame,
+            Self::Field => IconName::LayoutDashboard,
+            Self::Module => IconName::Folder,
+            Self::Snippet => IconName::BookOpen,
+        }
+    }
+}
+
+/// The reason of a completion request.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum CompletionTrigger {
+    /// Requested explicitly by the user, e.g.: `ctrl-space`.
+    Invoked,
+    /// Typed a word character or `.`.
+    Character(char),
+}
+
+/// An item of the completion menu, see [`InputState::on_completion_request`].
+#[derive(Debug, Clone, Default, PartialEq)]
+pub struct CompletionItem {
+    pub label: SharedString,
+    pub detail: Option<SharedString>,
+    pub kind: CompletionKind,
+    /// The text to insert, default is the label.
+    pub insert_text: Option<SharedString>,
+    /// Set true if the `insert_text` is a snippet with the `$1`, `${1:placeholder}`
+    /// and `$0` tab stops.
+    pub snippet: bool,
+}
+
+impl CompletionItem {
+    pub fn new(label: impl Into<SharedString>) -> Self {
+        Self {
+            label: label.into(),
+            ..Default::default()
+        }
+    }
+
+    /// Set the detail displayed at the right of the label, e.g.: the type signature.
+    pub fn detail(mut self, detail: impl Into<SharedString>) -> Self {
+        self.detail = Some(detail.into());
+        self
+    }
+
+    pub fn kind(mut self, kind: CompletionKind) -> Self {
+        self.kind = kind;
+        self
+    }
+
+    /// Set the text to insert instead of the label.
+    pub fn insert_text(mut self, text: impl Into<SharedString>) -> Self {
+        self.insert_text = Some(text.into());
+        self
+    }
+
+    /// Set a snippet to insert, e.g.: `fn ${1:name}($2) {\n    $0\n}`.
+    ///
+    /// Press `tab` to move through the tab stops after the snippet is inserted.
+    pub fn snippet(mut self, snippet: impl Into<SharedString>) -> Self {
+        self.insert_text = Some(snippet.into());
+        self.snippet = true;
+        self
+    }
+}
+
+pub(super) type CompletionProvider =
+    Rc<dyn Fn(LineColumn, CompletionTrigger, &mut Window, &mut App) -> Vec<CompletionItem>>;
+
+/// The opened completion menu.
+pub(super) struct CompletionMenu {
+    items: Vec<CompletionItem>,
+    /// The indices of the items matched the query.
+    matches: Vec<usize>,
+    selected_ix: usize,
+    /// The start offset of the word to complete.
+    pub(super) start: usize,
+}
+
+impl CompletionMenu {
+    pub(super) fn new(items: Vec<CompletionItem>, start: usize) -> Self {
+        Self {
+            matches: (0..items.len()).collect(),
+            items,
+            selected_ix: 0,
+            start,
+        }
+    }
+
+    /// Filter the items by the query, the items start with the query are listed first.
+    pub(super) fn filter(&mut self, query: &str) {
+        let query = query.to_lowercase();
+        let mut matches = self
+            .items
+            .iter()
+            .enumerate()
+            .filter(|(_, item)| is_subsequence(&item.label.to_lowercase(), &query))
+            .map(|(ix, item)| (!item.label.to_lowercase().starts_with(&query), ix))
+            .collect::<Vec<_>>();
+        matches.sort();
+
+        self.matches = matches.into_iter().map(|(_, ix)| ix).collect();
+        self.selected_ix = 0;
+    }
+
+    pub(super) fn is_empty(&self) -> bool {
+        self.matches.is_empty()
+    }
+
+    /// Select the previous (-1) or next (1) item, wrap around at the ends.
+    pub(super) fn select(&mut self, delta: isize) {
+        if self.matches.is_empty() {
+            return;
+        }
+        let len = self.matches.len() as isize;
+        self.selected_ix = (self.selected_ix as isize + delta).rem_euclid(len) as usize;
+    }
+
+    pub(super) fn selected_item(&self) -> Option<&CompletionItem> {
+        self.matches
+            .get(self.selected_ix)
+            .and_then(|ix| self.items.get(*ix))
+    }
+
+    pub(super) fn select_item(&mut self, ix: usize) {
+        if ix < self.matches.len() {
+            self.selected_ix = ix;
+        }
+    }
+
+    /// The range of the matches to display, keep the selected item visible.
+    fn visible_range(&self) -> Range<usize> {
+        let len = self.matches.len().min(MAX_VISIBLE_ITEMS);
+        let start = (self.selected_ix + 1).saturating_sub(len);
+        start..start + len
+    }
+}
+
+fn is_subsequence(text: &str, query: &str) -> bool {
+    let mut chars = text.chars();
+    query.chars().all(|q| chars.any(|c| c == q))
+}
+
+/// Returns true if the char is a part of a word to complete.
+pub(super) fn is_word_char(c: char) -> bool {
+    c.is_alphanumeric() || c == '_'
+}
+
+/// The tab stops of an inserted snippet, the ranges are the offsets in the text.
+pub(super) struct SnippetSession {
+    tabstops: Vec<Range<usize>>,
+    ix: usize,
+}
+
+impl SnippetSession {
+    /// Returns None if there is no tab stop after the first one.
+    pub(super) fn new(tabstops: Vec<Range<usize>>) -> Option<Self> {
+        (tabstops.len() > 1).then_some(Self { tabstops, ix: 0 })
+    }
+
+    /// Move to the next tab stop, returns None at the end of the session.
+    pub(super) fn next(&mut self) -> Option<Range<usize>> {
+        self.ix += 1;
+        self.tabstops.get(self.ix).cloned()
+    }
+
+    /// Returns true if the current tab stop is the last one.
+    pub(super) fn is_finished(&self) -> bool {
+        self.ix + 1 >= self.tabstops.len()
+    }
+
+    /// Move the tab stops for a text edit, that replaced the `range` by `new_len` bytes.
+    ///
+    /// The text typed at a tab stop is included in it.
+    pub(super) fn edit(&mut self, range: &Range<usize>, new_len: usize) {
+        let map = |offset: usize, is_end: bool| {
+            if offset < range.start || (offset == range.start && !is_end) {
+                offset
+            } else if offset >= range.end {
+                offset + new_len - range.len()
+            } else if is_end {
+                range.start + new_len
+            } else {
+                range.start
+            }
+        };
+
+        for tabstop in self.tabstops.iter_mut() {
+            *tabstop = map(tabstop.start, false)..map(tabstop.end, true);
+        }
+    }
+}
+
+/// Parse the snippet, returns the text to insert and the tab stop ranges in the text.
+///
+/// The tab stops are ordered by the index, `$0` is the last one and defaults to the end
+/// of the text. Use `\$` to insert a `$`.
+pub(super) fn parse_snippet(snippet: &str) -> (String, Vec<Range<usize>>) {
+    let mut text = String::new();
+    let mut tabstops: Vec<(usize, Range<usize>)> = vec![];
+    let mut chars = snippet.chars().peekable();
+
+    while let Some(c) = chars.next() {
+        match c {
+            '\\' if chars.peek() == Some(&'$') => text.push(chars.next().unwrap()),
+            '$' => {
+                let braced = chars.next_if_eq(&'{').is_some();
+                let mut index = String::new();
+                while let Some(c) = chars.next_if(|c| c.is_ascii_digit()) {
+                    index.push(c);
+                }
+                let Ok(index) = index.parse::<usize>() else {
+                    text.push('$');
+                    if braced {
+                        text.push('{');
+                    }
+                    continue;
+                };
+
+                let start = text.len();
+                if braced {
+                    if chars.next_if_eq(&':').is_some() {
+                        while let Some(c) = chars.next_if(|c| *c != '}') {
+                            text.push(c);
+                        }
+                    }
+                    chars.next_if_eq(&'}');
+                }
+                if !tabstops.iter().any(|(ix, _)| *ix == index) {
+                    tabstops.push((index, start..text.len()));
+                }
+            }
+            _ => text.push(c),
+        }
+    }
+
+    if !tabstops.iter().any(|(ix, _)| *ix == 0) {
+        tabstops.push((0, text.len()..text.len()));
+    }
+    tabstops.sort_by_key(|(ix, _)| if *ix == 0 { usize::MAX } else { *ix });
+
+    (text, tabstops.into_iter().map(|(_, range)| range).collect())
+}
+
+impl InputState {
+    pub(super) fn render_completion_menu(
+        &self,
+        cx: &mut Context<Self>,
+    ) -> Option<impl IntoElement> {
+        let menu = self.completion_menu.as_ref()?;
+        let bounds = self.last_bounds?;
+        let last_layout = self.last_layout.as_ref()?;
+        let (_, _, start_pos) = self.line_and_position_for_offset(menu.start);
+        let position = bounds.origin
+            + start_pos?
+            + point(last_layout.line_number_width, last_layout.line_height);
+
+        let visible_range = menu.visible_range();
+        let selected_ix = menu.selected_ix;
+        let items = menu.matches[visible_range.clone()]
+            .iter()
+            .filter_map(|ix| menu.items.get(*ix))
+            .cloned()
+            .collect::<Vec<_>>();
+
+        Some(
+            deferred(
+                anchored()
+                    .snap_to_window_with_margin(px(8.))
+                    .position(position)
+                    .child(
+                        v_flex()
+                            .id("completion-menu")
+                            .occlude()
+                            .min_w(px(200.))
+                            .max_w(px(500.))
+                            .p_1()
+                            .text_sm()
+                            .bg(cx.theme().popover)
+                            .text_color(cx.theme().popover_foreground)
+                            .border_1()
+                            .border_color(cx.theme().border)
+                            .rounded(cx.theme().radius)
+                            .shadow_md()
+                            .children(items.into_iter().enumerate().map(|(ix, item)| {
+                                let ix = visible_range.start + ix;
+                                let selected = ix == selected_ix;
+
+                                h_flex()
+                                    .id(ix)
+                                    .gap_2()
+                                    .px_1()
+                                    .py_0p5()
+                                    .rounded(cx.theme().radius)
+                                    .whitespace_nowrap()
+                                    .overflow_hidden()
+                                    .when(selected, |this| this.bg(cx.theme().accent))
+                                    .when(!selected, |this| {
+                                        this.hover(|this| this.bg(cx.theme().accent.alpha(0.7)))
+                                    })
+                                    .child(
+                                        Icon::new(item.kind.icon())
+                                            .xsmall()
+                                            .text_color(cx.theme().muted_foreground),
+                                    )
+                                    .child(div().flex_1().child(item.label.clone()))
+                                    .when_some(item.detail.clone(), |this, detail| {
+                                        this.child(
+                                            div()
+                                                .text_xs()
+                                                .text_color(cx.theme().muted_foreground)
+                                                .child(detail),
+                                        )
+                                    })
+                                    .on_mouse_down(
+                                        MouseButton::Left,
+                                        cx.listener(move |this, _, window, cx| {
+                                            cx.stop_propagation();
+                                            if let Some(menu) = this.completion_menu.as_mut() {
+                                                menu.select_item(ix);
+                                            }
+                                            this.accept_completion(window, cx);
+                                        }),
+                                    )
+                            }))
+                            .on_mouse_down_out(cx.listener(|this, _, _, cx| {
+                                this.hide_completion_menu(cx);
+                            })),
+                    ),
+            )
+            .with_priority(1),
+        )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{parse_snippet, CompletionItem, CompletionMenu, SnippetSession};
+
+    #[test]
+    fn test_parse_snippet() {
+        assert_eq!(
+            parse_snippet("fn ${1:name}($2) {\n    $0\n}"),
+            ("fn name() {\n    \n}".to_string(), vec![3..7, 8..8, 16..16])
+        );
+        assert_eq!(parse_snippet("foo"), ("foo".to_string(), vec![3..3]));
+        assert_eq!(
+            parse_snippet("\\$a $b ${c ${1}"),
+            ("$a $b ${c ".to_string(), vec![10..10, 10..10])
+        );
+    }
+
+    #[test]
+    fn test_snippet_session() {
+        assert!(SnippetSession::new(vec![3..3]).is_none());
+
+        let mut session = SnippetSession::new(vec![3..7, 8..8, 15..15]).unwrap();
+        // Replace `name` to `foo`.
+        session.edit(&(3..7), 3);
+        assert_eq!(session.tabstops, vec![3..6, 7..7, 14..14]);
+        assert_eq!(session.next(), Some(7..7));
+        // Type `a` in the second tab stop.
+        session.edit(&(7..7), 1);
+        assert_eq!(session.tabstops, vec![3..6, 7..8, 15..15]);
+        assert!(!session.is_finished());
+        assert_eq!(session.next(), Some(15..15));
+        assert!(session.is_finished());
+        assert_eq!(session.next(), None);
+    }
+
+    #[test]
+    fn test_completion_menu_filter() {
+        let mut menu = CompletionMenu::new(
+            vec![
+                CompletionItem::new("println"),
+                CompletionItem::new("print"),
+                CompletionItem::new("eprintln"),
+                CompletionItem::new("format"),
+            ],
+            0,
+        );
+
+        menu.filter("prl");
+        let labels = |menu: &CompletionMenu| {
+            menu.matches
+                .iter()
+                .map(|ix| menu.items[*ix].label.to_string())
+                .collect::<Vec<_>>()
+        };
+        assert_eq!(labels(&menu), vec!["println", "eprintln"]);
+
+        menu.filter("PRint");
+        assert_eq!(labels(&menu), vec!["println", "print", "eprintln"]);
+        menu.select(-1);
+        assert_eq!(menu.selected_item().unwrap().label, "eprintln");
+        menu.select(1);
+        assert_eq!(menu.selected_item().unwrap().label, "println");
+
+        menu.filter("xyz");
+        assert!(menu.is_empty());
+        assert!(menu.selected_item().is_none());
+    }
+}
@@ -0,0 +1,58 @@
+use std::path::{Path, PathBuf};
+
+use gpui::SharedString;
+
+/// Returns true if the `path` has one of the `extensions`, case insensitive,
+/// all the files are accepted if the `extensions` is empty.
+pub(super) fn is_accepted_file(path: &Path, extensions: &[SharedString]) -> bool {
+    if extensions.is_empty() {
+        return true;
+    }
+
+    let Some(ext) = path.extension().and_then(|ext| ext.to_str()) else {
+        return false;
+    };
+    extensions
+        .iter()
+        .any(|accepted| accepted.trim_start_matches('.').eq_ignore_ascii_case(ext))
+}
+
+/// Split the dropped `paths` into the accepted and the rejected files, keep the order.
+pub(super) fn partition_files(
+    paths: &[PathBuf],
+    extensions: &[SharedString],
+) -> (Vec<PathBuf>, Vec<PathBuf>) {
+    paths
+        .iter()
+        .cloned()
+        .partition(|path| is_accepted_file(path, extensions))
+}
+
+#[cfg(test)]
+mod tests {
+    use std::path::PathBuf;
+
+    use gpui::SharedString;
+
+    use super::partition_files;
+
+    #[test]
+    fn test_partition_files() {
+        let paths = ["a.PNG", "b.txt", "c.jpg", "Makefile"].map(PathBuf::from);
+
+        let (accepted, rejected) = partition_files(&paths, &[]);
+        assert_eq!(accepted, paths.to_vec());
+        assert!(rejected.is_empty());
+
+        let extensions = [SharedString::from("png"), SharedString::from(".jpg")];
+        let (accepted, rejected) = partition_files(&paths, &extensions);
+        assert_eq!(
+            accepted,
+            vec![PathBuf::from("a.PNG"), PathBuf::from("c.jpg")]
+        );
+        assert_eq!(
+            rejected,
+            vec![PathBuf::from("b.txt"), PathBuf::from("Makefile")]
+        );
+    }
+}
@@ -1,10 +1,10 @@
 use std::{ops::Range, rc::Rc};
 
 use gpui::{
-    fill, point, px, relative, size, App, Bounds, Corners, Element, ElementId, ElementInputHandler,
-    Entity, GlobalElementId, HighlightStyle, IntoElement, LayoutId, MouseButton, MouseMoveEvent,
-    Path, Pixels, Point, SharedString, Size, Style, TextAlign, TextRun, UnderlineStyle, Window,
-    WrappedLine,
+    fill, point, px, relative, size, App, Bounds, Corners, DispatchPhase, Element, ElementId,
+    ElementInputHandler, Entity, GlobalElementId, HighlightStyle, Hsla, IntoElement, LayoutId,
+    MouseButton, MouseDownEvent, MouseMoveEvent, Path, Pixels, Point, SharedString, Size, Style,
+    TextAlign, TextRun, UnderlineStyle, Window, WrappedLine,
 };
 use smallvec::SmallVec;
 
@@ -12,11 +12,19 @@ use crate::{
     highlighter::SyntaxHighlighter, input::blink_cursor::CURSOR_WIDTH, ActiveTheme as _, Root,
 };
 
-use super::{mode::InputMode, InputState, LastLayout};
+use super::{
+    fold::FoldMap,
+    marker::{marker_highlight_ranges, MarkerSeverity},
+    minimap::{minimap_line_height, minimap_reserved_width, MINIMAP_CHAR_WIDTH},
+    mode::InputMode,
+    DeferredScroll, InputState, LastLayout,
+};
 
 pub(super) const RIGHT_MARGIN: Pixels = px(10.);
 const BOTTOM_MARGIN_ROWS: usize = 1;
 pub(super) const LINE_NUMBER_RIGHT_MARGIN: Pixels = px(10.);
+/// The width of the fold marker area, between the line number and the text.
+pub(super) const FOLD_MARKER_WIDTH: Pixels = px(12.);
 
 pub(super) struct TextElement {
     state: Entity<InputState>,
@@ -37,7 +45,7 @@ impl TextElement {
         self
     }
 
-    fn paint_mouse_listeners(&mut self, window: &mut Window, _: &mut App) {
+    fn paint_mouse_listeners(&mut self, bounds: Bounds<Pixels>, window: &mut Window, _: &mut App) {
         window.on_mouse_event({
             let state = self.state.clone();
 
@@ -49,6 +57,128 @@ impl TextElement {
                 }
             }
         });
+
+        // Find the right clicked misspelled word, the text is painted after the context menu
+        // of the input, so this is called before the menu is built.
+        window.on_mouse_event({
+            let state = self.state.clone();
+
+            move |event: &MouseDownEvent, phase, window, cx| {
+                if phase == DispatchPhase::Bubble
+                    && event.button == MouseButton::Right
+                    && bounds.contains(&event.position)
+                {
+                    state.update(cx, |state, cx| {
+                        state.on_right_mouse_down(event, window, cx);
+                    });
+                }
+            }
+        });
+    }
+
+    /// Paint the minimap of the lines with the syntax colors, the viewport and the diagnostic markers.
+    fn paint_minimap(&mut self, prepaint: &PrepaintState, window: &mut Window, cx: &mut App) {
+        let theme = cx.theme().highlight_theme.clone();
+        let foreground = cx.theme().foreground;
+        let background = cx.theme().background;
+        let thumb_color = cx.theme().scrollbar_thumb.opacity(0.3);
+        let scroll_size = prepaint.scroll_size;
+        let scroll_offset = prepaint.cursor_scroll_offset;
+        let folds = &prepaint.folds;
+
+        self.state.update(cx, |state, _| {
+            let Some(bounds) = state.minimap_bounds() else {
+                return;
+            };
+            let input_height = state.input_bounds.size.height;
+
+            let InputState {
+                mode,
+                minimap,
+                text,
+                ..
+            } = state;
+            let InputMode::CodeEditor { highlighter, .. } = mode else {
+                return;
+            };
+
+            // Only the edited lines are rebuilt, the others are cached.
+            let highlighter = highlighter.borrow();
+            let default_color = foreground.opacity(0.6);
+            minimap.update(text, default_color, |range| match highlighter.as_ref() {
+                Some(highlighter) => highlighter
+                    .styles(range, &theme)
+                    .into_iter()
+                    .map(|(range, style)| (range, style.color.unwrap_or(foreground).opacity(0.6)))
+                    .collect(),
+                None => vec![],
+            });
+
+            window.paint_quad(fill(bounds, background));
+
+            let lines_count = minimap.len() - folds.hidden_lines_count(minimap.len());
+            let line_height = minimap_line_height(lines_count, bounds.size.height);
+            let mut row = 0;
+            let mut last_y = None;
+            for (ix, spans) in minimap.lines().enumerate() {
+                if folds.is_hidden(ix) {
+                    continue;
+                }
+                let y = bounds.top() + line_height * row as f32;
+                row += 1;
+
+                // Skip the lines painted on the same pixel row, for the long text.
+                let pixel_y = y.floor();
+                if last_y == Some(pixel_y) {
+                    continue;
+                }
+                last_y = Some(pixel_y);
+
+                for span in spans {
+                    window.paint_quad(fill(
+                        Bounds::new(
+                            point(
+                                bounds.left() + MINIMAP_CHAR_WIDTH * span.column as f32,
+                                pixel_y,
+                            ),
+                            size(
+                                MINIMAP_CHAR_WIDTH * span.len as f32,
+                                line_height.max(px(1.)),
+                            ),
+                        ),
+                        span.color,
+                    ));
+                }
+            }
+
+            let content_height = line_height * lines_count as f32;
+            for (ix, line_top, color) in prepaint.diagnostic_markers.iter() {
+                if folds.is_hidden(*ix) {
+                    continue;
+                }
+                let y = *line_top / scroll_size.height * content_height;
+                window.paint_quad(fill(
+                    Bounds::new(
+                        point(bounds.left(), bounds.top() + y),
+                        size(bounds.size.width, px(2.)),
+                    ),
+                    color.opacity(0.5),
+                ));
+            }
+
+            // The viewport of the editor.
+            if scroll_size.height > px(0.) {
+                let top = -scroll_offset.y / scroll_size.height * content_height;
+                let height = input_height / scroll_size.height * content_height;
+                window.paint_quad(fill(
+                    Bounds::new(
+                        point(bounds.left(), bounds.top() + top),
+                        size(bounds.size.width, height),
+                    ),
+                    thumb_color,
+                ));
+            }
+        });
     }
 
     /// Returns the:
@@ -71,6 +201,7 @@ impl TextElement {
             selected_range = (marked_range.end..marked_range.end).into();
         }
 
+        let right_margin = RIGHT_MARGIN + minimap_reserved_width(state.mode.minimap());
         let cursor = state.cursor();
         let mut current_line_index = None;
         let mut scroll_offset = state.scroll_handle.offset();
@@ -117,7 +248,7 @@ impl TextElement {
                 }
             }
 
-            offset_y += line.size(line_height).height;
+            offset_y += state.folds.line_height(line_ix, line, line_height);
             // +1 for skip the last `\n`
             prev_lines_offset += line.len() + 1;
         }
@@ -130,10 +261,10 @@ impl TextElement {
 
             if cursor_moved || selection_changed {
                 scroll_offset.x = if scroll_offset.x + cursor_pos.x
-                    > (bounds.size.width - line_number_width - RIGHT_MARGIN)
+                    > (bounds.size.width - line_number_width - right_margin)
                 {
                     // cursor is out of right
-                    bounds.size.width - line_number_width - RIGHT_MARGIN - cursor_pos.x
+                    bounds.size.width - line_number_width - right_margin - cursor_pos.x
                 } else if scroll_offset.x + cursor_pos.x < px(0.) {
                     // cursor is out of left
                     scroll_offset.x - cursor_pos.x
@@ -186,6 +317,38 @@ impl TextElement {
             };
         }
 
+        // Apply the scroll requested by `set_scroll_offset` or `scroll_to_line`,
+        // this takes precedence over keeping the cursor in view.
+        if let Some(deferred_scroll) = state.deferred_scroll {
+            let total_height = lines
+                .iter()
+                .enumerate()
+                .map(|(ix, line)| state.folds.line_height(ix, line, line_height))
+                .fold(px(0.), |acc, height| acc + height);
+            let max_offset_y = (total_height - bounds.size.height).max(px(0.));
+
+            match deferred_scroll {
+                DeferredScroll::Offset(offset) => {
+                    scroll_offset.x = offset.x.min(px(0.));
+                    scroll_offset.y = offset.y.clamp(-max_offset_y, px(0.));
+                }
+                DeferredScroll::Line(line_ix, align) => {
+                    let line_top = lines
+                        .iter()
+                        .take(line_ix)
+                        .enumerate()
+                        .map(|(ix, line)| state.folds.line_height(ix, line, line_height))
+                        .fold(px(0.), |acc, height| acc + height);
+                    let line_size = lines
+                        .get(line_ix)
+                        .map(|line| state.folds.line_height(line_ix, line, line_height))
+                        .unwrap_or(line_height);
+                    scroll_offset.y =
+                        align.offset_for(line_top, line_size, bounds.size.height, max_offset_y);
+                }
+            }
+        }
+
         bounds.origin = bounds.origin + scroll_offset;
 
         (cursor_bounds, scroll_offset, current_line_index)
@@ -221,12 +384,18 @@ impl TextElement {
         let mut line_corners = vec![];
 
         let mut offset_y = px(0.);
-        for line in lines.iter() {
+        for (line_ix, line) in lines.iter().enumerate() {
             let line_size = line.size(line_height);
             let line_wrap_width = line_size.width;
 
             let line_origin = point(px(0.), offset_y);
 
+            // The folded lines are not displayed, so no selection for them.
+            if state.folds.is_hidden(line_ix) {
+                prev_lines_offset += line.len() + 1;
+                continue;
+            }
+
             let line_cursor_start =
                 line.position_for_index(start_ix.saturating_sub(prev_lines_offset), line_height);
             let line_cursor_end =
@@ -349,7 +518,7 @@ impl TextElement {
         let mut visible_range = 0..total_lines;
         let mut line_bottom = px(0.);
         for (ix, line) in last_layout.lines.iter().enumerate() {
-            line_bottom += (line.wrap_boundaries.len() + 1) * line_height;
+            line_bottom += state.folds.line_height(ix, line, line_height);
 
             if line_bottom < -scroll_top {
                 visible_range.start = ix;
@@ -371,6 +540,7 @@ impl TextElement {
         cx: &mut App,
     ) -> Option<(usize, Vec<(Range<usize>, HighlightStyle)>)> {
         let theme = cx.theme().highlight_theme.clone();
+        let link_color = cx.theme().link;
         self.state.update(cx, |state, cx| match &state.mode {
             InputMode::CodeEditor {
                 language,
@@ -411,31 +581,69 @@ impl TextElement {
                 }
 
                 let mut marker_styles = vec![];
-                for marker in markers.iter() {
-                    if let Some(range) = &marker.range {
-                        if range.start < skipped_offset {
-                            continue;
-                        }
-
-                        let node_range = range.start..range.end;
-                        if node_range.start >= visible_range.start
-                            || node_range.end <= visible_range.end
-                        {
-                            marker_styles
-                                .push((node_range, marker.severity.highlight_style(&theme, cx)));
-                        }
+                for (range, severity) in marker_highlight_ranges(markers) {
+                    if range.end <= skipped_offset || range.start >= offset {
+                        continue;
                     }
+
+                    let range = range.start.max(skipped_offset)..range.end.min(offset);
+                    marker_styles.push((range, severity.highlight_style(&theme, cx)));
                 }
 
                 styles = gpui::combine_highlights(marker_styles, styles).collect();
 
                 Some((skipped_offset, styles))
             }
-            _ => None,
+            _ => match state.rich_text.as_ref() {
+                Some(rich_text) if !state.masked => {
+                    Some((0, rich_text.highlight_styles(link_color)))
+                }
+                _ => None,
+            },
         })
     }
 }
 
+/// Split the runs at the `range` boundaries, and underline the runs in the `range`.
+fn underline_runs(
+    runs: Vec<TextRun>,
+    range: &Range<usize>,
+    underline: UnderlineStyle,
+) -> Vec<TextRun> {
+    let mut result = Vec::with_capacity(runs.len() + 2);
+    let mut offset = 0;
+    for run in runs {
+        let run_range = offset..offset + run.len;
+        offset = run_range.end;
+
+        let start = range.start.clamp(run_range.start, run_range.end);
+        let end = range.end.clamp(run_range.start, run_range.end);
+        if start == end {
+            result.push(run);
+            continue;
+        }
+
+        for (part, underlined) in [
+            (run_range.start..start, false),
+            (start..end, true),
+            (end..run_range.end, false),
+        ] {
+            if part.is_empty() {
+                continue;
+            }
+
+            let mut run = run.clone();
+            run.len = part.len();
+            if underlined {
+                run.underline = Some(underline);
+            }
+            result.push(run);
+        }
+    }
+
+    result
+}
+
 pub(super) struct PrepaintState {
     /// The lines of entire lines.
     last_layout: LastLayout,
@@ -449,6 +657,14 @@ pub(super) struct PrepaintState {
     current_line_index: Option<usize>,
     selection_path: Option<Path<Pixels>>,
     bounds: Bounds<Pixels>,
+    /// The fold state when prepaint.
+    folds: FoldMap,
+    /// The fold markers of the visible lines, based on `visible_range`.
+    fold_markers: Vec<Option<WrappedLine>>,
+    /// The placeholder displayed after the folded line.
+    fold_ellipsis: Option<WrappedLine>,
+    /// The (line index, line top, color) of the lines with diagnostic markers.
+    diagnostic_markers: Vec<(usize, Pixels, Hsla)>,
 }
 
 impl IntoElement for TextElement {
@@ -543,6 +759,14 @@ impl Element for TextElement {
 
         let visible_range = self.calculate_visible_range(&state, line_height, bounds.size.height);
         let highlight_styles = self.highlight_lines(&visible_range, cx);
+        let misspelled_ranges = self.state.update(cx, |state, _| {
+            if state.masked {
+                return vec![];
+            }
+            state
+                .spellcheck
+                .misspelled_ranges(&state.text, visible_range.clone())
+        });
 
         let state = self.state.read(cx);
         let multi_line = state.mode.is_multi_line();
@@ -581,11 +805,14 @@ impl Element for TextElement {
                 None,
             )
             .unwrap();
-        let line_number_width = if state.mode.line_number() {
+        let mut line_number_width = if state.mode.line_number() {
             empty_line_number.last().unwrap().width() + LINE_NUMBER_RIGHT_MARGIN
         } else {
             px(0.)
         };
+        if state.mode.folding() {
+            line_number_width += FOLD_MARKER_WIDTH;
+        }
 
         let run = TextRun {
             len: display_text.len(),
@@ -595,21 +822,14 @@ impl Element for TextElement {
             underline: None,
             strikethrough: None,
         };
-        let marked_run = TextRun {
-            len: 0,
-            font: style.font(),
-            color: text_color,
-            background_color: None,
-            underline: Some(UnderlineStyle {
-                thickness: px(1.),
-                color: Some(text_color),
-                wavy: false,
-            }),
-            strikethrough: None,
+        let marked_underline = UnderlineStyle {
+            thickness: px(1.),
+            color: Some(text_color),
+            wavy: false,
         };
 
-        let runs = if !is_empty {
-            if let Some((skipped_offset, highlight_styles)) = highlight_styles {
+        let mut runs = match highlight_styles {
+            Some((skipped_offset, highlight_styles)) if !is_empty => {
                 let mut runs = vec![];
                 if skipped_offset > 0 {
                     runs.push(TextRun {
@@ -619,48 +839,35 @@ impl Element for TextElement {
                 }
 
                 runs.extend(highlight_styles.iter().map(|(range, style)| {
-                    let mut run = text_style.clone().highlight(*style).to_run(range.len());
-                    if let Some(marked_range) = &state.marked_range {
-                        if range.start >= marked_range.start && range.end <= marked_range.end {
-                            run.color = marked_run.color;
-                            run.strikethrough = marked_run.strikethrough;
-                            run.underline = marked_run.underline;
-                        }
-                    }
-
-                    run
+                    text_style.clone().highlight(*style).to_run(range.len())
                 }));
 
                 runs.into_iter().filter(|run| run.len > 0).collect()
-            } else {
-                vec![run]
-            }
-        } else if let Some(marked_range) = &state.marked_range {
-            // IME marked text
-            vec![
-                TextRun {
-                    len: marked_range.start.offset,
-                    ..run.clone()
-                },
-                TextRun {
-                    len: marked_range.end.offset - marked_range.start.offset,
-                    underline: marked_run.underline,
-                    ..run.clone()
-                },
-                TextRun {
-                    len: display_text.len() - marked_range.end.offset,
-                    ..run.clone()
-                },
-            ]
-            .into_iter()
-            .filter(|run| run.len > 0)
-            .collect()
-        } else {
-            vec![run]
+            }
+            _ => vec![run],
         };
 
+        // Underline the IME marked (composing) text, the masked text has different length.
+        if let Some(marked_range) = state.marked_range {
+            if !is_empty && !state.masked && !marked_range.is_empty() {
+                runs = underline_runs(runs, &marked_range.into(), marked_underline);
+            }
+        }
+        // Underline the misspelled words in the visible lines.
+        if !is_empty {
+            let misspelled_underline = UnderlineStyle {
+                thickness: px(1.),
+                color: Some(cx.theme().red),
+                wavy: true,
+            };
+            for range in misspelled_ranges.iter() {
+                runs = underline_runs(runs, range, misspelled_underline);
+            }
+        }
+
+        let minimap_width = minimap_reserved_width(state.mode.minimap());
         let wrap_width = if multi_line && state.soft_wrap {
-            Some(bounds.size.width - line_number_width)
+            Some(bounds.size.width - line_number_width - minimap_width)
         } else {
             None
         };
@@ -675,7 +882,11 @@ impl Element for TextElement {
 
         let mut max_line_width = px(0.);
         let mut total_wrapped_lines = 0;
-        for line in lines.iter() {
+        for (ix, line) in lines.iter().enumerate() {
+            // The folded lines are not displayed.
+            if state.folds.is_hidden(ix) {
+                continue;
+            }
             // FIXME: The `shape_text` measured width is not stable, sometime will large, sometime small.
             max_line_width = max_line_width.max(line.width());
             // +1 is the first line, `wrap_boundaries` is the wrapped lines after the `\n`.
@@ -683,8 +894,9 @@ impl Element for TextElement {
         }
 
         let scroll_size = size(
-            if max_line_width + line_number_width + RIGHT_MARGIN > bounds.size.width {
-                max_line_width + line_number_width + RIGHT_MARGIN
+            if max_line_width + line_number_width + RIGHT_MARGIN + minimap_width > bounds.size.width
+            {
+                max_line_width + line_number_width + RIGHT_MARGIN + minimap_width
             } else {
                 max_line_width
             },
@@ -741,6 +953,47 @@ impl Element for TextElement {
         );
 
         let state = self.state.read(cx);
+        let folds = state.folds.clone();
+        let shape_marker = |text: &str, window: &mut Window| {
+            window
+                .text_system()
+                .shape_text(
+                    SharedString::from(text.to_string()),
+                    font_size,
+                    &[TextRun {
+                        len: text.len(),
+                        font: style.font(),
+                        color: cx.theme().muted_foreground,
+                        background_color: None,
+                        underline: None,
+                        strikethrough: None,
+                    }],
+                    None,
+                    None,
+                )
+                .ok()
+                .and_then(|lines| lines.into_iter().next())
+        };
+
+        let mut fold_markers = vec![];
+        let mut fold_ellipsis = None;
+        if state.mode.folding() {
+            let (folded_marker, foldable_marker) =
+                (shape_marker("▸", window), shape_marker("▾", window));
+            for ix in visible_range.clone() {
+                fold_markers.push(if folds.is_folded(ix) {
+                    folded_marker.clone()
+                } else if folds.foldable_at(ix).is_some() && !folds.is_hidden(ix) {
+                    foldable_marker.clone()
+                } else {
+                    None
+                });
+            }
+            if folds.has_folded() {
+                fold_ellipsis = shape_marker("⋯", window);
+            }
+        }
+
         let line_numbers = if state.mode.line_number() {
             let mut line_numbers = vec![];
             let run_len = 4;
@@ -771,6 +1024,12 @@ impl Element for TextElement {
                 let ix = ix + visible_range.start;
                 let line_no = ix + 1;
 
+                // Keep an empty item for the folded line, to match the `visible_range`.
+                if folds.is_hidden(ix) {
+                    line_numbers.push(SmallVec::new());
+                    continue;
+                }
+
                 let mut line_no_text = format!("{:>4}", line_no);
                 if !line.wrap_boundaries.is_empty() {
                     line_no_text.push_str(&"\n    ".repeat(line.wrap_boundaries.len()));
@@ -793,6 +1052,38 @@ impl Element for TextElement {
             None
         };
 
+        // The lines with the diagnostic markers, the line uses the highest severity.
+        let mut marker_lines: Vec<(usize, MarkerSeverity)> = vec![];
+        if let Some(markers) = state.mode.markers() {
+            for (range, severity) in marker_highlight_ranges(markers) {
+                let line_ix = text.byte_to_line(range.start.min(text.len_bytes()));
+                match marker_lines.last_mut() {
+                    Some((last_ix, last_severity)) if *last_ix == line_ix => {
+                        if severity.priority() > last_severity.priority() {
+                            *last_severity = severity;
+                        }
+                    }
+                    _ => marker_lines.push((line_ix, severity)),
+                }
+            }
+        }
+        let mut diagnostic_markers = vec![];
+        if !marker_lines.is_empty() {
+            let theme = &cx.theme().highlight_theme;
+            let mut marker_lines = marker_lines.into_iter().peekable();
+            let mut line_top = px(0.);
+            for (ix, line) in lines.iter().enumerate() {
+                let Some((line_ix, severity)) = marker_lines.peek() else {
+                    break;
+                };
+                if *line_ix == ix {
+                    diagnostic_markers.push((ix, line_top, severity.fg(theme, cx)));
+                    marker_lines.next();
+                }
+                line_top += folds.line_height(ix, line, line_height);
+            }
+        }
+
         PrepaintState {
             bounds,
             last_layout: LastLayout {
@@ -808,6 +1099,10 @@ impl Element for TextElement {
             cursor_scroll_offset,
             current_line_index,
             selection_path,
+            folds,
+            fold_markers,
+            fold_ellipsis,
+            diagnostic_markers,
         }
     }
 
@@ -862,8 +1157,14 @@ impl Element for TextElement {
         let origin = bounds.origin;
 
         let mut invisible_top_padding = px(0.);
-        for line in prepaint.last_layout.lines.iter().take(visible_range.start) {
-            invisible_top_padding += line.size(line_height).height;
+        for (ix, line) in prepaint
+            .last_layout
+            .lines
+            .iter()
+            .take(visible_range.start)
+            .enumerate()
+        {
+            invisible_top_padding += prepaint.folds.line_height(ix, line, line_height);
         }
 
         let mut mask_offset_y = px(0.);
@@ -912,17 +1213,33 @@ impl Element for TextElement {
 
         // Paint text
         let mut offset_y = mask_offset_y + invisible_top_padding;
-        for line in prepaint
+        for (ix, line) in prepaint
             .last_layout
             .iter()
+            .enumerate()
             .skip(visible_range.start)
             .take(visible_range.len())
         {
+            if prepaint.folds.is_hidden(ix) {
+                continue;
+            }
+
             let p = point(
                 origin.x + prepaint.last_layout.line_number_width,
                 origin.y + offset_y,
             );
             _ = line.paint(p, line_height, TextAlign::Left, None, window, cx);
+
+            // Paint the ellipsis after the folded line.
+            if prepaint.folds.is_folded(ix) {
+                if let (Some(ellipsis), Some(end)) = (
+                    prepaint.fold_ellipsis.as_ref(),
+                    line.position_for_index(line.len(), line_height),
+                ) {
+                    let p = p + end + point(px(4.), px(0.));
+                    _ = ellipsis.paint(p, line_height, TextAlign::Left, None, window, cx);
+                }
+            }
             offset_y += line.size(line_height).height;
         }
 
@@ -980,6 +1297,63 @@ impl Element for TextElement {
                     offset_y += line_size.height;
                 }
             }
+
+            // Paint fold markers, on the first row of the lines.
+            let mut offset_y = invisible_top_padding;
+            let marker_x = input_bounds.origin.x + prepaint.last_layout.line_number_width
+                - LINE_NUMBER_RIGHT_MARGIN
+                - FOLD_MARKER_WIDTH;
+            for (ix, line) in prepaint
+                .last_layout
+                .iter()
+                .enumerate()
+                .skip(visible_range.start)
+                .take(visible_range.len())
+            {
+                let marker = prepaint
+                    .fold_markers
+                    .get(ix - visible_range.start)
+                    .and_then(|marker| marker.as_ref());
+                if let Some(marker) = marker {
+                    let p = point(
+                        marker_x + (FOLD_MARKER_WIDTH - marker.width()) / 2.,
+                        origin.y + offset_y,
+                    );
+                    _ = marker.paint(p, line_height, TextAlign::Left, None, window, cx);
+                }
+                offset_y += prepaint.folds.line_height(ix, line, line_height);
+            }
+
+            // Paint diagnostic markers at the left of the line numbers.
+            for (ix, line_top, color) in prepaint.diagnostic_markers.iter() {
+                if !visible_range.contains(ix) || prepaint.folds.is_hidden(*ix) {
+                    continue;
+                }
+
+                window.paint_quad(fill(
+                    Bounds::new(
+                        point(input_bounds.origin.x, origin.y + *line_top),
+                        size(px(2.), line_height),
+                    ),
+                    *color,
+                ));
+            }
+        }
+
+        self.paint_minimap(&prepaint, window, cx);
+
+        // Paint diagnostic markers on the scrollbar track, at the relative position of the lines.
+        if prepaint.scroll_size.height > input_bounds.size.height {
+            for (_, line_top, color) in prepaint.diagnostic_markers.iter() {
+                let y = *line_top / prepaint.scroll_size.height * input_bounds.size.height;
+                window.paint_quad(fill(
+                    Bounds::new(
+                        point(input_bounds.right() - px(8.), input_bounds.top() + y),
+                        size(px(6.), px(2.)),
+                    ),
+                    *color,
+                ));
+            }
         }
 
         self.state.update(cx, |state, cx| {
@@ -989,12 +1363,65 @@ impl Element for TextElement {
             state.set_input_bounds(input_bounds, cx);
             state.last_selected_range = Some(selected_range);
             state.scroll_size = prepaint.scroll_size;
+            state.deferred_scroll = None;
             state
                 .scroll_handle
                 .set_offset(prepaint.cursor_scroll_offset);
             cx.notify();
         });
 
-        self.paint_mouse_listeners(window, cx);
+        self.paint_mouse_listeners(input_bounds, window, cx);
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::{black, font, px, TextRun, UnderlineStyle};
+
+    use super::underline_runs;
+
+    #[test]
+    fn test_underline_runs() {
+        let run = |len: usize| TextRun {
+            len,
+            font: font("Helvetica"),
+            color: black(),
+            background_color: None,
+            underline: None,
+            strikethrough: None,
+        };
+        let underline = UnderlineStyle {
+            thickness: px(1.),
+            color: None,
+            wavy: false,
+        };
+        let summary = |runs: Vec<TextRun>| {
+            runs.iter()
+                .map(|run| (run.len, run.underline.is_some()))
+                .collect::<Vec<_>>()
+        };
+
+        assert_eq!(
+            summary(underline_runs(vec![run(10)], &(3..6), underline)),
+            vec![(3, false), (3, true), (4, false)]
+        );
+        // The range across multiple runs.
+        assert_eq!(
+            summary(underline_runs(
+                vec![run(4), run(4), run(4)],
+                &(2..10),
+                underline
+            )),
+            vec![(2, false), (2, true), (4, true), (2, true), (2, false)]
+        );
+        // The range at the end of text.
+        assert_eq!(
+            summary(underline_runs(vec![run(5)], &(5..5), underline)),
+            vec![(5, false)]
+        );
+        assert_eq!(
+            summary(underline_runs(vec![run(5)], &(0..5), underline)),
+            vec![(5, true)]
+        );
     }
 }
@@ -0,0 +1,340 @@
+use std::ops::Range;
+
+use gpui::{px, Pixels, WrappedLine};
+use ropey::Rope;
+use tree_sitter::Node;
+
+/// The code folding state of the code editor.
+///
+/// All the ranges are (zero based) line ranges `start..end`, the `start` line is
+/// displayed with a placeholder when folded, and the `start + 1..end` lines are hidden.
+#[derive(Debug, Default, Clone)]
+pub(super) struct FoldMap {
+    /// The foldable ranges sorted by start, at most one range for each start line.
+    foldable: Vec<Range<usize>>,
+    /// The folded ranges sorted by start.
+    folded: Vec<Range<usize>>,
+    /// Set true when the text is changed, to compute the foldable ranges again.
+    pub(super) dirty: bool,
+}
+
+impl FoldMap {
+    pub(super) fn new() -> Self {
+        Self {
+            dirty: true,
+            ..Default::default()
+        }
+    }
+
+    pub(super) fn set_foldable(&mut self, foldable: Vec<Range<usize>>) {
+        self.foldable = foldable;
+        self.dirty = false;
+    }
+
+    /// Returns the foldable range start at the line.
+    pub(super) fn foldable_at(&self, line_ix: usize) -> Option<&Range<usize>> {
+        self.foldable
+            .binary_search_by_key(&line_ix, |range| range.start)
+            .ok()
+            .map(|ix| &self.foldable[ix])
+    }
+
+    /// Returns true if there is a folded range start at the line.
+    pub(super) fn is_folded(&self, line_ix: usize) -> bool {
+        self.folded.iter().any(|range| range.start == line_ix)
+    }
+
+    /// Returns true if the line is hidden by a folded range.
+    pub(super) fn is_hidden(&self, line_ix: usize) -> bool {
+        self.folded
+            .iter()
+            .any(|range| range.start < line_ix && line_ix < range.end)
+    }
+
+    /// Returns true if there is any folded range.
+    pub(super) fn has_folded(&self) -> bool {
+        !self.folded.is_empty()
+    }
+
+    /// Returns the display height of the line, a hidden line is 0.
+    pub(super) fn line_height(
+        &self,
+        line_ix: usize,
+        line: &WrappedLine,
+        line_height: Pixels,
+    ) -> Pixels {
+        if self.is_hidden(line_ix) {
+            px(0.)
+        } else {
+            line.size(line_height).height
+        }
+    }
+
+    /// Returns the count of the hidden lines.
+    pub(super) fn hidden_lines_count(&self, lines_count: usize) -> usize {
+        (0..lines_count).filter(|ix| self.is_hidden(*ix)).count()
+    }
+
+    /// Fold the foldable range start at the line, returns false if not foldable.
+    pub(super) fn fold(&mut self, line_ix: usize) -> bool {
+        let Some(range) = self.foldable_at(line_ix).cloned() else {
+            return false;
+        };
+        if self.is_folded(line_ix) {
+            return false;
+        }
+
+        let ix = self
+            .folded
+            .partition_point(|folded| folded.start < range.start);
+        self.folded.insert(ix, range);
+        true
+    }
+
+    /// Unfold the folded range start at the line, returns false if not folded.
+    pub(super) fn unfold(&mut self, line_ix: usize) -> bool {
+        let len = self.folded.len();
+        self.folded.retain(|range| range.start != line_ix);
+        self.folded.len() != len
+    }
+
+    pub(super) fn toggle(&mut self, line_ix: usize) -> bool {
+        if self.is_folded(line_ix) {
+            self.unfold(line_ix)
+        } else {
+            self.fold(line_ix)
+        }
+    }
+
+    pub(super) fn fold_all(&mut self) {
+        self.folded = self.foldable.clone();
+    }
+
+    pub(super) fn unfold_all(&mut self) {
+        self.folded.clear();
+    }
+
+    /// Unfold the ranges that hide the line, returns true if any range is unfolded.
+    pub(super) fn reveal(&mut self, line_ix: usize) -> bool {
+        let len = self.folded.len();
+        self.folded
+            .retain(|range| !(range.start < line_ix && line_ix < range.end));
+        self.folded.len() != len
+    }
+
+    /// Update the folded ranges for a text edit, that replaced the `start_line..=old_end_line`
+    /// lines by the `start_line..=new_end_line` lines.
+    ///
+    /// The folded ranges after the edit are moved, and the edited ones are unfolded.
+    pub(super) fn edit(&mut self, start_line: usize, old_end_line: usize, new_end_line: usize) {
+        self.dirty = true;
+        self.folded.retain_mut(|range| {
+            if range.end <= start_line {
+                true
+            } else if range.start > old_end_line {
+                range.start = range.start + new_end_line - old_end_line;
+                range.end = range.end + new_end_line - old_end_line;
+                true
+            } else {
+                false
+            }
+        });
+    }
+}
+
+/// Returns the foldable ranges of the multi-line syntax nodes, and the consecutive line comments.
+///
+/// For the nodes start at the same line, the largest one is used.
+pub(super) fn syntax_fold_ranges(root: Node, text: &Rope) -> Vec<Range<usize>> {
+    let mut ranges: Vec<Range<usize>> = vec![];
+    let mut comment_lines = vec![];
+
+    let mut cursor = root.walk();
+    let mut visited_children = false;
+    loop {
+        if !visited_children {
+            let node = cursor.node();
+            let start = node.start_position();
+            let end = node.end_position();
+            // Exclude the trailing `\n` of the node.
+            let end_row = if end.column == 0 && end.row > start.row {
+                end.row - 1
+            } else {
+                end.row
+            };
+
+            if node.id() != root.id() && node.is_named() {
+                if end_row > start.row {
+                    ranges.push(start.row..end_row + 1);
+                } else if node.kind().contains("comment") && is_line_start(text, start) {
+                    comment_lines.push(start.row);
+                }
+            }
+        }
+
+        if !visited_children && cursor.goto_first_child() {
+            continue;
+        }
+        if cursor.goto_next_sibling() {
+            visited_children = false;
+            continue;
+        }
+        if !cursor.goto_parent() {
+            break;
+        }
+        visited_children = true;
+    }
+
+    // Fold the consecutive line comments as a block.
+    comment_lines.sort_unstable();
+    comment_lines.dedup();
+    let mut ix = 0;
+    while ix < comment_lines.len() {
+        let start = comment_lines[ix];
+        let mut end = start;
+        while ix + 1 < comment_lines.len() && comment_lines[ix + 1] == end + 1 {
+            ix += 1;
+            end += 1;
+        }
+        ranges.push(start..end + 1);
+        ix += 1;
+    }
+
+    normalize_fold_ranges(ranges)
+}
+
+/// Returns true if the position is the first non-whitespace char of the line.
+fn is_line_start(text: &Rope, position: tree_sitter::Point) -> bool {
+    let Some(line) = text.get_line(position.row) else {
+        return false;
+    };
+    line.chars()
+        .take_while(|c| c.is_whitespace())
+        .map(|c| c.len_utf8())
+        .sum::<usize>()
+        == position.column
+}
+
+/// Returns the foldable ranges by the indentation, for the languages without syntax tree.
+///
+/// A line is foldable if the following lines are more indented, the blank lines are ignored.
+pub(super) fn indent_fold_ranges(text: &Rope) -> Vec<Range<usize>> {
+    let mut ranges = vec![];
+    // The (line_ix, indent) of the unclosed lines.
+    let mut stack: Vec<(usize, usize)> = vec![];
+    let mut last_line = 0;
+
+    for (line_ix, line) in text.lines().enumerate() {
+        let mut indent = 0;
+        let mut blank = true;
+        for c in line.chars() {
+            match c {
+                ' ' => indent += 1,
+                '\t' => indent += 4,
+                '\r' | '\n' => break,
+                _ => {
+                    blank = false;
+                    break;
+                }
+            }
+        }
+        if blank {
+            continue;
+        }
+
+        while let Some(&(start, start_indent)) = stack.last() {
+            if start_indent < indent {
+                break;
+            }
+            stack.pop();
+            ranges.push(start..last_line + 1);
+        }
+        stack.push((line_ix, indent));
+        last_line = line_ix;
+    }
+
+    for (start, _) in stack {
+        ranges.push(start..last_line + 1);
+    }
+
+    normalize_fold_ranges(ranges)
+}
+
+/// Keep the largest range for each start line, and remove the ranges less than 2 lines.
+fn normalize_fold_ranges(mut ranges: Vec<Range<usize>>) -> Vec<Range<usize>> {
+    ranges.retain(|range| range.end >= range.start + 2);
+    ranges.sort_by(|a, b| a.start.cmp(&b.start).then(b.end.cmp(&a.end)));
+    ranges.dedup_by_key(|range| range.start);
+    ranges
+}
+
+#[cfg(test)]
+mod tests {
+    use ropey::Rope;
+
+    use super::{indent_fold_ranges, syntax_fold_ranges, FoldMap};
+
+    #[test]
+    fn test_indent_fold_ranges() {
+        let text =
+            Rope::from_str("def foo():\n    a = 1\n\n    if a:\n        b = 2\nc = 3\n  d\n");
+        assert_eq!(indent_fold_ranges(&text), vec![0..5, 3..5, 5..7]);
+
+        let text = Rope::from_str("a\n  b\n  c");
+        assert_eq!(indent_fold_ranges(&text), vec![0..3]);
+        assert_eq!(indent_fold_ranges(&Rope::from_str("")), vec![]);
+    }
+
+    #[test]
+    fn test_syntax_fold_ranges() {
+        let source = "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": 1\n}\n";
+        let mut parser = tree_sitter::Parser::new();
+        parser
+            .set_language(&tree_sitter_json::LANGUAGE.into())
+            .unwrap();
+        let tree = parser.parse(source, None).unwrap();
+
+        let ranges = syntax_fold_ranges(tree.root_node(), &Rope::from_str(source));
+        assert_eq!(ranges, vec![0..7, 1..5]);
+    }
+
+    #[test]
+    fn test_fold_map() {
+        let mut folds = FoldMap::new();
+        folds.set_foldable(vec![0..10, 2..5, 12..20]);
+
+        assert!(!folds.fold(1));
+        assert!(folds.fold(2));
+        assert!(folds.fold(0));
+        assert!(!folds.fold(0));
+        assert!(folds.is_folded(0));
+        assert!(!folds.is_hidden(0));
+        assert!(folds.is_hidden(1));
+        assert!(folds.is_hidden(9));
+        assert!(!folds.is_hidden(10));
+        assert_eq!(folds.hidden_lines_count(20), 9);
+
+        assert!(folds.unfold(0));
+        assert!(!folds.is_hidden(1));
+        assert!(folds.is_hidden(3));
+
+        // Reveal the hidden line.
+        assert!(folds.reveal(4));
+        assert!(!folds.has_folded());
+
+        folds.fold_all();
+        assert!(folds.is_hidden(13));
+        folds.unfold_all();
+        assert!(!folds.is_hidden(13));
+
+        // Insert 2 lines before the folded range.
+        folds.fold(12);
+        folds.edit(11, 11, 13);
+        assert!(!folds.is_hidden(13));
+        assert!(folds.is_folded(14));
+        assert!(folds.is_hidden(21));
+        // Edit in the folded range.
+        folds.edit(16, 16, 16);
+        assert!(!folds.has_folded());
+    }
+}
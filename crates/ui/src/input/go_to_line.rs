@@ -0,0 +1,302 @@
+use gpui::{
+    anchored, deferred, div, point, px, AppContext as _, Context, Corner, Entity,
+    InteractiveElement as _, IntoElement, ParentElement as _, Pixels, Point, SharedString,
+    Styled as _, Subscription, Window,
+};
+
+use crate::{v_flex, ActiveTheme as _, Sizable as _};
+
+use super::{
+    Enter, Escape, Indent, IndentInline, InputEvent, InputState, MoveDown, MovePageDown,
+    MovePageUp, MoveUp, NavigateBack, NavigateForward, Outdent, OutdentInline, RopeExt as _,
+    SelectDown, SelectUp, Selection, TextInput,
+};
+use crate::scroll::ScrollAlign;
+
+/// The go to line panel of the code editor, opened by the [`super::GoToLine`] action.
+pub(super) struct GoToLinePanel {
+    input: Entity<InputState>,
+    /// The selection and scroll offset before open, to restore on cancel.
+    origin: Selection,
+    origin_scroll: Point<Pixels>,
+    message: SharedString,
+    /// Is the `message` a warning, e.g.: the line is out of range.
+    warning: bool,
+    _subscriptions: Vec<Subscription>,
+}
+
+/// Parse the 1-based `line` or `line:column` text, e.g.: "12", "12:5".
+pub(super) fn parse_line_column(text: &str) -> Option<(usize, Option<usize>)> {
+    let text = text.trim();
+    let (line, column) = match text.split_once(':') {
+        Some((line, column)) => (line, Some(column.trim())),
+        None => (text, None),
+    };
+
+    let line = line.trim().parse::<usize>().ok()?;
+    let column = match column {
+        // Allow the trailing colon while typing the column.
+        Some("") | None => None,
+        Some(column) => Some(column.parse::<usize>().ok()?),
+    };
+
+    Some((line, column))
+}
+
+/// Clamp the 1-based `line` to the `line_count`, and returns whether it was out of range.
+pub(super) fn clamp_line(line: usize, line_count: usize) -> (usize, bool) {
+    let clamped = line.clamp(1, line_count.max(1));
+    (clamped, clamped != line)
+}
+
+impl InputState {
+    /// Open the go to line panel, or close it if it is already opened.
+    pub(super) fn toggle_go_to_line(
+        &mut self,
+        _: &super::GoToLine,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if self.go_to_line.is_some() {
+            self.cancel_go_to_line(window, cx);
+            return;
+        }
+
+        let input =
+            cx.new(|cx| InputState::new(window, cx).placeholder("Line number or line:column"));
+        let _subscriptions = vec![cx.subscribe_in(&input, window, Self::on_go_to_line_event)];
+        input.update(cx, |input, cx| input.focus(window, cx));
+
+        self.hide_completion_menu(cx);
+        self.go_to_line = Some(GoToLinePanel {
+            input,
+            origin: self.selected_range,
+            origin_scroll: self.scroll_offset(),
+            message: self.go_to_line_hint().into(),
+            warning: false,
+            _subscriptions,
+        });
+        cx.notify();
+    }
+
+    fn go_to_line_hint(&self) -> String {
+        let line = self.line_column().line;
+        format!("Current line: {} of {}", line, self.text.len_lines())
+    }
+
+    fn on_go_to_line_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::Change(text) => self.preview_go_to_line(text, window, cx),
+            InputEvent::PressEnter { .. } => self.confirm_go_to_line(window, cx),
+            // Keep the cursor, e.g.: clicked in the editor to place it.
+            InputEvent::Blur => {
+                if self.go_to_line.take().is_some() {
+                    cx.notify();
+                }
+            }
+            _ => {}
+        }
+    }
+
+    /// Move the cursor to the typed line to preview it, the panel is still opened.
+    fn preview_go_to_line(&mut self, text: &str, window: &mut Window, cx: &mut Context<Self>) {
+        let Some(panel) = self.go_to_line.as_ref() else {
+            return;
+        };
+        let (origin, origin_scroll) = (panel.origin, panel.origin_scroll);
+
+        let (message, warning) = if text.trim().is_empty() {
+            self.selected_range = origin;
+            self.set_scroll_offset(origin_scroll, cx);
+            (self.go_to_line_hint(), false)
+        } else if let Some((line, column)) = parse_line_column(text) {
+            let line_count = self.text.len_lines();
+            let (line, clamped) = clamp_line(line, line_count);
+            self.go_to_line(line, column, window, cx);
+            self.scroll_to_line(line, ScrollAlign::Center, window, cx);
+            if clamped {
+                (
+                    format!(
+                        "Line is out of range, go to line {} of {}",
+                        line, line_count
+                    ),
+                    true,
+                )
+            } else {
+                (format!("Go to line {}", line), false)
+            }
+        } else {
+            (format!("Invalid line number: {}", text.trim()), true)
+        };
+
+        if let Some(panel) = self.go_to_line.as_mut() {
+            panel.message = message.into();
+            panel.warning = warning;
+        }
+        cx.notify();
+    }
+
+    /// Close the panel and keep the cursor at the previewed line.
+    fn confirm_go_to_line(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let Some(panel) = self.go_to_line.take() else {
+            return;
+        };
+
+        // Record the position before the jump, to go back by `NavigateBack`.
+        if panel.origin != self.selected_range {
+            let cursor = if self.selection_reversed {
+                panel.origin.start
+            } else {
+                panel.origin.end
+            };
+            self.jump_list.push(self.text.line_column(cursor.offset));
+        }
+        self.focus(window, cx);
+        cx.notify();
+    }
+
+    /// Close the panel and restore the cursor and scroll position before open.
+    fn cancel_go_to_line(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let Some(panel) = self.go_to_line.take() else {
+            return;
+        };
+
+        self.selected_range = panel.origin;
+        self.set_scroll_offset(panel.origin_scroll, cx);
+        self.focus(window, cx);
+        cx.notify();
+    }
+
+    pub(super) fn navigate_back(
+        &mut self,
+        _: &NavigateBack,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let current = self.text.line_column(self.cursor().offset);
+        if let Some(pos) = self.jump_list.back(current) {
+            self.jump_to(pos, window, cx);
+        }
+    }
+
+    pub(super) fn navigate_forward(
+        &mut self,
+        _: &NavigateForward,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let current = self.text.line_column(self.cursor().offset);
+        if let Some(pos) = self.jump_list.forward(current) {
+            self.jump_to(pos, window, cx);
+        }
+    }
+
+    /// Move the cursor to the zero based `(line, column)` of the jump list.
+    fn jump_to(
+        &mut self,
+        (line_ix, column_ix): (usize, usize),
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.go_to_line(line_ix + 1, Some(column_ix + 1), window, cx);
+        let line = self.line_column().line;
+        self.scroll_to_line(line, ScrollAlign::Center, window, cx);
+    }
+
+    pub(super) fn render_go_to_line(&self, cx: &mut Context<Self>) -> Option<impl IntoElement> {
+        let panel = self.go_to_line.as_ref()?;
+        let bounds = self.last_bounds?;
+
+        Some(
+            deferred(
+                anchored()
+                    .anchor(Corner::TopRight)
+                    .snap_to_window_with_margin(px(8.))
+                    .position(bounds.top_right() + point(px(-8.), px(8.)))
+                    .child(
+                        v_flex()
+                            .id("go-to-line")
+                            .occlude()
+                            .w(px(280.))
+                            .gap_1()
+                            .p_1()
+                            .text_sm()
+                            .bg(cx.theme().popover)
+                            .text_color(cx.theme().popover_foreground)
+                            .border_1()
+                            .border_color(cx.theme().border)
+                            .rounded(cx.theme().radius)
+                            .shadow_md()
+                            .child(
+                                div()
+                                    // The panel is in the editor, stop the actions of the
+                                    // panel input from bubbling to the editor.
+                                    .on_action(cx.listener(|_, _: &Enter, _, _| {}))
+                                    .on_action(cx.listener(|this, _: &Escape, window, cx| {
+                                        this.cancel_go_to_line(window, cx)
+                                    }))
+                                    .on_action(cx.listener(|_, _: &IndentInline, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &OutdentInline, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &Indent, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &Outdent, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &MoveUp, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &MoveDown, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &SelectUp, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &SelectDown, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &MovePageUp, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &MovePageDown, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &NavigateBack, _, _| {}))
+                                    .on_action(cx.listener(|_, _: &NavigateForward, _, _| {}))
+                                    .child(TextInput::new(&panel.input).small()),
+                            )
+                            .child(
+                                div()
+                                    .px_1()
+                                    .text_xs()
+                                    .text_color(if panel.warning {
+                                        cx.theme().warning
+                                    } else {
+                                        cx.theme().muted_foreground
+                                    })
+                                    .child(panel.message.clone()),
+                            ),
+                    ),
+            )
+            .with_priority(1),
+        )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{clamp_line, parse_line_column};
+
+    #[test]
+    fn test_parse_line_column() {
+        assert_eq!(parse_line_column("12"), Some((12, None)));
+        assert_eq!(parse_line_column(" 12 "), Some((12, None)));
+        assert_eq!(parse_line_column("12:5"), Some((12, Some(5))));
+        assert_eq!(parse_line_column("12 : 5"), Some((12, Some(5))));
+        assert_eq!(parse_line_column("12:"), Some((12, None)));
+        assert_eq!(parse_line_column(""), None);
+        assert_eq!(parse_line_column(":5"), None);
+        assert_eq!(parse_line_column("12:a"), None);
+        assert_eq!(parse_line_column("-1"), None);
+        assert_eq!(parse_line_column("abc"), None);
+    }
+
+    #[test]
+    fn test_clamp_line() {
+        assert_eq!(clamp_line(5, 10), (5, false));
+        assert_eq!(clamp_line(10, 10), (10, false));
+        assert_eq!(clamp_line(11, 10), (10, true));
+        assert_eq!(clamp_line(0, 10), (1, true));
+        assert_eq!(clamp_line(1, 0), (1, false));
+    }
+}
@@ -0,0 +1,108 @@
+/// The max number of the positions to keep in the [`JumpList`].
+const MAX_JUMPS: usize = 100;
+
+/// The positions within this number of lines are merged into one jump, to avoid
+/// recording every keystroke of the same edit location.
+const MERGE_LINES: usize = 10;
+
+/// The navigation history of the cursor positions, like the jump list of an editor.
+///
+/// The positions are the zero based `(line, column)`, they are not adjusted by the later
+/// edits, so they should be clamped to the text when navigating.
+#[derive(Debug, Default, Clone)]
+pub(super) struct JumpList {
+    back: Vec<(usize, usize)>,
+    forward: Vec<(usize, usize)>,
+}
+
+impl JumpList {
+    /// Record a position to jump back to, the positions near the last one replace it.
+    ///
+    /// This clears the forward history, like a new navigation in a browser.
+    pub(super) fn push(&mut self, pos: (usize, usize)) {
+        self.forward.clear();
+        if let Some(last) = self.back.last_mut() {
+            if last.0.abs_diff(pos.0) <= MERGE_LINES {
+                *last = pos;
+                return;
+            }
+        }
+
+        self.back.push(pos);
+        if self.back.len() > MAX_JUMPS {
+            self.back.remove(0);
+        }
+    }
+
+    /// Returns the previous position to jump to from the `current` position.
+    ///
+    /// The positions on the current line are skipped, so the first back from an edit
+    /// goes to the edit location before it.
+    pub(super) fn back(&mut self, current: (usize, usize)) -> Option<(usize, usize)> {
+        let pos = Self::pop_other_line(&mut self.back, current)?;
+        self.forward.push(current);
+        Some(pos)
+    }
+
+    /// Returns the next position to jump to from the `current` position, after [`Self::back`].
+    pub(super) fn forward(&mut self, current: (usize, usize)) -> Option<(usize, usize)> {
+        let pos = Self::pop_other_line(&mut self.forward, current)?;
+        self.back.push(current);
+        Some(pos)
+    }
+
+    fn pop_other_line(
+        stack: &mut Vec<(usize, usize)>,
+        current: (usize, usize),
+    ) -> Option<(usize, usize)> {
+        while let Some(pos) = stack.pop() {
+            if pos.0 != current.0 {
+                return Some(pos);
+            }
+        }
+
+        None
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_jump_list() {
+        let mut jumps = JumpList::default();
+        assert_eq!(jumps.back((0, 0)), None);
+
+        jumps.push((10, 2));
+        // Merged into the last one.
+        jumps.push((15, 4));
+        jumps.push((50, 0));
+        jumps.push((100, 3));
+
+        // The last position is on the current line, skip it.
+        assert_eq!(jumps.back((100, 5)), Some((50, 0)));
+        assert_eq!(jumps.back((50, 0)), Some((15, 4)));
+        assert_eq!(jumps.back((15, 4)), None);
+        assert_eq!(jumps.forward((15, 4)), Some((50, 0)));
+        assert_eq!(jumps.forward((50, 0)), Some((100, 5)));
+        assert_eq!(jumps.forward((100, 5)), None);
+
+        // A new position clears the forward history.
+        assert_eq!(jumps.back((100, 5)), Some((50, 0)));
+        jumps.push((200, 0));
+        assert_eq!(jumps.forward((200, 0)), None);
+        assert_eq!(jumps.back((200, 0)), Some((15, 4)));
+    }
+
+    #[test]
+    fn test_jump_list_max() {
+        let mut jumps = JumpList::default();
+        for ix in 0..=MAX_JUMPS {
+            jumps.push((ix * 100, 0));
+        }
+
+        assert_eq!(jumps.back.len(), MAX_JUMPS);
+        assert_eq!(jumps.back.first(), Some(&(100, 0)));
+    }
+}
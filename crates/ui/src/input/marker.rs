@@ -1,9 +1,10 @@
 use crate::{
     highlighter::HighlightTheme,
-    input::{InputState, LineColumn},
+    input::{InputState, LineColumn, RopeExt as _},
 };
 use gpui::{px, App, HighlightStyle, Hsla, SharedString, UnderlineStyle};
 use itertools::Itertools;
+use ropey::Rope;
 use std::ops::Range;
 
 /// Marker represents a diagnostic message, such as an error or warning, in the code editor.
@@ -72,6 +73,80 @@ impl Marker {
 
         self.range = Some(start_byte..end_byte);
     }
+
+    /// Move the marker for a text edit, that replaced the `range` by `new_len` bytes,
+    /// `text` is the text after the edit.
+    ///
+    /// The marker after the edit is moved, the edit inside the marker resizes it.
+    /// Returns false if the marker is entirely replaced by the edit.
+    pub(super) fn edit(&mut self, range: &Range<usize>, new_len: usize, text: &Rope) -> bool {
+        let Some(marker_range) = self.range.as_ref() else {
+            return true;
+        };
+        // The marker is replaced by the edit.
+        if !range.is_empty() && range.start <= marker_range.start && marker_range.end <= range.end {
+            return false;
+        }
+
+        let map = |offset: usize, is_end: bool| {
+            if offset < range.start || (offset == range.start && is_end) {
+                offset
+            } else if offset >= range.end {
+                offset + new_len - range.len()
+            } else if is_end {
+                range.start + new_len
+            } else {
+                range.start
+            }
+        };
+        let new_range = map(marker_range.start, false)..map(marker_range.end, true);
+        let (start_line, start_column) = text.line_column(new_range.start);
+        let (end_line, end_column) = text.line_column(new_range.end);
+        self.start = (start_line + 1, start_column + 1).into();
+        self.end = (end_line + 1, end_column + 1).into();
+        self.range = Some(new_range);
+        true
+    }
+}
+
+/// Returns the non-overlapping ranges of the markers sorted by start, the overlapped part
+/// uses the highest severity.
+pub(super) fn marker_highlight_ranges(markers: &[Marker]) -> Vec<(Range<usize>, MarkerSeverity)> {
+    let mut boundaries = markers
+        .iter()
+        .filter_map(|marker| marker.range.as_ref())
+        .flat_map(|range| [range.start, range.end])
+        .collect::<Vec<_>>();
+    boundaries.sort_unstable();
+    boundaries.dedup();
+
+    let mut ranges: Vec<(Range<usize>, MarkerSeverity)> = vec![];
+    for window in boundaries.windows(2) {
+        let segment = window[0]..window[1];
+        let severity = markers
+            .iter()
+            .filter(|marker| {
+                marker.range.as_ref().map_or(false, |range| {
+                    range.start <= segment.start && segment.end <= range.end
+                })
+            })
+            .map(|marker| marker.severity)
+            .max_by_key(|severity| severity.priority());
+        let Some(severity) = severity else {
+            continue;
+        };
+
+        match ranges.last_mut() {
+            Some((last, last_severity))
+                if last.end == segment.start && *last_severity == severity =>
+            {
+                last.end = segment.end;
+            }
+            _ => ranges.push((segment, severity)),
+        }
+    }
+
+    ranges
 }
 
 /// Severity of the marker.
@@ -98,6 +173,16 @@ impl From<&str> for MarkerSeverity {
 }
 
 impl MarkerSeverity {
+    /// The priority to display the overlapping markers, the higher is displayed.
+    pub(super) fn priority(&self) -> u8 {
+        match self {
+            Self::Error => 3,
+            Self::Warning => 2,
+            Self::Info => 1,
+            Self::Hint => 0,
+        }
+    }
+
     pub(super) fn bg(&self, theme: &HighlightTheme, cx: &App) -> Hsla {
         match self {
             Self::Error => theme.style.status.error_background(cx),
@@ -143,3 +228,72 @@ impl MarkerSeverity {
         style
     }
 }
+
+#[cfg(test)]
+mod tests {
+    use ropey::Rope;
+
+    use super::{marker_highlight_ranges, Marker, MarkerSeverity};
+
+    fn marker(severity: MarkerSeverity, range: std::ops::Range<usize>) -> Marker {
+        Marker {
+            severity,
+            range: Some(range),
+            ..Default::default()
+        }
+    }
+
+    #[test]
+    fn test_marker_highlight_ranges() {
+        let markers = vec![
+            marker(MarkerSeverity::Warning, 0..10),
+            marker(MarkerSeverity::Error, 5..8),
+            marker(MarkerSeverity::Hint, 7..12),
+            marker(MarkerSeverity::Info, 20..25),
+        ];
+
+        assert_eq!(
+            marker_highlight_ranges(&markers),
+            vec![
+                (0..5, MarkerSeverity::Warning),
+                (5..8, MarkerSeverity::Error),
+                (8..10, MarkerSeverity::Warning),
+                (10..12, MarkerSeverity::Hint),
+                (20..25, MarkerSeverity::Info),
+            ]
+        );
+        assert_eq!(marker_highlight_ranges(&[]), vec![]);
+    }
+
+    #[test]
+    fn test_marker_edit() {
+        // The text after the edit.
+        let text = Rope::from_str("let a = 1;\nlet bb = 2;\n");
+
+        // Edit before the marker.
+        let mut m = marker(MarkerSeverity::Error, 15..17);
+        assert!(m.edit(&(4..5), 2, &text));
+        assert_eq!(m.range, Some(16..18));
+        assert_eq!((m.start.line, m.start.column), (2, 6));
+        assert_eq!((m.end.line, m.end.column), (2, 8));
+
+        // Edit after the marker.
+        let mut m = marker(MarkerSeverity::Error, 4..5);
+        assert!(m.edit(&(5..5), 1, &text));
+        assert_eq!(m.range, Some(4..5));
+
+        // Insert inside the marker.
+        let mut m = marker(MarkerSeverity::Error, 4..6);
+        assert!(m.edit(&(5..5), 1, &text));
+        assert_eq!(m.range, Some(4..7));
+
+        // Delete a part of the marker.
+        let mut m = marker(MarkerSeverity::Error, 4..8);
+        assert!(m.edit(&(2..5), 1, &text));
+        assert_eq!(m.range, Some(2..6));
+
+        // Replace the marker.
+        let mut m = marker(MarkerSeverity::Error, 4..6);
+        assert!(!m.edit(&(2..8), 0, &text));
+    }
+}
@@ -0,0 +1,269 @@
+use std::ops::Range;
+
+use gpui::{point, px, size, Bounds, Context, Hsla, Pixels, Point};
+use ropey::Rope;
+use smallvec::SmallVec;
+
+use crate::scroll;
+
+use super::InputState;
+
+/// The width of the minimap at the right of the code editor.
+pub(super) const MINIMAP_WIDTH: Pixels = px(80.);
+/// The height of a line in the minimap, it is less for the long files to fit the minimap height.
+pub(super) const MINIMAP_LINE_HEIGHT: Pixels = px(2.);
+/// The width of a char in the minimap.
+pub(super) const MINIMAP_CHAR_WIDTH: Pixels = px(1.);
+/// The max columns of a line rendered in the minimap.
+const MAX_COLUMNS: usize = 80;
+const TAB_COLUMNS: usize = 4;
+
+/// Returns the width reserved at the right of the text for the minimap and the scrollbar.
+pub(super) fn minimap_reserved_width(minimap: bool) -> Pixels {
+    if minimap {
+        MINIMAP_WIDTH + scroll::WIDTH
+    } else {
+        px(0.)
+    }
+}
+
+/// A colored block of the non-whitespace chars in a minimap line.
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub(super) struct MinimapSpan {
+    pub(super) column: usize,
+    pub(super) len: usize,
+    pub(super) color: Hsla,
+}
+
+/// The minimap spans of each line, only the changed lines are rebuilt after an edit.
+#[derive(Default)]
+pub(super) struct MinimapCache {
+    /// The `None` lines need to rebuild.
+    lines: Vec<Option<SmallVec<[MinimapSpan; 4]>>>,
+    /// The default text color of the built lines, to rebuild all when the theme is changed.
+    default_color: Option<Hsla>,
+}
+
+impl MinimapCache {
+    /// Rebuild all the lines, e.g.: the highlighter or theme is changed.
+    pub(super) fn invalidate(&mut self) {
+        self.lines.clear();
+    }
+
+    /// Update the lines for a text edit, that replaced the `start_line..=old_end_line`
+    /// lines by the `start_line..=new_end_line` lines.
+    pub(super) fn edit(&mut self, start_line: usize, old_end_line: usize, new_end_line: usize) {
+        if start_line >= self.lines.len() {
+            return;
+        }
+
+        let old_end = (old_end_line + 1).min(self.lines.len());
+        self.lines.splice(
+            start_line..old_end,
+            std::iter::repeat(None).take(new_end_line + 1 - start_line),
+        );
+    }
+
+    /// Build the changed lines, `styles` returns the highlight colors of a byte range.
+    pub(super) fn update(
+        &mut self,
+        text: &Rope,
+        default_color: Hsla,
+        mut styles: impl FnMut(&Range<usize>) -> Vec<(Range<usize>, Hsla)>,
+    ) {
+        if self.default_color != Some(default_color) {
+            self.invalidate();
+            self.default_color = Some(default_color);
+        }
+
+        let lines_count = text.len_lines();
+        self.lines.resize(lines_count, None);
+
+        for (ix, line) in self.lines.iter_mut().enumerate() {
+            if line.is_some() {
+                continue;
+            }
+
+            let start = text.line_to_byte(ix);
+            let line_text = text.line(ix).to_string();
+            let range = start..start + line_text.len();
+            *line = Some(line_spans(
+                &line_text,
+                start,
+                &styles(&range),
+                default_color,
+            ));
+        }
+    }
+
+    pub(super) fn lines(&self) -> impl Iterator<Item = &[MinimapSpan]> {
+        self.lines
+            .iter()
+            .map(|line| line.as_ref().map_or(&[][..], |spans| spans.as_slice()))
+    }
+
+    pub(super) fn len(&self) -> usize {
+        self.lines.len()
+    }
+}
+
+/// Split the line into the spans of the non-whitespace chars with the same color.
+fn line_spans(
+    line: &str,
+    line_start: usize,
+    styles: &[(Range<usize>, Hsla)],
+    default_color: Hsla,
+) -> SmallVec<[MinimapSpan; 4]> {
+    let mut spans: SmallVec<[MinimapSpan; 4]> = SmallVec::new();
+    let mut column = 0;
+
+    for (offset, c) in line.char_indices() {
+        if column >= MAX_COLUMNS {
+            break;
+        }
+
+        match c {
+            '\t' => column += TAB_COLUMNS,
+            c if c.is_whitespace() => column += 1,
+            _ => {
+                let offset = line_start + offset;
+                let color = styles
+                    .iter()
+                    .find(|(range, _)| range.contains(&offset))
+                    .map_or(default_color, |(_, color)| *color);
+
+                match spans.last_mut() {
+                    Some(last) if last.column + last.len == column && last.color == color => {
+                        last.len += 1;
+                    }
+                    _ => spans.push(MinimapSpan {
+                        column,
+                        len: 1,
+                        color,
+                    }),
+                }
+                column += 1;
+            }
+        }
+    }
+
+    spans
+}
+
+/// Returns the line height in the minimap for the lines count, to fit the minimap height.
+pub(super) fn minimap_line_height(lines_count: usize, height: Pixels) -> Pixels {
+    if lines_count == 0 {
+        return MINIMAP_LINE_HEIGHT;
+    }
+
+    MINIMAP_LINE_HEIGHT.min(height / lines_count as f32)
+}
+
+impl InputState {
+    /// Returns the minimap bounds at the left of the scrollbar, `None` if the minimap is hidden.
+    pub(super) fn minimap_bounds(&self) -> Option<Bounds<Pixels>> {
+        if !self.mode.minimap() {
+            return None;
+        }
+
+        let bounds = self.input_bounds;
+        Some(Bounds::new(
+            point(bounds.right() - scroll::WIDTH - MINIMAP_WIDTH, bounds.top()),
+            size(MINIMAP_WIDTH, bounds.size.height),
+        ))
+    }
+
+    /// Returns the height of the minimap lines, the folded lines are not displayed.
+    pub(super) fn minimap_content_height(&self, lines_count: usize, height: Pixels) -> Pixels {
+        let lines_count = lines_count - self.folds.hidden_lines_count(lines_count);
+        minimap_line_height(lines_count, height) * lines_count as f32
+    }
+
+    /// Scroll the editor to make the position of the minimap on the center of the viewport.
+    pub(super) fn scroll_to_minimap_position(
+        &mut self,
+        position: Point<Pixels>,
+        cx: &mut Context<Self>,
+    ) {
+        let (Some(bounds), Some(last_layout)) = (self.minimap_bounds(), &self.last_layout) else {
+            return;
+        };
+
+        let content_height =
+            self.minimap_content_height(last_layout.lines.len(), bounds.size.height);
+        if content_height <= px(0.) {
+            return;
+        }
+
+        let ratio = ((position.y - bounds.top()) / content_height).clamp(0., 1.);
+        let mut offset = self.scroll_handle.offset();
+        offset.y = self.input_bounds.size.height / 2. - self.scroll_size.height * ratio;
+        self.update_scroll_offset(Some(offset), cx);
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::{black, red};
+    use ropey::Rope;
+
+    use super::{line_spans, minimap_line_height, MinimapCache, MinimapSpan};
+
+    #[test]
+    fn test_line_spans() {
+        let spans = line_spans("  let a =\t1;", 10, &[(12..15, red())], black());
+        assert_eq!(
+            spans.as_slice(),
+            &[
+                MinimapSpan {
+                    column: 2,
+                    len: 3,
+                    color: red()
+                },
+                MinimapSpan {
+                    column: 6,
+                    len: 1,
+                    color: black()
+                },
+                MinimapSpan {
+                    column: 8,
+                    len: 1,
+                    color: black()
+                },
+                MinimapSpan {
+                    column: 13,
+                    len: 2,
+                    color: black()
+                },
+            ]
+        );
+    }
+
+    #[test]
+    fn test_minimap_cache() {
+        let mut cache = MinimapCache::default();
+        let mut built = vec![];
+        cache.update(&Rope::from_str("a\nb\nc"), black(), |range| {
+            built.push(range.clone());
+            vec![]
+        });
+        assert_eq!(cache.len(), 3);
+        assert_eq!(built, vec![0..2, 2..4, 4..5]);
+
+        // Insert a new line after the `b`, only rebuild the edited lines.
+        cache.edit(1, 1, 2);
+        built.clear();
+        cache.update(&Rope::from_str("a\nb\nd\nc"), black(), |range| {
+            built.push(range.clone());
+            vec![]
+        });
+        assert_eq!(cache.len(), 4);
+        assert_eq!(built, vec![2..4, 4..6]);
+    }
+
+    #[test]
+    fn test_minimap_line_height() {
+        assert_eq!(minimap_line_height(10, gpui::px(100.)), gpui::px(2.));
+        assert_eq!(minimap_line_height(200, gpui::px(100.)), gpui::px(0.5));
+    }
+}
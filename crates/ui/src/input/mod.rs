@@ -1,26 +1,44 @@
 mod blink_cursor;
 mod change;
 mod clear_button;
+mod combobox;
+mod completion;
 mod cursor;
+mod drop_files;
 mod element;
+mod fold;
+mod go_to_line;
 mod hover_popover;
+mod jump_list;
 mod marker;
 mod mask_pattern;
+mod minimap;
 mod mode;
 mod number_input;
 mod otp_input;
+mod rich_text;
+mod rich_text_toolbar;
 mod rope_ext;
+mod spellcheck;
 mod state;
+mod tag_input;
 mod text_input;
 mod text_wrapper;
+mod word;
 
 pub(crate) use clear_button::*;
+pub use combobox::{Combobox, ComboboxEvent, ComboboxState};
+pub use completion::{CompletionItem, CompletionKind, CompletionTrigger};
 pub(super) use cursor::*;
 pub use marker::*;
 pub use mask_pattern::MaskPattern;
 pub use mode::TabSize;
 pub use number_input::{NumberInput, NumberInputEvent, StepAction};
 pub use otp_input::*;
+pub use rich_text::{parse_markdown, to_markdown, FormatAttribute, TextFormat, TextSpan};
+pub use rich_text_toolbar::RichTextToolbar;
 pub(crate) use rope_ext::*;
+pub use spellcheck::SpellChecker;
 pub use state::*;
+pub use tag_input::{TagInput, TagInputEvent, TagInputState};
 pub use text_input::*;
@@ -8,6 +8,7 @@ use tree_sitter::{InputEdit, Point};
 use crate::input::RopeExt as _;
 use crate::{highlighter::SyntaxHighlighter, input::marker::Marker};
 
+use super::fold::{indent_fold_ranges, syntax_fold_ranges};
 use super::text_wrapper::TextWrapper;
 
 #[derive(Debug, Copy, Clone)]
@@ -55,6 +56,10 @@ pub enum InputMode {
         rows: usize,
         /// Show line number
         line_number: bool,
+        /// Show fold markers in the line number gutter.
+        folding: bool,
+        /// Show the minimap at the right of the editor.
+        minimap: bool,
         language: SharedString,
         highlighter: Rc<RefCell<Option<SyntaxHighlighter>>>,
         markers: Rc<Vec<Marker>>,
@@ -151,6 +156,46 @@ impl InputMode {
         }
     }
 
+    /// Return false if the mode is not [`InputMode::CodeEditor`] or the line number is hidden.
+    #[inline]
+    pub(super) fn folding(&self) -> bool {
+        match self {
+            InputMode::CodeEditor {
+                folding,
+                line_number,
+                ..
+            } => *folding && *line_number,
+            _ => false,
+        }
+    }
+
+    /// Return false if the mode is not [`InputMode::CodeEditor`].
+    #[inline]
+    pub(super) fn minimap(&self) -> bool {
+        match self {
+            InputMode::CodeEditor { minimap, .. } => *minimap,
+            _ => false,
+        }
+    }
+
+    /// Returns the foldable line ranges by the syntax tree,
+    /// or by the indentation if the language is not parsed.
+    pub(super) fn fold_ranges(&self, text: &Rope) -> Vec<Range<usize>> {
+        match self {
+            InputMode::CodeEditor { highlighter, .. } => {
+                let highlighter = highlighter.borrow();
+                let tree = highlighter
+                    .as_ref()
+                    .and_then(|highlighter| highlighter.tree());
+                match tree {
+                    Some(tree) => syntax_fold_ranges(tree.root_node(), text),
+                    None => indent_fold_ranges(text),
+                }
+            }
+            _ => vec![],
+        }
+    }
+
     #[inline]
     pub(super) fn tab_size(&self) -> Option<&TabSize> {
         match self {
@@ -218,9 +263,18 @@ impl InputMode {
         }
     }
 
-    pub(super) fn clear_markers(&mut self) {
+    /// Move the markers for a text edit, see [`Marker::edit`].
+    pub(super) fn edit_markers(&mut self, range: &Range<usize>, new_len: usize, text: &Rope) {
         match self {
-            InputMode::CodeEditor { markers, .. } => *markers = Rc::new(vec![]),
+            InputMode::CodeEditor { markers, .. } => {
+                if markers.is_empty() {
+                    return;
+                }
+
+                let mut new_markers = markers.as_ref().clone();
+                new_markers.retain_mut(|marker| marker.edit(range, new_len, text));
+                *markers = Rc::new(new_markers);
+            }
             _ => {}
         }
     }
@@ -245,14 +299,16 @@ impl InputMode {
             return None;
         };
 
-        for marker in markers.iter() {
-            if let Some(range) = marker.range.as_ref() {
-                if range.contains(&offset) {
-                    return Some(marker);
-                }
-            }
-        }
-        None
+        // Use the highest severity for the overlapping markers.
+        markers
+            .iter()
+            .filter(|marker| {
+                marker
+                    .range
+                    .as_ref()
+                    .map_or(false, |range| range.contains(&offset))
+            })
+            .max_by_key(|marker| marker.severity.priority())
     }
 }
 
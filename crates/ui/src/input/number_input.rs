@@ -6,7 +6,9 @@ use gpui::{
 
 use crate::{
     button::{Button, ButtonVariants as _},
-    h_flex, ActiveTheme, Disableable, IconName, Sizable, Size, StyleSized, StyledExt as _,
+    h_flex,
+    number_format::{format_number, parse_number, NumberFormat},
+    ActiveTheme, Disableable, IconName, Sizable, Size, StyleSized, StyledExt as _,
 };
 
 use super::{InputState, TextInput};
@@ -31,6 +33,8 @@ pub struct NumberInput {
     suffix: Option<AnyElement>,
     appearance: bool,
     disabled: bool,
+    number_format: Option<NumberFormat>,
+    step: f64,
 }
 
 impl NumberInput {
@@ -44,6 +48,8 @@ impl NumberInput {
             suffix: None,
             appearance: true,
             disabled: false,
+            number_format: None,
+            step: 1.,
         }
     }
 
@@ -57,6 +63,21 @@ impl NumberInput {
         self
     }
 
+    /// Set the format of the number, default is None.
+    ///
+    /// If set, the buttons and the up/down keys step the text parsed by the format and set it
+    /// to the result formatted by the format, before emitting the [`NumberInputEvent::Step`].
+    pub fn number_format(mut self, format: NumberFormat) -> Self {
+        self.number_format = Some(format);
+        self
+    }
+
+    /// Set the step of the [`NumberInput::number_format`], default is 1.
+    pub fn step(mut self, step: f64) -> Self {
+        self.step = step;
+        self
+    }
+
     pub fn increment(state: &Entity<InputState>, window: &mut Window, cx: &mut App) {
         state.update(cx, |state, cx| {
             state.on_action_increment(&Increment, window, cx);
@@ -94,19 +115,65 @@ impl Disableable for NumberInput {
 }
 
 impl InputState {
+    /// Returns the number of the text parsed by the `format`, None if it's not a number.
+    ///
+    /// For the [`NumberInput`] to read the value on [`NumberInputEvent::Step`].
+    pub fn number_value(&self, format: &NumberFormat) -> Option<f64> {
+        parse_number(&self.value(), format)
+    }
+
+    /// Set the text to the `value` formatted by the `format`.
+    pub fn set_number_value(
+        &mut self,
+        value: f64,
+        format: &NumberFormat,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.set_value(format_number(value, format), window, cx);
+    }
+
+    /// Add the `delta` to the number of the text parsed by the `format`, and set the text to
+    /// the result formatted by the `format`. The text not a number is stepped from 0.
+    ///
+    /// The [`NumberInput`] with the [`NumberInput::number_format`] steps the value by this.
+    pub fn step_number_value(
+        &mut self,
+        delta: f64,
+        format: &NumberFormat,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let value = self.number_value(format).unwrap_or_default() + delta;
+        self.set_number_value(value, format, window, cx);
+    }
+
     fn on_action_increment(&mut self, _: &Increment, window: &mut Window, cx: &mut Context<Self>) {
-        self.on_number_input_step(StepAction::Increment, window, cx);
+        self.on_number_input_step(StepAction::Increment, None, window, cx);
     }
 
     fn on_action_decrement(&mut self, _: &Decrement, window: &mut Window, cx: &mut Context<Self>) {
-        self.on_number_input_step(StepAction::Decrement, window, cx);
+        self.on_number_input_step(StepAction::Decrement, None, window, cx);
     }
 
-    fn on_number_input_step(&mut self, action: StepAction, _: &mut Window, cx: &mut Context<Self>) {
+    fn on_number_input_step(
+        &mut self,
+        action: StepAction,
+        format: Option<(&NumberFormat, f64)>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
         if self.disabled {
             return;
         }
 
+        if let Some((format, step)) = format {
+            let delta = match action {
+                StepAction::Decrement => -step,
+                StepAction::Increment => step,
+            };
+            self.step_number_value(delta, format, window, cx);
+        }
         cx.emit(NumberInputEvent::Step(action));
     }
 }
@@ -133,15 +200,38 @@ impl Sizable for NumberInput {
         self
     }
 }
+
+impl NumberInput {
+    fn step_listener(&self, action: StepAction) -> impl Fn(&mut Window, &mut App) + 'static {
+        let state = self.state.clone();
+        let format = self.number_format.clone();
+        let step = self.step;
+        move |window, cx| {
+            state.update(cx, |state, cx| {
+                let format = format.as_ref().map(|format| (format, step));
+                state.on_number_input_step(action, format, window, cx);
+            })
+        }
+    }
+}
+
 impl RenderOnce for NumberInput {
     fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
         let focused = self.state.focus_handle(cx).is_focused(window);
+        let on_increment = self.step_listener(StepAction::Increment);
+        let on_decrement = self.step_listener(StepAction::Decrement);
 
         h_flex()
             .id(("number-input", self.state.entity_id()))
             .key_context(KEY_CONTENT)
-            .on_action(window.listener_for(&self.state, InputState::on_action_increment))
-            .on_action(window.listener_for(&self.state, InputState::on_action_decrement))
+            .on_action({
+                let on_increment = self.step_listener(StepAction::Increment);
+                move |_: &Increment, window, cx| on_increment(window, cx)
+            })
+            .on_action({
+                let on_decrement = self.step_listener(StepAction::Decrement);
+                move |_: &Decrement, window, cx| on_decrement(window, cx)
+            })
             .flex_1()
             .input_size(self.size)
             .px(self.size.input_px() / 2.)
@@ -160,12 +250,7 @@ impl RenderOnce for NumberInput {
                     .icon(IconName::Minus)
                     .compact()
                     .disabled(self.disabled)
-                    .on_click({
-                        let state = self.state.clone();
-                        move |_, window, cx| {
-                            Self::decrement(&state, window, cx);
-                        }
-                    }),
+                    .on_click(move |_, window, cx| on_decrement(window, cx)),
             )
             .child(
                 TextInput::new(&self.state)
@@ -183,12 +268,7 @@ impl RenderOnce for NumberInput {
                     .icon(IconName::Plus)
                     .compact()
                     .disabled(self.disabled)
-                    .on_click({
-                        let state = self.state.clone();
-                        move |_, window, cx| {
-                            Self::increment(&state, window, cx);
-                        }
-                    }),
+                    .on_click(move |_, window, cx| on_increment(window, cx)),
             )
     }
 }
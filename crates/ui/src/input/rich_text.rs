@@ -0,0 +1,659 @@
+use std::ops::Range;
+
+use gpui::{px, FontStyle, FontWeight, HighlightStyle, Hsla, SharedString, UnderlineStyle};
+
+/// The inline format attributes to toggle on the selection of the rich text input.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum FormatAttribute {
+    Bold,
+    Italic,
+    Underline,
+}
+
+/// The inline format of a span in the rich text input.
+#[derive(Debug, Clone, Default, PartialEq, Eq)]
+pub struct TextFormat {
+    pub bold: bool,
+    pub italic: bool,
+    pub underline: bool,
+    /// The URL of the link, `None` if the span is not a link.
+    pub link: Option<SharedString>,
+}
+
+impl TextFormat {
+    /// Returns true if the format has the attribute.
+    pub fn has(&self, attr: FormatAttribute) -> bool {
+        match attr {
+            FormatAttribute::Bold => self.bold,
+            FormatAttribute::Italic => self.italic,
+            FormatAttribute::Underline => self.underline,
+        }
+    }
+
+    fn set(&mut self, attr: FormatAttribute, value: bool) {
+        match attr {
+            FormatAttribute::Bold => self.bold = value,
+            FormatAttribute::Italic => self.italic = value,
+            FormatAttribute::Underline => self.underline = value,
+        }
+    }
+
+    /// Returns the style to render the text, the links use the `link_color` and underline.
+    pub(super) fn highlight_style(&self, link_color: Hsla) -> HighlightStyle {
+        let mut style = HighlightStyle::default();
+        if self.bold {
+            style.font_weight = Some(FontWeight::BOLD);
+        }
+        if self.italic {
+            style.font_style = Some(FontStyle::Italic);
+        }
+        if self.link.is_some() {
+            style.color = Some(link_color);
+        }
+        if self.underline || self.link.is_some() {
+            style.underline = Some(UnderlineStyle {
+                thickness: px(1.),
+                color: None,
+                wavy: false,
+            });
+        }
+        style
+    }
+}
+
+/// A span of the rich text with the same format, the value of the rich text input.
+#[derive(Debug, Clone, Default, PartialEq, Eq)]
+pub struct TextSpan {
+    pub text: SharedString,
+    pub format: TextFormat,
+}
+
+impl TextSpan {
+    /// Create a span of the text without format.
+    pub fn new(text: impl Into<SharedString>) -> Self {
+        Self {
+            text: text.into(),
+            format: TextFormat::default(),
+        }
+    }
+
+    /// Set the span to bold.
+    pub fn bold(mut self) -> Self {
+        self.format.bold = true;
+        self
+    }
+
+    /// Set the span to italic.
+    pub fn italic(mut self) -> Self {
+        self.format.italic = true;
+        self
+    }
+
+    /// Set the span to underline.
+    pub fn underline(mut self) -> Self {
+        self.format.underline = true;
+        self
+    }
+
+    /// Set the span to a link of the `url`.
+    pub fn link(mut self, url: impl Into<SharedString>) -> Self {
+        self.format.link = Some(url.into());
+        self
+    }
+}
+
+/// The format runs of the rich text, the runs are the byte lengths with the
+/// format from the start, they always cover the entire text.
+#[derive(Debug, Clone, Default, PartialEq)]
+pub(super) struct FormatRuns {
+    runs: Vec<(usize, TextFormat)>,
+    /// The format to type at the offset, to toggle the format without selection.
+    typing: Option<(usize, TextFormat)>,
+}
+
+impl FormatRuns {
+    /// Create the runs without format for the text of `len` bytes.
+    pub(super) fn new(len: usize) -> Self {
+        let mut this = Self::default();
+        if len > 0 {
+            this.runs.push((len, TextFormat::default()));
+        }
+        this
+    }
+
+    /// Build the text and runs from the spans.
+    pub(super) fn from_spans(spans: &[TextSpan]) -> (String, Self) {
+        let mut text = String::new();
+        let mut this = Self::default();
+        for span in spans {
+            text.push_str(&span.text);
+            this.runs.push((span.text.len(), span.format.clone()));
+        }
+        this.normalize();
+        (text, this)
+    }
+
+    /// Returns the spans of the `text`, the text must be the one the runs are for.
+    pub(super) fn spans(&self, text: &str) -> Vec<TextSpan> {
+        self.ranges()
+            .filter_map(|(range, format)| {
+                Some(TextSpan {
+                    text: text.get(range)?.to_string().into(),
+                    format: format.clone(),
+                })
+            })
+            .collect()
+    }
+
+    /// Returns the styles of the runs to render the text.
+    pub(super) fn highlight_styles(&self, link_color: Hsla) -> Vec<(Range<usize>, HighlightStyle)> {
+        self.ranges()
+            .map(|(range, format)| (range, format.highlight_style(link_color)))
+            .collect()
+    }
+
+    fn ranges(&self) -> impl Iterator<Item = (Range<usize>, &TextFormat)> + '_ {
+        let mut start = 0;
+        self.runs.iter().map(move |(len, format)| {
+            let range = start..start + len;
+            start = range.end;
+            (range, format)
+        })
+    }
+
+    /// Returns the format of the char at the byte `offset`.
+    fn char_format(&self, offset: usize) -> Option<&TextFormat> {
+        self.ranges()
+            .find(|(range, _)| range.contains(&offset))
+            .map(|(_, format)| format)
+    }
+
+    /// Split the run at the `offset`, returns the index of the run starting at it.
+    fn split_at(&mut self, offset: usize) -> usize {
+        let mut start = 0;
+        for ix in 0..self.runs.len() {
+            let len = self.runs[ix].0;
+            if offset == start {
+                return ix;
+            }
+            if offset < start + len {
+                let format = self.runs[ix].1.clone();
+                self.runs[ix].0 = offset - start;
+                self.runs.insert(ix + 1, (start + len - offset, format));
+                return ix + 1;
+            }
+            start += len;
+        }
+        self.runs.len()
+    }
+
+    /// Remove the empty runs and merge the adjacent runs with the same format.
+    fn normalize(&mut self) {
+        let mut runs: Vec<(usize, TextFormat)> = Vec::with_capacity(self.runs.len());
+        for (len, format) in self.runs.drain(..) {
+            if len == 0 {
+                continue;
+            }
+            match runs.last_mut() {
+                Some(last) if last.1 == format => last.0 += len,
+                _ => runs.push((len, format)),
+            }
+        }
+        self.runs = runs;
+    }
+
+    /// Update the format of the runs in the `range`.
+    fn update(&mut self, range: &Range<usize>, f: impl Fn(&mut TextFormat)) {
+        let start_ix = self.split_at(range.start);
+        let end_ix = self.split_at(range.end);
+        for (_, format) in &mut self.runs[start_ix..end_ix] {
+            f(format);
+        }
+        self.normalize();
+    }
+
+    /// Returns the format of the new text to replace the `range`.
+    ///
+    /// The replaced text keeps the format of its first char, the inserted text continues
+    /// the char before, but a link does not extend at its boundaries.
+    fn insert_format(&self, range: &Range<usize>) -> TextFormat {
+        if let Some((offset, format)) = &self.typing {
+            if *offset == range.start && range.is_empty() {
+                return format.clone();
+            }
+        }
+        if !range.is_empty() {
+            if let Some(format) = self.char_format(range.start) {
+                return format.clone();
+            }
+        }
+
+        let prev = range
+            .start
+            .checked_sub(1)
+            .and_then(|offset| self.char_format(offset));
+        let next = self.char_format(range.end);
+        let mut format = prev.or(next).cloned().unwrap_or_default();
+        if format.link.is_some()
+            && (prev.is_none() || next.map(|next| &next.link) != Some(&format.link))
+        {
+            format.link = None;
+        }
+        format
+    }
+
+    /// Move the runs for a text edit, that replaced the `range` by `new_len` bytes.
+    pub(super) fn edit(&mut self, range: &Range<usize>, new_len: usize) {
+        let format = self.insert_format(range);
+        self.typing = None;
+
+        let start_ix = self.split_at(range.start);
+        let end_ix = self.split_at(range.end);
+        self.runs.splice(start_ix..end_ix, [(new_len, format)]);
+        self.normalize();
+    }
+
+    /// Returns the format of the `range`, an attribute is set only if all the text has it.
+    ///
+    /// For an empty range, it is the format to type at the offset.
+    pub(super) fn format_for_range(&self, range: &Range<usize>) -> TextFormat {
+        if range.is_empty() {
+            return self.insert_format(range);
+        }
+
+        let mut formats = self
+            .ranges()
+            .filter(|(run, _)| run.start < range.end && range.start < run.end)
+            .map(|(_, format)| format);
+        let Some(first) = formats.next() else {
+            return TextFormat::default();
+        };
+
+        let mut result = first.clone();
+        for format in formats {
+            result.bold &= format.bold;
+            result.italic &= format.italic;
+            result.underline &= format.underline;
+            if result.link != format.link {
+                result.link = None;
+            }
+        }
+        result
+    }
+
+    /// Toggle the attribute of the `range`, it is removed if all the text has it, otherwise it is set.
+    ///
+    /// For an empty range, it toggles the format to type at the offset.
+    pub(super) fn toggle(&mut self, range: &Range<usize>, attr: FormatAttribute) {
+        let mut format = self.format_for_range(range);
+        let value = !format.has(attr);
+        if range.is_empty() {
+            format.set(attr, value);
+            self.typing = Some((range.start, format));
+            return;
+        }
+
+        self.update(range, |format| format.set(attr, value));
+    }
+
+    /// Set the link of the `range`, `None` to remove the link.
+    ///
+    /// For an empty range, it updates the entire link at the offset if any.
+    pub(super) fn set_link(&mut self, range: &Range<usize>, link: Option<SharedString>) {
+        let range = if range.is_empty() {
+            match self.link_range(range.start) {
+                Some(range) => range,
+                None => return,
+            }
+        } else {
+            range.clone()
+        };
+
+        self.update(&range, |format| format.link = link.clone());
+    }
+
+    /// Returns the range of the link contains the char at the `offset`, or before it.
+    fn link_range(&self, offset: usize) -> Option<Range<usize>> {
+        let ranges = self.ranges().collect::<Vec<_>>();
+        let ix = ranges
+            .iter()
+            .position(|(range, format)| format.link.is_some() && range.contains(&offset))
+            .or_else(|| {
+                ranges
+                    .iter()
+                    .position(|(range, format)| format.link.is_some() && range.end == offset)
+            })?;
+
+        let link = &ranges[ix].1.link;
+        let mut start = ix;
+        while start > 0 && &ranges[start - 1].1.link == link {
+            start -= 1;
+        }
+        let mut end = ix;
+        while end + 1 < ranges.len() && &ranges[end + 1].1.link == link {
+            end += 1;
+        }
+        Some(ranges[start].0.start..ranges[end].0.end)
+    }
+}
+
+const ESCAPE_CHARS: [char; 5] = ['\\', '*', '[', ']', '<'];
+
+/// Serialize the spans to the Markdown subset: `**bold**`, `*italic*`, `<u>underline</u>` and `[link](url)`.
+///
+/// The Markdown chars in the text are escaped by `\`, see [`parse_markdown`] to read it back.
+pub fn to_markdown(spans: &[TextSpan]) -> String {
+    let mut out = String::new();
+    // The open attributes, the last is the innermost.
+    let mut stack: Vec<FormatAttribute> = vec![];
+    let mut link: Option<&SharedString> = None;
+
+    fn close(out: &mut String, attr: FormatAttribute) {
+        out.push_str(match attr {
+            FormatAttribute::Bold => "**",
+            FormatAttribute::Italic => "*",
+            FormatAttribute::Underline => "</u>",
+        });
+    }
+
+    for span in spans.iter().filter(|span| !span.text.is_empty()) {
+        let format = &span.format;
+        if link != format.link.as_ref() {
+            // Close all attributes to keep the link boundaries.
+            while let Some(attr) = stack.pop() {
+                close(&mut out, attr);
+            }
+            if let Some(url) = link {
+                out.push_str(&format!("]({})", encode_url(url)));
+            }
+            if format.link.is_some() {
+                out.push('[');
+            }
+            link = format.link.as_ref();
+        }
+
+        // Close from the first attribute that is not in the format, and the inner ones.
+        if let Some(ix) = stack.iter().position(|attr| !format.has(*attr)) {
+            while stack.len() > ix {
+                if let Some(attr) = stack.pop() {
+                    close(&mut out, attr);
+                }
+            }
+        }
+        for attr in [
+            FormatAttribute::Bold,
+            FormatAttribute::Italic,
+            FormatAttribute::Underline,
+        ] {
+            if format.has(attr) && !stack.contains(&attr) {
+                out.push_str(match attr {
+                    FormatAttribute::Bold => "**",
+                    FormatAttribute::Italic => "*",
+                    FormatAttribute::Underline => "<u>",
+                });
+                stack.push(attr);
+            }
+        }
+
+        for c in span.text.chars() {
+            if ESCAPE_CHARS.contains(&c) {
+                out.push('\\');
+            }
+            out.push(c);
+        }
+    }
+
+    while let Some(attr) = stack.pop() {
+        close(&mut out, attr);
+    }
+    if let Some(url) = link {
+        out.push_str(&format!("]({})", encode_url(url)));
+    }
+    out
+}
+
+fn encode_url(url: &str) -> String {
+    url.replace(' ', "%20").replace(')', "%29")
+}
+
+/// Parse the Markdown subset of [`to_markdown`] to the spans.
+///
+/// Other Markdown syntax is kept as plain text, an unclosed format continues to the end.
+pub fn parse_markdown(markdown: &str) -> Vec<TextSpan> {
+    let chars: Vec<char> = markdown.chars().collect();
+    let mut text = String::new();
+    let mut runs = FormatRuns::default();
+    let mut stack: Vec<FormatAttribute> = vec![];
+    // The text offset of the link start, and the char index of its `](`.
+    let mut link: Option<(usize, usize)> = None;
+
+    let push = |text: &mut String, runs: &mut FormatRuns, c: char, stack: &[FormatAttribute]| {
+        let mut format = TextFormat::default();
+        for attr in stack {
+            format.set(*attr, true);
+        }
+        text.push(c);
+        runs.runs.push((c.len_utf8(), format));
+    };
+
+    let mut ix = 0;
+    while ix < chars.len() {
+        let c = chars[ix];
+        match c {
+            '\\' if ix + 1 < chars.len() => {
+                push(&mut text, &mut runs, chars[ix + 1], &stack);
+                ix += 2;
+            }
+            '*' => {
+                let mut count = chars[ix..].iter().take_while(|c| **c == '*').count();
+                ix += count;
+                // Close the open ones first, the innermost first.
+                loop {
+                    match stack.last() {
+                        Some(FormatAttribute::Bold) if count >= 2 => count -= 2,
+                        Some(FormatAttribute::Italic) if count >= 1 => count -= 1,
+                        _ => break,
+                    }
+                    stack.pop();
+                }
+                while count > 0 {
+                    if count >= 2 && !stack.contains(&FormatAttribute::Bold) {
+                        stack.push(FormatAttribute::Bold);
+                        count -= 2;
+                    } else if !stack.contains(&FormatAttribute::Italic) {
+                        stack.push(FormatAttribute::Italic);
+                        count -= 1;
+                    } else {
+                        for _ in 0..count {
+                            push(&mut text, &mut runs, '*', &stack);
+                        }
+                        break;
+                    }
+                }
+            }
+            '<' if starts_with(&chars[ix..], "<u>")
+                && !stack.contains(&FormatAttribute::Underline) =>
+            {
+                stack.push(FormatAttribute::Underline);
+                ix += 3;
+            }
+            '<' if starts_with(&chars[ix..], "</u>")
+                && stack.contains(&FormatAttribute::Underline) =>
+            {
+                stack.retain(|attr| *attr != FormatAttribute::Underline);
+                ix += 4;
+            }
+            '[' if link.is_none() => match find_link_end(&chars, ix + 1) {
+                Some(end_ix) => {
+                    link = Some((text.len(), end_ix));
+                    ix += 1;
+                }
+                None => {
+                    push(&mut text, &mut runs, c, &stack);
+                    ix += 1;
+                }
+            },
+            ']' if link.map_or(false, |(_, end_ix)| end_ix == ix) => {
+                let (start, _) = link.take().unwrap_or_default();
+                let url_start = ix + 2;
+                let url_end = url_start
+                    + chars[url_start..]
+                        .iter()
+                        .position(|c| *c == ')')
+                        .unwrap_or(chars.len() - url_start);
+                let url: String = chars[url_start..url_end].iter().collect();
+                let url = url.replace("%20", " ").replace("%29", ")");
+                runs.update(&(start..text.len()), |format| {
+                    format.link = Some(url.clone().into())
+                });
+                ix = url_end + 1;
+            }
+            _ => {
+                push(&mut text, &mut runs, c, &stack);
+                ix += 1;
+            }
+        }
+    }
+
+    runs.normalize();
+    runs.spans(&text)
+}
+
+fn starts_with(chars: &[char], pattern: &str) -> bool {
+    chars.len() >= pattern.len() && pattern.chars().zip(chars).all(|(a, b)| a == *b)
+}
+
+/// Returns the char index of the `]` of the link text starts at `ix`, if it is followed by `(url)`.
+fn find_link_end(chars: &[char], mut ix: usize) -> Option<usize> {
+    while ix < chars.len() {
+        match chars[ix] {
+            '\\' => ix += 2,
+            '[' => return None,
+            ']' => {
+                let has_url = chars.get(ix + 1) == Some(&'(') && chars[ix + 1..].contains(&')');
+                return has_url.then_some(ix);
+            }
+            _ => ix += 1,
+        }
+    }
+    None
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{parse_markdown, to_markdown, FormatAttribute, FormatRuns, TextFormat, TextSpan};
+
+    #[test]
+    fn test_edit() {
+        let mut runs = FormatRuns::new(11);
+        runs.toggle(&(0..5), FormatAttribute::Bold);
+        assert_eq!(
+            runs.spans("Hello world"),
+            vec![TextSpan::new("Hello").bold(), TextSpan::new(" world")]
+        );
+
+        // Continue the format of the char before.
+        runs.edit(&(5..5), 1);
+        assert_eq!(
+            runs.spans("Hello! world"),
+            vec![TextSpan::new("Hello!").bold(), TextSpan::new(" world")]
+        );
+        // Replace keeps the format of the first replaced char.
+        runs.edit(&(4..8), 1);
+        assert_eq!(
+            runs.spans("Helloorld"),
+            vec![TextSpan::new("Hello").bold(), TextSpan::new("orld")]
+        );
+        runs.edit(&(0..9), 0);
+        assert_eq!(runs.spans(""), vec![]);
+    }
+
+    #[test]
+    fn test_toggle() {
+        let mut runs = FormatRuns::new(11);
+        runs.toggle(&(2..8), FormatAttribute::Italic);
+        assert!(runs.format_for_range(&(2..8)).italic);
+        assert!(!runs.format_for_range(&(0..8)).italic);
+
+        // Partially set, toggle to set all.
+        runs.toggle(&(0..8), FormatAttribute::Italic);
+        assert!(runs.format_for_range(&(0..8)).italic);
+        runs.toggle(&(0..11), FormatAttribute::Italic);
+        assert!(runs.format_for_range(&(0..11)).italic);
+        runs.toggle(&(0..11), FormatAttribute::Italic);
+        assert_eq!(runs, FormatRuns::new(11));
+
+        // Toggle without selection, the typed text uses it.
+        runs.toggle(&(5..5), FormatAttribute::Underline);
+        assert!(runs.format_for_range(&(5..5)).underline);
+        runs.edit(&(5..5), 3);
+        assert_eq!(
+            runs.spans("Hello!!! world"),
+            vec![
+                TextSpan::new("Hello"),
+                TextSpan::new("!!!").underline(),
+                TextSpan::new(" world")
+            ]
+        );
+    }
+
+    #[test]
+    fn test_link() {
+        let mut runs = FormatRuns::new(11);
+        runs.set_link(&(6..11), Some("https://example.com".into()));
+        runs.toggle(&(6..8), FormatAttribute::Bold);
+        assert_eq!(
+            runs.format_for_range(&(6..11)).link.as_deref(),
+            Some("https://example.com")
+        );
+
+        // Not to extend the link at the end.
+        runs.edit(&(11..11), 1);
+        assert_eq!(runs.format_for_range(&(11..12)), TextFormat::default());
+        // Inside the link.
+        runs.edit(&(9..9), 1);
+        assert!(runs.format_for_range(&(9..10)).link.is_some());
+
+        // Remove the entire link by the cursor in it.
+        runs.set_link(&(7..7), None);
+        assert!(runs
+            .spans("Hello woXrld!")
+            .iter()
+            .all(|span| span.format.link.is_none()));
+    }
+
+    #[test]
+    fn test_markdown() {
+        let spans = vec![
+            TextSpan::new("Hello "),
+            TextSpan::new("bold").bold(),
+            TextSpan::new(" and ").bold().italic(),
+            TextSpan::new("italic").italic(),
+            TextSpan::new(", "),
+            TextSpan::new("under").underline(),
+            TextSpan::new(" a ").link("https://example.com/a b"),
+            TextSpan::new("*link*")
+                .bold()
+                .link("https://example.com/a b"),
+            TextSpan::new(" [x] \\ <u>"),
+        ];
+        let markdown = to_markdown(&spans);
+        assert_eq!(
+            markdown,
+            "Hello **bold* and ****italic*, <u>under</u>[ a **\\*link\\***](https://example.com/a%20b) \\[x\\] \\\\ \\<u>"
+        );
+        assert_eq!(parse_markdown(&markdown), spans);
+
+        assert_eq!(
+            parse_markdown("*a***b** [c](u) [d] 2 * 3"),
+            vec![
+                TextSpan::new("a").italic(),
+                TextSpan::new("b").bold(),
+                TextSpan::new(" "),
+                TextSpan::new("c").link("u"),
+                TextSpan::new(" [d] 2 "),
+                TextSpan::new(" 3").italic(),
+            ]
+        );
+    }
+}
@@ -0,0 +1,141 @@
+use std::rc::Rc;
+
+use gpui::{
+    prelude::FluentBuilder as _, Action, App, Entity, IntoElement, ParentElement as _, RenderOnce,
+    SharedString, StyleRefinement, Styled, Window,
+};
+use rust_i18n::t;
+
+use super::{FormatAttribute, InputState, ToggleBold, ToggleItalic, ToggleUnderline, CONTEXT};
+use crate::{
+    button::{Button, ButtonVariants as _},
+    h_flex, Disableable as _, IconName, Selectable as _, Sizable, Size, StyledExt as _,
+};
+
+/// A toolbar to toggle the inline formats of a rich text input, see [`InputState::rich_text`].
+///
+/// The buttons are selected by the format of the selection.
+#[derive(IntoElement)]
+pub struct RichTextToolbar {
+    state: Entity<InputState>,
+    style: StyleRefinement,
+    size: Size,
+    on_link: Option<Rc<dyn Fn(&Entity<InputState>, &mut Window, &mut App)>>,
+}
+
+impl RichTextToolbar {
+    /// Create a toolbar for the rich text input `state`.
+    pub fn new(state: &Entity<InputState>) -> Self {
+        Self {
+            state: state.clone(),
+            style: StyleRefinement::default(),
+            size: Size::Small,
+            on_link: None,
+        }
+    }
+
+    /// Show the link button, the `handler` is called to ask the URL of the link, then call
+    /// [`InputState::set_link`] to apply it to the selection.
+    ///
+    /// The link button removes the link if the selection is a link.
+    pub fn on_link(
+        mut self,
+        handler: impl Fn(&Entity<InputState>, &mut Window, &mut App) + 'static,
+    ) -> Self {
+        self.on_link = Some(Rc::new(handler));
+        self
+    }
+}
+
+impl Styled for RichTextToolbar {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl Sizable for RichTextToolbar {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl RenderOnce for RichTextToolbar {
+    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = self.state.read(cx);
+        let format = state.selected_format();
+        let disabled = state.disabled || !state.is_rich_text();
+
+        let format_button = |attr: FormatAttribute| {
+            let (id, icon, tooltip, action): (_, _, SharedString, &dyn Action) = match attr {
+                FormatAttribute::Bold => (
+                    "bold",
+                    IconName::Bold,
+                    t!("RichText.bold").into(),
+                    &ToggleBold,
+                ),
+                FormatAttribute::Italic => (
+                    "italic",
+                    IconName::Italic,
+                    t!("RichText.italic").into(),
+                    &ToggleItalic,
+                ),
+                FormatAttribute::Underline => (
+                    "underline",
+                    IconName::Underline,
+                    t!("RichText.underline").into(),
+                    &ToggleUnderline,
+                ),
+            };
+
+            let state = self.state.clone();
+            Button::new(id)
+                .ghost()
+                .icon(icon)
+                .with_size(self.size)
+                .disabled(disabled)
+                .selected(format.has(attr))
+                .tooltip_with_action(tooltip, action, Some(CONTEXT))
+                .on_click(move |_, window, cx| {
+                    state.update(cx, |state, cx| {
+                        state.toggle_format(attr, window, cx);
+                        window.focus(&state.focus_handle);
+                    });
+                })
+        };
+
+        h_flex()
+            .gap_1()
+            .refine_style(&self.style)
+            .child(format_button(FormatAttribute::Bold))
+            .child(format_button(FormatAttribute::Italic))
+            .child(format_button(FormatAttribute::Underline))
+            .when_some(self.on_link, |this, on_link| {
+                let state = self.state.clone();
+                let has_link = format.link.is_some();
+                this.child(
+                    Button::new("link")
+                        .ghost()
+                        .icon(IconName::Link)
+                        .with_size(self.size)
+                        .disabled(disabled)
+                        .selected(has_link)
+                        .tooltip(SharedString::from(if has_link {
+                            t!("RichText.remove_link")
+                        } else {
+                            t!("RichText.link")
+                        }))
+                        .on_click(move |_, window, cx| {
+                            if has_link {
+                                state.update(cx, |state, cx| {
+                                    state.set_link(None, window, cx);
+                                    window.focus(&state.focus_handle);
+                                });
+                            } else {
+                                on_link(&state, window, cx);
+                            }
+                        }),
+                )
+            })
+    }
+}
@@ -0,0 +1,271 @@
+use std::{collections::HashSet, ops::Range, rc::Rc};
+
+use gpui::{App, Entity, SharedString, Window};
+use ropey::Rope;
+use rust_i18n::t;
+use unicode_segmentation::UnicodeSegmentation as _;
+
+use super::{word::subword_ranges, InputState};
+use crate::popup_menu::PopupMenu;
+
+/// The max number of the suggestions in the context menu.
+const MAX_SUGGESTIONS: usize = 8;
+
+/// The chars next to a word that make it a code token in the code mode,
+/// e.g.: `self.value`, `std::io`, `/usr/bin`, `#include`, `$HOME`.
+const CODE_PREFIX_CHARS: &[char] = &['.', ':', '/', '\\', '#', '$', '@', '<', '&'];
+const CODE_SUFFIX_CHARS: &[char] = &['(', ':', '/', '\\', '<', '='];
+
+/// A spell checker for the [`InputState::spell_checker`](super::InputState::spell_checker),
+/// e.g.: by a dictionary.
+pub trait SpellChecker {
+    /// Returns the suggestions for the misspelled `word`, or `None` if the word is correct.
+    fn check(&self, word: &str) -> Option<Vec<SharedString>>;
+
+    /// Add the `word` to the dictionary, called by the "Add to dictionary" menu.
+    ///
+    /// The word is accepted by the input anyway, implement it to save the word.
+    fn add_word(&self, _word: &str) {}
+}
+
+/// The misspelled words of each line, only the changed lines are checked again.
+#[derive(Default)]
+pub(super) struct SpellCheck {
+    checker: Option<Rc<dyn SpellChecker>>,
+    /// Skip the code-like tokens, e.g.: `snake_case`, `camelCase`, `std::io` and the `code spans`.
+    code_mode: bool,
+    /// The words accepted by the "Ignore" and "Add to dictionary" menus.
+    ignored: HashSet<String>,
+    /// The byte ranges of the misspelled words in each line, the `None` lines need to check.
+    lines: Vec<Option<Vec<Range<usize>>>>,
+    /// The misspelled word range at the right click, to show the context menu.
+    pub(super) menu_word: Option<Range<usize>>,
+}
+
+impl SpellCheck {
+    pub(super) fn set_checker(&mut self, checker: Option<Rc<dyn SpellChecker>>) {
+        self.checker = checker;
+        self.invalidate();
+    }
+
+    pub(super) fn set_code_mode(&mut self, code_mode: bool) {
+        if self.code_mode != code_mode {
+            self.code_mode = code_mode;
+            self.invalidate();
+        }
+    }
+
+    pub(super) fn is_enabled(&self) -> bool {
+        self.checker.is_some()
+    }
+
+    /// Check all the lines again, e.g.: the checker or the ignored words are changed.
+    pub(super) fn invalidate(&mut self) {
+        self.lines.clear();
+        self.menu_word = None;
+    }
+
+    /// Update the lines for a text edit, that replaced the `start_line..=old_end_line`
+    /// lines by the `start_line..=new_end_line` lines.
+    pub(super) fn edit(&mut self, start_line: usize, old_end_line: usize, new_end_line: usize) {
+        self.menu_word = None;
+        if start_line >= self.lines.len() {
+            return;
+        }
+
+        let old_end = (old_end_line + 1).min(self.lines.len());
+        self.lines.splice(
+            start_line..old_end,
+            std::iter::repeat(None).take(new_end_line + 1 - start_line),
+        );
+    }
+
+    /// Check the changed lines in the (zero based) `lines`, and returns the byte ranges
+    /// of the misspelled words in them.
+    pub(super) fn misspelled_ranges(
+        &mut self,
+        text: &Rope,
+        lines: Range<usize>,
+    ) -> Vec<Range<usize>> {
+        let Some(checker) = self.checker.as_ref() else {
+            return vec![];
+        };
+
+        self.lines.resize(text.len_lines(), None);
+        let lines = lines.start.min(self.lines.len())..lines.end.min(self.lines.len());
+
+        let mut ranges = vec![];
+        for ix in lines {
+            let line = text.line(ix).to_string();
+            let words = self.lines[ix].get_or_insert_with(|| {
+                check_ranges(&line, self.code_mode)
+                    .into_iter()
+                    .filter(|range| {
+                        let word = &line[range.clone()];
+                        !self.ignored.contains(word) && checker.check(word).is_some()
+                    })
+                    .collect()
+            });
+
+            let start = text.line_to_byte(ix);
+            ranges.extend(
+                words
+                    .iter()
+                    .map(|range| start + range.start..start + range.end),
+            );
+        }
+        ranges
+    }
+
+    /// Returns the range of the checked misspelled word at the `offset`.
+    pub(super) fn word_at(&self, text: &Rope, offset: usize) -> Option<Range<usize>> {
+        let line_ix = text.byte_to_line(offset.min(text.len_bytes()));
+        let start = text.line_to_byte(line_ix);
+        self.lines
+            .get(line_ix)?
+            .as_ref()?
+            .iter()
+            .map(|range| start + range.start..start + range.end)
+            .find(|range| range.start <= offset && offset <= range.end)
+    }
+
+    /// Returns the suggestions for the misspelled `word`.
+    pub(super) fn suggestions(&self, word: &str) -> Vec<SharedString> {
+        self.checker
+            .as_ref()
+            .and_then(|checker| checker.check(word))
+            .unwrap_or_default()
+    }
+
+    /// Accept the `word` in this input, if `add_to_dictionary` also add it to the checker.
+    pub(super) fn accept(&mut self, word: &str, add_to_dictionary: bool) {
+        if add_to_dictionary {
+            if let Some(checker) = self.checker.as_ref() {
+                checker.add_word(word);
+            }
+        }
+
+        self.ignored.insert(word.to_string());
+        // Only the lines with the word need to check again.
+        for line in self.lines.iter_mut() {
+            if line.as_ref().is_some_and(|ranges| !ranges.is_empty()) {
+                *line = None;
+            }
+        }
+        self.menu_word = None;
+    }
+}
+
+/// Build the context menu of the right clicked misspelled word, with the suggestions,
+/// "Add to dictionary" and "Ignore", the menu is empty if no misspelled word is clicked.
+pub(super) fn spellcheck_menu(state: &Entity<InputState>, menu: PopupMenu, cx: &App) -> PopupMenu {
+    let Some((range, word, suggestions)) = state.read(cx).spellcheck_menu_word() else {
+        return menu;
+    };
+
+    let word: SharedString = word.into();
+    let mut menu = menu;
+    if suggestions.is_empty() {
+        menu = menu.label(t!("Spellcheck.no_suggestions"));
+    }
+    for suggestion in suggestions.into_iter().take(MAX_SUGGESTIONS) {
+        let (state, range, word) = (state.clone(), range.clone(), word.clone());
+        menu = menu.menu_with_handler(suggestion.clone(), None, move |window, cx| {
+            state.update(cx, |state, cx| {
+                state.replace_misspelled_word(range.clone(), &word, &suggestion, window, cx);
+            });
+        });
+    }
+
+    let accept = |add_to_dictionary: bool| {
+        let (state, word) = (state.clone(), word.clone());
+        move |_: &mut Window, cx: &mut App| {
+            state.update(cx, |state, cx| {
+                state.accept_misspelled_word(&word, add_to_dictionary, cx);
+            });
+        }
+    };
+    menu.separator()
+        .menu_with_handler(t!("Spellcheck.add_to_dictionary"), None, accept(true))
+        .menu_with_handler(t!("Spellcheck.ignore"), None, accept(false))
+}
+
+/// Returns the byte ranges of the words to check in the `line`.
+///
+/// The words with the digits or the `_` are skipped, and in the `code_mode`, the code-like tokens
+/// are also skipped, e.g.: `camelCase`, `HTTP`, `self.value`, `std::io`, `call()` and the words
+/// in the `code spans`.
+fn check_ranges(line: &str, code_mode: bool) -> Vec<Range<usize>> {
+    let mut ranges = vec![];
+    let mut in_code = false;
+    let mut prev_char = None;
+
+    let mut tokens = line.split_word_bound_indices().peekable();
+    while let Some((ix, token)) = tokens.next() {
+        let next_char = tokens.peek().and_then(|(_, next)| next.chars().next());
+        let before = prev_char;
+        prev_char = token.chars().last();
+
+        if token == "`" {
+            in_code = !in_code;
+            continue;
+        }
+        let is_word = token.chars().any(char::is_alphabetic)
+            && token
+                .chars()
+                .all(|c| c.is_alphabetic() || c == '\'' || c == '’');
+        if !is_word {
+            continue;
+        }
+
+        if code_mode {
+            let is_code = in_code
+                || subword_ranges(token).len() > 1
+                || (token.chars().count() > 1 && token.chars().all(|c| !c.is_lowercase()))
+                || before.is_some_and(|c| CODE_PREFIX_CHARS.contains(&c))
+                || next_char.is_some_and(|c| CODE_SUFFIX_CHARS.contains(&c));
+            if is_code {
+                continue;
+            }
+        }
+
+        // Check the word without the quotes around, e.g.: 'hello'.
+        let word = token.trim_matches(|c| c == '\'' || c == '’');
+        if !word.is_empty() {
+            let start = ix + token.find(word).unwrap_or(0);
+            ranges.push(start..start + word.len());
+        }
+    }
+    ranges
+}
+
+#[cfg(test)]
+mod tests {
+    use super::check_ranges;
+
+    fn words(line: &str, code_mode: bool) -> Vec<&str> {
+        check_ranges(line, code_mode)
+            .into_iter()
+            .map(|range| &line[range])
+            .collect()
+    }
+
+    #[test]
+    fn test_check_ranges() {
+        assert_eq!(
+            words("Helo, wrld! I don't know.\n", false),
+            vec!["Helo", "wrld", "I", "don't", "know"]
+        );
+        assert_eq!(words("foo_bar v2 123 'quoted'", false), vec!["quoted"]);
+        assert_eq!(words("café naïve", false), vec!["café", "naïve"]);
+
+        let line = "Call `parse_args` or self.value, std::io and getValue(), HTTP is ok";
+        assert_eq!(
+            words(line, false),
+            vec!["Call", "or", "std", "io", "and", "getValue", "HTTP", "is", "ok"]
+        );
+        assert_eq!(words(line, true), vec!["Call", "or", "and", "is", "ok"]);
+        // The code span is ended in the line.
+        assert_eq!(words("`a b` c", true), vec!["c"]);
+    }
+}
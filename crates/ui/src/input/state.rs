@@ -6,17 +6,20 @@ use gpui::Action;
 use ropey::{Rope, RopeSlice};
 use serde::Deserialize;
 use smallvec::SmallVec;
+use std::borrow::Cow;
 use std::cell::RefCell;
 use std::ops::{Deref, Range};
+use std::path::PathBuf;
 use std::rc::Rc;
 use unicode_segmentation::*;
 
 use gpui::{
-    actions, div, point, prelude::FluentBuilder as _, px, App, AppContext, Bounds, ClipboardItem,
-    Context, Entity, EntityInputHandler, EventEmitter, FocusHandle, Focusable,
-    InteractiveElement as _, IntoElement, KeyBinding, KeyDownEvent, MouseButton, MouseDownEvent,
-    MouseMoveEvent, MouseUpEvent, ParentElement as _, Pixels, Point, Render, ScrollHandle,
-    ScrollWheelEvent, SharedString, Styled as _, Subscription, UTF16Selection, Window, WrappedLine,
+    actions, div, point, prelude::FluentBuilder as _, px, App, AppContext, Bounds, ClipboardEntry,
+    ClipboardItem, Context, Entity, EntityInputHandler, EventEmitter, ExternalPaths, FocusHandle,
+    Focusable, InteractiveElement as _, IntoElement, KeyBinding, KeyDownEvent, MouseButton,
+    MouseDownEvent, MouseMoveEvent, MouseUpEvent, ParentElement as _, Pixels, Point, Render,
+    ScrollHandle, ScrollWheelEvent, SharedString, Styled as _, Subscription, UTF16Selection,
+    Window, WrappedLine,
 };
 
 // TODO:
@@ -25,16 +28,34 @@ use gpui::{
 use super::{
     blink_cursor::BlinkCursor,
     change::Change,
+    combobox,
+    drop_files::partition_files,
     element::TextElement,
+    go_to_line::GoToLinePanel,
+    jump_list::JumpList,
     mask_pattern::MaskPattern,
     mode::{InputMode, TabSize},
     number_input,
+    rich_text::{parse_markdown, to_markdown, FormatAttribute, FormatRuns, TextFormat, TextSpan},
+    tag_input,
     text_wrapper::TextWrapper,
+    word,
 };
+use crate::input::completion::{
+    is_word_char, parse_snippet, CompletionItem, CompletionMenu, CompletionProvider,
+    CompletionTrigger, SnippetSession,
+};
+use crate::input::fold::FoldMap;
 use crate::input::hover_popover::DiagnosticPopover;
 use crate::input::marker::Marker;
+use crate::input::minimap::MinimapCache;
+use crate::input::spellcheck::{SpellCheck, SpellChecker};
 use crate::input::{Cursor, LineColumn, RopeExt, Selection};
-use crate::{history::History, scroll::ScrollbarState, Root};
+use crate::{
+    history::History,
+    scroll::{ScrollAlign, ScrollbarState},
+    Root,
+};
 
 #[derive(Action, Clone, PartialEq, Eq, Deserialize)]
 #[action(namespace = input, no_json)]
@@ -87,16 +108,35 @@ actions!(
         MoveToEnd,
         MoveToPreviousWord,
         MoveToNextWord,
-        Escape
+        ShowCompletion,
+        Escape,
+        ToggleBold,
+        ToggleItalic,
+        ToggleUnderline,
+        Transpose,
+        ConvertToUpperCase,
+        ConvertToLowerCase,
+        ConvertToCapitalize,
+        GoToLine,
+        NavigateBack,
+        NavigateForward
     ]
 );
 
 #[derive(Clone)]
 pub enum InputEvent {
     Change(SharedString),
-    PressEnter { secondary: bool },
+    PressEnter {
+        secondary: bool,
+    },
     Focus,
     Blur,
+    /// The files dropped on the input, see [`InputState::accept_files`].
+    FilesDropped(Vec<PathBuf>),
+    /// The dropped files not in the [`InputState::file_extensions`], to show the feedback.
+    FilesRejected(Vec<PathBuf>),
+    /// The image pasted from the clipboard, with the bytes and the MIME type, e.g.: `image/png`.
+    ImagePasted(Vec<u8>, SharedString),
 }
 
 pub(super) const CONTEXT: &str = "Input";
@@ -162,6 +202,7 @@ pub fn init(cx: &mut App) {
         KeyBinding::new("ctrl-shift-right", SelectToNextWordEnd, Some(CONTEXT)),
         #[cfg(target_os = "macos")]
         KeyBinding::new("ctrl-cmd-space", ShowCharacterPalette, Some(CONTEXT)),
+        KeyBinding::new("ctrl-space", ShowCompletion, Some(CONTEXT)),
         #[cfg(target_os = "macos")]
         KeyBinding::new("cmd-a", SelectAll, Some(CONTEXT)),
         #[cfg(not(target_os = "macos"))]
@@ -207,12 +248,66 @@ pub fn init(cx: &mut App) {
         #[cfg(target_os = "macos")]
         KeyBinding::new("cmd-shift-down", SelectToEnd, Some(CONTEXT)),
         #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-home", MoveToStart, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-end", MoveToEnd, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-shift-home", SelectToStart, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-shift-end", SelectToEnd, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
         KeyBinding::new("ctrl-z", Undo, Some(CONTEXT)),
         #[cfg(not(target_os = "macos"))]
         KeyBinding::new("ctrl-y", Redo, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-b", ToggleBold, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-b", ToggleBold, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-i", ToggleItalic, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-i", ToggleItalic, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-u", ToggleUnderline, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-u", ToggleUnderline, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("ctrl-t", Transpose, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-k cmd-u", ConvertToUpperCase, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-k ctrl-u", ConvertToUpperCase, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-k cmd-l", ConvertToLowerCase, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-k ctrl-l", ConvertToLowerCase, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-g", GoToLine, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-g", GoToLine, Some(CONTEXT)),
+        // The `cmd-[` and `cmd-]` are used by the `Outdent` and `Indent`,
+        // so use the same keys of the navigation as VS Code.
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("ctrl--", NavigateBack, Some(CONTEXT)),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("ctrl-shift--", NavigateForward, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("alt-left", NavigateBack, Some(CONTEXT)),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("alt-right", NavigateForward, Some(CONTEXT)),
     ]);
 
     number_input::init(cx);
+    combobox::init(cx);
+    tag_input::init(cx);
+}
+
+/// The scroll request to apply on the next layout, when the lines are known.
+#[derive(Debug, Clone, Copy)]
+pub(super) enum DeferredScroll {
+    Offset(Point<Pixels>),
+    /// Zero based line index.
+    Line(usize, ScrollAlign),
 }
 
 #[derive(Clone)]
@@ -270,10 +365,27 @@ pub struct InputState {
     pub(super) soft_wrap: bool,
     pub(super) pattern: Option<regex::Regex>,
     pub(super) validate: Option<Box<dyn Fn(&str, &mut Context<Self>) -> bool + 'static>>,
+    /// The max length of the text, counted in grapheme clusters.
+    pub(super) max_length: Option<usize>,
+    /// The inline formats of the rich text, `None` for the plain text.
+    pub(super) rich_text: Option<FormatRuns>,
+    /// Move by the camelCase and snake_case subwords.
+    pub(super) subword: bool,
     pub(crate) scroll_handle: ScrollHandle,
     pub(super) scroll_state: ScrollbarState,
     /// The size of the scrollable content.
     pub(crate) scroll_size: gpui::Size<Pixels>,
+    pub(super) deferred_scroll: Option<DeferredScroll>,
+    pub(super) folds: FoldMap,
+    pub(super) minimap: MinimapCache,
+    /// Set true when dragging the viewport of the minimap.
+    pub(super) minimap_dragging: bool,
+    /// The misspelled words by the spell checker.
+    pub(super) spellcheck: SpellCheck,
+    /// Accept the dropped files and the pasted images.
+    pub(super) accept_files: bool,
+    /// The extensions of the files to accept, all the files are accepted if empty.
+    pub(super) file_extensions: Rc<[SharedString]>,
 
     /// The mask pattern for formatting the input text
     pub(crate) mask_pattern: MaskPattern,
@@ -281,6 +393,13 @@ pub struct InputState {
 
     /// Popover
     diagnostic_popover: Option<Entity<DiagnosticPopover>>,
+    completion_provider: Option<CompletionProvider>,
+    pub(super) completion_menu: Option<CompletionMenu>,
+    /// The tab stops of the last inserted snippet.
+    snippet: Option<SnippetSession>,
+    pub(super) go_to_line: Option<GoToLinePanel>,
+    /// The navigation history of the edit locations and jumps, only for the code editor.
+    pub(super) jump_list: JumpList,
 
     /// To remember the horizontal column (x-coordinate) of the cursor position for keep column for move up/down.
     preferred_column: Option<usize>,
@@ -341,6 +460,9 @@ impl InputState {
             loading: false,
             pattern: None,
             validate: None,
+            max_length: None,
+            rich_text: None,
+            subword: false,
             mode: InputMode::SingleLine,
             last_layout: None,
             last_bounds: None,
@@ -349,10 +471,22 @@ impl InputState {
             scroll_handle: ScrollHandle::new(),
             scroll_state: ScrollbarState::default(),
             scroll_size: gpui::size(px(0.), px(0.)),
+            deferred_scroll: None,
+            folds: FoldMap::new(),
+            minimap: MinimapCache::default(),
+            minimap_dragging: false,
+            spellcheck: SpellCheck::default(),
+            accept_files: false,
+            file_extensions: Rc::new([]),
             preferred_column: None,
             placeholder: SharedString::default(),
             mask_pattern: MaskPattern::default(),
             diagnostic_popover: None,
+            completion_provider: None,
+            completion_menu: None,
+            snippet: None,
+            go_to_line: None,
+            jump_list: JumpList::default(),
             _subscriptions,
         }
     }
@@ -404,6 +538,8 @@ impl InputState {
             language,
             highlighter: Rc::new(RefCell::new(None)),
             line_number: true,
+            folding: false,
+            minimap: false,
             markers: Rc::new(vec![]),
         };
         self
@@ -423,6 +559,36 @@ impl InputState {
         self
     }
 
+    /// Set enable/disable code folding, only for [`InputMode::CodeEditor`] mode with line number.
+    ///
+    /// The foldable ranges are computed from the syntax tree, or by the indentation
+    /// if the language is not supported. The fold markers are shown in the line number gutter.
+    pub fn folding(mut self, folding: bool) -> Self {
+        if let InputMode::CodeEditor { folding: f, .. } = &mut self.mode {
+            *f = folding;
+        }
+        self
+    }
+
+    /// Set show/hide the minimap at the right of the editor, only for [`InputMode::CodeEditor`] mode.
+    ///
+    /// The minimap renders the whole text with the syntax colors in a reduced resolution,
+    /// click or drag it to scroll the editor.
+    pub fn show_minimap(mut self, minimap: bool) -> Self {
+        if let InputMode::CodeEditor { minimap: m, .. } = &mut self.mode {
+            *m = minimap;
+        }
+        self
+    }
+
+    /// Set show/hide the minimap, only for [`InputMode::CodeEditor`] mode.
+    pub fn set_show_minimap(&mut self, minimap: bool, _: &mut Window, cx: &mut Context<Self>) {
+        if let InputMode::CodeEditor { minimap: m, .. } = &mut self.mode {
+            *m = minimap;
+        }
+        cx.notify();
+    }
+
     /// Set line number, only for [`InputMode::CodeEditor`] mode.
     pub fn set_line_number(&mut self, line_number: bool, _: &mut Window, cx: &mut Context<Self>) {
         if let InputMode::CodeEditor { line_number: l, .. } = &mut self.mode {
@@ -478,6 +644,8 @@ impl InputState {
             } => {
                 *language = new_language.into();
                 *highlighter.borrow_mut() = None;
+                self.folds.dirty = true;
+                self.minimap.invalidate();
             }
             _ => {}
         }
@@ -488,6 +656,7 @@ impl InputState {
         match &mut self.mode {
             InputMode::CodeEditor { highlighter, .. } => {
                 *highlighter.borrow_mut() = None;
+                self.minimap.invalidate();
             }
             _ => {}
         }
@@ -497,6 +666,9 @@ impl InputState {
     /// Set markers, only for [`InputMode::CodeEditor`] mode.
     ///
     /// For example to set the diagnostic markers in the code editor.
+    ///
+    /// The markers move with the text edits, a marker replaced by an edit is removed.
+    /// Overlapping markers are displayed with the highest severity.
     pub fn set_markers(&mut self, markers: Vec<Marker>, _: &mut Window, _: &mut Context<Self>) {
         let mut markers = markers;
         for marker in &mut markers {
@@ -548,7 +720,7 @@ impl InputState {
                 return (line_index, sub_line_index, Some(adjusted_pos));
             }
 
-            y_offset += line.size(line_height).height;
+            y_offset += self.folds.line_height(line_index, line, line_height);
             prev_lines_offset += line.len() + 1;
         }
         (0, 0, None)
@@ -569,7 +741,11 @@ impl InputState {
             return;
         };
 
-        let new_line_ix = line_ix.saturating_add_signed(move_lines);
+        let mut new_line_ix = line_ix.saturating_add_signed(move_lines);
+        // Skip the folded lines.
+        while new_line_ix > 0 && self.folds.is_hidden(new_line_ix) {
+            new_line_ix = new_line_ix.saturating_add_signed(move_lines.signum());
+        }
         let Some(line) = self.text.get_line(new_line_ix) else {
             return;
         };
@@ -604,6 +780,7 @@ impl InputState {
         self.replace_text(value, window, cx);
         self.disabled = was_disabled;
         self.history.ignore = false;
+        self.jump_list = JumpList::default();
         // Ensure cursor to start when set text
         if self.mode.is_single_line() {
             self.selected_range =
@@ -713,12 +890,256 @@ impl InputState {
         self.pattern = Some(pattern);
     }
 
+    /// Set the max length of the text, counted in grapheme clusters, so an emoji or a character
+    /// with the combining marks counts as one.
+    ///
+    /// The inserted or pasted text is truncated to fill up to the max length.
+    pub fn max_length(mut self, max_length: usize) -> Self {
+        self.max_length = Some(max_length);
+        self
+    }
+
+    /// Set the max length of the text with reference, None for no limit.
+    ///
+    /// The current text is kept even if it is longer than the max length.
+    pub fn set_max_length(
+        &mut self,
+        max_length: Option<usize>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.max_length = max_length;
+        cx.notify();
+    }
+
+    /// Returns the length of the text, counted in grapheme clusters.
+    pub fn text_length(&self) -> usize {
+        self.text.to_string().graphemes(true).count()
+    }
+
+    /// Set true to edit the text with the inline bold, italic, underline and links.
+    ///
+    /// Use `cmd-b`, `cmd-i` and `cmd-u` (`ctrl-` on Windows and Linux) to toggle the format of
+    /// the selection, see also [`crate::input::RichTextToolbar`]. The undo and redo only
+    /// restore the text, the formats of the restored text follow the text before it.
+    pub fn rich_text(mut self, rich_text: bool) -> Self {
+        self.rich_text = rich_text.then(|| FormatRuns::new(self.text.len_bytes()));
+        self
+    }
+
+    /// Set true to move and delete by the subwords, e.g.: `foo` and `Bar` of `fooBar`,
+    /// `foo` and `bar` of `foo_bar`, default is false.
+    pub fn subword(mut self, subword: bool) -> Self {
+        self.subword = subword;
+        self
+    }
+
+    /// Set true to move and delete by the subwords, see [`Self::subword`].
+    pub fn set_subword(&mut self, subword: bool, _: &mut Window, cx: &mut Context<Self>) {
+        self.subword = subword;
+        cx.notify();
+    }
+
+    /// Returns true if the input is a rich text input, see [`Self::rich_text`].
+    pub fn is_rich_text(&self) -> bool {
+        self.rich_text.is_some()
+    }
+
+    /// Returns the value of the rich text input as the spans with the same format.
+    ///
+    /// For the plain text input, it is a single span without format.
+    pub fn rich_value(&self) -> Vec<TextSpan> {
+        let text = self.text.to_string();
+        match self.rich_text.as_ref() {
+            Some(rich_text) => rich_text.spans(&text),
+            None if text.is_empty() => vec![],
+            None => vec![TextSpan::new(text)],
+        }
+    }
+
+    /// Set the value of the rich text input by the spans, the formats are ignored for the plain text input.
+    pub fn set_rich_value(
+        &mut self,
+        spans: &[TextSpan],
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let (text, runs) = FormatRuns::from_spans(spans);
+        let len = text.len();
+        self.set_value(text, window, cx);
+        // The text may be changed by the max length or the mask pattern.
+        if self.rich_text.is_some() && self.text.len_bytes() == len {
+            self.rich_text = Some(runs);
+        }
+        cx.notify();
+    }
+
+    /// Returns the value of the rich text input as Markdown, see [`crate::input::to_markdown`].
+    pub fn markdown_value(&self) -> String {
+        to_markdown(&self.rich_value())
+    }
+
+    /// Set the value of the rich text input from Markdown, see [`crate::input::parse_markdown`].
+    pub fn set_markdown_value(
+        &mut self,
+        markdown: &str,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.set_rich_value(&parse_markdown(markdown), window, cx);
+    }
+
+    /// Returns the format of the selection, an attribute is set only if all the selected text has it.
+    ///
+    /// Without selection, it is the format to type at the cursor.
+    pub fn selected_format(&self) -> TextFormat {
+        self.rich_text
+            .as_ref()
+            .map(|rich_text| rich_text.format_for_range(&self.selected_range.into()))
+            .unwrap_or_default()
+    }
+
+    /// Toggle the format attribute of the selection, or the format to type without selection.
+    pub fn toggle_format(&mut self, attr: FormatAttribute, _: &mut Window, cx: &mut Context<Self>) {
+        let range: Range<usize> = self.selected_range.into();
+        let Some(rich_text) = self.rich_text.as_mut() else {
+            return;
+        };
+        rich_text.toggle(&range, attr);
+        if !range.is_empty() {
+            cx.emit(InputEvent::Change(self.unmask_value()));
+        }
+        cx.notify();
+    }
+
+    /// Set the link of the selection, `None` to remove the link.
+    ///
+    /// Without selection, it updates the entire link at the cursor if any.
+    pub fn set_link(&mut self, url: Option<SharedString>, _: &mut Window, cx: &mut Context<Self>) {
+        let range: Range<usize> = self.selected_range.into();
+        let Some(rich_text) = self.rich_text.as_mut() else {
+            return;
+        };
+        rich_text.set_link(&range, url);
+        cx.emit(InputEvent::Change(self.unmask_value()));
+        cx.notify();
+    }
+
+    pub(super) fn toggle_bold(
+        &mut self,
+        _: &ToggleBold,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.toggle_format(FormatAttribute::Bold, window, cx);
+    }
+
+    pub(super) fn toggle_italic(
+        &mut self,
+        _: &ToggleItalic,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.toggle_format(FormatAttribute::Italic, window, cx);
+    }
+
+    pub(super) fn toggle_underline(
+        &mut self,
+        _: &ToggleUnderline,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.toggle_format(FormatAttribute::Underline, window, cx);
+    }
+
     /// Set the validation function of the input field.
     pub fn validate(mut self, f: impl Fn(&str, &mut Context<Self>) -> bool + 'static) -> Self {
         self.validate = Some(Box::new(f));
         self
     }
 
+    /// Set the completion provider, it is called with the cursor position and the trigger
+    /// to return the completion items, e.g.: from a language server.
+    ///
+    /// The completion menu is opened on typing a word character or `.`, or by `ctrl-space`,
+    /// and filtered by the word before the cursor while typing.
+    pub fn on_completion_request(
+        mut self,
+        f: impl Fn(LineColumn, CompletionTrigger, &mut Window, &mut App) -> Vec<CompletionItem>
+            + 'static,
+    ) -> Self {
+        self.completion_provider = Some(Rc::new(f));
+        self
+    }
+
+    /// Set the spell checker, the misspelled words are underlined with a red wavy line,
+    /// right click a misspelled word to show the suggestions, "Add to dictionary" and "Ignore".
+    ///
+    /// The visible lines are checked on render, only the changed lines are checked again.
+    pub fn spell_checker(mut self, checker: impl SpellChecker + 'static) -> Self {
+        self.spellcheck.set_checker(Some(Rc::new(checker)));
+        self
+    }
+
+    /// Set or remove the spell checker, see [`Self::spell_checker`].
+    pub fn set_spell_checker(
+        &mut self,
+        checker: Option<Rc<dyn SpellChecker>>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.spellcheck.set_checker(checker);
+        cx.notify();
+    }
+
+    /// Set true to skip the code-like tokens in the spell checking, default is false.
+    ///
+    /// For example: `camelCase`, `HTTP`, `self.value`, `std::io`, `call()` and the words
+    /// in the `code spans`, the words with the digits or the `_` are always skipped.
+    pub fn spellcheck_code_mode(mut self, code_mode: bool) -> Self {
+        self.spellcheck.set_code_mode(code_mode);
+        self
+    }
+
+    /// Set the code mode of the spell checking, see [`Self::spellcheck_code_mode`].
+    pub fn set_spellcheck_code_mode(
+        &mut self,
+        code_mode: bool,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.spellcheck.set_code_mode(code_mode);
+        cx.notify();
+    }
+
+    /// Set true to accept the files dropped on the input and the images pasted from the
+    /// clipboard, default is false.
+    ///
+    /// The input does not insert them, but emits [`InputEvent::FilesDropped`] and
+    /// [`InputEvent::ImagePasted`], then the host decides how to show them, e.g.: insert a chip
+    /// or a thumbnail. The text paste is not changed.
+    pub fn accept_files(mut self, accept: bool) -> Self {
+        self.accept_files = accept;
+        self
+    }
+
+    /// Set the extensions of the files to accept, e.g.: `["png", "jpg"]`, case insensitive,
+    /// default is empty to accept all the files.
+    ///
+    /// The other dropped files are rejected with [`InputEvent::FilesRejected`].
+    pub fn file_extensions(
+        mut self,
+        extensions: impl IntoIterator<Item = impl Into<SharedString>>,
+    ) -> Self {
+        self.file_extensions = extensions.into_iter().map(Into::into).collect();
+        self
+    }
+
+    /// Returns the extensions of the files to accept, see [`Self::file_extensions`].
+    pub fn accepted_file_extensions(&self) -> &[SharedString] {
+        &self.file_extensions
+    }
+
     /// Set true to show indicator at the input right.
     pub fn set_loading(&mut self, loading: bool, _: &mut Window, cx: &mut Context<Self>) {
         self.loading = loading;
@@ -730,6 +1151,9 @@ impl InputState {
         let text: SharedString = value.into();
         self.text = Rope::from_str(text.as_str());
         self.text_wrapper.text = self.text.clone();
+        if self.rich_text.is_some() {
+            self.rich_text = Some(FormatRuns::new(self.text.len_bytes()));
+        }
         self
     }
 
@@ -778,6 +1202,131 @@ impl InputState {
         self.move_to(Cursor::new(offset), window, cx);
     }
 
+    /// Fold the (1-based) line if it is the start of a foldable range.
+    pub fn fold_line(&mut self, line: usize, _: &mut Window, cx: &mut Context<Self>) {
+        if self.folds.fold(line.saturating_sub(1)) {
+            self.move_cursor_out_of_folds();
+            cx.notify();
+        }
+    }
+
+    /// Unfold the (1-based) line if it is folded.
+    pub fn unfold_line(&mut self, line: usize, _: &mut Window, cx: &mut Context<Self>) {
+        if self.folds.unfold(line.saturating_sub(1)) {
+            cx.notify();
+        }
+    }
+
+    /// Toggle the fold of the (1-based) line.
+    pub fn toggle_fold(&mut self, line: usize, _: &mut Window, cx: &mut Context<Self>) {
+        if self.folds.toggle(line.saturating_sub(1)) {
+            self.move_cursor_out_of_folds();
+            cx.notify();
+        }
+    }
+
+    /// Fold all the foldable ranges.
+    pub fn fold_all(&mut self, _: &mut Window, cx: &mut Context<Self>) {
+        self.folds.fold_all();
+        self.move_cursor_out_of_folds();
+        cx.notify();
+    }
+
+    /// Unfold all the folded ranges.
+    pub fn unfold_all(&mut self, _: &mut Window, cx: &mut Context<Self>) {
+        self.folds.unfold_all();
+        cx.notify();
+    }
+
+    /// Move the cursor to the end of the fold start line, if it is hidden by the folds.
+    ///
+    /// Otherwise the folds will be revealed by the cursor in the next render.
+    fn move_cursor_out_of_folds(&mut self) {
+        let cursor_line = self.text.byte_to_line(self.cursor().offset);
+        if !self.folds.is_hidden(cursor_line) {
+            return;
+        }
+
+        let mut line_ix = cursor_line;
+        while line_ix > 0 && self.folds.is_hidden(line_ix) {
+            line_ix -= 1;
+        }
+        let line_len = self
+            .text
+            .line(line_ix)
+            .to_string()
+            .trim_end_matches(['\r', '\n'])
+            .len();
+        let offset = self.text.line_to_byte(line_ix) + line_len;
+        self.selected_range = (offset..offset).into();
+    }
+
+    /// Update the folds, the minimap and the spellcheck lines before replace the `range` of the text
+    /// with `new_text`.
+    fn update_lines_for_edit(&mut self, range: &Range<usize>, new_text: &str) {
+        let len = self.text.len_bytes();
+        let start_line = self.text.byte_to_line(range.start.min(len));
+        let old_end_line = self.text.byte_to_line(range.end.min(len));
+        let new_end_line = start_line + new_text.matches('\n').count();
+        self.folds.edit(start_line, old_end_line, new_end_line);
+        self.minimap.edit(start_line, old_end_line, new_end_line);
+        self.spellcheck.edit(start_line, old_end_line, new_end_line);
+    }
+
+    /// Returns the current scroll offset, can be used to restore it later.
+    pub fn scroll_offset(&self) -> Point<Pixels> {
+        self.scroll_handle.offset()
+    }
+
+    /// Set the scroll offset, e.g.: restore a previously saved [`Self::scroll_offset`].
+    ///
+    /// If the input has not been laid out yet, the offset will be applied on the first paint.
+    pub fn set_scroll_offset(&mut self, offset: Point<Pixels>, cx: &mut Context<Self>) {
+        if self.last_layout.is_none() {
+            self.deferred_scroll = Some(DeferredScroll::Offset(offset));
+            cx.notify();
+            return;
+        }
+
+        self.deferred_scroll = None;
+        self.update_scroll_offset(Some(offset), cx);
+    }
+
+    /// Returns the (1-based) line number at the top of the viewport.
+    ///
+    /// Use with [`Self::scroll_to_line`] to restore the scroll position by line,
+    /// this is more stable than [`Self::scroll_offset`] when the wrap width is changed.
+    pub fn scroll_top_line(&self) -> usize {
+        let Some(last_layout) = self.last_layout.as_ref() else {
+            return 1;
+        };
+
+        let scroll_top = -self.scroll_handle.offset().y;
+        let mut line_bottom = px(0.);
+        for (ix, line) in last_layout.lines.iter().enumerate() {
+            line_bottom += self.folds.line_height(ix, line, last_layout.line_height);
+            if line_bottom > scroll_top {
+                return ix + 1;
+            }
+        }
+
+        last_layout.lines.len().max(1)
+    }
+
+    /// Scroll to the (1-based) line, and place it at the `align` position of the viewport.
+    ///
+    /// Unlike [`Self::go_to_line`], this will not move the cursor.
+    pub fn scroll_to_line(
+        &mut self,
+        line: usize,
+        align: ScrollAlign,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.deferred_scroll = Some(DeferredScroll::Line(line.saturating_sub(1), align));
+        cx.notify();
+    }
+
     /// Focus the input field.
     pub fn focus(&self, window: &mut Window, _: &mut Context<Self>) {
         self.focus_handle.focus(window);
@@ -810,6 +1359,11 @@ impl InputState {
     }
 
     pub(super) fn up(&mut self, _: &MoveUp, window: &mut Window, cx: &mut Context<Self>) {
+        if let Some(menu) = self.completion_menu.as_mut() {
+            menu.select(-1);
+            cx.notify();
+            return;
+        }
         if self.mode.is_single_line() {
             return;
         }
@@ -826,6 +1380,11 @@ impl InputState {
     }
 
     pub(super) fn down(&mut self, _: &MoveDown, window: &mut Window, cx: &mut Context<Self>) {
+        if let Some(menu) = self.completion_menu.as_mut() {
+            menu.select(1);
+            cx.notify();
+            return;
+        }
         if self.mode.is_single_line() {
             return;
         }
@@ -1043,28 +1602,17 @@ impl InputState {
     /// Return the start offset of the previous word.
     fn previous_start_of_word(&mut self) -> usize {
         let offset = self.selected_range.start.offset;
-        let prev_str = self
-            .text_for_range_utf8(0..offset)
-            .as_str()
-            .unwrap_or_default();
-        UnicodeSegmentation::split_word_bound_indices(prev_str)
-            .filter(|(_, s)| !s.trim_start().is_empty())
-            .next_back()
-            .map(|(i, _)| i)
-            .unwrap_or(0)
+        let prev_str: Cow<str> = self.text_for_range_utf8(0..offset).into();
+        word::previous_word_start(&prev_str, self.subword)
     }
 
     /// Return the next end offset of the next word.
     fn next_end_of_word(&mut self) -> usize {
         let offset = self.cursor().offset;
-        let next_str = self
+        let next_str: Cow<str> = self
             .text_for_range_utf8(offset..self.text.len_bytes())
-            .as_str()
-            .unwrap_or_default();
-        UnicodeSegmentation::split_word_bound_indices(next_str)
-            .find(|(_, s)| !s.trim_start().is_empty())
-            .map(|(i, s)| offset + i + s.len())
-            .unwrap_or(self.text.len_bytes())
+            .into();
+        offset + word::next_word_end(&next_str, self.subword)
     }
 
     /// Get start of line
@@ -1191,6 +1739,11 @@ impl InputState {
     }
 
     pub(super) fn backspace(&mut self, _: &Backspace, window: &mut Window, cx: &mut Context<Self>) {
+        if self.marked_range.is_some() {
+            self.delete_in_marked_text(false, window, cx);
+            return;
+        }
+
         if self.selected_range.is_empty() {
             self.select_to(
                 Cursor::new(self.previous_boundary(self.cursor().offset)),
@@ -1203,6 +1756,11 @@ impl InputState {
     }
 
     pub(super) fn delete(&mut self, _: &Delete, window: &mut Window, cx: &mut Context<Self>) {
+        if self.marked_range.is_some() {
+            self.delete_in_marked_text(true, window, cx);
+            return;
+        }
+
         if self.selected_range.is_empty() {
             self.select_to(
                 Cursor::new(self.next_boundary(self.cursor().offset)),
@@ -1214,6 +1772,52 @@ impl InputState {
         self.pause_blink_cursor(cx);
     }
 
+    /// Delete a character before (or after if `forward`) the cursor in the IME marked text,
+    /// the rest of the marked text keeps composing.
+    ///
+    /// Otherwise the [`Self::replace_text_in_range`] will replace the entire marked text.
+    fn delete_in_marked_text(
+        &mut self,
+        forward: bool,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let Some(marked_range) = self.marked_range else {
+            return;
+        };
+        let marked_range: Range<usize> = marked_range.into();
+        let cursor = self
+            .cursor()
+            .offset
+            .clamp(marked_range.start, marked_range.end);
+        let delete_range = if forward {
+            cursor..self.next_boundary(cursor).min(marked_range.end)
+        } else {
+            self.previous_boundary(cursor).max(marked_range.start)..cursor
+        };
+        if delete_range.is_empty() {
+            return;
+        }
+
+        let mut new_text = self.text_for_range_utf8(marked_range.clone()).to_string();
+        new_text.replace_range(
+            delete_range.start - marked_range.start..delete_range.end - marked_range.start,
+            "",
+        );
+        let new_cursor_utf16 = new_text[..delete_range.start - marked_range.start]
+            .encode_utf16()
+            .count();
+
+        self.replace_and_mark_text_in_range(
+            None,
+            &new_text,
+            Some(new_cursor_utf16..new_cursor_utf16),
+            window,
+            cx,
+        );
+        self.pause_blink_cursor(cx);
+    }
+
     pub(super) fn delete_to_beginning_of_line(
         &mut self,
         _: &DeleteToBeginningOfLine,
@@ -1285,7 +1889,92 @@ impl InputState {
         self.pause_blink_cursor(cx);
     }
 
+    /// Swap the chars around the cursor, or the last two chars at the end of the line.
+    pub(super) fn transpose(&mut self, _: &Transpose, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.selected_range.is_empty() {
+            return;
+        }
+
+        let offset = self.cursor().offset;
+        let line_ix = self.text.byte_to_line(offset);
+        let line_start = self.text.line_to_byte(line_ix);
+        let line = self.text.line(line_ix).to_string();
+        let line = line.trim_end_matches(['\r', '\n']);
+
+        let Some((range, new_text)) = word::transpose(line, offset - line_start) else {
+            return;
+        };
+        let range = line_start + range.start..line_start + range.end;
+        self.replace_text_in_range(Some(self.range_to_utf16(&range)), &new_text, window, cx);
+        self.pause_blink_cursor(cx);
+    }
+
+    pub(super) fn convert_to_upper_case(
+        &mut self,
+        _: &ConvertToUpperCase,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.convert_case(|text| text.to_uppercase(), window, cx);
+    }
+
+    pub(super) fn convert_to_lower_case(
+        &mut self,
+        _: &ConvertToLowerCase,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.convert_case(|text| text.to_lowercase(), window, cx);
+    }
+
+    pub(super) fn convert_to_capitalize(
+        &mut self,
+        _: &ConvertToCapitalize,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.convert_case(word::to_capitalize, window, cx);
+    }
+
+    /// Replace the selected text by the `convert`, or the word at the cursor if nothing is selected,
+    /// and then select the converted text.
+    fn convert_case(
+        &mut self,
+        convert: impl Fn(&str) -> String,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let mut range: Range<usize> = self.selected_range.into();
+        if range.is_empty() {
+            let line_ix = self.text.byte_to_line(range.start);
+            let line_start = self.text.line_to_byte(line_ix);
+            let line = self.text.line(line_ix).to_string();
+            let word_range = word::word_range(&line, range.start - line_start);
+            range = line_start + word_range.start..line_start + word_range.end;
+        }
+        if range.is_empty() {
+            return;
+        }
+
+        let text = self.text_for_range_utf8(range.clone()).to_string();
+        let new_text = convert(&text);
+        if new_text == text {
+            return;
+        }
+
+        self.replace_text_in_range(Some(self.range_to_utf16(&range)), &new_text, window, cx);
+        let end = self.cursor().offset;
+        self.selected_range = (range.start..end).into();
+        self.selection_reversed = false;
+        cx.notify();
+    }
+
     pub(super) fn enter(&mut self, action: &Enter, window: &mut Window, cx: &mut Context<Self>) {
+        if self.completion_menu.is_some() {
+            self.accept_completion(window, cx);
+            return;
+        }
+
         if self.mode.is_multi_line() {
             // Get current line indent
             let indent = if self.mode.is_code_editor() {
@@ -1313,6 +2002,19 @@ impl InputState {
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
+        if self.completion_menu.is_some() {
+            self.accept_completion(window, cx);
+            return;
+        }
+        if self.move_to_next_tabstop(cx) {
+            return;
+        }
+        // The single line input has no indent, e.g.: let the Table to move to the next cell.
+        if self.mode.tab_size().is_none() {
+            cx.propagate();
+            return;
+        }
+
         self.indent(false, window, cx);
     }
 
@@ -1326,6 +2028,11 @@ impl InputState {
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
+        if self.mode.tab_size().is_none() {
+            cx.propagate();
+            return;
+        }
+
         self.outdent(false, window, cx);
     }
 
@@ -1477,6 +2184,11 @@ impl InputState {
         if self.marked_range.is_some() {
             self.unmark_text(window, cx);
         }
+        self.snippet = None;
+        if self.completion_menu.is_some() {
+            self.hide_completion_menu(cx);
+            return;
+        }
 
         if self.clean_on_escape {
             return self.clean(window, cx);
@@ -1491,16 +2203,44 @@ impl InputState {
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
-        // If there have IME marked range and is empty (Means pressed Esc to abort IME typing)
-        // Clear the marked range.
-        if let Some(marked_range) = &self.marked_range {
-            if marked_range.len() == 0 {
-                self.marked_range = None;
+        // Click the minimap to scroll to the position, and drag to scroll continuously.
+        if event.button == MouseButton::Left {
+            self.minimap_dragging = false;
+            if let Some(minimap_bounds) = self.minimap_bounds() {
+                if minimap_bounds.contains(&event.position) {
+                    self.minimap_dragging = true;
+                    self.scroll_to_minimap_position(event.position, cx);
+                    return;
+                }
+            }
+        }
+
+        // Click the line number gutter to toggle the fold.
+        if event.button == MouseButton::Left && self.mode.folding() {
+            if let Some(line_ix) = self.foldable_line_for_gutter_position(event.position) {
+                self.toggle_fold(line_ix + 1, window, cx);
+                return;
+            }
+        }
+
+        let mut offset = self.index_for_mouse_position(event.position, window, cx);
+
+        // Click to cancel the IME composition, remove the marked text,
+        // and adjust the clicked offset for the removed text.
+        if let Some(marked_range) = self.marked_range {
+            if !marked_range.is_empty() {
+                let marked_range: Range<usize> = marked_range.into();
+                if offset >= marked_range.end {
+                    offset -= marked_range.len();
+                } else if offset > marked_range.start {
+                    offset = marked_range.start;
+                }
+                self.replace_text_in_range(None, "", window, cx);
             }
+            self.marked_range = None;
         }
 
         self.selecting = true;
-        let offset = self.index_for_mouse_position(event.position, window, cx);
         // Double click to select word
         if event.button == MouseButton::Left && event.click_count == 2 {
             self.select_word(offset, window, cx);
@@ -1514,6 +2254,60 @@ impl InputState {
         }
     }
 
+    /// Right click a misspelled word to show the spellcheck context menu.
+    pub(super) fn on_right_mouse_down(
+        &mut self,
+        event: &MouseDownEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if !self.spellcheck.is_enabled() || self.masked {
+            return;
+        }
+
+        let offset = self.index_for_mouse_position(event.position, window, cx);
+        self.spellcheck.menu_word = self.spellcheck.word_at(&self.text, offset);
+        cx.notify();
+    }
+
+    /// Returns the right clicked misspelled word, its range and the suggestions.
+    pub(super) fn spellcheck_menu_word(&self) -> Option<(Range<usize>, String, Vec<SharedString>)> {
+        let range = self.spellcheck.menu_word.clone()?;
+        let word = self.text_for_range_utf8(range.clone()).to_string();
+        let suggestions = self.spellcheck.suggestions(&word);
+        Some((range, word, suggestions))
+    }
+
+    /// Replace the misspelled `word` at the `range` by the `suggestion`.
+    pub(super) fn replace_misspelled_word(
+        &mut self,
+        range: Range<usize>,
+        word: &str,
+        suggestion: &str,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        // The text is changed after the right click.
+        if range.end > self.text.len_bytes() || self.text_for_range_utf8(range.clone()) != word {
+            return;
+        }
+
+        let range_utf16 = self.range_to_utf16(&range);
+        self.replace_text_in_range(Some(range_utf16), suggestion, window, cx);
+    }
+
+    /// Accept the misspelled `word` by the "Ignore" menu, or the "Add to dictionary" menu
+    /// if `add_to_dictionary` is true.
+    pub(super) fn accept_misspelled_word(
+        &mut self,
+        word: &str,
+        add_to_dictionary: bool,
+        cx: &mut Context<Self>,
+    ) {
+        self.spellcheck.accept(word, add_to_dictionary);
+        cx.notify();
+    }
+
     pub(super) fn on_mouse_up(
         &mut self,
         _: &MouseUpEvent,
@@ -1521,6 +2315,7 @@ impl InputState {
         _cx: &mut Context<Self>,
     ) {
         self.selecting = false;
+        self.minimap_dragging = false;
         self.selected_word_range = None;
     }
 
@@ -1572,7 +2367,11 @@ impl InputState {
         self.diagnostic_popover = None;
     }
 
-    fn update_scroll_offset(&mut self, offset: Option<Point<Pixels>>, cx: &mut Context<Self>) {
+    pub(super) fn update_scroll_offset(
+        &mut self,
+        offset: Option<Point<Pixels>>,
+        cx: &mut Context<Self>,
+    ) {
         let mut offset = offset.unwrap_or(self.scroll_handle.offset());
 
         let safe_y_range =
@@ -1586,6 +2385,162 @@ impl InputState {
         cx.notify();
     }
 
+    pub(super) fn show_completion(
+        &mut self,
+        _: &ShowCompletion,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.request_completion(CompletionTrigger::Invoked, window, cx);
+    }
+
+    /// Request the completion items for the word before the cursor, and open the menu.
+    fn request_completion(
+        &mut self,
+        trigger: CompletionTrigger,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let Some(provider) = self.completion_provider.clone() else {
+            return;
+        };
+
+        let offset = self.cursor().offset;
+        let line_start = self.text.line_to_byte(self.text.byte_to_line(offset));
+        let word_len = self
+            .text_for_range_utf8(line_start..offset)
+            .to_string()
+            .chars()
+            .rev()
+            .take_while(|c| is_word_char(*c))
+            .map(|c| c.len_utf8())
+            .sum::<usize>();
+        let start = offset - word_len;
+
+        let items = provider(self.line_column(), trigger, window, cx);
+        let mut menu = CompletionMenu::new(items, start);
+        menu.filter(&self.text_for_range_utf8(start..offset).to_string());
+        self.completion_menu = (!menu.is_empty()).then_some(menu);
+        cx.notify();
+    }
+
+    /// Update the completion menu after the text is typed, see [`Self::on_completion_request`].
+    fn update_completion(&mut self, new_text: &str, window: &mut Window, cx: &mut Context<Self>) {
+        if self.completion_provider.is_none() {
+            return;
+        }
+
+        let mut chars = new_text.chars();
+        let typed = match (chars.next(), chars.next()) {
+            (Some(c), None) => Some(c),
+            _ => None,
+        };
+        match typed {
+            Some(c) if is_word_char(c) && self.completion_menu.is_some() => {
+                self.filter_completion(cx)
+            }
+            Some(c) if is_word_char(c) || c == '.' => {
+                self.request_completion(CompletionTrigger::Character(c), window, cx)
+            }
+            None if new_text.is_empty() && self.completion_menu.is_some() => {
+                self.filter_completion(cx)
+            }
+            _ => self.hide_completion_menu(cx),
+        }
+    }
+
+    /// Filter the completion menu by the word before the cursor, hide it if the cursor
+    /// leaves the word.
+    fn filter_completion(&mut self, cx: &mut Context<Self>) {
+        let Some(menu) = self.completion_menu.as_ref() else {
+            return;
+        };
+
+        let offset = self.cursor().offset;
+        if offset < menu.start {
+            return self.hide_completion_menu(cx);
+        }
+        let query = self.text_for_range_utf8(menu.start..offset).to_string();
+        if !query.chars().all(is_word_char) {
+            return self.hide_completion_menu(cx);
+        }
+
+        if let Some(menu) = self.completion_menu.as_mut() {
+            menu.filter(&query);
+            if menu.is_empty() {
+                self.completion_menu = None;
+            }
+        }
+        cx.notify();
+    }
+
+    pub(super) fn hide_completion_menu(&mut self, cx: &mut Context<Self>) {
+        if self.completion_menu.take().is_some() {
+            cx.notify();
+        }
+    }
+
+    /// Replace the word before the cursor by the selected completion item.
+    pub(super) fn accept_completion(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let Some(menu) = self.completion_menu.take() else {
+            return;
+        };
+        let Some(item) = menu.selected_item().cloned() else {
+            cx.notify();
+            return;
+        };
+
+        let range = menu.start..self.cursor().offset;
+        let (text, tabstops) = if item.snippet {
+            parse_snippet(item.insert_text.as_ref().unwrap_or(&item.label))
+        } else {
+            let text = item.insert_text.unwrap_or(item.label).to_string();
+            let len = text.len();
+            (text, vec![len..len])
+        };
+
+        // Take the provider to not request the completion again for the inserted text.
+        let provider = self.completion_provider.take();
+        self.snippet = None;
+        let range_utf16 = self.range_to_utf16(&range);
+        self.replace_text_in_range(Some(range_utf16), &text, window, cx);
+        self.completion_provider = provider;
+
+        let tabstops = tabstops
+            .into_iter()
+            .map(|tabstop| range.start + tabstop.start..range.start + tabstop.end)
+            .collect::<Vec<_>>();
+        if let Some(first) = tabstops.first().cloned() {
+            self.select_tabstop(first, cx);
+        }
+        self.snippet = SnippetSession::new(tabstops);
+    }
+
+    /// Move to the next tab stop of the inserted snippet, returns false if there is no snippet.
+    fn move_to_next_tabstop(&mut self, cx: &mut Context<Self>) -> bool {
+        let Some(snippet) = self.snippet.as_mut() else {
+            return false;
+        };
+        let Some(tabstop) = snippet.next() else {
+            self.snippet = None;
+            return false;
+        };
+        if snippet.is_finished() {
+            self.snippet = None;
+        }
+
+        self.select_tabstop(tabstop, cx);
+        true
+    }
+
+    fn select_tabstop(&mut self, tabstop: Range<usize>, cx: &mut Context<Self>) {
+        let len = self.text.len_bytes();
+        self.selected_range = (tabstop.start.min(len)..tabstop.end.min(len)).into();
+        self.selection_reversed = false;
+        self.update_preferred_column();
+        cx.notify();
+    }
+
     pub(super) fn show_character_palette(
         &mut self,
         _: &ShowCharacterPalette,
@@ -1616,6 +2571,25 @@ impl InputState {
 
     pub(super) fn paste(&mut self, _: &Paste, window: &mut Window, cx: &mut Context<Self>) {
         if let Some(clipboard) = cx.read_from_clipboard() {
+            // Emit the images instead of the text of them, e.g.: the file name.
+            if self.accept_files {
+                let images = clipboard
+                    .entries()
+                    .iter()
+                    .filter_map(|entry| match entry {
+                        ClipboardEntry::Image(image) => Some(image),
+                        _ => None,
+                    })
+                    .collect::<Vec<_>>();
+                if !images.is_empty() {
+                    for image in images {
+                        let mime = image.format.mime_type();
+                        cx.emit(InputEvent::ImagePasted(image.bytes.clone(), mime.into()));
+                    }
+                    return;
+                }
+            }
+
             let mut new_text = clipboard.text().unwrap_or_default();
             if !self.mode.is_multi_line() {
                 new_text = new_text.replace('\n', "");
@@ -1625,6 +2599,23 @@ impl InputState {
         }
     }
 
+    /// Emit the files dropped on the input, the files not in the `file_extensions` are rejected.
+    pub(super) fn on_drop_files(
+        &mut self,
+        paths: &ExternalPaths,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let (accepted, rejected) = partition_files(paths.paths(), &self.file_extensions);
+        if !rejected.is_empty() {
+            cx.emit(InputEvent::FilesRejected(rejected));
+        }
+        if !accepted.is_empty() {
+            self.focus(window, cx);
+            cx.emit(InputEvent::FilesDropped(accepted));
+        }
+    }
+
     fn push_history(
         &mut self,
         range: &Range<usize>,
@@ -1642,6 +2633,10 @@ impl InputState {
 
         let new_range = range.start..range.start + new_text.len();
 
+        if self.mode.is_code_editor() {
+            self.jump_list.push(self.text.line_column(range.start));
+        }
+
         self.history
             .push(Change::new(range.clone(), &old_text, new_range, new_text));
     }
@@ -1731,7 +2726,13 @@ impl InputState {
         let mut index = 0;
         let mut y_offset = px(0.);
 
-        for (_, line) in last_layout.lines.iter().enumerate() {
+        for (ix, line) in last_layout.lines.iter().enumerate() {
+            // The folded lines are not displayed.
+            if self.folds.is_hidden(ix) {
+                index += line.len() + 1;
+                continue;
+            }
+
             let line_origin = self.line_origin_with_y_offset(&mut y_offset, &line, line_height);
             let pos = inner_position - line_origin;
 
@@ -1775,6 +2776,35 @@ impl InputState {
         }
     }
 
+    /// Returns the (zero based) foldable or folded line index, if the position is in the line number gutter.
+    fn foldable_line_for_gutter_position(&self, position: Point<Pixels>) -> Option<usize> {
+        let (Some(bounds), Some(last_layout)) =
+            (self.last_bounds.as_ref(), self.last_layout.as_ref())
+        else {
+            return None;
+        };
+
+        let x = position.x - self.input_bounds.origin.x;
+        if x < px(0.) || x >= last_layout.line_number_width {
+            return None;
+        }
+
+        let y = position.y - bounds.origin.y;
+        let mut line_top = px(0.);
+        for (ix, line) in last_layout.lines.iter().enumerate() {
+            let line_bottom = line_top + self.folds.line_height(ix, line, last_layout.line_height);
+            if y >= line_top && y < line_bottom {
+                // Only the first row of the wrapped line has the fold marker.
+                let is_first_row = y < line_top + last_layout.line_height;
+                let foldable = self.folds.is_folded(ix) || self.folds.foldable_at(ix).is_some();
+                return (is_first_row && foldable).then_some(ix);
+            }
+            line_top = line_bottom;
+        }
+
+        None
+    }
+
     /// Returns a y offsetted point for the line origin.
     fn line_origin_with_y_offset(
         &self,
@@ -1966,6 +2996,7 @@ impl InputState {
     }
 
     fn on_blur(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        self.completion_menu = None;
         self.blink_cursor.update(cx, |cursor, cx| {
             cursor.stop(cx);
         });
@@ -1991,6 +3022,11 @@ impl InputState {
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
+        if self.minimap_dragging {
+            self.scroll_to_minimap_position(event.position, cx);
+            return;
+        }
+
         if self.text.len_bytes() == 0 {
             return;
         }
@@ -2089,6 +3125,21 @@ impl InputState {
     }
 }
 
+/// Convert the UTF-16 offset in the `text` to UTF-8 offset.
+fn utf8_offset_from_utf16(text: &str, offset_utf16: usize) -> usize {
+    let mut utf8_offset = 0;
+    let mut utf16_count = 0;
+    for ch in text.chars() {
+        if utf16_count >= offset_utf16 {
+            break;
+        }
+        utf16_count += ch.len_utf16();
+        utf8_offset += ch.len_utf8();
+    }
+
+    utf8_offset
+}
+
 impl EntityInputHandler for InputState {
     fn text_for_range(
         &mut self,
@@ -2127,9 +3178,29 @@ impl EntityInputHandler for InputState {
         self.marked_range = None;
     }
 
+    /// Truncate the `new_text` to replace the `range` within the max length.
+    fn truncate_to_max_length<'a>(&self, range: &Range<usize>, new_text: &'a str) -> &'a str {
+        let Some(max_length) = self.max_length else {
+            return new_text;
+        };
+
+        let kept_length = self
+            .text_for_range_utf8(0..range.start)
+            .to_string()
+            .graphemes(true)
+            .count()
+            + self
+                .text_for_range_utf8(range.end..self.text.len_bytes())
+                .to_string()
+                .graphemes(true)
+                .count();
+        truncate_graphemes(new_text, max_length.saturating_sub(kept_length))
+    }
+
     /// Replace text in range.
     ///
     /// - If the new text is invalid, it will not be replaced.
+    /// - If the new text exceeds the max length, it will be truncated.
     /// - If `range_utf16` is not provided, the current selected range will be used.
     fn replace_text_in_range(
         &mut self,
@@ -2148,6 +3219,13 @@ impl EntityInputHandler for InputState {
             .or(self.marked_range.map(|range| range.into()))
             .unwrap_or(self.selected_range.into());
 
+        let truncated_text = self.truncate_to_max_length(&range, new_text);
+        // No room for the new text, keep the text as is.
+        if truncated_text.is_empty() && !new_text.is_empty() {
+            return;
+        }
+        let new_text = truncated_text;
+
         let pending_text: SharedString = (self.text_for_range_utf8(0..range.start).to_string()
             + new_text
             + &self
@@ -2164,9 +3242,16 @@ impl EntityInputHandler for InputState {
         let new_offset = (range.start + new_text_len).min(mask_text.len());
 
         self.push_history(&range, &new_text, window, cx);
+        self.update_lines_for_edit(&range, new_text);
         self.text = Rope::from_str(&mask_text);
 
-        self.mode.clear_markers();
+        self.mode.edit_markers(&range, new_text_len, &self.text);
+        if let Some(rich_text) = self.rich_text.as_mut() {
+            rich_text.edit(&range, new_text_len);
+        }
+        if let Some(snippet) = self.snippet.as_mut() {
+            snippet.edit(&range, new_text_len);
+        }
         self.text_wrapper.update(&self.text, false, cx);
         self.mode
             .update_highlighter(&range, &self.text, &new_text, true, cx);
@@ -2175,6 +3260,7 @@ impl EntityInputHandler for InputState {
         self.update_preferred_column();
         self.update_scroll_offset(None, cx);
         self.mode.update_auto_grow(&self.text_wrapper);
+        self.update_completion(new_text, window, cx);
         cx.emit(InputEvent::Change(self.unmask_value()));
         cx.notify();
     }
@@ -2208,8 +3294,15 @@ impl EntityInputHandler for InputState {
         }
 
         self.push_history(&range, new_text, window, cx);
+        self.update_lines_for_edit(&range, new_text);
         self.text = Rope::from_str(&pending_text);
-        self.mode.clear_markers();
+        self.mode.edit_markers(&range, new_text.len(), &self.text);
+        if let Some(rich_text) = self.rich_text.as_mut() {
+            rich_text.edit(&range, new_text.len());
+        }
+        if let Some(snippet) = self.snippet.as_mut() {
+            snippet.edit(&range, new_text.len());
+        }
         self.text_wrapper.update(&self.text, false, cx);
         self.mode
             .update_highlighter(&range, &self.text, &new_text, true, cx);
@@ -2221,8 +3314,12 @@ impl EntityInputHandler for InputState {
             self.marked_range = Some((range.start..range.start + new_text.len()).into());
             self.selected_range = new_selected_range_utf16
                 .as_ref()
-                .map(|range_utf16| self.range_from_utf16(range_utf16))
-                .map(|new_range| new_range.start + range.start..new_range.end + range.end)
+                // The new selected range is relative to the `new_text`.
+                .map(|range_utf16| {
+                    utf8_offset_from_utf16(new_text, range_utf16.start)
+                        ..utf8_offset_from_utf16(new_text, range_utf16.end)
+                })
+                .map(|new_range| new_range.start + range.start..new_range.end + range.start)
                 .unwrap_or_else(|| range.start + new_text.len()..range.start + new_text.len())
                 .into();
         }
@@ -2232,7 +3329,8 @@ impl EntityInputHandler for InputState {
     }
 
     /// Used to position IME candidates.
-    /// TODO: Fix position of IME candidates in multi-line text input.
+    ///
+    /// The `bounds` is the text bounds with the scroll offset, so the result follows the scrolling.
     fn bounds_for_range(
         &mut self,
         range_utf16: Range<usize>,
@@ -2251,7 +3349,7 @@ impl EntityInputHandler for InputState {
         let mut y_offset = px(0.);
         let mut index_offset = 0;
 
-        for line in last_layout.lines.iter() {
+        for (ix, line) in last_layout.lines.iter().enumerate() {
             if start_origin.is_some() && end_origin.is_some() {
                 break;
             }
@@ -2273,13 +3371,16 @@ impl EntityInputHandler for InputState {
             }
 
             index_offset += line.len() + 1;
-            y_offset += line.size(line_height).height;
+            y_offset += self.folds.line_height(ix, line, line_height);
         }
 
         let start_origin = start_origin.unwrap_or_default();
-        let mut end_origin = end_origin.unwrap_or_default();
-        // Ensure at same line.
-        end_origin.y = start_origin.y;
+        let mut end_origin = end_origin.unwrap_or(start_origin);
+        // The range may be wrapped to the next rows in multi-line input,
+        // keep the bounds in the start row, to show the IME panel under the caret.
+        if end_origin.y != start_origin.y || end_origin.x < start_origin.x {
+            end_origin = start_origin;
+        }
 
         Some(Bounds::from_corners(
             bounds.origin + line_number_origin + start_origin,
@@ -2319,6 +3420,26 @@ impl Render for InputState {
         self.text_wrapper.update(&self.text, false, cx);
         self.mode
             .update_highlighter(&(0..0), &self.text, "", false, cx);
+        if self.mode.folding() {
+            if self.folds.dirty {
+                self.folds.set_foldable(self.mode.fold_ranges(&self.text));
+            }
+            // Unfold to reveal the cursor, e.g.: moved by search or go to line.
+            let cursor_line = self.text.byte_to_line(self.cursor().offset);
+            self.folds.reveal(cursor_line);
+        }
+        // Hide the completion menu if the cursor leaves the word, e.g.: moved by click or keys.
+        if let Some(menu) = self.completion_menu.as_ref() {
+            let offset = self.cursor().offset;
+            if offset < menu.start
+                || !self
+                    .text_for_range_utf8(menu.start..offset)
+                    .chars()
+                    .all(is_word_char)
+            {
+                self.completion_menu = None;
+            }
+        }
 
         div()
             .id("input-state")
@@ -2328,5 +3449,33 @@ impl Render for InputState {
             .overflow_x_hidden()
             .child(TextElement::new(cx.entity().clone()).placeholder(self.placeholder.clone()))
             .children(self.diagnostic_popover.clone())
+            .children(self.render_completion_menu(cx))
+            .children(self.render_go_to_line(cx))
+    }
+}
+
+/// Returns the text truncated to the `max` grapheme clusters.
+fn truncate_graphemes(text: &str, max: usize) -> &str {
+    match text.grapheme_indices(true).nth(max) {
+        Some((ix, _)) => &text[..ix],
+        None => text,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::truncate_graphemes;
+
+    #[test]
+    fn test_truncate_graphemes() {
+        assert_eq!(truncate_graphemes("hello", 3), "hel");
+        assert_eq!(truncate_graphemes("hello", 5), "hello");
+        assert_eq!(truncate_graphemes("hello", 10), "hello");
+        assert_eq!(truncate_graphemes("hello", 0), "");
+        assert_eq!(truncate_graphemes("你好世界", 2), "你好");
+        // The emoji with the modifier and the combining marks count as one.
+        assert_eq!(truncate_graphemes("👍🏽👍🏽👍🏽", 2), "👍🏽👍🏽");
+        assert_eq!(truncate_graphemes("e\u{301}e\u{301}", 1), "e\u{301}");
+        assert_eq!(truncate_graphemes("👨‍👩‍👧a", 1), "👨‍👩‍👧");
     }
 }
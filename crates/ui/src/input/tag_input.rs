@@ -0,0 +1,415 @@
+use gpui::{
+    div, prelude::FluentBuilder as _, px, App, AppContext as _, Context, ElementId, Empty, Entity,
+    EventEmitter, FocusHandle, Focusable, InteractiveElement as _, IntoElement, KeyBinding,
+    MouseButton, ParentElement, Render, RenderOnce, SharedString, StatefulInteractiveElement as _,
+    StyleRefinement, Styled, Subscription, Window,
+};
+
+use crate::{
+    actions::Confirm, h_flex, tag::Tag, ActiveTheme, Icon, IconName, Sizable, Size,
+    StyleSized as _, StyledExt as _,
+};
+
+use super::{Backspace, InputEvent, InputState, Paste, TextInput};
+
+const CONTEXT: &str = "TagInput";
+
+pub fn init(cx: &mut App) {
+    cx.bind_keys([KeyBinding::new(
+        "enter",
+        Confirm { secondary: false },
+        Some(CONTEXT),
+    )])
+}
+
+/// Events emitted by the [`TagInputState`].
+#[derive(Clone)]
+pub enum TagInputEvent {
+    /// The tags have been changed, the argument is all the tags.
+    Change(Vec<String>),
+}
+
+/// State of the [`TagInput`].
+pub struct TagInputState {
+    input: Entity<InputState>,
+    tags: Vec<String>,
+    /// The last known text, to ignore the change event of setting the value by code.
+    query: SharedString,
+    max_tags: Option<usize>,
+    dedup: bool,
+    validate: Option<Box<dyn Fn(&str) -> bool + 'static>>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl TagInputState {
+    pub fn new(window: &mut Window, cx: &mut Context<Self>) -> Self {
+        let input = cx.new(|cx| InputState::new(window, cx));
+        let _subscriptions = vec![cx.subscribe_in(&input, window, Self::on_input_event)];
+
+        Self {
+            input,
+            tags: Vec::new(),
+            query: SharedString::default(),
+            max_tags: None,
+            dedup: false,
+            validate: None,
+            _subscriptions,
+        }
+    }
+
+    /// Set the default tags.
+    pub fn default_tags(mut self, tags: impl IntoIterator<Item = impl Into<String>>) -> Self {
+        self.tags = tags.into_iter().map(Into::into).collect();
+        self
+    }
+
+    /// Set the placeholder of the input.
+    pub fn placeholder(
+        self,
+        placeholder: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> Self {
+        self.input.update(cx, |input, cx| {
+            input.set_placeholder(placeholder, window, cx);
+        });
+        self
+    }
+
+    /// Set the max number of tags, default: None (no limit).
+    pub fn max_tags(mut self, max_tags: usize) -> Self {
+        self.max_tags = Some(max_tags);
+        self
+    }
+
+    /// Set true to ignore the tags that already exist, default: false
+    pub fn dedup(mut self, dedup: bool) -> Self {
+        self.dedup = dedup;
+        self
+    }
+
+    /// Set a validate function, the tag will be rejected if it returns false.
+    ///
+    /// The rejected text is kept in the input, so the user can fix it.
+    pub fn validate(mut self, f: impl Fn(&str) -> bool + 'static) -> Self {
+        self.validate = Some(Box::new(f));
+        self
+    }
+
+    /// Returns the [`InputState`] of the text field.
+    pub fn input(&self) -> &Entity<InputState> {
+        &self.input
+    }
+
+    /// Returns the tags.
+    pub fn tags(&self) -> &[String] {
+        &self.tags
+    }
+
+    /// Replace all the tags, this will not emit [`TagInputEvent::Change`].
+    pub fn set_tags(
+        &mut self,
+        tags: impl IntoIterator<Item = impl Into<String>>,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.tags = tags.into_iter().map(Into::into).collect();
+        cx.notify();
+    }
+
+    /// Remove the tag at the given index.
+    pub fn remove_tag(&mut self, ix: usize, _: &mut Window, cx: &mut Context<Self>) {
+        if ix >= self.tags.len() {
+            return;
+        }
+
+        self.tags.remove(ix);
+        cx.emit(TagInputEvent::Change(self.tags.clone()));
+        cx.notify();
+    }
+
+    pub fn focus(&self, window: &mut Window, cx: &mut App) {
+        self.input.focus_handle(cx).focus(window);
+    }
+
+    fn is_full(&self) -> bool {
+        self.max_tags
+            .map_or(false, |max_tags| self.tags.len() >= max_tags)
+    }
+
+    /// Push a tag, returns false if the tag is rejected.
+    ///
+    /// The duplicate tag is not rejected in dedup mode, it is just ignored.
+    fn push_tag(&mut self, tag: &str) -> bool {
+        let tag = tag.trim();
+        if tag.is_empty() {
+            return true;
+        }
+        if self.dedup && self.tags.iter().any(|t| t == tag) {
+            return true;
+        }
+        if self.is_full() || !self.validate.as_ref().map_or(true, |f| f(tag)) {
+            return false;
+        }
+
+        self.tags.push(tag.to_string());
+        true
+    }
+
+    /// Push the tags and update the input text with the rejected ones and the remaining text.
+    fn push_tags<'a>(
+        &mut self,
+        tags: impl IntoIterator<Item = &'a str>,
+        remaining: &str,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let old_len = self.tags.len();
+        let mut rest = tags
+            .into_iter()
+            .filter(|tag| !self.push_tag(tag))
+            .map(|tag| tag.trim())
+            .collect::<Vec<_>>();
+        if !remaining.is_empty() {
+            rest.push(remaining);
+        }
+
+        let text: SharedString = rest.join(", ").into();
+        self.query = text.clone();
+        self.input.update(cx, |input, cx| {
+            input.set_value(text, window, cx);
+        });
+
+        if self.tags.len() != old_len {
+            cx.emit(TagInputEvent::Change(self.tags.clone()));
+        }
+        cx.notify();
+    }
+
+    fn on_input_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let InputEvent::Change(text) = event else {
+            return;
+        };
+        if *text == self.query {
+            return;
+        }
+
+        self.query = text.clone();
+        if let Some((tags, remaining)) = split_tags(text) {
+            self.push_tags(tags, remaining, window, cx);
+        }
+    }
+
+    fn on_action_confirm(&mut self, _: &Confirm, window: &mut Window, cx: &mut Context<Self>) {
+        let text = self.input.read(cx).value();
+        if text.trim().is_empty() {
+            // Let the parent to handle the Enter, e.g.: submit form.
+            cx.propagate();
+            return;
+        }
+
+        self.push_tags([text.as_str()], "", window, cx);
+    }
+
+    fn on_action_backspace(&mut self, _: &Backspace, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.input.read(cx).value().is_empty() || self.tags.is_empty() {
+            return;
+        }
+
+        cx.stop_propagation();
+        self.remove_tag(self.tags.len() - 1, window, cx);
+    }
+
+    fn on_action_paste(&mut self, _: &Paste, window: &mut Window, cx: &mut Context<Self>) {
+        // The single line input will remove the newlines, so we split the text here.
+        let Some(text) = cx.read_from_clipboard().and_then(|item| item.text()) else {
+            return;
+        };
+        if !text.contains([',', '\n']) {
+            return;
+        }
+
+        cx.stop_propagation();
+        let text = format!("{}{}", self.input.read(cx).value(), text);
+        if let Some((tags, remaining)) = split_tags(&text) {
+            self.push_tags(tags, remaining, window, cx);
+        }
+    }
+}
+
+/// Split the text by comma or newline, returns the completed tags and the remaining text,
+/// or None if there is no separator.
+fn split_tags(text: &str) -> Option<(Vec<&str>, &str)> {
+    let ix = text.rfind([',', '\n'])?;
+    let tags = text[..ix]
+        .split([',', '\n'])
+        .map(|tag| tag.trim())
+        .filter(|tag| !tag.is_empty())
+        .collect();
+
+    Some((tags, text[ix + 1..].trim_start()))
+}
+
+impl EventEmitter<TagInputEvent> for TagInputState {}
+impl Focusable for TagInputState {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.input.focus_handle(cx)
+    }
+}
+
+impl Render for TagInputState {
+    fn render(&mut self, _: &mut Window, _: &mut Context<Self>) -> impl IntoElement {
+        Empty
+    }
+}
+
+/// An input to enter multiple values as removable tags, e.g.: email recipients.
+///
+/// Press Enter or type a comma to create a tag, press Backspace on the empty input
+/// to remove the last tag, and paste the comma or newline separated text to create many.
+#[derive(IntoElement)]
+pub struct TagInput {
+    id: ElementId,
+    style: StyleRefinement,
+    state: Entity<TagInputState>,
+    size: Size,
+    disabled: bool,
+}
+
+impl TagInput {
+    pub fn new(state: &Entity<TagInputState>) -> Self {
+        Self {
+            id: ("tag-input", state.entity_id()).into(),
+            style: StyleRefinement::default(),
+            state: state.clone(),
+            size: Size::default(),
+            disabled: false,
+        }
+    }
+
+    /// Set the disable state for the tag input.
+    pub fn disabled(mut self, disabled: bool) -> Self {
+        self.disabled = disabled;
+        self
+    }
+}
+
+impl Sizable for TagInput {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl Styled for TagInput {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl Focusable for TagInput {
+    fn focus_handle(&self, cx: &App) -> FocusHandle {
+        self.state.focus_handle(cx)
+    }
+}
+
+impl RenderOnce for TagInput {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = self.state.read(cx);
+        let focused = state.focus_handle(cx).is_focused(window);
+        let tag_size = match self.size {
+            Size::Large => Size::Medium,
+            _ => Size::Small,
+        };
+
+        h_flex()
+            .id(self.id)
+            .key_context(CONTEXT)
+            .when(!self.disabled, |this| {
+                this.on_action(window.listener_for(&self.state, TagInputState::on_action_confirm))
+                    .capture_action(
+                        window.listener_for(&self.state, TagInputState::on_action_backspace),
+                    )
+                    .capture_action(
+                        window.listener_for(&self.state, TagInputState::on_action_paste),
+                    )
+            })
+            .w_full()
+            .flex_wrap()
+            .gap_1()
+            .py_1()
+            .px(self.size.input_px() / 2.)
+            .bg(cx.theme().background)
+            .border_1()
+            .border_color(cx.theme().input)
+            .rounded(cx.theme().radius)
+            .when(cx.theme().shadow, |this| this.shadow_xs())
+            .when(self.disabled, |this| {
+                this.bg(cx.theme().muted).shadow_none()
+            })
+            .when(focused && !self.disabled, |this| this.focused_border(cx))
+            .refine_style(&self.style)
+            .on_click({
+                let focus_handle = state.focus_handle(cx);
+                move |_, window, _| focus_handle.focus(window)
+            })
+            .children(state.tags.iter().enumerate().map(|(ix, tag)| {
+                Tag::secondary().with_size(tag_size).child(
+                    h_flex()
+                        .gap_1()
+                        .child(tag.clone())
+                        .when(!self.disabled, |this| {
+                            this.child(
+                                div()
+                                    .id(("remove-tag", ix))
+                                    .cursor_pointer()
+                                    .text_color(cx.theme().muted_foreground)
+                                    .hover(|this| this.text_color(cx.theme().foreground))
+                                    .child(Icon::new(IconName::Close).size(px(10.)))
+                                    .on_mouse_down(MouseButton::Left, |_, _, cx| {
+                                        cx.stop_propagation()
+                                    })
+                                    .on_click(window.listener_for(
+                                        &self.state,
+                                        move |state, _, window, cx| {
+                                            state.remove_tag(ix, window, cx);
+                                        },
+                                    )),
+                            )
+                        }),
+                )
+            }))
+            .child(
+                div().flex_1().min_w_24().child(
+                    TextInput::new(&state.input)
+                        .appearance(false)
+                        .with_size(self.size)
+                        .disabled(self.disabled)
+                        .px_1(),
+                ),
+            )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::split_tags;
+
+    #[test]
+    fn test_split_tags() {
+        assert_eq!(split_tags("foo"), None);
+        assert_eq!(split_tags("foo,"), Some((vec!["foo"], "")));
+        assert_eq!(split_tags("foo, bar"), Some((vec!["foo"], "bar")));
+        assert_eq!(
+            split_tags("a@b.com\n c@d.com,, e@f.com\n"),
+            Some((vec!["a@b.com", "c@d.com", "e@f.com"], ""))
+        );
+        assert_eq!(split_tags(" , "), Some((vec![], "")));
+    }
+}
@@ -1,14 +1,17 @@
 use gpui::prelude::FluentBuilder as _;
 use gpui::{
-    div, px, relative, AnyElement, App, DefiniteLength, Entity, InteractiveElement as _,
-    IntoElement, IsZero, MouseButton, ParentElement as _, Rems, RenderOnce, StyleRefinement,
-    Styled, Window,
+    div, px, relative, AnyElement, App, DefiniteLength, Entity, ExternalPaths,
+    InteractiveElement as _, IntoElement, IsZero, MouseButton, ParentElement as _, Rems,
+    RenderOnce, StyleRefinement, Styled, Window,
 };
 
 use crate::button::{Button, ButtonVariants as _};
+use crate::context_menu::ContextMenuExt as _;
 use crate::indicator::Indicator;
 use crate::input::clear_button;
+use crate::input::drop_files::is_accepted_file;
 use crate::input::element::{LINE_NUMBER_RIGHT_MARGIN, RIGHT_MARGIN};
+use crate::input::spellcheck::spellcheck_menu;
 use crate::scroll::Scrollbar;
 use crate::ActiveTheme;
 use crate::{h_flex, StyledExt};
@@ -28,6 +31,7 @@ pub struct TextInput {
     appearance: bool,
     cleanable: bool,
     mask_toggle: bool,
+    show_count: bool,
     disabled: bool,
     bordered: bool,
     focus_bordered: bool,
@@ -53,17 +57,25 @@ impl TextInput {
             appearance: true,
             cleanable: false,
             mask_toggle: false,
+            show_count: false,
             disabled: false,
             bordered: true,
             focus_bordered: true,
         }
     }
 
+    /// Set the prefix element inside the input borders, e.g.: a search icon or a currency symbol.
+    ///
+    /// The prefix is not a part of the value, clicking it focuses the input,
+    /// unless it is an interactive element like a [`Button`].
     pub fn prefix(mut self, prefix: impl IntoElement) -> Self {
         self.prefix = Some(prefix.into_any_element());
         self
     }
 
+    /// Set the suffix element inside the input borders, e.g.: an unit, it is placed after the clear button.
+    ///
+    /// See also [`TextInput::prefix`].
     pub fn suffix(mut self, suffix: impl IntoElement) -> Self {
         self.suffix = Some(suffix.into_any_element());
         self
@@ -111,6 +123,14 @@ impl TextInput {
         self
     }
 
+    /// Set to show the character count, e.g.: `120/280` with the [`InputState::max_length`].
+    ///
+    /// The count turns to the warning color when near or over the max length.
+    pub fn show_count(mut self) -> Self {
+        self.show_count = true;
+        self
+    }
+
     /// Set to disable the input field.
     pub fn disabled(mut self, disabled: bool) -> Self {
         self.disabled = disabled;
@@ -178,7 +198,22 @@ impl RenderOnce for TextInput {
             && !state.loading
             && state.text.len_bytes() > 0
             && state.mode.is_single_line();
-        let has_suffix = suffix.is_some() || state.loading || self.mask_toggle || show_clear_button;
+        let count = self.show_count.then(|| {
+            let length = state.text_length();
+            match state.max_length {
+                // Near the limit when 90% of the max length is used.
+                Some(max_length) => (
+                    format!("{}/{}", length, max_length),
+                    length * 10 >= max_length * 9,
+                ),
+                None => (length.to_string(), false),
+            }
+        });
+        let has_suffix = suffix.is_some()
+            || state.loading
+            || self.mask_toggle
+            || show_clear_button
+            || count.is_some();
 
         div()
             .id(("input", self.state.entity_id()))
@@ -200,12 +235,23 @@ impl RenderOnce for TextInput {
                     .on_action(window.listener_for(&self.state, InputState::cut))
                     .on_action(window.listener_for(&self.state, InputState::undo))
                     .on_action(window.listener_for(&self.state, InputState::redo))
+                    .on_action(window.listener_for(&self.state, InputState::transpose))
+                    .on_action(window.listener_for(&self.state, InputState::convert_to_upper_case))
+                    .on_action(window.listener_for(&self.state, InputState::convert_to_lower_case))
+                    .on_action(window.listener_for(&self.state, InputState::convert_to_capitalize))
                     .when(state.mode.is_multi_line(), |this| {
                         this.on_action(window.listener_for(&self.state, InputState::indent_inline))
                             .on_action(window.listener_for(&self.state, InputState::outdent_inline))
                             .on_action(window.listener_for(&self.state, InputState::indent_block))
                             .on_action(window.listener_for(&self.state, InputState::outdent_block))
                     })
+                    .when(state.rich_text.is_some(), |this| {
+                        this.on_action(window.listener_for(&self.state, InputState::toggle_bold))
+                            .on_action(window.listener_for(&self.state, InputState::toggle_italic))
+                            .on_action(
+                                window.listener_for(&self.state, InputState::toggle_underline),
+                            )
+                    })
             })
             .on_action(window.listener_for(&self.state, InputState::left))
             .on_action(window.listener_for(&self.state, InputState::right))
@@ -233,7 +279,13 @@ impl RenderOnce for TextInput {
             .on_action(window.listener_for(&self.state, InputState::select_to_start))
             .on_action(window.listener_for(&self.state, InputState::select_to_end))
             .on_action(window.listener_for(&self.state, InputState::show_character_palette))
+            .on_action(window.listener_for(&self.state, InputState::show_completion))
             .on_action(window.listener_for(&self.state, InputState::copy))
+            .when(state.mode.is_code_editor(), |this| {
+                this.on_action(window.listener_for(&self.state, InputState::toggle_go_to_line))
+                    .on_action(window.listener_for(&self.state, InputState::navigate_back))
+                    .on_action(window.listener_for(&self.state, InputState::navigate_forward))
+            })
             .on_key_down(window.listener_for(&self.state, InputState::on_key_down))
             .on_mouse_down(
                 MouseButton::Left,
@@ -267,15 +319,47 @@ impl RenderOnce for TextInput {
                             })
                     })
             })
+            .when(state.accept_files && !state.disabled, |this| {
+                let extensions = state.file_extensions.clone();
+                this.drag_over::<ExternalPaths>(move |this, paths, _, cx| {
+                    // Highlight the drop target in danger if any file will be rejected.
+                    let accepted = paths
+                        .paths()
+                        .iter()
+                        .all(|path| is_accepted_file(path, &extensions));
+                    this.bg(cx.theme().drop_target).border_color(if accepted {
+                        cx.theme().drag_border
+                    } else {
+                        cx.theme().danger
+                    })
+                })
+                .on_drop(window.listener_for(&self.state, InputState::on_drop_files))
+            })
             .input_px(self.size)
             .items_center()
             .gap(gap_x)
-            .children(prefix)
+            .when_some(prefix, |this, prefix| {
+                this.child(
+                    h_flex()
+                        .id("prefix")
+                        .flex_shrink_0()
+                        .items_center()
+                        .cursor_default()
+                        .child(prefix),
+                )
+            })
+            // The context menu is painted before the text, see `TextElement::paint_mouse_listeners`.
+            .when(state.spellcheck.is_enabled() && !state.disabled, |this| {
+                let state = self.state.clone();
+                this.context_menu(move |menu, _, cx| spellcheck_menu(&state, menu, cx))
+            })
             .child(self.state.clone())
             .when(has_suffix, |this| {
                 this.pr(self.size.input_px() / 2.).child(
                     h_flex()
                         .id("suffix")
+                        .flex_shrink_0()
+                        .cursor_default()
                         .gap(gap_x)
                         .when(self.appearance, |this| this.bg(bg))
                         .items_center()
@@ -291,10 +375,23 @@ impl RenderOnce for TextInput {
                                 move |_, window, cx| {
                                     state.update(cx, |state, cx| {
                                         state.clean(window, cx);
+                                        state.focus(window, cx);
                                     })
                                 }
                             }))
                         })
+                        .when_some(count, |this, (count, near_limit)| {
+                            this.child(
+                                div()
+                                    .text_xs()
+                                    .text_color(if near_limit {
+                                        cx.theme().warning
+                                    } else {
+                                        cx.theme().muted_foreground
+                                    })
+                                    .child(count),
+                            )
+                        })
                         .children(suffix),
                 )
             })
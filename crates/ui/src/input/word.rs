@@ -0,0 +1,213 @@
+use std::ops::Range;
+
+use unicode_segmentation::UnicodeSegmentation as _;
+
+/// Returns the ranges of the subwords in the `word`, split at the `_` and the
+/// camelCase humps, e.g.: `parseHTTPRequest_v2` is `parse`, `HTTP`, `Request`, `_`, `v2`.
+pub(super) fn subword_ranges(word: &str) -> Vec<Range<usize>> {
+    let chars = word.char_indices().collect::<Vec<_>>();
+    let mut ranges = vec![];
+    let mut start = 0;
+
+    for ix in 1..chars.len() {
+        let (offset, c) = chars[ix];
+        let prev = chars[ix - 1].1;
+        let next = chars.get(ix + 1).map(|(_, c)| *c);
+
+        // The last upper case of an acronym starts the next hump: `HTTPRequest`.
+        let acronym_end =
+            prev.is_uppercase() && c.is_uppercase() && next.is_some_and(|c| c.is_lowercase());
+        let is_boundary =
+            (prev == '_') != (c == '_') || (prev.is_lowercase() && c.is_uppercase()) || acronym_end;
+        if is_boundary {
+            ranges.push(start..offset);
+            start = offset;
+        }
+    }
+
+    if start < word.len() {
+        ranges.push(start..word.len());
+    }
+    ranges
+}
+
+/// Returns the start offset of the word before the end of the `text`.
+///
+/// If `subword` is true, stop at the start of the subwords, see [`subword_ranges`].
+pub(super) fn previous_word_start(text: &str, subword: bool) -> usize {
+    let Some((ix, word)) = text
+        .split_word_bound_indices()
+        .filter(|(_, s)| !s.trim_start().is_empty())
+        .next_back()
+    else {
+        return 0;
+    };
+
+    if !subword {
+        return ix;
+    }
+
+    subword_ranges(word)
+        .into_iter()
+        .filter(|range| !word[range.clone()].chars().all(|c| c == '_'))
+        .next_back()
+        .map(|range| ix + range.start)
+        .unwrap_or(ix)
+}
+
+/// Returns the end offset of the word after the start of the `text`, or the length
+/// of the `text` if there is no word.
+///
+/// If `subword` is true, stop at the end of the subwords, see [`subword_ranges`].
+pub(super) fn next_word_end(text: &str, subword: bool) -> usize {
+    let Some((ix, word)) = text
+        .split_word_bound_indices()
+        .find(|(_, s)| !s.trim_start().is_empty())
+    else {
+        return text.len();
+    };
+
+    if !subword {
+        return ix + word.len();
+    }
+
+    subword_ranges(word)
+        .into_iter()
+        .find(|range| !word[range.clone()].chars().all(|c| c == '_'))
+        .map(|range| ix + range.end)
+        .unwrap_or(ix + word.len())
+}
+
+/// Returns the range of the word at the `offset` in the `text`, the range is empty if
+/// there is no word at the `offset`.
+pub(super) fn word_range(text: &str, offset: usize) -> Range<usize> {
+    let is_word = |c: char| c.is_alphanumeric() || c == '_';
+
+    let start = text[..offset]
+        .char_indices()
+        .rev()
+        .take_while(|(_, c)| is_word(*c))
+        .last()
+        .map(|(ix, _)| ix)
+        .unwrap_or(offset);
+    let end = text[offset..]
+        .char_indices()
+        .find(|(_, c)| !is_word(*c))
+        .map(|(ix, _)| offset + ix)
+        .unwrap_or(text.len());
+
+    start..end
+}
+
+/// Returns the range and the text to swap the chars around the `offset` of the `line`,
+/// the last two chars are swapped at the end of the line, like Emacs.
+///
+/// Returns None if there are less than two chars to swap.
+pub(super) fn transpose(line: &str, offset: usize) -> Option<(Range<usize>, String)> {
+    let mut offset = offset.min(line.len());
+    if offset == line.len() {
+        offset = line[..offset].char_indices().next_back()?.0;
+    }
+
+    let (prev_ix, prev) = line[..offset].char_indices().next_back()?;
+    let next = line[offset..].chars().next()?;
+    let range = prev_ix..offset + next.len_utf8();
+
+    Some((range, format!("{}{}", next, prev)))
+}
+
+/// Returns the `text` with the first letter of each word in upper case and the
+/// rest in lower case.
+pub(super) fn to_capitalize(text: &str) -> String {
+    text.split_word_bounds()
+        .map(|word| {
+            let mut chars = word.chars();
+            match chars.next() {
+                Some(first) => first
+                    .to_uppercase()
+                    .chain(chars.flat_map(char::to_lowercase))
+                    .collect(),
+                None => String::new(),
+            }
+        })
+        .collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn subwords(word: &str) -> Vec<&str> {
+        subword_ranges(word)
+            .into_iter()
+            .map(|range| &word[range])
+            .collect()
+    }
+
+    #[test]
+    fn test_subword_ranges() {
+        assert_eq!(subwords("hello"), vec!["hello"]);
+        assert_eq!(subwords("camelCase"), vec!["camel", "Case"]);
+        assert_eq!(subwords("PascalCase"), vec!["Pascal", "Case"]);
+        assert_eq!(subwords("snake_case"), vec!["snake", "_", "case"]);
+        assert_eq!(subwords("__init__"), vec!["__", "init", "__"]);
+        assert_eq!(
+            subwords("parseHTTPRequest_v2"),
+            vec!["parse", "HTTP", "Request", "_", "v2"]
+        );
+        assert_eq!(subwords("HTTP"), vec!["HTTP"]);
+        assert!(subwords("").is_empty());
+    }
+
+    #[test]
+    fn test_previous_word_start() {
+        assert_eq!(previous_word_start("", false), 0);
+        assert_eq!(previous_word_start("   ", false), 0);
+        assert_eq!(previous_word_start("let fooBar", false), 4);
+        assert_eq!(previous_word_start("let fooBar  ", false), 4);
+        assert_eq!(previous_word_start("let fooBar", true), 7);
+        assert_eq!(previous_word_start("let foo", true), 4);
+        assert_eq!(previous_word_start("foo_bar", true), 4);
+        assert_eq!(previous_word_start("foo_", true), 0);
+        assert_eq!(previous_word_start("__", true), 0);
+    }
+
+    #[test]
+    fn test_next_word_end() {
+        assert_eq!(next_word_end("", false), 0);
+        assert_eq!(next_word_end("   ", false), 3);
+        assert_eq!(next_word_end("fooBar baz", false), 6);
+        assert_eq!(next_word_end("  fooBar", false), 8);
+        assert_eq!(next_word_end("fooBar baz", true), 3);
+        assert_eq!(next_word_end("Bar baz", true), 3);
+        assert_eq!(next_word_end("_bar", true), 4);
+        assert_eq!(next_word_end("__", true), 2);
+    }
+
+    #[test]
+    fn test_word_range() {
+        assert_eq!(word_range("hello world", 0), 0..5);
+        assert_eq!(word_range("hello world", 3), 0..5);
+        assert_eq!(word_range("hello world", 5), 0..5);
+        assert_eq!(word_range("hello world", 6), 6..11);
+        assert_eq!(word_range("a  b", 2), 2..2);
+        assert_eq!(word_range("你好 world", 3), 0..6);
+    }
+
+    #[test]
+    fn test_transpose() {
+        assert_eq!(transpose("abc", 1), Some((0..2, "ba".to_string())));
+        assert_eq!(transpose("abc", 3), Some((1..3, "cb".to_string())));
+        assert_eq!(transpose("a你", 1), Some((0..4, "你a".to_string())));
+        assert_eq!(transpose("abc", 0), None);
+        assert_eq!(transpose("a", 1), None);
+        assert_eq!(transpose("", 0), None);
+    }
+
+    #[test]
+    fn test_to_capitalize() {
+        assert_eq!(to_capitalize("hello wORLD"), "Hello World");
+        assert_eq!(to_capitalize("foo-bar baz_qux"), "Foo-Bar Baz_qux");
+        assert_eq!(to_capitalize(""), "");
+    }
+}
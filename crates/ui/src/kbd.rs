@@ -1,37 +1,72 @@
 use gpui::{
-    div, relative, Action, AsKeystroke, IntoElement, KeyContext, Keystroke, ParentElement as _,
-    RenderOnce, StyleRefinement, Styled, Window,
+    div, relative, Action, AsKeystroke, IntoElement, KeyBinding, KeyContext, Keystroke,
+    ParentElement as _, RenderOnce, StyleRefinement, Styled, Window,
 };
 
-use crate::{ActiveTheme, StyledExt};
+use crate::{h_flex, ActiveTheme, StyledExt};
 
-/// A key binding tag
+/// A key binding tag, renders each keystroke of the key binding as a key cap.
 #[derive(IntoElement, Clone, Debug)]
 pub struct Kbd {
     style: StyleRefinement,
-    stroke: Keystroke,
+    strokes: Vec<Keystroke>,
     appearance: bool,
 }
 
 impl From<Keystroke> for Kbd {
     fn from(stroke: Keystroke) -> Self {
-        Self {
-            style: StyleRefinement::default(),
-            stroke,
-            appearance: true,
-        }
+        Self::new(stroke)
+    }
+}
+
+impl From<&KeyBinding> for Kbd {
+    fn from(binding: &KeyBinding) -> Self {
+        Self::from_binding(binding)
     }
 }
 
 impl Kbd {
     pub fn new(stroke: Keystroke) -> Self {
+        Self::with_strokes(vec![stroke])
+    }
+
+    fn with_strokes(strokes: Vec<Keystroke>) -> Self {
         Self {
             style: StyleRefinement::default(),
-            stroke,
+            strokes,
             appearance: true,
         }
     }
 
+    /// Create a [`Kbd`] with all the keystrokes of the key binding, e.g.: `cmd-k cmd-s`.
+    pub fn from_binding(binding: &KeyBinding) -> Self {
+        Self::with_strokes(
+            binding
+                .keystrokes()
+                .iter()
+                .map(|key| key.as_keystroke().clone())
+                .collect(),
+        )
+    }
+
+    /// Parse the keystrokes separated by spaces, e.g.: `cmd-shift-p` or `cmd-k cmd-s`.
+    ///
+    /// Use the `secondary` modifier for `cmd` on macOS and `ctrl` on Windows and Linux,
+    /// e.g.: `secondary-shift-p`.
+    ///
+    /// Returns None if any keystroke is invalid.
+    pub fn parse(source: &str) -> Option<Self> {
+        let strokes = source
+            .split_whitespace()
+            .map(|source| Keystroke::parse(source).ok())
+            .collect::<Option<Vec<_>>>()?;
+        if strokes.is_empty() {
+            return None;
+        }
+
+        Some(Self::with_strokes(strokes))
+    }
+
     /// Set the appearance of the keybinding.
     pub fn appearance(mut self, appearance: bool) -> Self {
         self.appearance = appearance;
@@ -51,14 +86,23 @@ impl Kbd {
         };
 
         bindings.first().and_then(|binding| {
-            if let Some(key) = binding.keystrokes().first() {
-                Some(Self::new(key.as_keystroke().clone()))
-            } else {
+            if binding.keystrokes().is_empty() {
                 None
+            } else {
+                Some(Self::from_binding(binding))
             }
         })
     }
 
+    /// Return the Platform specific string of the keystrokes, separated by spaces.
+    pub fn format_strokes<'a>(strokes: impl IntoIterator<Item = &'a Keystroke>) -> String {
+        strokes
+            .into_iter()
+            .map(Self::format)
+            .collect::<Vec<_>>()
+            .join(" ")
+    }
+
     /// Return the Platform specific keybinding string by KeyStroke
     ///
     /// macOS: https://support.apple.com/en-us/HT201236
@@ -188,23 +232,33 @@ impl Styled for Kbd {
 impl RenderOnce for Kbd {
     fn render(self, _: &mut gpui::Window, cx: &mut gpui::App) -> impl gpui::IntoElement {
         if !self.appearance {
-            return Self::format(&self.stroke).into_any_element();
+            return Self::format_strokes(&self.strokes).into_any_element();
+        }
+
+        let key_cap = |stroke: &Keystroke| {
+            div()
+                .border_1()
+                .border_color(cx.theme().border)
+                .text_color(cx.theme().muted_foreground)
+                .bg(cx.theme().background)
+                .py_0p5()
+                .px_1()
+                .min_w_5()
+                .text_center()
+                .rounded_sm()
+                .line_height(relative(1.))
+                .text_xs()
+                .child(Self::format(stroke))
+        };
+
+        if let [stroke] = self.strokes.as_slice() {
+            return key_cap(stroke).refine_style(&self.style).into_any_element();
         }
 
-        div()
-            .border_1()
-            .border_color(cx.theme().border)
-            .text_color(cx.theme().muted_foreground)
-            .bg(cx.theme().background)
-            .py_0p5()
-            .px_1()
-            .min_w_5()
-            .text_center()
-            .rounded_sm()
-            .line_height(relative(1.))
-            .text_xs()
+        h_flex()
+            .gap_1()
             .refine_style(&self.style)
-            .child(Self::format(&self.stroke))
+            .children(self.strokes.iter().map(key_cap))
             .into_any_element()
     }
 }
@@ -255,6 +309,10 @@ mod tests {
                 "⌃⌥⇧⌘A"
             );
         } else {
+            assert_eq!(
+                Kbd::format(&Keystroke::parse("secondary-shift-p").unwrap()),
+                "Ctrl+Shift+P"
+            );
             assert_eq!(Kbd::format(&Keystroke::parse("a").unwrap()), "A");
             assert_eq!(Kbd::format(&Keystroke::parse("ctrl-a").unwrap()), "Ctrl+A");
             assert_eq!(
@@ -287,4 +345,23 @@ mod tests {
             );
         }
     }
+
+    #[test]
+    fn test_parse() {
+        use super::Kbd;
+
+        let kbd = Kbd::parse("cmd-k  cmd-s").unwrap();
+        assert_eq!(kbd.strokes.len(), 2);
+        assert_eq!(
+            Kbd::format_strokes(&kbd.strokes),
+            format!(
+                "{} {}",
+                Kbd::format(&kbd.strokes[0]),
+                Kbd::format(&kbd.strokes[1])
+            )
+        );
+        assert_eq!(Kbd::parse("enter").unwrap().strokes.len(), 1);
+        assert!(Kbd::parse("").is_none());
+        assert!(Kbd::parse("  ").is_none());
+    }
 }
@@ -0,0 +1,86 @@
+//! The key bindings scoped to the key contexts of the components.
+//!
+//! A component pushes a named key context on its element, e.g.: `.key_context("Modal")`,
+//! or by [`KeyContextExt::key_context_when`] only when it is active, and declares its key
+//! bindings in the context by [`ContextBindings`].
+//!
+//! ## Resolution order
+//!
+//! The key contexts from the root to the focused element are a stack, e.g.:
+//! `Modal > Popover > Input` for an input in a popover of a modal. For a keystroke:
+//!
+//! 1. The bindings of the contexts in the stack are matched, the binding of the most
+//!    specific (innermost) context comes first, e.g.: `escape` is `input::Escape` in the
+//!    `Input`, then `Cancel` in the `Popover`, and then `Cancel` in the `Modal`.
+//!    In the same context, the binding registered later takes precedence.
+//! 2. The action of the first binding is dispatched from the focused element to the root,
+//!    the first handler of the action handles it and stops it.
+//! 3. A handler that does nothing for the key, e.g.: the dropdown is already closed, calls
+//!    `cx.propagate()` to let the action bubble to the outer elements, if no handler stops
+//!    it, the key falls through to the next binding of the outer context.
+//!
+//! So a handler should only call `cx.propagate()` when it does not handle the key, for
+//! example, the Escape that closes a popover must not propagate, otherwise it also closes
+//! the modal of the popover.
+use gpui::{Action, App, InteractiveElement, KeyBinding};
+
+/// The key bindings declared in a key context of a component.
+///
+/// ```ignore
+/// const CONTEXT: &str = "Popover";
+///
+/// pub fn init(cx: &mut App) {
+///     ContextBindings::new(CONTEXT)
+///         .bind("escape", Cancel)
+///         .bind("secondary-enter", Confirm { secondary: true })
+///         .register(cx);
+/// }
+/// ```
+pub struct ContextBindings {
+    context: &'static str,
+    bindings: Vec<KeyBinding>,
+}
+
+impl ContextBindings {
+    /// Create the bindings of the key `context`, the context pushed by the component element.
+    pub fn new(context: &'static str) -> Self {
+        Self {
+            context,
+            bindings: vec![],
+        }
+    }
+
+    /// Bind the `keystrokes` to the `action` in the context, e.g.: `escape`, `cmd-k cmd-s`.
+    ///
+    /// Use the `secondary` modifier for `cmd` on macOS and `ctrl` on Windows and Linux.
+    pub fn bind<A: Action>(mut self, keystrokes: &str, action: A) -> Self {
+        self.bindings
+            .push(KeyBinding::new(keystrokes, action, Some(self.context)));
+        self
+    }
+
+    /// Returns the key context of the bindings.
+    pub fn context(&self) -> &'static str {
+        self.context
+    }
+
+    /// Register the bindings to the app.
+    pub fn register(self, cx: &mut App) {
+        cx.bind_keys(self.bindings);
+    }
+}
+
+/// Extends the [`InteractiveElement`] to push the key context of a component.
+pub trait KeyContextExt: InteractiveElement + Sized {
+    /// Push the key `context` only when the component is `active`, e.g.: a panel is opened,
+    /// so its bindings are not resolved while it is inactive.
+    fn key_context_when(self, active: bool, context: &'static str) -> Self {
+        if active {
+            self.key_context(context)
+        } else {
+            self
+        }
+    }
+}
+
+impl<E: InteractiveElement> KeyContextExt for E {}
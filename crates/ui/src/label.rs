@@ -5,7 +5,7 @@ use gpui::{
     SharedString, StyleRefinement, Styled, StyledText, Window,
 };
 
-use crate::{ActiveTheme, StyledExt};
+use crate::{text::TextView, ActiveTheme, StyledExt};
 
 const MASKED: &'static str = "•";
 
@@ -16,6 +16,7 @@ pub struct Label {
     secondary: Option<SharedString>,
     masked: bool,
     highlights_text: Option<SharedString>,
+    selectable: bool,
 }
 
 impl Label {
@@ -27,6 +28,7 @@ impl Label {
             secondary: None,
             masked: false,
             highlights_text: None,
+            selectable: false,
         }
     }
 
@@ -47,6 +49,18 @@ impl Label {
         self
     }
 
+    /// Set the label to be selectable, default is false.
+    ///
+    /// The text can be selected by mouse and copied by `cmd-c` (`ctrl-c`), but not editable.
+    /// The masked label is never selectable.
+    ///
+    /// The selection state is keyed by the text, so the labels with the same text
+    /// should be placed in parents with different ids (e.g. list rows or table cells).
+    pub fn selectable(mut self, selectable: bool) -> Self {
+        self.selectable = selectable;
+        self
+    }
+
     fn full_text(&self) -> SharedString {
         match &self.secondary {
             Some(secondary) => format!("{} {}", self.label, secondary).into(),
@@ -140,7 +154,7 @@ impl Styled for Label {
 }
 
 impl RenderOnce for Label {
-    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
         let mut text = self.full_text();
         let chars_count = text.chars().count();
 
@@ -149,14 +163,31 @@ impl RenderOnce for Label {
         };
 
         let highlights = self.measure_highlights(text.len(), cx);
+        let selectable = self.selectable && !self.masked;
 
         div()
             .line_height(rems(1.25))
             .text_color(cx.theme().foreground)
             .refine_style(&self.style)
-            .child(
-                StyledText::new(&text).when_some(highlights, |this, hl| this.with_highlights(hl)),
-            )
+            .map(|this| {
+                if selectable {
+                    this.child(
+                        TextView::plain(
+                            SharedString::from(format!("label:{}", text)),
+                            text,
+                            window,
+                            cx,
+                        )
+                        .highlights(highlights.unwrap_or_default())
+                        .selectable(true),
+                    )
+                } else {
+                    this.child(
+                        StyledText::new(&text)
+                            .when_some(highlights, |this, hl| this.with_highlights(hl)),
+                    )
+                }
+            })
     }
 }
 
@@ -1,3 +1,4 @@
+mod breakpoint;
 mod event;
 mod focusable;
 mod global_state;
@@ -23,27 +24,37 @@ pub mod avatar;
 pub mod badge;
 pub mod breadcrumb;
 pub mod button;
+pub mod card;
 pub mod chart;
 pub mod checkbox;
 pub mod clipboard;
+pub mod code_block;
 pub mod color_picker;
+pub mod command_palette;
 pub mod description_list;
+pub mod diff;
 pub mod divider;
 pub mod dock;
 pub mod drawer;
 pub mod dropdown;
+pub mod emoji_picker;
 pub mod form;
 pub mod go_board;
+pub mod grid;
 pub mod group_box;
 pub mod highlighter;
 pub mod history;
 pub mod indicator;
 pub mod input;
+pub mod keymap;
 pub mod label;
 pub mod link;
 pub mod list;
+pub mod loading_overlay;
 pub mod modal;
 pub mod notification;
+pub mod number_format;
+pub mod pagination;
 pub mod plot;
 pub mod popover;
 pub mod progress;
@@ -53,6 +64,7 @@ pub mod scroll;
 pub mod sidebar;
 pub mod skeleton;
 pub mod slider;
+pub mod spinner;
 pub mod switch;
 pub mod tab;
 pub mod table;
@@ -60,6 +72,10 @@ pub mod tag;
 pub mod text;
 pub mod theme;
 pub mod tooltip;
+pub mod vim_mode;
+
+#[cfg(any(test, feature = "test-support"))]
+pub mod test_support;
 
 #[cfg(feature = "webview")]
 pub mod webview;
@@ -70,6 +86,7 @@ use gpui::{App, SharedString};
 pub use wry;
 
 pub use crate::Disableable;
+pub use breakpoint::*;
 pub use event::InteractiveElementExt;
 pub use focusable::FocusableCycle;
 pub use index_path::IndexPath;
@@ -80,7 +97,9 @@ pub use root::{ContextModal, Root};
 pub use styled::*;
 pub use time::*;
 pub use title_bar::*;
-pub use virtual_list::{h_virtual_list, v_virtual_list, VirtualList, VirtualListScrollHandle};
+pub use virtual_list::{
+    h_virtual_list, v_virtual_list, v_virtual_list_measured, VirtualList, VirtualListScrollHandle,
+};
 pub use window_border::{window_border, window_paddings, WindowBorder};
 
 pub use icon::*;
@@ -98,20 +117,29 @@ rust_i18n::i18n!("locales", fallback = "en");
 pub fn init(cx: &mut App) {
     theme::init(cx);
     global_state::init(cx);
+    breakpoint::init(cx);
     #[cfg(any(feature = "inspector", debug_assertions))]
     inspector::init(cx);
     highlighter::init(cx);
+    card::init(cx);
+    command_palette::init(cx);
     date_picker::init(cx);
     dock::init(cx);
     drawer::init(cx);
     dropdown::init(cx);
+    emoji_picker::init(cx);
+    grid::init(cx);
     input::init(cx);
     list::init(cx);
+    loading_overlay::init(cx);
     modal::init(cx);
+    pagination::init(cx);
     popover::init(cx);
     menu::init(cx);
+    sidebar::init(cx);
     table::init(cx);
     text::init(cx);
+    vim_mode::init(cx);
 }
 
 #[inline]
@@ -1,6 +1,6 @@
 use std::rc::Rc;
 
-use gpui::{App, Pixels, Size};
+use gpui::{px, App, Pixels, Size};
 
 use crate::IndexPath;
 
@@ -69,6 +69,10 @@ pub(crate) struct RowsCache {
     /// The sections, the item is number of rows in each section.
     pub(crate) sections: Rc<Vec<usize>>,
     pub(crate) entries_sizes: Rc<Vec<Size<Pixels>>>,
+    /// The top position of each flattened row.
+    entries_origins: Rc<Vec<Pixels>>,
+    /// The flattened row index of each section header.
+    section_header_positions: Rc<Vec<usize>>,
     measured_size: MeasuredEntrySize,
 }
 
@@ -104,6 +108,63 @@ impl RowsCache {
         self.sections.get(section).cloned().unwrap_or(0)
     }
 
+    /// Returns the flattened row index of the section header.
+    pub(crate) fn section_header_position(&self, section: usize) -> Option<usize> {
+        self.section_header_positions.get(section).copied()
+    }
+
+    /// Returns the section of the sticky header at the given scroll top (relative to the content),
+    /// and the top offset of the header.
+    ///
+    /// The offset is negative when the header is pushed up by the next section header.
+    ///
+    /// Returns None if the section header is not scrolled out, or the header is empty.
+    pub(crate) fn sticky_section_header(&self, scroll_top: Pixels) -> Option<(usize, Pixels)> {
+        let header_height = self.measured_size.section_header_size.height;
+        if header_height <= px(0.) {
+            return None;
+        }
+
+        let origin_of =
+            |section: usize| self.entries_origins[self.section_header_positions[section]];
+        let section = self
+            .section_header_positions
+            .partition_point(|&ix| self.entries_origins[ix] < scroll_top)
+            .checked_sub(1)?;
+
+        let offset = if section + 1 < self.section_header_positions.len() {
+            (origin_of(section + 1) - scroll_top - header_height).min(px(0.))
+        } else {
+            px(0.)
+        };
+
+        Some((section, offset))
+    }
+
+    /// Returns the drop target at the given top position (relative to the content) for reordering,
+    /// the bool is true to insert after the target.
+    ///
+    /// Dropping on the section header inserts before the first row of the section,
+    /// and on the section footer inserts after the last row.
+    pub(crate) fn drop_target(&self, top: Pixels) -> Option<(IndexPath, bool)> {
+        let ix = self
+            .entries_origins
+            .partition_point(|&origin| origin <= top)
+            .checked_sub(1)?;
+
+        match self.entities[ix] {
+            RowEntry::Entry(path) => {
+                let half = self.entries_sizes[ix].height / 2.;
+                Some((path, top >= self.entries_origins[ix] + half))
+            }
+            RowEntry::SectionHeader(section) => Some((IndexPath::new(0).section(section), false)),
+            RowEntry::SectionFooter(section) => match self.rows_count(section) {
+                0 => Some((IndexPath::new(0).section(section), false)),
+                rows_count => Some((IndexPath::new(rows_count - 1).section(section), true)),
+            },
+        }
+    }
+
     /// Return prev row, if the row is the first in the first section, goes to the last row.
     pub(crate) fn prev(&self, path: IndexPath) -> IndexPath {
         let mut path = path;
@@ -143,6 +204,21 @@ impl RowsCache {
         path
     }
 
+    /// Returns the first row of the first non-empty section, None if there is no row.
+    pub(crate) fn first(&self) -> Option<IndexPath> {
+        (0..self.sections_count())
+            .find(|section| self.rows_count(*section) > 0)
+            .map(|section| IndexPath::new(0).section(section))
+    }
+
+    /// Returns the last row of the last non-empty section, None if there is no row.
+    pub(crate) fn last(&self) -> Option<IndexPath> {
+        (0..self.sections_count())
+            .rev()
+            .find(|section| self.rows_count(*section) > 0)
+            .map(|section| IndexPath::new(self.rows_count(section) - 1).section(section))
+    }
+
     pub(crate) fn prepare_if_needed<F>(
         &mut self,
         sections_count: usize,
@@ -163,6 +239,11 @@ impl RowsCache {
             return;
         }
 
+        self.rebuild(new_sections, measured_size);
+    }
+
+    /// Rebuild the flattened rows with the rows count of each section.
+    fn rebuild(&mut self, new_sections: Vec<usize>, measured_size: MeasuredEntrySize) {
         let mut entries_sizes = vec![];
         let mut total_items_count = 0;
         self.measured_size = measured_size;
@@ -190,16 +271,53 @@ impl RowsCache {
                 })
                 .collect(),
         );
+        self.entries_origins = Rc::new(
+            entries_sizes
+                .iter()
+                .scan(px(0.), |top, size| {
+                    let origin = *top;
+                    *top += size.height;
+                    Some(origin)
+                })
+                .collect(),
+        );
+        self.section_header_positions = Rc::new(
+            self.entities
+                .iter()
+                .enumerate()
+                .filter(|(_, entry)| entry.is_section_header())
+                .map(|(ix, _)| ix)
+                .collect(),
+        );
         self.entries_sizes = Rc::new(entries_sizes);
         self.items_count = total_items_count;
     }
 }
 
+/// Returns the new index of the item moved from `from` to before (or after) the `target`.
+///
+/// The result is the index after the item is removed, e.g.: `Vec::remove(from)` then `Vec::insert(to)`.
+pub(crate) fn reorder_index(from: IndexPath, target: IndexPath, after: bool) -> IndexPath {
+    let mut to = target;
+    if after {
+        to.row += 1;
+    }
+    if from.section == to.section && from.row < to.row {
+        to.row -= 1;
+    }
+    to
+}
+
 #[cfg(test)]
 mod tests {
     use std::rc::Rc;
 
-    use crate::{list::cache::RowsCache, IndexPath};
+    use gpui::{px, size};
+
+    use crate::{
+        list::cache::{reorder_index, MeasuredEntrySize, RowsCache},
+        IndexPath,
+    };
 
     #[test]
     fn test_prev_next() {
@@ -272,4 +390,118 @@ mod tests {
             IndexPath::new(3).section(1)
         );
     }
+
+    #[test]
+    fn test_first_last() {
+        let mut row_cache = RowsCache::default();
+        assert_eq!(row_cache.first(), None);
+        assert_eq!(row_cache.last(), None);
+
+        row_cache.sections = Rc::new(vec![0, 2, 3, 0]);
+        assert_eq!(row_cache.first(), Some(IndexPath::new(0).section(1)));
+        assert_eq!(row_cache.last(), Some(IndexPath::new(2).section(2)));
+    }
+
+    #[test]
+    fn test_sticky_section_header() {
+        let mut row_cache = RowsCache::default();
+        let measured_size = MeasuredEntrySize {
+            item_size: size(px(100.), px(30.)),
+            section_header_size: size(px(100.), px(20.)),
+            section_footer_size: size(px(100.), px(0.)),
+        };
+        // section 0: header 0..20, rows 20..80, footer 80
+        // section 1: header 80..100, rows 100..190, footer 190
+        row_cache.rebuild(vec![2, 3], measured_size);
+
+        assert_eq!(row_cache.section_header_position(1), Some(4));
+        assert_eq!(row_cache.sticky_section_header(px(0.)), None);
+        assert_eq!(row_cache.sticky_section_header(px(10.)), Some((0, px(0.))));
+        assert_eq!(row_cache.sticky_section_header(px(60.)), Some((0, px(0.))));
+        // Pushed up by the section 1 header.
+        assert_eq!(
+            row_cache.sticky_section_header(px(70.)),
+            Some((0, px(-10.)))
+        );
+        assert_eq!(
+            row_cache.sticky_section_header(px(80.)),
+            Some((0, px(-20.)))
+        );
+        assert_eq!(row_cache.sticky_section_header(px(90.)), Some((1, px(0.))));
+
+        // No header
+        let measured_size = MeasuredEntrySize {
+            section_header_size: size(px(0.), px(0.)),
+            ..measured_size
+        };
+        row_cache.rebuild(vec![2, 3], measured_size);
+        assert_eq!(row_cache.sticky_section_header(px(10.)), None);
+    }
+
+    #[test]
+    fn test_drop_target() {
+        let mut row_cache = RowsCache::default();
+        let measured_size = MeasuredEntrySize {
+            item_size: size(px(100.), px(30.)),
+            section_header_size: size(px(100.), px(20.)),
+            section_footer_size: size(px(100.), px(10.)),
+        };
+        // section 0: header 0..20, rows 20..80, footer 80..90
+        // section 1: header 90..110, no rows, footer 110..120
+        row_cache.rebuild(vec![2, 0], measured_size);
+
+        assert_eq!(
+            row_cache.drop_target(px(5.)),
+            Some((IndexPath::new(0).section(0), false))
+        );
+        assert_eq!(
+            row_cache.drop_target(px(25.)),
+            Some((IndexPath::new(0).section(0), false))
+        );
+        assert_eq!(
+            row_cache.drop_target(px(40.)),
+            Some((IndexPath::new(0).section(0), true))
+        );
+        assert_eq!(
+            row_cache.drop_target(px(85.)),
+            Some((IndexPath::new(1).section(0), true))
+        );
+        assert_eq!(
+            row_cache.drop_target(px(115.)),
+            Some((IndexPath::new(0).section(1), false))
+        );
+        assert_eq!(row_cache.drop_target(px(-1.)), None);
+    }
+
+    #[test]
+    fn test_reorder_index() {
+        // Move down
+        assert_eq!(
+            reorder_index(IndexPath::new(0), IndexPath::new(2), false),
+            IndexPath::new(1)
+        );
+        assert_eq!(
+            reorder_index(IndexPath::new(0), IndexPath::new(2), true),
+            IndexPath::new(2)
+        );
+        // Move up
+        assert_eq!(
+            reorder_index(IndexPath::new(3), IndexPath::new(1), false),
+            IndexPath::new(1)
+        );
+        assert_eq!(
+            reorder_index(IndexPath::new(3), IndexPath::new(1), true),
+            IndexPath::new(2)
+        );
+        // Same position
+        assert_eq!(
+            reorder_index(IndexPath::new(1), IndexPath::new(1), true),
+            IndexPath::new(1)
+        );
+        // Move to other section
+        assert_eq!(
+            reorder_index(IndexPath::new(0), IndexPath::new(2).section(1), true),
+            IndexPath::new(3).section(1)
+        );
+    }
 }
@@ -1,9 +1,13 @@
-use gpui::{AnyElement, App, Context, IntoElement, ParentElement as _, Styled as _, Task, Window};
+use gpui::{
+    div, prelude::FluentBuilder as _, AnyElement, App, Context, IntoElement, ParentElement as _,
+    SharedString, Styled as _, Task, Window,
+};
+use rust_i18n::t;
 
 use crate::{
-    h_flex,
+    button::{Button, ButtonVariants as _},
     list::{loading::Loading, List},
-    ActiveTheme as _, Icon, IconName, IndexPath, Selectable,
+    v_flex, ActiveTheme as _, Icon, IconName, IndexPath, Selectable, Sizable as _,
 };
 
 /// A delegate for the List.
@@ -68,11 +72,65 @@ pub trait ListDelegate: Sized + 'static {
 
     /// Return a Element to show when list is empty.
     fn render_empty(&self, window: &mut Window, cx: &mut Context<List<Self>>) -> impl IntoElement {
-        h_flex()
+        v_flex()
             .size_full()
             .justify_center()
+            .items_center()
+            .gap_2()
             .text_color(cx.theme().muted_foreground.opacity(0.6))
             .child(Icon::new(IconName::Inbox).size_12())
+            .child(div().text_sm().child(SharedString::from(t!("List.empty"))))
+            .into_any_element()
+    }
+
+    /// Returns the error message to show the error view instead of the items, e.g.: failed to load.
+    ///
+    /// Default is None.
+    fn error(&self, cx: &App) -> Option<SharedString> {
+        None
+    }
+
+    /// Return true to show a retry button in the default error view.
+    ///
+    /// Default: false
+    fn can_retry(&self, cx: &App) -> bool {
+        false
+    }
+
+    /// Called when the retry button in the error view is clicked, you can reload the items here.
+    fn perform_retry(&mut self, window: &mut Window, cx: &mut Context<List<Self>>) {}
+
+    /// Return a Element to show when the [`ListDelegate::error`] is Some,
+    /// default is an icon with the error message, and a retry button if [`ListDelegate::can_retry`].
+    fn render_error(
+        &self,
+        error: SharedString,
+        window: &mut Window,
+        cx: &mut Context<List<Self>>,
+    ) -> impl IntoElement {
+        v_flex()
+            .size_full()
+            .justify_center()
+            .items_center()
+            .gap_2()
+            .text_color(cx.theme().muted_foreground)
+            .child(
+                Icon::new(IconName::TriangleAlert)
+                    .size_12()
+                    .text_color(cx.theme().danger.opacity(0.8)),
+            )
+            .child(div().text_sm().child(error))
+            .when(self.can_retry(cx), |this| {
+                this.child(
+                    Button::new("retry")
+                        .outline()
+                        .small()
+                        .label(t!("List.retry"))
+                        .on_click(cx.listener(|this, _, window, cx| {
+                            this.delegate_mut().perform_retry(window, cx);
+                        })),
+                )
+            })
             .into_any_element()
     }
 
@@ -1,25 +1,31 @@
+use std::collections::HashSet;
 use std::ops::Range;
+use std::rc::Rc;
 use std::time::Duration;
 
 use crate::actions::{Cancel, Confirm, SelectNext, SelectPrev};
 use crate::input::InputState;
-use crate::list::cache::{MeasuredEntrySize, RowEntry, RowsCache};
+use crate::list::cache::{reorder_index, MeasuredEntrySize, RowEntry, RowsCache};
 use crate::list::ListDelegate;
+use crate::vim_mode::{self, MoveDown, MoveToBottom, MoveToTop, MoveUp, StartFilter, ToggleVisual};
+use crate::{
+    h_flex,
+    scroll::{ScrollAlign, ScrollHandleOffsetable as _},
+    v_virtual_list, Icon, IndexPath, Selectable, Sizable as _, StyledExt, VirtualListScrollHandle,
+};
 use crate::{
     input::{InputEvent, TextInput},
     scroll::{Scrollbar, ScrollbarState},
     v_flex, ActiveTheme, IconName, Size,
 };
-use crate::{
-    v_virtual_list, Icon, IndexPath, Selectable, Sizable as _, StyledExt, VirtualListScrollHandle,
-};
 use gpui::{
-    div, prelude::FluentBuilder, AppContext, Entity, FocusHandle, Focusable, InteractiveElement,
-    IntoElement, KeyBinding, Length, MouseButton, ParentElement, Render, Styled, Task, Window,
+    canvas, div, prelude::FluentBuilder, AnyElement, AppContext, Bounds, DragMoveEvent, Entity,
+    EntityId, FocusHandle, Focusable, InteractiveElement, IntoElement, KeyBinding, Length,
+    MouseButton, ParentElement, Render, StatefulInteractiveElement, Styled, Task, Window,
 };
 use gpui::{
-    px, size, App, AvailableSpace, Context, Edges, EventEmitter, ListSizingBehavior,
-    MouseDownEvent, Pixels, ScrollStrategy, Subscription,
+    px, size, App, AvailableSpace, Context, Edges, EventEmitter, IsZero as _, ListSizingBehavior,
+    MouseDownEvent, Pixels, Point, ScrollStrategy, Subscription,
 };
 use rust_i18n::t;
 use smol::Timer;
@@ -43,8 +49,53 @@ pub enum ListEvent {
     Confirm(IndexPath),
     /// Pressed ESC to deselect the item.
     Cancel,
+    /// Dropped the dragging item to reorder, only for [`List::reorderable`].
+    ///
+    /// The `to` is the new index after the item is removed from `from`,
+    /// the list does not change the data, the delegate should move the item.
+    Reordered { from: IndexPath, to: IndexPath },
+}
+
+/// The dragging item of the [`List`], used to render the drag preview.
+#[derive(Clone)]
+pub(crate) struct DragListItem {
+    entity_id: EntityId,
+    ix: IndexPath,
+    width: Pixels,
+    render_item: Rc<dyn Fn(&mut Window, &mut App) -> AnyElement>,
 }
 
+impl Render for DragListItem {
+    fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        div()
+            .w(self.width)
+            .overflow_hidden()
+            .bg(cx.theme().background)
+            .border_1()
+            .border_color(cx.theme().border)
+            .rounded(cx.theme().radius)
+            .shadow_md()
+            .opacity(0.9)
+            .child((self.render_item)(window, cx))
+    }
+}
+
+/// The state of reordering by dragging.
+struct DragState {
+    from: IndexPath,
+    /// The drop target, the bool is true to insert after the target.
+    target: Option<(IndexPath, bool)>,
+    /// The last mouse position, to update the target while auto scrolling.
+    position: Point<Pixels>,
+    /// The auto scroll speed when dragging near the top or bottom edge.
+    auto_scroll: Pixels,
+    cancelled: bool,
+}
+
+/// The edge size to trigger auto scrolling while dragging.
+const AUTO_SCROLL_EDGE: Pixels = px(32.);
+const AUTO_SCROLL_SPEED: Pixels = px(8.);
+
 pub struct List<D: ListDelegate> {
     focus_handle: FocusHandle,
     delegate: D,
@@ -61,8 +112,20 @@ pub struct List<D: ListDelegate> {
     rows_cache: RowsCache,
     selected_index: Option<IndexPath>,
     deferred_scroll_to_index: Option<(IndexPath, ScrollStrategy)>,
+    deferred_scroll_align: Option<(IndexPath, ScrollAlign)>,
     mouse_right_clicked_index: Option<IndexPath>,
     reset_on_cancel: bool,
+    sticky_section_headers: bool,
+    collapsible_sections: bool,
+    collapsed_sections: HashSet<usize>,
+    reorderable: bool,
+    drag_handle: bool,
+    drag_state: Option<DragState>,
+    vim_mode: bool,
+    /// The start of the visual selection in the vim mode, the end is the selected index.
+    visual_anchor: Option<IndexPath>,
+    bounds: Bounds<Pixels>,
+    _auto_scroll_task: Task<()>,
     _search_task: Task<()>,
     _load_more_task: Task<()>,
     _query_input_subscription: Subscription,
@@ -87,6 +150,7 @@ where
             last_query: None,
             selected_index: None,
             deferred_scroll_to_index: None,
+            deferred_scroll_align: None,
             mouse_right_clicked_index: None,
             scroll_handle: VirtualListScrollHandle::new(),
             scroll_state: ScrollbarState::default(),
@@ -96,6 +160,16 @@ where
             querying: false,
             size: Size::default(),
             reset_on_cancel: true,
+            sticky_section_headers: false,
+            collapsible_sections: false,
+            collapsed_sections: HashSet::new(),
+            reorderable: false,
+            drag_handle: false,
+            drag_state: None,
+            vim_mode: false,
+            visual_anchor: None,
+            bounds: Bounds::default(),
+            _auto_scroll_task: Task::ready(()),
             paddings: Edges::default(),
             _search_task: Task::ready(()),
             _load_more_task: Task::ready(()),
@@ -130,6 +204,93 @@ where
         self
     }
 
+    /// Set to keep the section header at the top of the list while scrolling its section,
+    /// and then pushed up by the next section header, default is false.
+    ///
+    /// The section header is rendered by [`ListDelegate::render_section_header`].
+    pub fn sticky_section_headers(mut self, sticky: bool) -> Self {
+        self.sticky_section_headers = sticky;
+        self
+    }
+
+    /// Set to collapse or expand the section by clicking the section header, default is false.
+    pub fn collapsible_sections(mut self, collapsible: bool) -> Self {
+        self.collapsible_sections = collapsible;
+        self
+    }
+
+    /// Set to reorder the items by dragging, default is false.
+    ///
+    /// When dropped, the [`ListEvent::Reordered`] is emitted, press `escape` to cancel the dragging.
+    pub fn reorderable(mut self, reorderable: bool) -> Self {
+        self.reorderable = reorderable;
+        self
+    }
+
+    /// Set to only start dragging from a drag handle at the start of the item, default is false.
+    ///
+    /// This is useful when the item has interactive content, e.g.: button, switch.
+    pub fn drag_handle(mut self, drag_handle: bool) -> Self {
+        self.drag_handle = drag_handle;
+        self
+    }
+
+    /// Set to navigate by the single keys while the list is focused, default is false,
+    /// see [`crate::vim_mode`].
+    ///
+    /// The list is focused rather than the query input, press `/` to focus the query input,
+    /// and `enter` or `escape` in it to focus the list again.
+    pub fn vim_mode(mut self, vim_mode: bool) -> Self {
+        self.vim_mode = vim_mode;
+        self
+    }
+
+    /// Set the vim mode, see [`List::vim_mode`].
+    pub fn set_vim_mode(&mut self, vim_mode: bool, _: &mut Window, cx: &mut Context<Self>) {
+        self.vim_mode = vim_mode;
+        self.visual_anchor = None;
+        cx.notify();
+    }
+
+    /// Returns the first and last index of the visual selection in the vim mode, in order,
+    /// None if it is not started.
+    pub fn visual_selection(&self) -> Option<(IndexPath, IndexPath)> {
+        let anchor = self.visual_anchor?;
+        let selected = self.selected_index?;
+        if (anchor.section, anchor.row) <= (selected.section, selected.row) {
+            Some((anchor, selected))
+        } else {
+            Some((selected, anchor))
+        }
+    }
+
+    fn is_visual_selected(&self, ix: IndexPath) -> bool {
+        self.visual_selection().is_some_and(|(start, end)| {
+            ((start.section, start.row)..=(end.section, end.row)).contains(&(ix.section, ix.row))
+        })
+    }
+
+    /// Returns true if the section is collapsed.
+    pub fn is_section_collapsed(&self, section: usize) -> bool {
+        self.collapsed_sections.contains(&section)
+    }
+
+    /// Collapse or expand the section.
+    pub fn set_section_collapsed(
+        &mut self,
+        section: usize,
+        collapsed: bool,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if collapsed {
+            self.collapsed_sections.insert(section);
+        } else {
+            self.collapsed_sections.remove(&section);
+        }
+        cx.notify();
+    }
+
     pub fn set_query_input(
         &mut self,
         query_input: Entity<InputState>,
@@ -218,11 +379,39 @@ where
         cx.notify();
     }
 
+    /// Scroll to the item at the given index, and place it at the `align` position of the viewport.
+    pub fn scroll_to(
+        &mut self,
+        ix: IndexPath,
+        align: ScrollAlign,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.deferred_scroll_to_index = None;
+        self.deferred_scroll_align = Some((ix, align));
+        cx.notify();
+    }
+
     /// Get scroll handle
     pub fn scroll_handle(&self) -> &VirtualListScrollHandle {
         &self.scroll_handle
     }
 
+    /// Returns the current scroll offset of the list, can be used to restore it later.
+    pub fn scroll_offset(&self) -> Point<Pixels> {
+        self.scroll_handle.offset()
+    }
+
+    /// Set the scroll offset of the list, e.g.: restore a previously saved [`Self::scroll_offset`].
+    ///
+    /// The offset will be clamped to the content size on the next paint.
+    pub fn set_scroll_offset(&mut self, offset: Point<Pixels>, cx: &mut Context<Self>) {
+        self.deferred_scroll_to_index = None;
+        self.deferred_scroll_align = None;
+        self.scroll_handle.set_offset(offset);
+        cx.notify();
+    }
+
     pub fn scroll_to_selected_item(&mut self, _: &mut Window, cx: &mut Context<Self>) {
         if let Some(ix) = self.selected_index {
             self.deferred_scroll_to_index = Some((ix, ScrollStrategy::Top));
@@ -250,6 +439,7 @@ where
                     return;
                 }
 
+                self.visual_anchor = None;
                 self.set_querying(true, window, cx);
                 let search = self.delegate.perform_search(&text, window, cx);
 
@@ -274,6 +464,11 @@ where
                     });
                 });
             }
+            // Back to the list to navigate the results.
+            InputEvent::PressEnter { .. } if self.vim_mode => {
+                self.focus_handle.focus(window);
+                cx.notify();
+            }
             InputEvent::PressEnter { secondary } => self.on_action_confirm(
                 &Confirm {
                     secondary: *secondary,
@@ -326,6 +521,26 @@ where
     }
 
     fn on_action_cancel(&mut self, _: &Cancel, window: &mut Window, cx: &mut Context<Self>) {
+        // Cancel the dragging only, the drop will be ignored.
+        if let Some(drag_state) = self.drag_state.as_mut() {
+            drag_state.cancelled = true;
+            drag_state.target = None;
+            drag_state.auto_scroll = px(0.);
+            cx.notify();
+            return;
+        }
+
+        if self.visual_anchor.take().is_some() {
+            cx.notify();
+            return;
+        }
+        // Back to the list from the query input.
+        if self.vim_mode && !self.focus_handle.is_focused(window) {
+            self.focus_handle.focus(window);
+            cx.notify();
+            return;
+        }
+
         cx.propagate();
         if self.reset_on_cancel {
             self._set_selected_index(None, window, cx);
@@ -397,31 +612,140 @@ where
         self.select_item(next_ix, window, cx);
     }
 
+    fn on_action_move_down(&mut self, _: &MoveDown, window: &mut Window, cx: &mut Context<Self>) {
+        self.on_action_select_next(&SelectNext, window, cx);
+    }
+
+    fn on_action_move_up(&mut self, _: &MoveUp, window: &mut Window, cx: &mut Context<Self>) {
+        self.on_action_select_prev(&SelectPrev, window, cx);
+    }
+
+    fn on_action_move_to_top(
+        &mut self,
+        _: &MoveToTop,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if let Some(ix) = self.rows_cache.first() {
+            self.select_item(ix, window, cx);
+        }
+    }
+
+    fn on_action_move_to_bottom(
+        &mut self,
+        _: &MoveToBottom,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if let Some(ix) = self.rows_cache.last() {
+            self.select_item(ix, window, cx);
+        }
+    }
+
+    fn on_action_start_filter(
+        &mut self,
+        _: &StartFilter,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let Some(query_input) = self.query_input.clone() else {
+            cx.propagate();
+            return;
+        };
+
+        query_input.update(cx, |input, cx| input.focus(window, cx));
+        cx.notify();
+    }
+
+    fn on_action_toggle_visual(
+        &mut self,
+        _: &ToggleVisual,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.visual_anchor = match self.visual_anchor {
+            Some(_) => None,
+            None => self.selected_index,
+        };
+        cx.notify();
+    }
+
     fn render_list_item(
         &self,
         ix: IndexPath,
         window: &mut Window,
         cx: &mut Context<Self>,
     ) -> impl IntoElement {
-        let selected = self.selected_index.map(|s| s.eq_row(ix)).unwrap_or(false);
+        let selected = self.selected_index.map(|s| s.eq_row(ix)).unwrap_or(false)
+            || self.is_visual_selected(ix);
         let mouse_right_clicked = self
             .mouse_right_clicked_index
             .map(|s| s.eq_row(ix))
             .unwrap_or(false);
 
+        let drop_indicator = self
+            .drag_state
+            .as_ref()
+            .and_then(|drag_state| drag_state.target)
+            .filter(|(target, _)| *target == ix)
+            .map(|(_, after)| after);
+
+        let item = self.delegate.render_item(ix, window, cx).map(|item| {
+            item.selected(selected)
+                .secondary_selected(mouse_right_clicked)
+        });
+
         div()
             .id("list-item")
             .w_full()
             .relative()
-            .children(self.delegate.render_item(ix, window, cx).map(|item| {
-                item.selected(selected)
-                    .secondary_selected(mouse_right_clicked)
-            }))
+            .map(|this| {
+                if self.reorderable && self.drag_handle {
+                    this.child(
+                        h_flex()
+                            .w_full()
+                            .child(
+                                div()
+                                    .id("drag-handle")
+                                    .flex_shrink_0()
+                                    .px_1()
+                                    .cursor_grab()
+                                    .text_color(cx.theme().muted_foreground)
+                                    .child(Icon::new(IconName::EllipsisVertical).xsmall())
+                                    .on_drag(self.drag_item(ix, cx), |drag, _, _, cx| {
+                                        cx.stop_propagation();
+                                        cx.new(|_| drag.clone())
+                                    }),
+                            )
+                            .child(div().flex_1().min_w_0().children(item)),
+                    )
+                } else {
+                    this.children(item)
+                }
+            })
+            .when(self.reorderable && !self.drag_handle, |this| {
+                this.on_drag(self.drag_item(ix, cx), |drag, _, _, cx| {
+                    cx.stop_propagation();
+                    cx.new(|_| drag.clone())
+                })
+            })
+            .when_some(drop_indicator, |this, after| {
+                this.child(
+                    div()
+                        .absolute()
+                        .left_0()
+                        .right_0()
+                        .h(px(2.))
+                        .bg(cx.theme().drag_border)
+                        .map(|this| if after { this.bottom_0() } else { this.top_0() }),
+                )
+            })
             .when(self.selectable, |this| {
                 this.on_mouse_down(
                     MouseButton::Left,
                     cx.listener(move |this, ev: &MouseDownEvent, window, cx| {
                         this.mouse_right_clicked_index = None;
+                        this.visual_anchor = None;
                         this.selected_index = Some(ix);
                         this.on_action_confirm(
                             &Confirm {
@@ -442,6 +766,203 @@ where
             })
     }
 
+    fn drag_item(&self, ix: IndexPath, cx: &mut Context<Self>) -> DragListItem {
+        let view = cx.entity().downgrade();
+
+        DragListItem {
+            entity_id: cx.entity_id(),
+            ix,
+            width: self.bounds.size.width,
+            render_item: Rc::new(move |window, cx| {
+                view.update(cx, |list, cx| {
+                    list.delegate
+                        .render_item(ix, window, cx)
+                        .map(|item| item.into_any_element())
+                })
+                .ok()
+                .flatten()
+                .unwrap_or_else(|| div().into_any_element())
+            }),
+        }
+    }
+
+    fn on_item_drag_move(
+        &mut self,
+        event: &DragMoveEvent<DragListItem>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let drag = event.drag(cx);
+        if drag.entity_id != cx.entity_id() {
+            return;
+        }
+        let from = drag.ix;
+
+        let drag_state = self.drag_state.get_or_insert_with(|| DragState {
+            from,
+            target: None,
+            position: event.event.position,
+            auto_scroll: px(0.),
+            cancelled: false,
+        });
+        if drag_state.cancelled {
+            return;
+        }
+
+        let position = event.event.position;
+        let bounds = event.bounds;
+        drag_state.position = position;
+        drag_state.auto_scroll = if position.y < bounds.top() + AUTO_SCROLL_EDGE {
+            AUTO_SCROLL_SPEED
+        } else if position.y > bounds.bottom() - AUTO_SCROLL_EDGE {
+            -AUTO_SCROLL_SPEED
+        } else {
+            px(0.)
+        };
+
+        let auto_scroll = drag_state.auto_scroll;
+        self.update_drop_target();
+        if !auto_scroll.is_zero() {
+            self.start_auto_scroll(window, cx);
+        }
+        cx.notify();
+    }
+
+    fn update_drop_target(&mut self) {
+        let Some(drag_state) = self.drag_state.as_mut() else {
+            return;
+        };
+
+        let top = drag_state.position.y
+            - self.bounds.top()
+            - self.scroll_handle.offset().y
+            - self.paddings.top;
+        drag_state.target = self.rows_cache.drop_target(top);
+    }
+
+    /// Keep scrolling while dragging near the top or bottom edge.
+    fn start_auto_scroll(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        self._auto_scroll_task = cx.spawn_in(window, async move |this, window| loop {
+            Timer::after(Duration::from_millis(16)).await;
+
+            let Ok(true) = this.update_in(window, |this, _, cx| this.auto_scroll(cx)) else {
+                break;
+            };
+        });
+    }
+
+    /// Scroll by the auto scroll speed, returns false to stop.
+    fn auto_scroll(&mut self, cx: &mut Context<Self>) -> bool {
+        let Some(speed) = self.drag_state.as_ref().map(|state| state.auto_scroll) else {
+            return false;
+        };
+        if speed.is_zero() {
+            return false;
+        }
+
+        let max_offset =
+            (self.scroll_handle.content_size().height - self.bounds.size.height).max(px(0.));
+        let mut offset = self.scroll_handle.offset();
+        offset.y = (offset.y + speed).min(px(0.)).max(-max_offset);
+        self.scroll_handle.set_offset(offset);
+        self.update_drop_target();
+        cx.notify();
+        true
+    }
+
+    fn on_item_drop(&mut self, drag: &DragListItem, _: &mut Window, cx: &mut Context<Self>) {
+        if drag.entity_id != cx.entity_id() {
+            return;
+        }
+
+        let Some(drag_state) = self.drag_state.take() else {
+            return;
+        };
+        cx.notify();
+        if drag_state.cancelled {
+            return;
+        }
+
+        if let Some((target, after)) = drag_state.target {
+            let to = reorder_index(drag_state.from, target, after);
+            if to != drag_state.from {
+                cx.emit(ListEvent::Reordered {
+                    from: drag_state.from,
+                    to,
+                });
+            }
+        }
+    }
+
+    fn render_section_header(
+        &self,
+        section_ix: usize,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> Option<AnyElement> {
+        let header = self
+            .delegate
+            .render_section_header(section_ix, window, cx)?;
+
+        Some(
+            div()
+                .when(self.collapsible_sections, |this| {
+                    this.on_mouse_down(
+                        MouseButton::Left,
+                        cx.listener(move |this, _, window, cx| {
+                            let collapsed = !this.is_section_collapsed(section_ix);
+                            this.set_section_collapsed(section_ix, collapsed, window, cx);
+                        }),
+                    )
+                })
+                .child(header)
+                .into_any_element(),
+        )
+    }
+
+    /// Render the header of the section at the top of the viewport,
+    /// only this header is rendered, the others are in the virtual list.
+    fn render_sticky_section_header(
+        &self,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> Option<impl IntoElement> {
+        if !self.sticky_section_headers {
+            return None;
+        }
+
+        let scroll_top = -self.scroll_handle.offset().y - self.paddings.top;
+        let (section_ix, offset) = self.rows_cache.sticky_section_header(scroll_top)?;
+        let header = self.render_section_header(section_ix, window, cx)?;
+
+        Some(
+            div()
+                .id("sticky-section-header")
+                .occlude()
+                .absolute()
+                .top(offset)
+                .left_0()
+                .right_0()
+                .pl(self.paddings.left)
+                .pr(self.paddings.right)
+                .bg(cx.theme().background)
+                .when(self.collapsible_sections, |this| {
+                    // Scroll back to the header, the rows below are changed after toggle.
+                    let header_ix = self.rows_cache.section_header_position(section_ix);
+                    this.on_mouse_down(
+                        MouseButton::Left,
+                        cx.listener(move |this, _, _, _| {
+                            if let Some(header_ix) = header_ix {
+                                this.scroll_handle
+                                    .scroll_to_item(header_ix, ScrollStrategy::Top);
+                            }
+                        }),
+                    )
+                })
+                .child(header),
+        )
+    }
+
     fn render_items(
         &mut self,
         items_count: usize,
@@ -449,16 +970,19 @@ where
         window: &mut Window,
         cx: &mut Context<Self>,
     ) -> impl IntoElement {
+        // The items of the collapsed sections are not counted, but the section headers are still visible.
+        let is_empty = items_count == 0 && self.collapsed_sections.is_empty();
+
         v_flex()
             .flex_grow()
             .relative()
             .h_full()
             .when_some(self.max_height, |this, h| this.max_h(h))
             .overflow_hidden()
-            .when(items_count == 0, |this| {
+            .when(is_empty, |this| {
                 this.child(self.delegate().render_empty(window, cx))
             })
-            .when(items_count > 0, {
+            .when(!is_empty, {
                 let rows_cache = self.rows_cache.clone();
                 |this| {
                     this.child(
@@ -489,10 +1013,9 @@ where
                                                 list.render_list_item(index, window, cx)
                                                     .into_any_element(),
                                             ),
-                                            RowEntry::SectionHeader(section_ix) => list
-                                                .delegate()
-                                                .render_section_header(section_ix, window, cx)
-                                                .map(|r| r.into_any_element()),
+                                            RowEntry::SectionHeader(section_ix) => {
+                                                list.render_section_header(section_ix, window, cx)
+                                            }
                                             RowEntry::SectionFooter(section_ix) => list
                                                 .delegate()
                                                 .render_section_footer(section_ix, window, cx)
@@ -511,6 +1034,32 @@ where
                     )
                 }
             })
+            .when(!is_empty, |this| {
+                this.children(self.render_sticky_section_header(window, cx))
+            })
+            .when(self.reorderable, |this| {
+                let view = cx.entity().clone();
+                this.on_drag_move(cx.listener(Self::on_item_drag_move))
+                    .on_drop(cx.listener(Self::on_item_drop))
+                    // Dropped outside the list.
+                    .on_mouse_up_out(
+                        MouseButton::Left,
+                        cx.listener(|this, _, _, cx| {
+                            if this.drag_state.take().is_some() {
+                                cx.notify();
+                            }
+                        }),
+                    )
+                    // To save the bounds of the list.
+                    .child(
+                        canvas(
+                            move |bounds, _, cx| view.update(cx, |r, _| r.bounds = bounds),
+                            |_, _, _, _| {},
+                        )
+                        .absolute()
+                        .size_full(),
+                    )
+            })
             .children(self.render_scrollbar(window, cx))
     }
 
@@ -541,8 +1090,13 @@ where
             measured_size.section_footer_size = el.layout_as_root(available_space, window, cx);
         }
 
+        let collapsed_sections = &self.collapsed_sections;
         self.rows_cache
             .prepare_if_needed(sections_count, measured_size, cx, |section_ix, cx| {
+                if collapsed_sections.contains(&section_ix) {
+                    return 0;
+                }
+
                 self.delegate.items_count(section_ix, cx)
             });
     }
@@ -553,10 +1107,9 @@ where
     D: ListDelegate,
 {
     fn focus_handle(&self, cx: &App) -> FocusHandle {
-        if let Some(query_input) = &self.query_input {
-            query_input.focus_handle(cx)
-        } else {
-            self.focus_handle.clone()
+        match &self.query_input {
+            Some(query_input) if !self.vim_mode => query_input.focus_handle(cx),
+            _ => self.focus_handle.clone(),
         }
     }
 }
@@ -574,6 +1127,11 @@ where
                 self.scroll_handle.scroll_to_item(item_ix, strategy);
             }
         }
+        if let Some((ix, align)) = self.deferred_scroll_align.take() {
+            if let Some(item_ix) = self.rows_cache.position_of(&ix) {
+                self.scroll_handle.scroll_to(item_ix, align);
+            }
+        }
 
         let items_count = self.rows_cache.items_count();
         let entities_count = self.rows_cache.len();
@@ -589,8 +1147,11 @@ where
             None
         };
 
+        // Type into the query input rather than navigate while it is focused.
+        let vim_active = self.vim_mode && self.focus_handle.is_focused(window);
+
         v_flex()
-            .key_context("List")
+            .key_context(vim_mode::key_context("List", vim_active))
             .id("list")
             .track_focus(&self.focus_handle)
             .size_full()
@@ -626,8 +1187,18 @@ where
                     .on_action(cx.listener(Self::on_action_confirm))
                     .on_action(cx.listener(Self::on_action_select_next))
                     .on_action(cx.listener(Self::on_action_select_prev))
+                    .when(self.vim_mode, |this| {
+                        this.on_action(cx.listener(Self::on_action_move_down))
+                            .on_action(cx.listener(Self::on_action_move_up))
+                            .on_action(cx.listener(Self::on_action_move_to_top))
+                            .on_action(cx.listener(Self::on_action_move_to_bottom))
+                            .on_action(cx.listener(Self::on_action_start_filter))
+                            .on_action(cx.listener(Self::on_action_toggle_visual))
+                    })
                     .map(|this| {
-                        if let Some(view) = initial_view {
+                        if let Some(error) = self.delegate.error(cx) {
+                            this.child(self.delegate().render_error(error, window, cx))
+                        } else if let Some(view) = initial_view {
                             this.child(view)
                         } else {
                             this.child(self.render_items(items_count, entities_count, window, cx))
@@ -0,0 +1,168 @@
+use std::time::Duration;
+
+use gpui::{
+    actions, div, prelude::FluentBuilder as _, Animation, AnimationExt as _, AnyElement, App,
+    AppContext as _, ElementId, FocusHandle, InteractiveElement as _, IntoElement, KeyBinding,
+    ParentElement, RenderOnce, SharedString, StyleRefinement, Styled, Window,
+};
+
+use crate::{
+    animation::AnimationSettings as _, spinner::Spinner, v_flex, ActiveTheme as _, StyledExt,
+};
+
+const CONTEXT: &str = "LoadingOverlay";
+
+actions!(loading_overlay, [BlockFocus]);
+
+pub fn init(cx: &mut App) {
+    // Keep the focus on the overlay, to not tab into the blocked content.
+    cx.bind_keys([
+        KeyBinding::new("tab", BlockFocus, Some(CONTEXT)),
+        KeyBinding::new("shift-tab", BlockFocus, Some(CONTEXT)),
+    ]);
+}
+
+struct LoadingOverlayState {
+    focus_handle: FocusHandle,
+    /// Keep the overlay visible to fade out after loading.
+    visible: bool,
+}
+
+/// A wrapper to block the content with a dimmed scrim and a spinner while loading, e.g.: submitting a form.
+///
+/// The scrim intercepts the mouse events, and the focus in the content is moved to the overlay.
+///
+/// ```ignore
+/// LoadingOverlay::new("form")
+///     .loading(self.submitting)
+///     .message("Submitting...")
+///     .child(my_form)
+/// ```
+#[derive(IntoElement)]
+pub struct LoadingOverlay {
+    id: ElementId,
+    style: StyleRefinement,
+    loading: bool,
+    message: Option<SharedString>,
+    children: Vec<AnyElement>,
+}
+
+impl LoadingOverlay {
+    pub fn new(id: impl Into<ElementId>) -> Self {
+        Self {
+            id: id.into(),
+            style: StyleRefinement::default(),
+            loading: false,
+            message: None,
+            children: Vec::new(),
+        }
+    }
+
+    /// Set the loading state, the overlay is shown while loading is true.
+    pub fn loading(mut self, loading: bool) -> Self {
+        self.loading = loading;
+        self
+    }
+
+    /// Set the message to show below the spinner, default is None.
+    pub fn message(mut self, message: impl Into<SharedString>) -> Self {
+        self.message = Some(message.into());
+        self
+    }
+}
+
+impl Styled for LoadingOverlay {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl ParentElement for LoadingOverlay {
+    fn extend(&mut self, elements: impl IntoIterator<Item = AnyElement>) {
+        self.children.extend(elements);
+    }
+}
+
+impl RenderOnce for LoadingOverlay {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let loading = self.loading;
+        let state = window.use_keyed_state(self.id.clone(), cx, |_, cx| LoadingOverlayState {
+            focus_handle: cx.focus_handle(),
+            visible: loading,
+        });
+        let duration = Duration::from_secs_f64(0.2);
+        let animated = cx.animations_enabled();
+        let fading_out = animated && !loading && state.read(cx).visible;
+
+        if fading_out {
+            cx.spawn({
+                let state = state.clone();
+                async move |cx| {
+                    cx.background_executor().timer(duration).await;
+                    _ = state.update(cx, |this, cx| {
+                        this.visible = false;
+                        cx.notify();
+                    });
+                }
+            })
+            .detach();
+        } else if loading != state.read(cx).visible {
+            state.update(cx, |this, _| this.visible = loading);
+        }
+
+        let focus_handle = state.read(cx).focus_handle.clone();
+        if loading && focus_handle.contains_focused(window, cx) && !focus_handle.is_focused(window)
+        {
+            let focus_handle = focus_handle.clone();
+            window.defer(cx, move |window, _| window.focus(&focus_handle));
+        }
+
+        div()
+            .id(self.id)
+            .relative()
+            .track_focus(&focus_handle)
+            .when(loading, |this| {
+                this.key_context(CONTEXT)
+                    .on_action(|_: &BlockFocus, _, _| {})
+            })
+            .refine_style(&self.style)
+            .children(self.children)
+            .when(loading || fading_out, |this| {
+                let overlay = v_flex()
+                    .id("loading-overlay")
+                    .absolute()
+                    .top_0()
+                    .left_0()
+                    .size_full()
+                    .occlude()
+                    .items_center()
+                    .justify_center()
+                    .gap_2()
+                    .bg(cx.theme().background.opacity(0.6))
+                    .child(
+                        Spinner::new()
+                            .centered(true)
+                            .color(cx.theme().muted_foreground)
+                            .when_some(self.message, |this, message| this.label(message)),
+                    );
+
+                if !animated {
+                    return this.child(overlay);
+                }
+
+                if fading_out {
+                    this.child(overlay.with_animation(
+                        "fade-out",
+                        Animation::new(duration),
+                        |this, delta| this.opacity(1. - delta),
+                    ))
+                } else {
+                    this.child(overlay.with_animation(
+                        "fade-in",
+                        Animation::new(duration),
+                        |this, delta| this.opacity(delta),
+                    ))
+                }
+            })
+    }
+}
@@ -282,6 +282,39 @@ impl PopupMenu {
         self
     }
 
+    /// Add Menu Item with a click handler instead of an action.
+    pub fn menu_with_handler(
+        self,
+        label: impl Into<SharedString>,
+        icon: Option<Icon>,
+        handler: impl Fn(&mut Window, &mut App) + 'static,
+    ) -> Self {
+        self.menu_with_handler_and_disabled(label, icon, handler, false)
+    }
+
+    /// Add Menu Item with a click handler and disabled state
+    pub fn menu_with_handler_and_disabled(
+        mut self,
+        label: impl Into<SharedString>,
+        icon: Option<Icon>,
+        handler: impl Fn(&mut Window, &mut App) + 'static,
+        disabled: bool,
+    ) -> Self {
+        if icon.is_some() {
+            self.has_icon = true;
+        }
+
+        self.menu_items.push(PopupMenuItem::Item {
+            icon,
+            label: label.into(),
+            disabled,
+            action: None,
+            is_link: false,
+            handler: Rc::new(handler),
+        });
+        self
+    }
+
     /// Add Menu Item with check icon
     pub fn menu_with_check(
         self,
@@ -630,12 +663,14 @@ impl PopupMenu {
     ) -> Option<impl IntoElement> {
         if let Some(action) = action {
             if let Some(key_binding) = window.bindings_for_action(action.deref()).first() {
-                let el = div().text_color(cx.theme().muted_foreground).children(
-                    key_binding
-                        .keystrokes()
-                        .into_iter()
-                        .map(|key| Kbd::format(key.as_keystroke())),
-                );
+                let el = div()
+                    .text_color(cx.theme().muted_foreground)
+                    .child(Kbd::format_strokes(
+                        key_binding
+                            .keystrokes()
+                            .iter()
+                            .map(|key| key.as_keystroke()),
+                    ));
 
                 return Some(el);
             }
@@ -3,24 +3,26 @@ use std::{rc::Rc, time::Duration};
 use gpui::{
     anchored, div, hsla, point, prelude::FluentBuilder, px, relative, Animation, AnimationExt as _,
     AnyElement, App, Axis, Bounds, BoxShadow, ClickEvent, Div, Edges, FocusHandle, Hsla,
-    InteractiveElement, IntoElement, KeyBinding, MouseButton, ParentElement, Pixels, Point,
-    RenderOnce, SharedString, StyleRefinement, Styled, Window,
+    InteractiveElement, IntoElement, MouseButton, ParentElement, Pixels, Point, RenderOnce,
+    SharedString, StyleRefinement, Styled, Window,
 };
 use rust_i18n::t;
 
 use crate::{
     actions::{Cancel, Confirm},
-    animation::cubic_bezier,
+    animation::{cubic_bezier, AnimationSettings as _},
     button::{Button, ButtonVariant, ButtonVariants as _},
-    h_flex, v_flex, ActiveTheme as _, ContextModal, IconName, Root, Sizable as _, StyledExt,
+    h_flex,
+    keymap::ContextBindings,
+    v_flex, ActiveTheme as _, ContextModal, IconName, Root, Sizable as _, StyledExt,
 };
 
 const CONTEXT: &str = "Modal";
 pub fn init(cx: &mut App) {
-    cx.bind_keys([
-        KeyBinding::new("escape", Cancel, Some(CONTEXT)),
-        KeyBinding::new("enter", Confirm { secondary: false }, Some(CONTEXT)),
-    ]);
+    ContextBindings::new(CONTEXT)
+        .bind("escape", Cancel)
+        .bind("enter", Confirm { secondary: false })
+        .register(cx);
 }
 
 type RenderButtonFn = Box<dyn FnOnce(&mut Window, &mut App) -> AnyElement>;
@@ -371,6 +373,7 @@ impl RenderOnce for Modal {
             paddings.bottom = pb.to_pixels(base_size, rem_size);
         }
 
+        let animated = cx.animations_enabled();
         let animation = Animation::new(Duration::from_secs_f64(0.25))
             .with_easing(cubic_bezier(0.32, 0.72, 0., 1.));
 
@@ -502,27 +505,47 @@ impl RenderOnce for Modal {
                                         .children(footer(render_ok, render_cancel, window, cx)),
                                 )
                             })
-                            .with_animation("slide-down", animation.clone(), move |this, delta| {
-                                let y_offset = px(0.) + delta * px(30.);
-                                // This is equivalent to `shadow_xl` with an extra opacity.
-                                let shadow = vec![
-                                    BoxShadow {
-                                        color: hsla(0., 0., 0., 0.1 * delta),
-                                        offset: point(px(0.), px(20.)),
-                                        blur_radius: px(25.),
-                                        spread_radius: px(-5.),
-                                    },
-                                    BoxShadow {
-                                        color: hsla(0., 0., 0., 0.1 * delta),
-                                        offset: point(px(0.), px(8.)),
-                                        blur_radius: px(10.),
-                                        spread_radius: px(-6.),
+                            .map(|this| {
+                                if !animated {
+                                    return this.top(y + px(30.)).shadow_xl().into_any_element();
+                                }
+
+                                this.with_animation(
+                                    "slide-down",
+                                    animation.clone(),
+                                    move |this, delta| {
+                                        let y_offset = px(0.) + delta * px(30.);
+                                        // This is equivalent to `shadow_xl` with an extra opacity.
+                                        let shadow = vec![
+                                            BoxShadow {
+                                                color: hsla(0., 0., 0., 0.1 * delta),
+                                                offset: point(px(0.), px(20.)),
+                                                blur_radius: px(25.),
+                                                spread_radius: px(-5.),
+                                            },
+                                            BoxShadow {
+                                                color: hsla(0., 0., 0., 0.1 * delta),
+                                                offset: point(px(0.), px(8.)),
+                                                blur_radius: px(10.),
+                                                spread_radius: px(-6.),
+                                            },
+                                        ];
+                                        this.top(y + y_offset).shadow(shadow)
                                     },
-                                ];
-                                this.top(y + y_offset).shadow(shadow)
+                                )
+                                .into_any_element()
                             }),
                     )
-                    .with_animation("fade-in", animation, move |this, delta| this.opacity(delta)),
+                    .map(|this| {
+                        if !animated {
+                            return this.into_any_element();
+                        }
+
+                        this.with_animation("fade-in", animation, move |this, delta| {
+                            this.opacity(delta)
+                        })
+                        .into_any_element()
+                    }),
             )
     }
 }
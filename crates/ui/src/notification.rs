@@ -6,26 +6,31 @@ use std::{
 };
 
 use gpui::{
-    div, prelude::FluentBuilder, px, Animation, AnimationExt, AnyElement, App, AppContext,
-    ClickEvent, Context, DismissEvent, ElementId, Entity, EventEmitter, InteractiveElement as _,
-    IntoElement, ParentElement as _, Render, SharedString, StatefulInteractiveElement,
-    StyleRefinement, Styled, Subscription, Window,
+    div, prelude::FluentBuilder, px, Animation, AnimationExt, AnyElement, AnyWindowHandle, App,
+    AppContext, ClickEvent, Context, DismissEvent, ElementId, Entity, EventEmitter, Global,
+    InteractiveElement as _, IntoElement, ParentElement as _, Render, SharedString,
+    StatefulInteractiveElement, StyleRefinement, Styled, Subscription, Window,
 };
 use smol::Timer;
 
 use crate::{
-    animation::cubic_bezier,
+    animation::{cubic_bezier, AnimationSettings as _},
     button::{Button, ButtonVariants as _},
-    h_flex, v_flex, ActiveTheme as _, Icon, IconName, Sizable as _, StyledExt,
+    h_flex,
+    indicator::Indicator,
+    v_flex, ActiveTheme as _, Icon, IconName, Root, Sizable as _, StyledExt,
 };
 
-#[derive(Debug, Clone, Copy, Default)]
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
 pub enum NotificationType {
     #[default]
     Info,
     Success,
     Warning,
     Error,
+    /// A notification with a spinner, it is not auto hidden, update it to the result by the
+    /// [`NotificationHandle`].
+    Loading,
 }
 
 impl NotificationType {
@@ -35,6 +40,9 @@ impl NotificationType {
             Self::Success => Icon::new(IconName::CircleCheck).text_color(cx.theme().success),
             Self::Warning => Icon::new(IconName::TriangleAlert).text_color(cx.theme().warning),
             Self::Error => Icon::new(IconName::CircleX).text_color(cx.theme().danger),
+            Self::Loading => {
+                Icon::new(IconName::LoaderCircle).text_color(cx.theme().muted_foreground)
+            }
         }
     }
 }
@@ -73,6 +81,8 @@ pub struct Notification {
     action_builder: Option<Rc<dyn Fn(&mut Window, &mut Context<Self>) -> Button>>,
     content_builder: Option<Rc<dyn Fn(&mut Window, &mut Context<Self>) -> AnyElement>>,
     on_click: Option<Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>>,
+    /// The count of the same notifications pushed with the same id, shown when more than 1.
+    count: usize,
     closing: bool,
 }
 
@@ -127,6 +137,7 @@ impl Notification {
             action_builder: None,
             content_builder: None,
             on_click: None,
+            count: 1,
             closing: false,
         }
     }
@@ -160,6 +171,22 @@ impl Notification {
             .with_type(NotificationType::Error)
     }
 
+    /// Create a loading notification, e.g.: "Saving...", it is not auto hidden.
+    ///
+    /// Update it to the result by the [`NotificationHandle`] from [`NotificationExt::push_notification`].
+    pub fn loading(message: impl Into<SharedString>) -> Self {
+        Self::new()
+            .message(message)
+            .with_type(NotificationType::Loading)
+            .autohide(false)
+    }
+
+    /// Returns true if the notification has the same type, title and message,
+    /// to collapse the repeated notifications with a count.
+    fn is_same(&self, other: &Self) -> bool {
+        self.type_ == other.type_ && self.title == other.title && self.message == other.message
+    }
+
     /// Set the type for unique identification of the notification.
     ///
     /// ```rs
@@ -172,6 +199,9 @@ impl Notification {
     }
 
     /// Set the type and id of the notification, used to uniquely identify the notification.
+    ///
+    /// The repeated notifications with the same id, type, title and message are collapsed
+    /// into one with a count.
     pub fn id1<T: Sized + 'static>(mut self, key: impl Into<ElementId>) -> Self {
         self.id = (TypeId::of::<T>(), key.into()).into();
         self
@@ -225,6 +255,11 @@ impl Notification {
 
     /// Dismiss the notification.
     pub fn dismiss(&mut self, _: &mut Window, cx: &mut Context<Self>) {
+        if !cx.animations_enabled() {
+            cx.emit(DismissEvent);
+            return;
+        }
+
         self.closing = true;
         cx.notify();
 
@@ -262,13 +297,19 @@ impl Styled for Notification {
 impl Render for Notification {
     fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
         let closing = self.closing;
+        let count = self.count;
         let icon = match self.type_ {
-            None => self.icon.clone(),
-            Some(type_) => Some(type_.icon(cx)),
+            None => self.icon.clone().map(|icon| icon.into_any_element()),
+            Some(NotificationType::Loading) => Some(
+                Indicator::new()
+                    .icon(NotificationType::Loading.icon(cx))
+                    .into_any_element(),
+            ),
+            Some(type_) => Some(type_.icon(cx).into_any_element()),
         };
         let has_icon = icon.is_some();
 
-        h_flex()
+        let notification = h_flex()
             .id("notification")
             .group("")
             .occlude()
@@ -301,6 +342,18 @@ impl Render for Notification {
                         this.child(child_builder(window, cx))
                     }),
             )
+            .when(count > 1, |this| {
+                this.child(
+                    div()
+                        .flex_shrink_0()
+                        .px_1p5()
+                        .rounded_full()
+                        .bg(cx.theme().secondary)
+                        .text_xs()
+                        .text_color(cx.theme().secondary_foreground)
+                        .child(format!("×{}", count)),
+                )
+            })
             .when_some(self.action_builder.clone(), |this, action_builder| {
                 this.child(action_builder(window, cx).small().mr_3p5())
             })
@@ -324,7 +377,13 @@ impl Render for Notification {
                             .xsmall()
                             .on_click(cx.listener(|this, _, window, cx| this.dismiss(window, cx))),
                     ),
-            )
+            );
+
+        if !cx.animations_enabled() {
+            return notification.into_any_element();
+        }
+
+        notification
             .with_animation(
                 ElementId::NamedInteger("slide-down".into(), closing as u64),
                 Animation::new(Duration::from_secs_f64(0.25))
@@ -346,6 +405,7 @@ impl Render for Notification {
                     }
                 },
             )
+            .into_any_element()
     }
 }
 
@@ -372,12 +432,31 @@ impl NotificationList {
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
-        let notification = notification.into();
+        self.push_inner(notification.into(), true, window, cx);
+    }
+
+    /// Push the notification, replace the one with the same id in place to keep unique,
+    /// and count the repeated notifications if `collapse`.
+    fn push_inner(
+        &mut self,
+        mut notification: Notification,
+        collapse: bool,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
         let id = notification.id.clone();
         let autohide = notification.autohide;
 
-        // Remove the notification by id, for keep unique.
-        self.notifications.retain(|note| note.read(cx).id != id);
+        let ix = self
+            .notifications
+            .iter()
+            .position(|note| note.read(cx).id == id);
+        if let Some(ix) = ix {
+            let prev = self.notifications[ix].read(cx);
+            if collapse && !prev.closing && prev.is_same(&notification) {
+                notification.count = prev.count + 1;
+            }
+        }
 
         let notification = cx.new(|_| notification);
 
@@ -389,7 +468,10 @@ impl NotificationList {
             }),
         );
 
-        self.notifications.push_back(notification.clone());
+        match ix {
+            Some(ix) => self.notifications[ix] = notification.clone(),
+            None => self.notifications.push_back(notification.clone()),
+        }
         if autohide {
             // Sleep for 5 seconds to autohide the notification
             cx.spawn_in(window, async move |_, cx| {
@@ -419,6 +501,12 @@ impl NotificationList {
         cx.notify();
     }
 
+    fn contains(&self, id: &NotificationId, cx: &App) -> bool {
+        self.notifications
+            .iter()
+            .any(|note| &note.read(cx).id == id)
+    }
+
     pub fn clear(&mut self, _: &mut Window, cx: &mut Context<Self>) {
         self.notifications.clear();
         cx.notify();
@@ -451,3 +539,124 @@ impl Render for NotificationList {
         )
     }
 }
+
+/// The notifications pushed before any window is able to show them, see [`NotificationExt`].
+#[derive(Default)]
+struct PendingNotifications(Vec<Notification>);
+
+impl Global for PendingNotifications {}
+
+pub(crate) fn has_pending(cx: &App) -> bool {
+    cx.try_global::<PendingNotifications>()
+        .map_or(false, |pending| !pending.0.is_empty())
+}
+
+/// Push the pending notifications to the list, called when the [`Root`] renders.
+pub(crate) fn flush_pending(list: &Entity<NotificationList>, window: &mut Window, cx: &mut App) {
+    if !has_pending(cx) {
+        return;
+    }
+
+    let notifications = std::mem::take(&mut cx.global_mut::<PendingNotifications>().0);
+    list.update(cx, |list, cx| {
+        for notification in notifications {
+            list.push_inner(notification, true, window, cx);
+        }
+    });
+}
+
+fn notification_list(window: &Window, cx: &App) -> Option<Entity<NotificationList>> {
+    let root = window.root::<Root>().flatten()?;
+    Some(root.read(cx).notification.clone())
+}
+
+/// Returns the window showing the notification with the id.
+fn window_of(id: &NotificationId, cx: &mut App) -> Option<AnyWindowHandle> {
+    cx.windows().into_iter().find(|window| {
+        window
+            .update(cx, |_, window, cx| {
+                notification_list(window, cx).map_or(false, |list| list.read(cx).contains(id, cx))
+            })
+            .unwrap_or(false)
+    })
+}
+
+fn push_to_app(notification: Notification, collapse: bool, cx: &mut App) {
+    let window = window_of(&notification.id, cx)
+        .or_else(|| cx.active_window())
+        .or_else(|| cx.windows().first().copied());
+
+    let mut notification = Some(notification);
+    if let Some(window) = window {
+        let _ = window.update(cx, |_, window, cx| {
+            if let Some(list) = notification_list(window, cx) {
+                if let Some(notification) = notification.take() {
+                    list.update(cx, |list, cx| {
+                        list.push_inner(notification, collapse, window, cx)
+                    });
+                }
+            }
+        });
+    }
+
+    // Keep it until a window with Root renders, so it is never lost.
+    if let Some(notification) = notification {
+        cx.default_global::<PendingNotifications>()
+            .0
+            .push(notification);
+    }
+}
+
+/// A handle to update or dismiss a pushed notification, e.g.: from "Saving..." to "Saved".
+#[derive(Debug, Clone)]
+pub struct NotificationHandle {
+    id: NotificationId,
+}
+
+impl NotificationHandle {
+    /// Replace the notification in place, the id of the `notification` is ignored.
+    pub fn update(&self, notification: impl Into<Notification>, cx: &mut App) {
+        let mut notification = notification.into();
+        notification.id = self.id.clone();
+        push_to_app(notification, false, cx);
+    }
+
+    /// Dismiss the notification, or remove it if it is still pending.
+    pub fn dismiss(&self, cx: &mut App) {
+        if let Some(pending) = cx.try_global::<PendingNotifications>() {
+            if pending.0.iter().any(|note| note.id == self.id) {
+                cx.global_mut::<PendingNotifications>()
+                    .0
+                    .retain(|note| note.id != self.id);
+            }
+        }
+
+        if let Some(window) = window_of(&self.id, cx) {
+            let _ = window.update(cx, |_, window, cx| {
+                if let Some(list) = notification_list(window, cx) {
+                    list.update(cx, |list, cx| list.close(self.id.clone(), window, cx));
+                }
+            });
+        }
+    }
+}
+
+/// Extension trait for [`App`] to push notifications without a [`Window`], e.g.: from background tasks.
+pub trait NotificationExt {
+    /// Push a notification to the window showing the notification with the same id,
+    /// or the active window, returns a handle to update it later.
+    ///
+    /// If there is no window to show it yet, the notification is queued and shown when a window renders the [`Root`].
+    fn push_notification(&mut self, notification: impl Into<Notification>) -> NotificationHandle;
+}
+
+impl NotificationExt for App {
+    fn push_notification(&mut self, notification: impl Into<Notification>) -> NotificationHandle {
+        let notification = notification.into();
+        let handle = NotificationHandle {
+            id: notification.id.clone(),
+        };
+        push_to_app(notification, true, self);
+        handle
+    }
+}
@@ -0,0 +1,340 @@
+//! Format and parse the numbers by the locale, shared by the numeric components, e.g.:
+//! the [`crate::input::NumberInput`], [`crate::slider::Slider`], the axes of the
+//! [`crate::chart`] and [`crate::pagination`].
+//!
+//! The locale is [`crate::locale`] by default, so [`crate::set_locale`] switches the numbers
+//! with the translations, or set a fixed one by [`NumberFormat::locale`].
+//!
+//! ```ignore
+//! let format = NumberFormat::currency("$");
+//! assert_eq!(format_number(1234.5, &format), "$1,234.50");
+//! assert_eq!(parse_number("$1,234.50", &format), Some(1234.5));
+//! ```
+use gpui::SharedString;
+
+/// The compact units from the smallest, e.g.: `1.2K`.
+const COMPACT_UNITS: [&str; 4] = ["K", "M", "B", "T"];
+
+/// The style of the [`NumberFormat`].
+#[derive(Debug, Clone, Default, PartialEq)]
+pub enum NumberStyle {
+    /// The plain number, e.g.: `1,234.5`.
+    #[default]
+    Decimal,
+    /// The number with a unit of thousands, e.g.: `1.2K`, `3.4M`.
+    Compact,
+    /// The ratio as a percentage, e.g.: `0.25` is `25%`.
+    Percent,
+    /// The amount with the currency symbol, e.g.: `$1,234.50`, or `1.234,50 €` in `it`.
+    Currency(SharedString),
+}
+
+/// The options to format and parse a number, see [`format_number`] and [`parse_number`].
+#[derive(Debug, Clone, PartialEq)]
+pub struct NumberFormat {
+    style: NumberStyle,
+    min_fraction_digits: Option<usize>,
+    max_fraction_digits: Option<usize>,
+    grouping: bool,
+    locale: Option<SharedString>,
+}
+
+impl Default for NumberFormat {
+    fn default() -> Self {
+        Self {
+            style: NumberStyle::default(),
+            min_fraction_digits: None,
+            max_fraction_digits: None,
+            grouping: true,
+            locale: None,
+        }
+    }
+}
+
+impl NumberFormat {
+    /// The plain number with up to 3 fraction digits.
+    pub fn decimal() -> Self {
+        Self::default()
+    }
+
+    /// The number with a unit of thousands with up to 1 fraction digit.
+    pub fn compact() -> Self {
+        Self::default().style(NumberStyle::Compact)
+    }
+
+    /// The percentage without the fraction digits.
+    pub fn percent() -> Self {
+        Self::default().style(NumberStyle::Percent)
+    }
+
+    /// The amount with the currency `symbol` and 2 fraction digits.
+    pub fn currency(symbol: impl Into<SharedString>) -> Self {
+        Self::default().style(NumberStyle::Currency(symbol.into()))
+    }
+
+    pub fn style(mut self, style: NumberStyle) -> Self {
+        self.style = style;
+        self
+    }
+
+    /// Set the min and max number of the fraction digits, the default is by the style.
+    pub fn fraction_digits(mut self, min: usize, max: usize) -> Self {
+        self.min_fraction_digits = Some(min.min(max));
+        self.max_fraction_digits = Some(max);
+        self
+    }
+
+    /// Set whether to group the integer digits by the thousands separator, default is true.
+    pub fn grouping(mut self, grouping: bool) -> Self {
+        self.grouping = grouping;
+        self
+    }
+
+    /// Set the locale of the separators, e.g.: `en`, `it`, default is [`crate::locale`].
+    pub fn locale(mut self, locale: impl Into<SharedString>) -> Self {
+        self.locale = Some(locale.into());
+        self
+    }
+
+    fn resolved_fraction_digits(&self) -> (usize, usize) {
+        let (min, max) = match self.style {
+            NumberStyle::Decimal => (0, 3),
+            NumberStyle::Compact => (0, 1),
+            NumberStyle::Percent => (0, 0),
+            NumberStyle::Currency(_) => (2, 2),
+        };
+        let max = self.max_fraction_digits.unwrap_or(max);
+        (self.min_fraction_digits.unwrap_or(min).min(max), max)
+    }
+
+    fn symbols(&self) -> NumberSymbols {
+        match &self.locale {
+            Some(locale) => NumberSymbols::of(locale),
+            None => NumberSymbols::of(&crate::locale()),
+        }
+    }
+}
+
+/// The separators of a locale.
+#[derive(Debug, Clone, Copy, PartialEq)]
+struct NumberSymbols {
+    decimal: char,
+    group: char,
+    /// Whether the currency symbol is after the amount.
+    currency_after: bool,
+}
+
+impl NumberSymbols {
+    fn of(locale: &str) -> Self {
+        let language = locale.split(['-', '_']).next().unwrap_or_default();
+        match language.to_ascii_lowercase().as_str() {
+            "it" | "de" | "es" | "pt" | "nl" | "id" | "tr" | "da" => Self {
+                decimal: ',',
+                group: '.',
+                currency_after: true,
+            },
+            "fr" | "ru" | "uk" | "pl" | "cs" | "sv" | "fi" | "nb" => Self {
+                decimal: ',',
+                group: '\u{a0}',
+                currency_after: true,
+            },
+            _ => Self {
+                decimal: '.',
+                group: ',',
+                currency_after: false,
+            },
+        }
+    }
+}
+
+/// Format the `value` by the `format`, the non-finite value is formatted as is.
+pub fn format_number(value: f64, format: &NumberFormat) -> SharedString {
+    if !value.is_finite() {
+        return value.to_string().into();
+    }
+
+    let symbols = format.symbols();
+    let (min_digits, max_digits) = format.resolved_fraction_digits();
+    let (value, suffix) = match &format.style {
+        NumberStyle::Compact => compact(value, max_digits),
+        NumberStyle::Percent => (value * 100., "%"),
+        _ => (value, ""),
+    };
+
+    let digits = format!("{:.*}", max_digits, value.abs());
+    let (int_part, frac_part) = digits.split_once('.').unwrap_or((&digits, ""));
+    // Avoid the `-0` if the value is rounded to zero.
+    let sign = if value < 0. && digits.chars().any(|c| c.is_ascii_digit() && c != '0') {
+        "-"
+    } else {
+        ""
+    };
+
+    let mut number = String::with_capacity(digits.len() + 8);
+    for (ix, ch) in int_part.chars().enumerate() {
+        if format.grouping && ix > 0 && (int_part.len() - ix) % 3 == 0 {
+            number.push(symbols.group);
+        }
+        number.push(ch);
+    }
+    let frac_part = frac_part.trim_end_matches('0');
+    let frac_len = frac_part.len().max(min_digits);
+    if frac_len > 0 {
+        number.push(symbols.decimal);
+        number.push_str(frac_part);
+        number.extend(std::iter::repeat('0').take(frac_len - frac_part.len()));
+    }
+    number.push_str(suffix);
+
+    match &format.style {
+        NumberStyle::Currency(symbol) if symbols.currency_after => {
+            format!("{}{}\u{a0}{}", sign, number, symbol)
+        }
+        NumberStyle::Currency(symbol) => format!("{}{}{}", sign, symbol, number),
+        _ => format!("{}{}", sign, number),
+    }
+    .into()
+}
+
+/// Returns the scaled value and the unit, the unit is bumped if the rounded value is 1000,
+/// e.g.: `999_999` is `1M` rather than `1000K`.
+fn compact(value: f64, max_digits: usize) -> (f64, &'static str) {
+    let precision = 10f64.powi(max_digits as i32);
+    let mut scaled = value;
+    let mut unit = "";
+    for next_unit in COMPACT_UNITS {
+        if (scaled.abs() * precision).round() / precision < 1000. {
+            break;
+        }
+        scaled /= 1000.;
+        unit = next_unit;
+    }
+    (scaled, unit)
+}
+
+/// Parse the `text` formatted by the `format`, the separators of the locale, the currency
+/// symbol, the percent sign and the compact unit are optional.
+///
+/// Returns None if the text is not a number.
+pub fn parse_number(text: &str, format: &NumberFormat) -> Option<f64> {
+    let symbols = format.symbols();
+    let mut text = text
+        .chars()
+        .filter(|ch| !ch.is_whitespace() && *ch != symbols.group)
+        .map(|ch| match ch {
+            '\u{2212}' => '-',
+            ch if ch == symbols.decimal => '.',
+            ch => ch,
+        })
+        .collect::<String>();
+
+    let mut scale = 1.;
+    let mut percent = false;
+    match &format.style {
+        NumberStyle::Compact => {
+            let unit = COMPACT_UNITS
+                .iter()
+                .position(|unit| text.to_ascii_uppercase().ends_with(unit));
+            if let Some(ix) = unit {
+                text.pop();
+                scale = 1000f64.powi(ix as i32 + 1);
+            }
+        }
+        NumberStyle::Percent => {
+            text = text.trim_end_matches('%').to_string();
+            percent = true;
+        }
+        NumberStyle::Currency(symbol) => text = text.replace(&**symbol, ""),
+        NumberStyle::Decimal => {}
+    }
+
+    let digits = text.strip_prefix(['-', '+']).unwrap_or(&text);
+    if digits.is_empty() || !digits.chars().all(|ch| ch.is_ascii_digit() || ch == '.') {
+        return None;
+    }
+
+    let value = text.parse::<f64>().ok()? * scale;
+    // Divide by 100 for the exact result, e.g.: `7%` is `0.07` rather than `7 * 0.01`.
+    Some(if percent { value / 100. } else { value })
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{format_number, parse_number, NumberFormat};
+
+    #[test]
+    fn test_format_decimal() {
+        let en = NumberFormat::decimal().locale("en");
+        assert_eq!(format_number(0., &en), "0");
+        assert_eq!(format_number(1234567.891, &en), "1,234,567.891");
+        assert_eq!(format_number(-1234.5, &en), "-1,234.5");
+        assert_eq!(format_number(0.0001, &en), "0");
+        assert_eq!(format_number(-0.0001, &en), "0");
+        assert_eq!(format_number(123., &en), "123");
+        assert_eq!(format_number(f64::NAN, &en), "NaN");
+
+        let it = NumberFormat::decimal().locale("it");
+        assert_eq!(format_number(1234567.891, &it), "1.234.567,891");
+        let fr = NumberFormat::decimal().locale("fr-FR");
+        assert_eq!(format_number(1234.5, &fr), "1\u{a0}234,5");
+
+        let format = en.fraction_digits(2, 2).grouping(false);
+        assert_eq!(format_number(1234.5, &format), "1234.50");
+        assert_eq!(format_number(1.005, &format), "1.00");
+    }
+
+    #[test]
+    fn test_format_styles() {
+        let compact = NumberFormat::compact().locale("en");
+        assert_eq!(format_number(999., &compact), "999");
+        assert_eq!(format_number(1234., &compact), "1.2K");
+        assert_eq!(format_number(3_400_000., &compact), "3.4M");
+        assert_eq!(format_number(999_999., &compact), "1M");
+        assert_eq!(format_number(-5_600_000_000., &compact), "-5.6B");
+        assert_eq!(format_number(1e15, &compact), "1,000T");
+
+        let percent = NumberFormat::percent().locale("en");
+        assert_eq!(format_number(0.256, &percent), "26%");
+        assert_eq!(
+            format_number(0.256, &percent.fraction_digits(1, 1)),
+            "25.6%"
+        );
+
+        let usd = NumberFormat::currency("$").locale("en");
+        assert_eq!(format_number(1234.5, &usd), "$1,234.50");
+        assert_eq!(format_number(-3., &usd), "-$3.00");
+        let eur = NumberFormat::currency("€").locale("it");
+        assert_eq!(format_number(1234.5, &eur), "1.234,50\u{a0}€");
+    }
+
+    #[test]
+    fn test_parse_number() {
+        let en = NumberFormat::decimal().locale("en");
+        assert_eq!(parse_number("1,234.5", &en), Some(1234.5));
+        assert_eq!(parse_number(" -12 ", &en), Some(-12.));
+        assert_eq!(parse_number("+.5", &en), Some(0.5));
+        assert_eq!(parse_number("", &en), None);
+        assert_eq!(parse_number("-", &en), None);
+        assert_eq!(parse_number("abc", &en), None);
+        assert_eq!(parse_number("inf", &en), None);
+        assert_eq!(parse_number("1.2.3", &en), None);
+
+        let it = NumberFormat::decimal().locale("it");
+        assert_eq!(parse_number("1.234,5", &it), Some(1234.5));
+
+        let compact = NumberFormat::compact().locale("en");
+        assert_eq!(parse_number("1.2k", &compact), Some(1200.));
+        assert_eq!(parse_number("3M", &compact), Some(3_000_000.));
+
+        let percent = NumberFormat::percent().locale("en");
+        assert_eq!(parse_number("7%", &percent), Some(0.07));
+        assert_eq!(parse_number("25", &percent), Some(0.25));
+
+        let eur = NumberFormat::currency("€").locale("it");
+        assert_eq!(parse_number("1.234,50\u{a0}€", &eur), Some(1234.5));
+        assert_eq!(
+            parse_number(&format_number(-98765.4, &eur), &eur),
+            Some(-98765.4)
+        );
+    }
+}
@@ -0,0 +1,452 @@
+use gpui::{
+    actions, div, prelude::FluentBuilder as _, App, AppContext as _, Context, ElementId, Entity,
+    EventEmitter, FocusHandle, Focusable, InteractiveElement as _, IntoElement, KeyBinding,
+    ParentElement as _, RenderOnce, SharedString, StyleRefinement, Styled, Window,
+};
+use rust_i18n::t;
+
+use crate::{
+    button::{Button, ButtonVariants as _},
+    h_flex,
+    number_format::{format_number, NumberFormat},
+    popup_menu::PopupMenuExt as _,
+    ActiveTheme as _, Disableable as _, IconName, Sizable, Size, StyledExt as _,
+};
+
+const CONTEXT: &str = "Pagination";
+/// The pages to show at each side of the current page, the count of the page items is constant.
+const SIBLINGS: usize = 1;
+
+actions!(pagination, [PrevPage, NextPage, FirstPage, LastPage]);
+
+pub fn init(cx: &mut App) {
+    cx.bind_keys([
+        KeyBinding::new("left", PrevPage, Some(CONTEXT)),
+        KeyBinding::new("right", NextPage, Some(CONTEXT)),
+        KeyBinding::new("home", FirstPage, Some(CONTEXT)),
+        KeyBinding::new("end", LastPage, Some(CONTEXT)),
+    ]);
+}
+
+/// Format the page number or the count of items with the thousands separator of the locale.
+fn format_count(count: usize) -> SharedString {
+    format_number(count as f64, &NumberFormat::decimal())
+}
+
+pub enum PaginationEvent {
+    /// The page is changed, with the new page number (start from 1).
+    PageChanged(usize),
+    /// The page size is changed, with the new page size.
+    PageSizeChanged(usize),
+}
+
+/// A page number button or an ellipsis for the collapsed pages.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+enum PageItem {
+    Page(usize),
+    Ellipsis,
+}
+
+/// Returns the page items with the first and last pages, and the siblings of the current page,
+/// the pages between are collapsed into an ellipsis.
+fn page_items(page: usize, pages_count: usize) -> Vec<PageItem> {
+    // The first, last, current, siblings and the two ellipses.
+    let max_items = 2 * SIBLINGS + 5;
+    if pages_count <= max_items {
+        return (1..=pages_count).map(PageItem::Page).collect();
+    }
+
+    // The pages at a side without ellipsis, to keep the count of the items.
+    let side_pages = 2 * SIBLINGS + 3;
+    let mut items = vec![];
+    if page <= SIBLINGS + 3 {
+        items.extend((1..=side_pages).map(PageItem::Page));
+        items.push(PageItem::Ellipsis);
+        items.push(PageItem::Page(pages_count));
+    } else if page + SIBLINGS + 2 >= pages_count {
+        items.push(PageItem::Page(1));
+        items.push(PageItem::Ellipsis);
+        items.extend((pages_count + 1 - side_pages..=pages_count).map(PageItem::Page));
+    } else {
+        items.push(PageItem::Page(1));
+        items.push(PageItem::Ellipsis);
+        items.extend((page - SIBLINGS..=page + SIBLINGS).map(PageItem::Page));
+        items.push(PageItem::Ellipsis);
+        items.push(PageItem::Page(pages_count));
+    }
+    items
+}
+
+/// State of the [`Pagination`], the page number starts from 1.
+pub struct PaginationState {
+    focus_handle: FocusHandle,
+    page: usize,
+    page_size: usize,
+    total: usize,
+}
+
+impl PaginationState {
+    pub fn new(cx: &mut App) -> Self {
+        Self {
+            focus_handle: cx.focus_handle(),
+            page: 1,
+            page_size: 10,
+            total: 0,
+        }
+    }
+
+    /// Set the total number of items, default is 0.
+    pub fn total(mut self, total: usize) -> Self {
+        self.total = total;
+        self
+    }
+
+    /// Set the number of items per page, default is 10.
+    pub fn page_size(mut self, page_size: usize) -> Self {
+        self.page_size = page_size.max(1);
+        self
+    }
+
+    /// Returns the current page number, start from 1.
+    pub fn page(&self) -> usize {
+        self.page
+    }
+
+    /// Returns the number of items per page.
+    pub fn current_page_size(&self) -> usize {
+        self.page_size
+    }
+
+    /// Returns the total number of items.
+    pub fn total_count(&self) -> usize {
+        self.total
+    }
+
+    /// Returns the count of the pages, at least 1.
+    pub fn pages_count(&self) -> usize {
+        self.total.div_ceil(self.page_size).max(1)
+    }
+
+    /// Returns the range of the item indexes in the current page.
+    pub fn items_range(&self) -> std::ops::Range<usize> {
+        let start = ((self.page - 1) * self.page_size).min(self.total);
+        start..(start + self.page_size).min(self.total)
+    }
+
+    /// Set the total number of items, the page is clamped to the last page.
+    pub fn set_total(&mut self, total: usize, cx: &mut Context<Self>) {
+        self.total = total;
+        let page = self.page.min(self.pages_count());
+        self.set_page(page, cx);
+        cx.notify();
+    }
+
+    /// Go to the page, clamped to the pages, emits [`PaginationEvent::PageChanged`] if changed.
+    pub fn set_page(&mut self, page: usize, cx: &mut Context<Self>) {
+        let page = page.clamp(1, self.pages_count());
+        if page == self.page {
+            return;
+        }
+
+        self.page = page;
+        cx.emit(PaginationEvent::PageChanged(page));
+        cx.notify();
+    }
+
+    /// Set the number of items per page, emits [`PaginationEvent::PageSizeChanged`] if changed.
+    ///
+    /// The page is changed to keep the first item of the current page visible.
+    pub fn set_page_size(&mut self, page_size: usize, cx: &mut Context<Self>) {
+        let page_size = page_size.max(1);
+        if page_size == self.page_size {
+            return;
+        }
+
+        let first_ix = self.items_range().start;
+        self.page_size = page_size;
+        cx.emit(PaginationEvent::PageSizeChanged(page_size));
+        self.set_page(first_ix / page_size + 1, cx);
+        cx.notify();
+    }
+
+    fn prev_page(&mut self, _: &PrevPage, _: &mut Window, cx: &mut Context<Self>) {
+        self.set_page(self.page.saturating_sub(1), cx);
+    }
+
+    fn next_page(&mut self, _: &NextPage, _: &mut Window, cx: &mut Context<Self>) {
+        self.set_page(self.page + 1, cx);
+    }
+
+    fn first_page(&mut self, _: &FirstPage, _: &mut Window, cx: &mut Context<Self>) {
+        self.set_page(1, cx);
+    }
+
+    fn last_page(&mut self, _: &LastPage, _: &mut Window, cx: &mut Context<Self>) {
+        self.set_page(self.pages_count(), cx);
+    }
+}
+
+impl EventEmitter<PaginationEvent> for PaginationState {}
+
+impl Focusable for PaginationState {
+    fn focus_handle(&self, _: &App) -> FocusHandle {
+        self.focus_handle.clone()
+    }
+}
+
+/// A pagination to navigate the pages of any data source, e.g.: a server-paged [`crate::table::Table`].
+///
+/// Use `left` / `right` to go to the previous / next page, `home` / `end` to the first / last page.
+#[derive(IntoElement)]
+pub struct Pagination {
+    id: ElementId,
+    state: Entity<PaginationState>,
+    style: StyleRefinement,
+    size: Size,
+    compact: bool,
+    first_last: bool,
+    page_sizes: Vec<usize>,
+    summary: bool,
+}
+
+impl Pagination {
+    /// Create a new [`Pagination`] bind to the [`PaginationState`].
+    pub fn new(id: impl Into<ElementId>, state: &Entity<PaginationState>) -> Self {
+        Self {
+            id: id.into(),
+            state: state.clone(),
+            style: StyleRefinement::default(),
+            size: Size::default(),
+            compact: false,
+            first_last: false,
+            page_sizes: vec![],
+            summary: false,
+        }
+    }
+
+    /// Set true to only show the previous, next buttons and the current page, for tight layouts.
+    pub fn compact(mut self, compact: bool) -> Self {
+        self.compact = compact;
+        self
+    }
+
+    /// Set true to show the first and last page buttons, default is false.
+    pub fn first_last(mut self, first_last: bool) -> Self {
+        self.first_last = first_last;
+        self
+    }
+
+    /// Set the page sizes to show a page size selector, default is empty to hide it.
+    pub fn page_sizes(mut self, page_sizes: impl Into<Vec<usize>>) -> Self {
+        self.page_sizes = page_sizes.into();
+        self
+    }
+
+    /// Set true to show the "Showing X–Y of Z" label, default is false.
+    pub fn summary(mut self, summary: bool) -> Self {
+        self.summary = summary;
+        self
+    }
+
+    fn page_button(&self, page: usize, disabled: bool, button: Button) -> Button {
+        let state = self.state.clone();
+        button
+            .ghost()
+            .with_size(self.size)
+            .disabled(disabled)
+            .on_click(move |_, window, cx| {
+                state.update(cx, |state, cx| {
+                    state.focus_handle.focus(window);
+                    state.set_page(page, cx);
+                })
+            })
+    }
+}
+
+impl Styled for Pagination {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl Sizable for Pagination {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl RenderOnce for Pagination {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = self.state.read(cx);
+        let page = state.page;
+        let pages_count = state.pages_count();
+        let page_size = state.page_size;
+        let items_range = state.items_range();
+        let total = state.total;
+        let focus_handle = state.focus_handle.clone();
+
+        h_flex()
+            .id(self.id.clone())
+            .key_context(CONTEXT)
+            .track_focus(&focus_handle)
+            .on_action(window.listener_for(&self.state, PaginationState::prev_page))
+            .on_action(window.listener_for(&self.state, PaginationState::next_page))
+            .on_action(window.listener_for(&self.state, PaginationState::first_page))
+            .on_action(window.listener_for(&self.state, PaginationState::last_page))
+            .gap_1()
+            .items_center()
+            .refine_style(&self.style)
+            .when(self.summary, |this| {
+                let (start, end) = if total == 0 {
+                    (0, 0)
+                } else {
+                    (items_range.start + 1, items_range.end)
+                };
+
+                this.child(
+                    div()
+                        .mr_2()
+                        .text_sm()
+                        .text_color(cx.theme().muted_foreground)
+                        .child(SharedString::from(t!(
+                            "Pagination.summary",
+                            start = format_count(start),
+                            end = format_count(end),
+                            total = format_count(total)
+                        ))),
+                )
+            })
+            .when(self.first_last, |this| {
+                this.child(self.page_button(
+                    1,
+                    page == 1,
+                    Button::new("first").icon(IconName::ChevronsLeft),
+                ))
+            })
+            .child(self.page_button(
+                page.saturating_sub(1),
+                page == 1,
+                Button::new("prev").icon(IconName::ChevronLeft),
+            ))
+            .map(|this| {
+                if self.compact {
+                    return this.child(div().px_2().text_sm().child(format!(
+                        "{} / {}",
+                        format_count(page),
+                        format_count(pages_count)
+                    )));
+                }
+
+                this.children(page_items(page, pages_count).into_iter().enumerate().map(
+                    |(ix, item)| {
+                        match item {
+                            PageItem::Page(item_page) => self
+                                .page_button(
+                                    ("page", item_page),
+                                    item_page,
+                                    false,
+                                    Button::new(("page", item_page)).label(format_count(item_page)),
+                                )
+                                .when(item_page == page, |this| this.outline())
+                                .into_any_element(),
+                            PageItem::Ellipsis => Button::new(("ellipsis", ix))
+                                .ghost()
+                                .with_size(self.size)
+                                .icon(IconName::Ellipsis)
+                                .disabled(true)
+                                .into_any_element(),
+                        }
+                    },
+                ))
+            })
+            .child(self.page_button(
+                page + 1,
+                page == pages_count,
+                Button::new("next").icon(IconName::ChevronRight),
+            ))
+            .when(self.first_last, |this| {
+                this.child(self.page_button(
+                    pages_count,
+                    page == pages_count,
+                    Button::new("last").icon(IconName::ChevronsRight),
+                ))
+            })
+            .when(!self.page_sizes.is_empty(), |this| {
+                let state = self.state.clone();
+                let page_sizes = self.page_sizes.clone();
+                this.child(
+                    Button::new("page-size")
+                        .outline()
+                        .with_size(self.size)
+                        .label(SharedString::from(t!(
+                            "Pagination.page_size",
+                            size = format_count(page_size)
+                        )))
+                        .popup_menu(move |mut menu, _, _| {
+                            for size in page_sizes.iter().copied() {
+                                let state = state.clone();
+                                menu = menu.menu_with_handler(
+                                    SharedString::from(t!(
+                                        "Pagination.page_size",
+                                        size = format_count(size)
+                                    )),
+                                    None,
+                                    move |_, cx| {
+                                        state.update(cx, |state, cx| state.set_page_size(size, cx))
+                                    },
+                                );
+                            }
+                            menu
+                        }),
+                )
+            })
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{page_items, PageItem::*};
+
+    #[test]
+    fn test_page_items() {
+        assert_eq!(page_items(1, 1), vec![Page(1)]);
+        assert_eq!(page_items(3, 7), (1..=7).map(Page).collect::<Vec<_>>());
+        assert_eq!(
+            page_items(4, 20),
+            vec![
+                Page(1),
+                Page(2),
+                Page(3),
+                Page(4),
+                Page(5),
+                Ellipsis,
+                Page(20)
+            ]
+        );
+        assert_eq!(
+            page_items(10, 20),
+            vec![
+                Page(1),
+                Ellipsis,
+                Page(9),
+                Page(10),
+                Page(11),
+                Ellipsis,
+                Page(20)
+            ]
+        );
+        assert_eq!(
+            page_items(17, 20),
+            vec![
+                Page(1),
+                Ellipsis,
+                Page(16),
+                Page(17),
+                Page(18),
+                Page(19),
+                Page(20)
+            ]
+        );
+    }
+}
@@ -3,7 +3,17 @@ use gpui::{
     Window,
 };
 
-use super::{label::Label, label::Text, label::TEXT_GAP, label::TEXT_SIZE, origin_point};
+use num_traits::{Num, ToPrimitive};
+
+use super::{
+    label::Label,
+    label::Text,
+    label::TEXT_GAP,
+    label::TEXT_SIZE,
+    origin_point,
+    scale::{ScaleLinear, Sealed},
+};
+use crate::number_format::{format_number, NumberFormat};
 
 pub const AXIS_GAP: f32 = 18.;
 
@@ -35,6 +45,30 @@ impl AxisText {
         self.align = align;
         self
     }
+
+    /// Returns the labels of the values of the `scale` at the `ticks`, formatted by the
+    /// `format`, e.g.: for the [`Axis::y_label`] at the grid lines.
+    pub fn numbers<T>(
+        scale: &ScaleLinear<T>,
+        ticks: &[f32],
+        format: &NumberFormat,
+        color: Hsla,
+    ) -> Vec<Self>
+    where
+        T: Copy + PartialOrd + Num + ToPrimitive + Sealed,
+    {
+        ticks
+            .iter()
+            .filter_map(|tick| {
+                let value = scale.invert(*tick)?;
+                Some(Self::new(
+                    format_number(value, format),
+                    *tick + TEXT_GAP,
+                    color,
+                ))
+            })
+            .collect()
+    }
 }
 
 #[derive(Default)]
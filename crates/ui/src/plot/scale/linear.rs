@@ -39,6 +39,16 @@ where
             range_diff: range_max - range_min,
         }
     }
+
+    /// Returns the value of the domain at the `tick` of the range, the inverse of [`Scale::tick`].
+    pub fn invert(&self, tick: f32) -> Option<f64> {
+        if self.range_diff == 0. {
+            return None;
+        }
+
+        let ratio = 1. - (tick - self.range_min) / self.range_diff;
+        Some(self.domain_min.to_f64()? + ratio as f64 * self.domain_diff.to_f64()?)
+    }
 }
 
 impl<T> Scale<T> for ScaleLinear<T>
@@ -89,4 +99,15 @@ mod tests {
         assert_eq!(scale.tick(&2.), Some(0.));
         assert_eq!(scale.tick(&3.), Some(0.));
     }
+
+    #[test]
+    fn test_scale_linear_invert() {
+        let scale = ScaleLinear::new(vec![0., 50., 200.], vec![0., 100.]);
+        assert_eq!(scale.invert(100.), Some(0.));
+        assert_eq!(scale.invert(50.), Some(100.));
+        assert_eq!(scale.invert(0.), Some(200.));
+
+        let scale = ScaleLinear::new(vec![1., 2.], vec![]);
+        assert_eq!(scale.invert(0.), None);
+    }
 }
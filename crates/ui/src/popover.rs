@@ -1,18 +1,20 @@
 use gpui::{
     anchored, deferred, div, prelude::FluentBuilder as _, px, AnyElement, App, Bounds, Context,
     Corner, DismissEvent, DispatchPhase, Element, ElementId, Entity, EventEmitter, FocusHandle,
-    Focusable, GlobalElementId, Hitbox, InteractiveElement as _, IntoElement, KeyBinding, LayoutId,
+    Focusable, GlobalElementId, Hitbox, InteractiveElement as _, IntoElement, LayoutId,
     ManagedView, MouseButton, MouseDownEvent, ParentElement, Pixels, Point, Render, Style,
     StyleRefinement, Styled, Window,
 };
 use std::{cell::RefCell, rc::Rc};
 
-use crate::{actions::Cancel, Selectable, StyledExt as _};
+use crate::{actions::Cancel, keymap::ContextBindings, Selectable, StyledExt as _};
 
 const CONTEXT: &str = "Popover";
 
 pub fn init(cx: &mut App) {
-    cx.bind_keys([KeyBinding::new("escape", Cancel, Some(CONTEXT))])
+    ContextBindings::new(CONTEXT)
+        .bind("escape", Cancel)
+        .register(cx);
 }
 
 pub struct PopoverContent {
@@ -56,8 +58,8 @@ impl Render for PopoverContent {
             .refine_style(&self.style)
             .track_focus(&self.focus_handle)
             .key_context(CONTEXT)
+            // Stop the Escape here, do not close the modal or drawer of the popover.
             .on_action(cx.listener(|_, _: &Cancel, _, cx| {
-                cx.propagate();
                 cx.emit(DismissEvent);
             }))
             .child(self.content.clone()(window, cx))
@@ -6,8 +6,10 @@ use gpui::{
 };
 
 mod panel;
+mod resizable_box;
 mod resize_handle;
 pub use panel::*;
+pub use resizable_box::*;
 pub(crate) use resize_handle::*;
 
 pub(crate) const PANEL_MIN_SIZE: Pixels = px(100.);
@@ -0,0 +1,249 @@
+use std::ops::Range;
+
+use gpui::{
+    canvas, div, prelude::FluentBuilder as _, px, AnyElement, App, AppContext as _, Bounds,
+    Context, DragMoveEvent, ElementId, Empty, Entity, EntityId, EventEmitter,
+    InteractiveElement as _, IntoElement, MouseButton, MouseDownEvent, ParentElement, Pixels,
+    Point, Render, RenderOnce, Size, StatefulInteractiveElement as _, StyleRefinement, Styled,
+    Window,
+};
+
+use crate::{resizable::PANEL_MIN_SIZE, ActiveTheme as _, Icon, IconName, StyledExt as _};
+
+pub enum ResizableBoxEvent {
+    /// The box is resized by dragging the corner handle, with the new size.
+    Resized(Size<Pixels>),
+}
+
+#[derive(Clone)]
+struct DragResizableBox(EntityId);
+
+impl Render for DragResizableBox {
+    fn render(&mut self, _: &mut Window, _: &mut Context<Self>) -> impl IntoElement {
+        Empty
+    }
+}
+
+/// The position and size of a [`ResizableBox`] when starting to drag the corner handle.
+#[derive(Debug, Clone, Copy)]
+struct ResizeDrag {
+    position: Point<Pixels>,
+    size: Size<Pixels>,
+}
+
+/// State for a [`ResizableBox`].
+#[derive(Debug, Clone, Default)]
+pub struct ResizableBoxState {
+    /// The size after resized, `None` to use the initial size.
+    size: Option<Size<Pixels>>,
+    bounds: Bounds<Pixels>,
+    drag: Option<ResizeDrag>,
+}
+
+impl ResizableBoxState {
+    pub fn new(cx: &mut App) -> Entity<Self> {
+        cx.new(|_| Self::default())
+    }
+
+    /// Get the size of the box after resized, `None` if it is not resized yet.
+    pub fn size(&self) -> Option<Size<Pixels>> {
+        self.size
+    }
+
+    /// Set the size of the box.
+    pub fn set_size(&mut self, size: Size<Pixels>, cx: &mut Context<Self>) {
+        self.size = Some(size);
+        cx.notify();
+    }
+}
+
+impl EventEmitter<ResizableBoxEvent> for ResizableBoxState {}
+
+/// Returns the size resized by the `delta` from the `size`, clamped to the ranges.
+fn resize_size(
+    size: Size<Pixels>,
+    delta: Point<Pixels>,
+    width_range: &Range<Pixels>,
+    height_range: &Range<Pixels>,
+) -> Size<Pixels> {
+    gpui::size(
+        (size.width + delta.x).clamp(width_range.start, width_range.end),
+        (size.height + delta.y).clamp(height_range.start, height_range.end),
+    )
+}
+
+/// Create a [`ResizableBox`] to resize the width and height by dragging the bottom-right corner.
+pub fn resizable_box(id: impl Into<ElementId>, state: Entity<ResizableBoxState>) -> ResizableBox {
+    ResizableBox::new(id, state)
+}
+
+/// A box wraps any element with a corner handle to resize both the width and height.
+#[derive(IntoElement)]
+pub struct ResizableBox {
+    id: ElementId,
+    state: Entity<ResizableBoxState>,
+    initial_size: Option<Size<Pixels>>,
+    width_range: Range<Pixels>,
+    height_range: Range<Pixels>,
+    style: StyleRefinement,
+    children: Vec<AnyElement>,
+}
+
+impl ResizableBox {
+    fn new(id: impl Into<ElementId>, state: Entity<ResizableBoxState>) -> Self {
+        Self {
+            id: id.into(),
+            state,
+            initial_size: None,
+            width_range: PANEL_MIN_SIZE..Pixels::MAX,
+            height_range: PANEL_MIN_SIZE..Pixels::MAX,
+            style: StyleRefinement::default(),
+            children: vec![],
+        }
+    }
+
+    /// Set the initial size of the box, default is the size of the children.
+    pub fn initial_size(mut self, size: Size<Pixels>) -> Self {
+        self.initial_size = Some(size);
+        self
+    }
+
+    /// Set the width range to limit the resize.
+    ///
+    /// Default is [`PANEL_MIN_SIZE`] to [`Pixels::MAX`].
+    pub fn width_range(mut self, range: impl Into<Range<Pixels>>) -> Self {
+        self.width_range = range.into();
+        self
+    }
+
+    /// Set the height range to limit the resize.
+    ///
+    /// Default is [`PANEL_MIN_SIZE`] to [`Pixels::MAX`].
+    pub fn height_range(mut self, range: impl Into<Range<Pixels>>) -> Self {
+        self.height_range = range.into();
+        self
+    }
+}
+
+impl Styled for ResizableBox {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl ParentElement for ResizableBox {
+    fn extend(&mut self, elements: impl IntoIterator<Item = AnyElement>) {
+        self.children.extend(elements);
+    }
+}
+
+impl RenderOnce for ResizableBox {
+    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = self.state.clone();
+        let entity_id = state.entity_id();
+        let size = state.read(cx).size.or(self.initial_size);
+        let (width_range, height_range) = (self.width_range, self.height_range);
+
+        div()
+            .id(self.id)
+            .relative()
+            .min_w(width_range.start)
+            .max_w(width_range.end)
+            .min_h(height_range.start)
+            .max_h(height_range.end)
+            .refine_style(&self.style)
+            .when_some(size, |this, size| this.w(size.width).h(size.height))
+            .child({
+                let state = state.clone();
+                canvas(
+                    move |bounds, _, cx| state.update(cx, |state, _| state.bounds = bounds),
+                    |_, _, _, _| {},
+                )
+                .absolute()
+                .size_full()
+            })
+            .children(self.children)
+            .on_drag_move({
+                let state = state.clone();
+                move |e: &DragMoveEvent<DragResizableBox>, _, cx| {
+                    if e.drag(cx).0 != entity_id {
+                        return;
+                    }
+
+                    state.update(cx, |state, cx| {
+                        let Some(drag) = state.drag else {
+                            return;
+                        };
+
+                        let delta = e.event.position - drag.position;
+                        let new_size = resize_size(drag.size, delta, &width_range, &height_range);
+                        if state.size == Some(new_size) {
+                            return;
+                        }
+
+                        state.size = Some(new_size);
+                        cx.emit(ResizableBoxEvent::Resized(new_size));
+                        cx.notify();
+                    })
+                }
+            })
+            .child(
+                div()
+                    .id("corner-resize-handle")
+                    .absolute()
+                    .right(px(1.))
+                    .bottom(px(1.))
+                    .size_3()
+                    .cursor_nwse_resize()
+                    .on_mouse_down(MouseButton::Left, {
+                        let state = state.clone();
+                        move |e: &MouseDownEvent, _, cx| {
+                            state.update(cx, |state, _| {
+                                state.drag = Some(ResizeDrag {
+                                    position: e.position,
+                                    size: state.bounds.size,
+                                });
+                            });
+                        }
+                    })
+                    .on_drag(DragResizableBox(entity_id), |drag, _, _, cx| {
+                        cx.stop_propagation();
+                        cx.new(|_| drag.clone())
+                    })
+                    .child(
+                        Icon::new(IconName::ResizeCorner)
+                            .size_3()
+                            .text_color(cx.theme().muted_foreground.opacity(0.5)),
+                    ),
+            )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::{point, px, size};
+
+    use super::resize_size;
+
+    #[test]
+    fn test_resize_size() {
+        let width_range = px(100.)..px(300.);
+        let height_range = px(50.)..px(200.);
+        let start = size(px(150.), px(100.));
+
+        assert_eq!(
+            resize_size(start, point(px(20.), px(-10.)), &width_range, &height_range),
+            size(px(170.), px(90.))
+        );
+        // Clamp on each axis.
+        assert_eq!(
+            resize_size(
+                start,
+                point(px(500.), px(-80.)),
+                &width_range,
+                &height_range
+            ),
+            size(px(300.), px(50.))
+        );
+    }
+}
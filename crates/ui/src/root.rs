@@ -2,7 +2,7 @@ use crate::{
     drawer::Drawer,
     input::InputState,
     modal::Modal,
-    notification::{Notification, NotificationList},
+    notification::{self, Notification, NotificationList},
     window_border, ActiveTheme, Placement,
 };
 use gpui::{
@@ -376,6 +376,13 @@ impl Render for Root {
         let base_font_size = cx.theme().font_size;
         window.set_rem_size(base_font_size);
 
+        // Show the notifications pushed before this window is created.
+        if notification::has_pending(cx) {
+            cx.defer_in(window, |root, window, cx| {
+                notification::flush_pending(&root.notification, window, cx);
+            });
+        }
+
         window_border().child(
             div()
                 .id("root")
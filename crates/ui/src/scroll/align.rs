@@ -0,0 +1,73 @@
+use gpui::{px, Pixels};
+
+/// The alignment of the target item in the viewport when scrolling to it.
+#[derive(Debug, Default, Clone, Copy, PartialEq, Eq)]
+pub enum ScrollAlign {
+    /// Align the item to the start of the viewport.
+    #[default]
+    Start,
+    /// Align the item to the center of the viewport.
+    Center,
+    /// Align the item to the end of the viewport.
+    End,
+}
+
+impl ScrollAlign {
+    /// Returns the scroll offset (negative value) to place the item in the viewport.
+    ///
+    /// - `item_start` is the item position relative to the content start.
+    /// - `max_offset` is the max scrollable distance, the result is clamped to `[-max_offset, 0]`.
+    pub(crate) fn offset_for(
+        &self,
+        item_start: Pixels,
+        item_size: Pixels,
+        viewport: Pixels,
+        max_offset: Pixels,
+    ) -> Pixels {
+        let offset = match self {
+            Self::Start => -item_start,
+            Self::Center => (viewport - item_size) / 2. - item_start,
+            Self::End => viewport - item_size - item_start,
+        };
+
+        offset.min(px(0.)).max(-max_offset.max(px(0.)))
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use gpui::px;
+
+    use super::ScrollAlign;
+
+    #[test]
+    fn test_offset_for() {
+        let max = px(900.);
+        assert_eq!(
+            ScrollAlign::Start.offset_for(px(200.), px(20.), px(100.), max),
+            px(-200.)
+        );
+        assert_eq!(
+            ScrollAlign::Center.offset_for(px(200.), px(20.), px(100.), max),
+            px(-160.)
+        );
+        assert_eq!(
+            ScrollAlign::End.offset_for(px(200.), px(20.), px(100.), max),
+            px(-120.)
+        );
+
+        // Clamp to the top or bottom.
+        assert_eq!(
+            ScrollAlign::End.offset_for(px(20.), px(20.), px(100.), max),
+            px(0.)
+        );
+        assert_eq!(
+            ScrollAlign::Start.offset_for(px(980.), px(20.), px(100.), max),
+            px(-900.)
+        );
+        assert_eq!(
+            ScrollAlign::Center.offset_for(px(0.), px(20.), px(100.), px(-10.)),
+            px(0.)
+        );
+    }
+}
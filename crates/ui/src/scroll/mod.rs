@@ -1,7 +1,9 @@
+mod align;
 mod scrollable;
 mod scrollable_mask;
 mod scrollbar;
 
+pub use align::*;
 pub use scrollable::*;
 pub use scrollable_mask::*;
 pub use scrollbar::*;
@@ -1,8 +1,10 @@
-use crate::{v_flex, ActiveTheme, Collapsible};
+use crate::{h_flex, v_flex, ActiveTheme, Collapsible, Icon, IconName};
 use gpui::{
-    div, prelude::FluentBuilder as _, App, Div, IntoElement, ParentElement, RenderOnce,
-    SharedString, Styled as _, Window,
+    div, percentage, prelude::FluentBuilder as _, App, ClickEvent, Div, InteractiveElement as _,
+    IntoElement, ParentElement, RenderOnce, SharedString, StatefulInteractiveElement as _,
+    Styled as _, Window,
 };
+use std::rc::Rc;
 
 /// A sidebar group
 #[derive(IntoElement)]
@@ -10,7 +12,10 @@ pub struct SidebarGroup<E: Collapsible + IntoElement + 'static> {
     base: Div,
     label: SharedString,
     collapsed: bool,
+    expanded: bool,
+    on_toggle: Option<Rc<dyn Fn(&bool, &mut Window, &mut App)>>,
     children: Vec<E>,
+    groups: Vec<SidebarGroup<E>>,
 }
 
 impl<E: Collapsible + IntoElement> SidebarGroup<E> {
@@ -19,7 +24,10 @@ impl<E: Collapsible + IntoElement> SidebarGroup<E> {
             base: div().gap_2().flex_col(),
             label: label.into(),
             collapsed: false,
+            expanded: true,
+            on_toggle: None,
             children: Vec::new(),
+            groups: Vec::new(),
         }
     }
 
@@ -32,6 +40,28 @@ impl<E: Collapsible + IntoElement> SidebarGroup<E> {
         self.children.extend(children);
         self
     }
+
+    /// Add a nested group, it will be rendered after the children.
+    pub fn group(mut self, group: SidebarGroup<E>) -> Self {
+        self.groups.push(group);
+        self
+    }
+
+    /// Set the expanded state of the group, default: true
+    ///
+    /// Use [`SidebarState`](super::SidebarState) to remember it.
+    pub fn expanded(mut self, expanded: bool) -> Self {
+        self.expanded = expanded;
+        self
+    }
+
+    /// Make the group header clickable to toggle the expanded state.
+    ///
+    /// The argument is the new expanded state.
+    pub fn on_toggle(mut self, on_toggle: impl Fn(&bool, &mut Window, &mut App) + 'static) -> Self {
+        self.on_toggle = Some(Rc::new(on_toggle));
+        self
+    }
 }
 impl<E: Collapsible + IntoElement> Collapsible for SidebarGroup<E> {
     fn is_collapsed(&self) -> bool {
@@ -45,27 +75,59 @@ impl<E: Collapsible + IntoElement> Collapsible for SidebarGroup<E> {
 }
 impl<E: Collapsible + IntoElement> RenderOnce for SidebarGroup<E> {
     fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+        // In collapsed (rail) mode the headers are hidden, so always show the items.
+        let expanded = self.expanded || self.collapsed;
+        let is_nested = !self.groups.is_empty();
+
         v_flex()
             .relative()
             .p_2()
             .when(!self.collapsed, |this| {
                 this.child(
-                    div()
+                    h_flex()
+                        .id("header")
                         .flex_shrink_0()
                         .px_2()
+                        .justify_between()
                         .rounded(cx.theme().radius)
                         .text_xs()
                         .text_color(cx.theme().sidebar_foreground.opacity(0.7))
                         .h_8()
-                        .child(self.label),
+                        .child(self.label)
+                        .when_some(self.on_toggle, |this, on_toggle| {
+                            let on_click =
+                                move |_: &ClickEvent, window: &mut Window, cx: &mut App| {
+                                    on_toggle(&!expanded, window, cx);
+                                };
+
+                            this.cursor_pointer()
+                                .hover(|this| this.text_color(cx.theme().sidebar_accent_foreground))
+                                .child(
+                                    Icon::new(IconName::ChevronRight)
+                                        .size_3p5()
+                                        .when(expanded, |this| this.rotate(percentage(90. / 360.))),
+                                )
+                                .on_click(on_click)
+                        }),
+                )
+            })
+            .when(expanded, |this| {
+                this.child(
+                    self.base.children(
+                        self.children
+                            .into_iter()
+                            .map(|child| child.collapsed(self.collapsed)),
+                    ),
                 )
+                .when(is_nested, |this| {
+                    this.child(
+                        v_flex().when(!self.collapsed, |this| this.pl_2()).children(
+                            self.groups.into_iter().enumerate().map(|(ix, group)| {
+                                div().id(ix).child(group.collapsed(self.collapsed))
+                            }),
+                        ),
+                    )
+                })
             })
-            .child(
-                self.base.children(
-                    self.children
-                        .into_iter()
-                        .map(|child| child.collapsed(self.collapsed)),
-                ),
-            )
     }
 }
@@ -1,15 +1,39 @@
-use crate::{h_flex, v_flex, ActiveTheme as _, Collapsible, Icon, IconName, StyledExt};
+use crate::{
+    actions::{Confirm, SelectNext, SelectPrev},
+    h_flex,
+    tooltip::Tooltip,
+    v_flex, ActiveTheme as _, Collapsible, Icon, IconName, StyledExt,
+};
 use gpui::{
     div, percentage, prelude::FluentBuilder as _, AnyElement, App, ClickEvent, ElementId,
-    InteractiveElement as _, IntoElement, ParentElement as _, RenderOnce, SharedString,
-    StatefulInteractiveElement as _, Styled as _, Window,
+    FocusHandle, InteractiveElement as _, IntoElement, KeyBinding, MouseButton, ParentElement as _,
+    RenderOnce, SharedString, StatefulInteractiveElement as _, Styled as _, Window,
 };
 use std::rc::Rc;
 
+const CONTEXT: &str = "SidebarMenu";
+
+pub(crate) fn init(cx: &mut App) {
+    cx.bind_keys([
+        KeyBinding::new("up", SelectPrev, Some(CONTEXT)),
+        KeyBinding::new("down", SelectNext, Some(CONTEXT)),
+        KeyBinding::new("enter", Confirm { secondary: false }, Some(CONTEXT)),
+    ]);
+}
+
+type ItemHandler = Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>;
+
+/// The keyboard navigation state of the [`SidebarMenu`].
+struct SidebarMenuState {
+    focus_handle: FocusHandle,
+    highlighted_ix: Option<usize>,
+}
+
 #[derive(IntoElement)]
 pub struct SidebarMenu {
     collapsed: bool,
     items: Vec<SidebarMenuItem>,
+    active_route: Option<SharedString>,
 }
 
 impl SidebarMenu {
@@ -17,9 +41,18 @@ impl SidebarMenu {
         Self {
             items: Vec::new(),
             collapsed: false,
+            active_route: None,
         }
     }
 
+    /// Set the current route, the item (or sub item) with the same route will be active.
+    ///
+    /// The parent of the active sub item will be opened.
+    pub fn active_route(mut self, route: impl Into<SharedString>) -> Self {
+        self.active_route = Some(route.into());
+        self
+    }
+
     pub fn child(mut self, child: impl Into<SidebarMenuItem>) -> Self {
         self.items.push(child.into());
         self
@@ -44,13 +77,96 @@ impl Collapsible for SidebarMenu {
     }
 }
 impl RenderOnce for SidebarMenu {
-    fn render(self, _window: &mut Window, _cx: &mut App) -> impl IntoElement {
-        v_flex().gap_2().children(
-            self.items
-                .into_iter()
-                .enumerate()
-                .map(|(ix, item)| item.id(ix).collapsed(self.collapsed)),
-        )
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let state = window.use_keyed_state("sidebar-menu", cx, |_, cx| SidebarMenuState {
+            focus_handle: cx.focus_handle(),
+            highlighted_ix: None,
+        });
+        let focus_handle = state.read(cx).focus_handle.clone();
+        let highlighted_ix = if focus_handle.is_focused(window) {
+            state.read(cx).highlighted_ix
+        } else {
+            None
+        };
+
+        // Flatten the visible items for keyboard navigation.
+        let mut handlers: Vec<ItemHandler> = vec![];
+        let items = self
+            .items
+            .into_iter()
+            .map(|mut item| {
+                if let Some(route) = &self.active_route {
+                    item.active = item.route.as_ref() == Some(route)
+                        || item
+                            .children
+                            .iter()
+                            .any(|child| child.route.as_ref() == Some(route));
+                }
+                item.highlighted = highlighted_ix == Some(handlers.len());
+                handlers.push(item.handler.clone());
+
+                let is_open = item.is_open() && !self.collapsed;
+                for child in item.children.iter_mut() {
+                    if let Some(route) = &self.active_route {
+                        child.active = child.route.as_ref() == Some(route);
+                    }
+                    if is_open {
+                        child.highlighted = highlighted_ix == Some(handlers.len());
+                        handlers.push(child.handler.clone());
+                    }
+                }
+                item
+            })
+            .collect::<Vec<_>>();
+        let items_count = handlers.len();
+
+        v_flex()
+            .id("sidebar-menu")
+            .key_context(CONTEXT)
+            .track_focus(&focus_handle)
+            .gap_2()
+            .on_mouse_down(MouseButton::Left, {
+                let focus_handle = focus_handle.clone();
+                move |_, window, _| focus_handle.focus(window)
+            })
+            .on_action({
+                let state = state.clone();
+                move |_: &SelectNext, _, cx| {
+                    state.update(cx, |state, cx| {
+                        state.highlighted_ix = match state.highlighted_ix {
+                            Some(ix) if ix + 1 < items_count => Some(ix + 1),
+                            _ => Some(0),
+                        };
+                        cx.notify();
+                    });
+                }
+            })
+            .on_action({
+                let state = state.clone();
+                move |_: &SelectPrev, _, cx| {
+                    state.update(cx, |state, cx| {
+                        state.highlighted_ix = match state.highlighted_ix {
+                            Some(ix) if ix > 0 => Some(ix - 1),
+                            _ => Some(items_count.saturating_sub(1)),
+                        };
+                        cx.notify();
+                    });
+                }
+            })
+            .on_action(move |_: &Confirm, window, cx| {
+                let Some(ix) = state.read(cx).highlighted_ix else {
+                    return;
+                };
+                if let Some(handler) = handlers.get(ix) {
+                    handler(&ClickEvent::default(), window, cx);
+                }
+            })
+            .children(
+                items
+                    .into_iter()
+                    .enumerate()
+                    .map(|(ix, item)| item.id(ix).collapsed(self.collapsed)),
+            )
     }
 }
 
@@ -60,8 +176,10 @@ pub struct SidebarMenuItem {
     id: ElementId,
     icon: Option<Icon>,
     label: SharedString,
-    handler: Rc<dyn Fn(&ClickEvent, &mut Window, &mut App)>,
+    handler: ItemHandler,
+    route: Option<SharedString>,
     active: bool,
+    highlighted: bool,
     collapsed: bool,
     children: Vec<Self>,
     suffix: Option<AnyElement>,
@@ -75,7 +193,9 @@ impl SidebarMenuItem {
             icon: None,
             label: label.into(),
             handler: Rc::new(|_, _, _| {}),
+            route: None,
             active: false,
+            highlighted: false,
             collapsed: false,
             children: Vec::new(),
             suffix: None,
@@ -94,6 +214,12 @@ impl SidebarMenuItem {
         self
     }
 
+    /// Set the route of the menu item, see [`SidebarMenu::active_route`].
+    pub fn route(mut self, route: impl Into<SharedString>) -> Self {
+        self.route = Some(route.into());
+        self
+    }
+
     /// Set the active state of the menu item
     pub fn active(mut self, active: bool) -> Self {
         self.active = active;
@@ -146,6 +272,8 @@ impl RenderOnce for SidebarMenuItem {
         let is_active = self.active;
         let is_open = self.is_open();
         let is_submenu = self.is_submenu();
+        let is_highlighted = self.highlighted;
+        let label = self.label.clone();
 
         div()
             .id(self.id.clone())
@@ -168,6 +296,10 @@ impl RenderOnce for SidebarMenuItem {
                         this.bg(cx.theme().sidebar_accent.opacity(0.8))
                             .text_color(cx.theme().sidebar_accent_foreground)
                     })
+                    .when(is_highlighted && !is_active, |this| {
+                        this.bg(cx.theme().sidebar_accent.opacity(0.8))
+                            .text_color(cx.theme().sidebar_accent_foreground)
+                    })
                     .when(is_active && !is_submenu, |this| {
                         this.font_medium()
                             .bg(cx.theme().sidebar_accent)
@@ -175,10 +307,14 @@ impl RenderOnce for SidebarMenuItem {
                     })
                     .when_some(self.icon.clone(), |this, icon| this.child(icon))
                     .when(is_collapsed, |this| {
-                        this.justify_center().when(is_active, |this| {
-                            this.bg(cx.theme().sidebar_accent)
-                                .text_color(cx.theme().sidebar_accent_foreground)
-                        })
+                        this.justify_center()
+                            .when(is_active, |this| {
+                                this.bg(cx.theme().sidebar_accent)
+                                    .text_color(cx.theme().sidebar_accent_foreground)
+                            })
+                            .tooltip(move |window, cx| {
+                                Tooltip::new(label.clone()).build(window, cx)
+                            })
                     })
                     .when(!is_collapsed, |this| {
                         this.h_7()
@@ -1,14 +1,18 @@
 use crate::{
+    animation::{cubic_bezier, AnimationSettings as _},
     button::{Button, ButtonVariants},
     h_flex,
     scroll::ScrollbarAxis,
-    v_flex, ActiveTheme, Collapsible, Icon, IconName, Side, Sizable, StyledExt,
+    v_flex, ActiveTheme, Breakpoint, Collapsible, Icon, IconName, Side, Sizable, StyledExt,
+    WindowBreakpointExt as _,
 };
 use gpui::{
-    div, prelude::FluentBuilder, px, AnyElement, App, ClickEvent, DefiniteLength,
-    InteractiveElement as _, IntoElement, ParentElement, Pixels, RenderOnce, Styled, Window,
+    div, prelude::FluentBuilder, px, AbsoluteLength, Animation, AnimationExt as _, AnyElement, App,
+    ClickEvent, DefiniteLength, ElementId, InteractiveElement as _, IntoElement, ParentElement,
+    Pixels, RenderOnce, SharedString, Styled, Task, Window,
 };
-use std::rc::Rc;
+use serde::{Deserialize, Serialize};
+use std::{collections::HashSet, rc::Rc, time::Duration};
 
 mod footer;
 mod group;
@@ -22,6 +26,41 @@ pub use menu::*;
 const DEFAULT_WIDTH: Pixels = px(255.);
 const COLLAPSED_WIDTH: Pixels = px(48.);
 
+pub(crate) fn init(cx: &mut App) {
+    menu::init(cx);
+}
+
+/// The state of the [`Sidebar`] that can be persisted, e.g.: save to the settings file.
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct SidebarState {
+    /// Whether the sidebar is collapsed into the rail mode.
+    pub collapsed: bool,
+    /// The labels of the collapsed [`SidebarGroup`]s.
+    pub collapsed_groups: HashSet<SharedString>,
+}
+
+impl SidebarState {
+    /// Toggle the rail mode of the sidebar.
+    pub fn toggle_collapsed(&mut self) {
+        self.collapsed = !self.collapsed;
+    }
+
+    /// Returns true if the group is expanded, all groups are expanded by default.
+    pub fn is_group_expanded(&self, group: &str) -> bool {
+        !self.collapsed_groups.contains(group)
+    }
+
+    /// Set the expanded state of the group.
+    pub fn set_group_expanded(&mut self, group: impl Into<SharedString>, expanded: bool) {
+        let group = group.into();
+        if expanded {
+            self.collapsed_groups.remove(&group);
+        } else {
+            self.collapsed_groups.insert(group);
+        }
+    }
+}
+
 /// A sidebar
 #[derive(IntoElement)]
 pub struct Sidebar<E: Collapsible + IntoElement + 'static> {
@@ -36,6 +75,7 @@ pub struct Sidebar<E: Collapsible + IntoElement + 'static> {
     width: DefiniteLength,
     border_width: Pixels,
     collapsed: bool,
+    collapse_below: Option<Breakpoint>,
 }
 
 impl<E: Collapsible + IntoElement> Sidebar<E> {
@@ -49,6 +89,7 @@ impl<E: Collapsible + IntoElement> Sidebar<E> {
             width: DEFAULT_WIDTH.into(),
             border_width: px(1.),
             collapsed: false,
+            collapse_below: None,
         }
     }
 
@@ -84,6 +125,13 @@ impl<E: Collapsible + IntoElement> Sidebar<E> {
         self
     }
 
+    /// Collapse the sidebar into the rail mode when the window is narrower than the
+    /// `breakpoint`, e.g.: [`Breakpoint::Md`], whatever [`Sidebar::collapsed`] is.
+    pub fn collapse_below(mut self, breakpoint: Breakpoint) -> Self {
+        self.collapse_below = Some(breakpoint);
+        self
+    }
+
     /// Set the header of the sidebar.
     pub fn header(mut self, header: impl IntoElement) -> Self {
         self.header = Some(header.into_any_element());
@@ -184,8 +232,67 @@ impl RenderOnce for SidebarToggleButton {
     }
 }
 
+struct SidebarCollapsedState {
+    /// The collapsed state the width is settled at.
+    collapsed: bool,
+    /// The collapsed state being animated to, and the timer to settle it after the animation.
+    animation: Option<(bool, Task<()>)>,
+}
+
 impl<E: Collapsible + IntoElement> RenderOnce for Sidebar<E> {
-    fn render(mut self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+    fn render(mut self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        if self
+            .collapse_below
+            .is_some_and(|breakpoint| window.breakpoint(cx) < breakpoint)
+        {
+            self.collapsed = true;
+        }
+        let collapsed = self.collapsed;
+        let collapsed_state =
+            window.use_keyed_state("sidebar-collapsed", cx, |_, _| SidebarCollapsedState {
+                collapsed,
+                animation: None,
+            });
+        // Only the width in pixels can be animated.
+        let expanded_width = match self.width {
+            DefiniteLength::Absolute(AbsoluteLength::Pixels(width)) => Some(width),
+            _ => None,
+        };
+        let duration = Duration::from_secs_f64(0.2);
+        let animated = cx.animations_enabled()
+            && expanded_width.is_some()
+            && collapsed_state.read(cx).collapsed != collapsed;
+
+        if animated {
+            let animating_to = collapsed_state
+                .read(cx)
+                .animation
+                .as_ref()
+                .map(|(to, _)| *to);
+            // Spawn once per toggle, a toggle again replaces the timer of the previous one.
+            if animating_to != Some(collapsed) {
+                let task = cx.spawn({
+                    let collapsed_state = collapsed_state.clone();
+                    async move |cx| {
+                        cx.background_executor().timer(duration).await;
+                        _ = collapsed_state.update(cx, |this, cx| {
+                            this.collapsed = collapsed;
+                            this.animation = None;
+                            cx.notify();
+                        });
+                    }
+                });
+                collapsed_state.update(cx, |this, _| this.animation = Some((collapsed, task)));
+            }
+        } else if collapsed_state.read(cx).collapsed != collapsed
+            || collapsed_state.read(cx).animation.is_some()
+        {
+            collapsed_state.update(cx, |this, _| {
+                this.collapsed = collapsed;
+                this.animation = None;
+            });
+        }
+
         v_flex()
             .id("sidebar")
             .w(self.width)
@@ -220,5 +327,24 @@ impl<E: Collapsible + IntoElement> RenderOnce for Sidebar<E> {
             .when_some(self.footer.take(), |this, footer| {
                 this.child(h_flex().id("footer").gap_2().p_2().child(footer))
             })
+            .map(|this| {
+                let Some(expanded_width) = expanded_width.filter(|_| animated) else {
+                    return this.into_any_element();
+                };
+
+                this.with_animation(
+                    ElementId::NamedInteger("sidebar-collapse".into(), collapsed as u64),
+                    Animation::new(duration).with_easing(cubic_bezier(0.4, 0., 0.2, 1.)),
+                    move |this, delta| {
+                        let (from, to) = if collapsed {
+                            (expanded_width, COLLAPSED_WIDTH)
+                        } else {
+                            (COLLAPSED_WIDTH, expanded_width)
+                        };
+                        this.w(from + (to - from) * delta)
+                    },
+                )
+                .into_any_element()
+            })
     }
 }
@@ -1,14 +1,29 @@
-use crate::{ActiveTheme, StyledExt};
+use crate::{animation::AnimationSettings as _, ActiveTheme, StyledExt};
 use gpui::{
-    bounce, div, ease_in_out, Animation, AnimationExt, IntoElement, RenderOnce, StyleRefinement,
-    Styled,
+    bounce, div, ease_in_out, linear_color_stop, linear_gradient, prelude::FluentBuilder as _,
+    relative, Animation, AnimationExt, AnyElement, App, ElementId, InteractiveElement as _,
+    IntoElement, ParentElement, RenderOnce, StyleRefinement, Styled, Task, Window,
 };
 use std::time::Duration;
 
+/// The shape of a [`Skeleton`].
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum SkeletonShape {
+    /// A rectangle, e.g.: image, card.
+    #[default]
+    Rect,
+    /// A single line of text.
+    Text,
+    /// A circle, e.g.: avatar.
+    Circle,
+}
+
 #[derive(IntoElement)]
 pub struct Skeleton {
     style: StyleRefinement,
     secondary: bool,
+    shape: SkeletonShape,
+    shimmer: bool,
 }
 
 impl Skeleton {
@@ -16,14 +31,47 @@ impl Skeleton {
         Self {
             style: StyleRefinement::default(),
             secondary: false,
+            shape: SkeletonShape::default(),
+            shimmer: false,
         }
     }
 
+    /// Create a rectangle skeleton with shimmer animation, e.g.: for image.
+    pub fn rect() -> Self {
+        Self::new().shape(SkeletonShape::Rect).shimmer(true)
+    }
+
+    /// Create a text line skeleton with shimmer animation.
+    ///
+    /// Use `w` to configure the width of the line, default is full width.
+    pub fn text() -> Self {
+        Self::new().shape(SkeletonShape::Text).shimmer(true)
+    }
+
+    /// Create a circle skeleton with shimmer animation, e.g.: for avatar.
+    ///
+    /// Use `size` to configure the diameter, default is `size_10`.
+    pub fn circle() -> Self {
+        Self::new().shape(SkeletonShape::Circle).shimmer(true)
+    }
+
     /// Set use secondary color.
     pub fn secondary(mut self, secondary: bool) -> Self {
         self.secondary = secondary;
         self
     }
+
+    /// Set the shape of the skeleton, default is [`SkeletonShape::Rect`].
+    pub fn shape(mut self, shape: SkeletonShape) -> Self {
+        self.shape = shape;
+        self
+    }
+
+    /// Set to use shimmer animation instead of pulse, default: false
+    pub fn shimmer(mut self, shimmer: bool) -> Self {
+        self.shimmer = shimmer;
+        self
+    }
 }
 
 impl Styled for Skeleton {
@@ -34,16 +82,58 @@ impl Styled for Skeleton {
 
 impl RenderOnce for Skeleton {
     fn render(self, _: &mut gpui::Window, cx: &mut gpui::App) -> impl IntoElement {
-        div()
-            .w_full()
-            .h_4()
-            .bg(if self.secondary {
-                cx.theme().skeleton.opacity(0.5)
-            } else {
-                cx.theme().skeleton
+        let animated = cx.animations_enabled();
+        let bg = if self.secondary {
+            cx.theme().skeleton.opacity(0.5)
+        } else {
+            cx.theme().skeleton
+        };
+        let highlight = cx.theme().background.opacity(0.35);
+
+        let base = div()
+            .map(|this| match self.shape {
+                SkeletonShape::Rect => this.w_full().h_4(),
+                SkeletonShape::Text => this.w_full().h_4().rounded(cx.theme().radius),
+                SkeletonShape::Circle => this.flex_shrink_0().size_10().rounded_full(),
             })
-            .refine_style(&self.style)
-            .with_animation(
+            .bg(bg)
+            .refine_style(&self.style);
+
+        if !animated {
+            return base.into_any_element();
+        }
+
+        if self.shimmer {
+            base.relative()
+                .overflow_hidden()
+                .child(
+                    div()
+                        .absolute()
+                        .top_0()
+                        .bottom_0()
+                        .w(relative(0.5))
+                        .flex()
+                        .child(div().w_1_2().h_full().bg(linear_gradient(
+                            90.,
+                            linear_color_stop(highlight.opacity(0.), 0.),
+                            linear_color_stop(highlight, 1.),
+                        )))
+                        .child(div().w_1_2().h_full().bg(linear_gradient(
+                            90.,
+                            linear_color_stop(highlight, 0.),
+                            linear_color_stop(highlight.opacity(0.), 1.),
+                        )))
+                        .with_animation(
+                            "skeleton-shimmer",
+                            Animation::new(Duration::from_millis(1500))
+                                .repeat()
+                                .with_easing(ease_in_out),
+                            move |this, delta| this.left(relative(-0.5 + delta * 1.5)),
+                        ),
+                )
+                .into_any_element()
+        } else {
+            base.with_animation(
                 "skeleton",
                 Animation::new(Duration::from_secs(2))
                     .repeat()
@@ -53,5 +143,135 @@ impl RenderOnce for Skeleton {
                     this.opacity(v)
                 },
             )
+            .into_any_element()
+        }
+    }
+}
+
+struct SkeletonLoaderState {
+    /// Keep the skeleton visible to fade out after loading.
+    visible: bool,
+    /// The timer to hide the skeleton after the fade, dropped to cancel it if loading again.
+    fade_task: Option<Task<()>>,
+}
+
+/// A wrapper to show a skeleton placeholder while loading,
+/// and then cross-fade to the real content when loaded.
+///
+/// ```ignore
+/// SkeletonLoader::new("card")
+///     .loading(self.loading)
+///     .skeleton(v_flex().gap_2().child(Skeleton::circle()).child(Skeleton::text().w_32()))
+///     .child(my_card)
+/// ```
+#[derive(IntoElement)]
+pub struct SkeletonLoader {
+    id: ElementId,
+    style: StyleRefinement,
+    loading: bool,
+    skeleton: Option<AnyElement>,
+    children: Vec<AnyElement>,
+}
+
+impl SkeletonLoader {
+    pub fn new(id: impl Into<ElementId>) -> Self {
+        Self {
+            id: id.into(),
+            style: StyleRefinement::default(),
+            loading: false,
+            skeleton: None,
+            children: Vec::new(),
+        }
+    }
+
+    /// Set the loading state, the skeleton is shown while loading is true.
+    pub fn loading(mut self, loading: bool) -> Self {
+        self.loading = loading;
+        self
+    }
+
+    /// Set the skeleton placeholder, this should mirror the layout of the content.
+    pub fn skeleton(mut self, skeleton: impl IntoElement) -> Self {
+        self.skeleton = Some(skeleton.into_any_element());
+        self
+    }
+}
+
+impl Styled for SkeletonLoader {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+impl ParentElement for SkeletonLoader {
+    fn extend(&mut self, elements: impl IntoIterator<Item = AnyElement>) {
+        self.children.extend(elements);
+    }
+}
+
+impl RenderOnce for SkeletonLoader {
+    fn render(self, window: &mut Window, cx: &mut App) -> impl IntoElement {
+        let loading = self.loading;
+        let state = window.use_keyed_state(self.id.clone(), cx, |_, _| SkeletonLoaderState {
+            visible: loading,
+            fade_task: None,
+        });
+        let duration = Duration::from_secs_f64(0.25);
+        let fading = cx.animations_enabled() && !loading && state.read(cx).visible;
+
+        if fading {
+            if state.read(cx).fade_task.is_none() {
+                let task = cx.spawn({
+                    let state = state.clone();
+                    async move |cx| {
+                        cx.background_executor().timer(duration).await;
+                        _ = state.update(cx, |this, cx| {
+                            this.visible = false;
+                            this.fade_task = None;
+                            cx.notify();
+                        });
+                    }
+                });
+                state.update(cx, |this, _| this.fade_task = Some(task));
+            }
+        } else if loading != state.read(cx).visible || state.read(cx).fade_task.is_some() {
+            state.update(cx, |this, _| {
+                this.visible = loading;
+                this.fade_task = None;
+            });
+        }
+
+        div()
+            .id(self.id)
+            .relative()
+            .refine_style(&self.style)
+            .map(|this| {
+                if loading {
+                    return this.children(self.skeleton);
+                }
+
+                if !fading {
+                    return this.children(self.children);
+                }
+
+                this.child(div().children(self.children).with_animation(
+                    "fade-in",
+                    Animation::new(duration),
+                    |this, delta| this.opacity(delta),
+                ))
+                .when_some(self.skeleton, |this, skeleton| {
+                    this.child(
+                        div()
+                            .absolute()
+                            .top_0()
+                            .left_0()
+                            .size_full()
+                            .child(skeleton)
+                            .with_animation("fade-out", Animation::new(duration), |this, delta| {
+                                this.opacity(1. - delta)
+                            }),
+                    )
+                })
+            })
     }
 }
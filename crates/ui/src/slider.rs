@@ -1,6 +1,11 @@
 use std::ops::Range;
 
-use crate::{h_flex, tooltip::Tooltip, ActiveTheme, AxisExt, StyledExt};
+use crate::{
+    h_flex,
+    number_format::{format_number, NumberFormat},
+    tooltip::Tooltip,
+    ActiveTheme, AxisExt, StyledExt,
+};
 use gpui::{
     canvas, div, prelude::FluentBuilder as _, px, Along, App, AppContext as _, Axis, Background,
     Bounds, Context, Corners, DragMoveEvent, Empty, Entity, EntityId, EventEmitter, Hsla,
@@ -257,6 +262,7 @@ pub struct Slider {
     axis: Axis,
     style: StyleRefinement,
     disabled: bool,
+    number_format: NumberFormat,
 }
 
 impl Slider {
@@ -267,6 +273,7 @@ impl Slider {
             state: state.clone(),
             style: StyleRefinement::default(),
             disabled: false,
+            number_format: NumberFormat::default(),
         }
     }
 
@@ -288,6 +295,12 @@ impl Slider {
         self
     }
 
+    /// Set the format of the value in the thumb tooltip, default is [`NumberFormat::decimal`].
+    pub fn number_format(mut self, number_format: NumberFormat) -> Self {
+        self.number_format = number_format;
+        self
+    }
+
     #[allow(clippy::too_many_arguments)]
     fn render_thumb(
         &self,
@@ -303,6 +316,7 @@ impl Slider {
         let entity_id = self.state.entity_id();
         let value = state.value;
         let axis = self.axis;
+        let number_format = self.number_format.clone();
         let id = ("slider-thumb", is_start as u32);
 
         if self.disabled {
@@ -363,11 +377,8 @@ impl Slider {
                 },
             ))
             .tooltip(move |window, cx| {
-                Tooltip::new(format!(
-                    "{}",
-                    if is_start { value.start() } else { value.end() }
-                ))
-                .build(window, cx)
+                let value = if is_start { value.start() } else { value.end() };
+                Tooltip::new(format_number(value as f64, &number_format)).build(window, cx)
             })
     }
 }
@@ -0,0 +1,247 @@
+use std::time::Duration;
+
+use gpui::{
+    div, ease_in_out, percentage, prelude::FluentBuilder as _, px, Animation, AnimationExt as _,
+    AnyElement, App, Div, Hsla, IntoElement, ParentElement, Pixels, RenderOnce, SharedString,
+    StyleRefinement, Styled, Transformation, Window,
+};
+
+use crate::{
+    animation::AnimationSettings as _, ActiveTheme as _, Icon, IconName, Sizable, Size,
+    StyledExt as _,
+};
+
+/// The style of a [`Spinner`].
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum SpinnerVariant {
+    /// A spinning arc icon.
+    #[default]
+    Arc,
+    /// Three pulsing dots.
+    Dots,
+    /// Four bouncing bars.
+    Bars,
+}
+
+/// A loading spinner, with an optional label.
+///
+/// This is inline by default, e.g.: in a Button, use [`Spinner::centered`] to fill and
+/// center in the parent, e.g.: for a loading placeholder.
+///
+/// The animation is only running while the spinner is rendered, and it is static
+/// when the animations are disabled by [`crate::animation::AnimationSettings`].
+///
+/// ```ignore
+/// Spinner::new().variant(SpinnerVariant::Dots).small().label("Loading...")
+/// ```
+#[derive(IntoElement)]
+pub struct Spinner {
+    style: StyleRefinement,
+    variant: SpinnerVariant,
+    size: Size,
+    icon: Icon,
+    speed: Duration,
+    color: Option<Hsla>,
+    label: Option<SharedString>,
+    centered: bool,
+}
+
+impl Spinner {
+    pub fn new() -> Self {
+        Self {
+            style: StyleRefinement::default(),
+            variant: SpinnerVariant::default(),
+            size: Size::Medium,
+            icon: Icon::new(IconName::Loader),
+            speed: Duration::from_secs_f64(0.8),
+            color: None,
+            label: None,
+            centered: false,
+        }
+    }
+
+    /// Set the style of the spinner, default is [`SpinnerVariant::Arc`].
+    pub fn variant(mut self, variant: SpinnerVariant) -> Self {
+        self.variant = variant;
+        self
+    }
+
+    /// Set the icon to spin for the [`SpinnerVariant::Arc`], default is [`IconName::Loader`].
+    pub fn icon(mut self, icon: impl Into<Icon>) -> Self {
+        self.icon = icon.into();
+        self
+    }
+
+    /// Set the duration of one animation cycle, default is 0.8s.
+    pub fn speed(mut self, speed: Duration) -> Self {
+        self.speed = speed;
+        self
+    }
+
+    /// Set the color of the spinner and the label.
+    ///
+    /// Default is the current text color for the [`SpinnerVariant::Arc`],
+    /// and `theme.muted_foreground` for the others.
+    pub fn color(mut self, color: Hsla) -> Self {
+        self.color = Some(color);
+        self
+    }
+
+    /// Set the label to show after the spinner, or below it when centered.
+    pub fn label(mut self, label: impl Into<SharedString>) -> Self {
+        self.label = Some(label.into());
+        self
+    }
+
+    /// Set true to fill the parent and center the spinner, default is false.
+    pub fn centered(mut self, centered: bool) -> Self {
+        self.centered = centered;
+        self
+    }
+}
+
+impl Sizable for Spinner {
+    fn with_size(mut self, size: impl Into<Size>) -> Self {
+        self.size = size.into();
+        self
+    }
+}
+
+impl Styled for Spinner {
+    fn style(&mut self) -> &mut StyleRefinement {
+        &mut self.style
+    }
+}
+
+/// Returns the width and height of the spinner by the size, same as the [`Icon`].
+fn spinner_size(size: Size) -> Pixels {
+    match size {
+        Size::Size(px) => px,
+        Size::XSmall => px(12.),
+        Size::Small => px(14.),
+        Size::Medium => px(16.),
+        Size::Large => px(24.),
+    }
+}
+
+/// Returns the triangle wave from 0 to 1 and back to 0 of the animation `delta`,
+/// the `offset` is the phase in 0..1 to stagger the items.
+fn pulse(delta: f32, offset: f32) -> f32 {
+    let t = (delta - offset).rem_euclid(1.);
+    1. - (t * 2. - 1.).abs()
+}
+
+/// Animate the item `ix` of `count` items by the `pulse`, the items are staggered evenly.
+fn animate_item(
+    item: Div,
+    ix: usize,
+    count: usize,
+    speed: Duration,
+    animated: bool,
+    animator: impl Fn(Div, f32) -> Div + 'static,
+) -> AnyElement {
+    let offset = ix as f32 / count as f32;
+    if !animated {
+        return animator(item, pulse(0., offset)).into_any_element();
+    }
+
+    item.with_animation(
+        ("spinner-item", ix),
+        Animation::new(speed).repeat(),
+        move |this, delta| animator(this, pulse(delta, offset)),
+    )
+    .into_any_element()
+}
+
+impl RenderOnce for Spinner {
+    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+        let animated = cx.animations_enabled();
+        let size = spinner_size(self.size);
+        let color = self.color.unwrap_or(cx.theme().muted_foreground);
+        let speed = self.speed;
+
+        let indicator = match self.variant {
+            SpinnerVariant::Arc => {
+                let icon = self
+                    .icon
+                    .with_size(self.size)
+                    .when_some(self.color, |this, color| this.text_color(color));
+
+                if animated {
+                    icon.with_animation(
+                        "spinner-arc",
+                        Animation::new(speed).repeat().with_easing(ease_in_out),
+                        |this, delta| this.transform(Transformation::rotate(percentage(delta))),
+                    )
+                    .into_any_element()
+                } else {
+                    icon.into_any_element()
+                }
+            }
+            SpinnerVariant::Dots => div()
+                .flex()
+                .flex_none()
+                .items_center()
+                .h(size)
+                .gap(size / 8.)
+                .children((0..3).map(|ix| {
+                    let dot = div().size(size / 4.).rounded_full().bg(color);
+                    animate_item(dot, ix, 3, speed, animated, |this, v| {
+                        this.opacity(0.3 + 0.7 * v)
+                    })
+                }))
+                .into_any_element(),
+            SpinnerVariant::Bars => div()
+                .flex()
+                .flex_none()
+                .items_center()
+                .h(size)
+                .gap(size / 8.)
+                .children((0..4).map(move |ix| {
+                    let bar = div().w(size / 8.).rounded_full().bg(color);
+                    animate_item(bar, ix, 4, speed, animated, move |this, v| {
+                        this.h(size * (0.4 + 0.6 * v))
+                    })
+                }))
+                .into_any_element(),
+        };
+
+        div()
+            .flex()
+            .items_center()
+            .map(|this| {
+                if self.centered {
+                    this.flex_col().size_full().justify_center().gap_2()
+                } else {
+                    this.flex_none().gap(size / 2.)
+                }
+            })
+            .when_some(self.color, |this, color| this.text_color(color))
+            .map(|this| match self.size {
+                Size::XSmall => this.text_xs(),
+                Size::Small | Size::Medium => this.text_sm(),
+                Size::Large => this.text_base(),
+                Size::Size(_) => this,
+            })
+            .refine_style(&self.style)
+            .child(indicator)
+            .when_some(self.label, |this, label| this.child(label))
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::pulse;
+
+    #[test]
+    fn test_pulse() {
+        assert_eq!(pulse(0., 0.), 0.);
+        assert_eq!(pulse(0.25, 0.), 0.5);
+        assert_eq!(pulse(0.5, 0.), 1.);
+        assert_eq!(pulse(1., 0.), 0.);
+        // The offset item is behind by the phase, and wraps around.
+        assert_eq!(pulse(0.5, 0.25), 0.5);
+        assert_eq!(pulse(0., 0.5), 1.);
+        assert_eq!(pulse(0.25, 0.75), 1.);
+    }
+}
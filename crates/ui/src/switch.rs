@@ -1,5 +1,6 @@
 use crate::{
-    h_flex, text::Text, tooltip::Tooltip, ActiveTheme, Disableable, Side, Sizable, Size, StyledExt,
+    animation::AnimationSettings as _, h_flex, indicator::Indicator, text::Text, tooltip::Tooltip,
+    ActiveTheme, Disableable, Side, Sizable, Size, StyledExt,
 };
 use gpui::{
     div, prelude::FluentBuilder as _, px, Animation, AnimationExt as _, App, ElementId,
@@ -9,6 +10,10 @@ use gpui::{
 use std::{rc::Rc, time::Duration};
 
 /// A Switch element that can be toggled on or off.
+///
+/// The switch is controlled by [`Switch::checked`], so for an async change (e.g.: a network call),
+/// set [`Switch::loading`] in the `on_change` handler, and then update the `checked`
+/// with the confirmed value when it is done, the switch stays in place until then.
 #[derive(IntoElement)]
 pub struct Switch {
     id: ElementId,
@@ -20,6 +25,9 @@ pub struct Switch {
     on_click: Option<Rc<dyn Fn(&bool, &mut Window, &mut App)>>,
     size: Size,
     tooltip: Option<SharedString>,
+    loading: bool,
+    on_text: Option<SharedString>,
+    off_text: Option<SharedString>,
 }
 
 impl Switch {
@@ -35,6 +43,9 @@ impl Switch {
             label_side: Side::Right,
             size: Size::Medium,
             tooltip: None,
+            loading: false,
+            on_text: None,
+            off_text: None,
         }
     }
 
@@ -56,6 +67,34 @@ impl Switch {
         self
     }
 
+    /// Set the change handler, the argument is the requested checked state.
+    ///
+    /// This is the same as [`Switch::on_click`].
+    pub fn on_change<F>(self, handler: F) -> Self
+    where
+        F: Fn(&bool, &mut Window, &mut App) + 'static,
+    {
+        self.on_click(handler)
+    }
+
+    /// Set the pending state, a spinner is shown in the toggle and it can't be clicked,
+    /// default: false
+    pub fn loading(mut self, loading: bool) -> Self {
+        self.loading = loading;
+        self
+    }
+
+    /// Set the text to show beside the track for the on and off states.
+    pub fn state_labels(
+        mut self,
+        on_text: impl Into<SharedString>,
+        off_text: impl Into<SharedString>,
+    ) -> Self {
+        self.on_text = Some(on_text.into());
+        self.off_text = Some(off_text.into());
+        self
+    }
+
     pub fn label_side(mut self, label_side: Side) -> Self {
         self.label_side = label_side;
         self
@@ -109,12 +148,19 @@ impl RenderOnce for Switch {
 
         let (bg_width, bg_height) = match self.size {
             Size::XSmall | Size::Small => (px(28.), px(16.)),
+            Size::Large => (px(44.), px(24.)),
             _ => (px(36.), px(20.)),
         };
         let bar_width = match self.size {
             Size::XSmall | Size::Small => px(12.),
+            Size::Large => px(20.),
             _ => px(16.),
         };
+        let state_text = if checked {
+            self.on_text.clone()
+        } else {
+            self.off_text.clone()
+        };
         let inset = px(2.);
         let radius = if cx.theme().radius >= px(4.) {
             bg_height
@@ -148,13 +194,26 @@ impl RenderOnce for Switch {
                         .child(
                             // Switch Toggle
                             div()
+                                .flex()
+                                .items_center()
+                                .justify_center()
                                 .rounded(radius)
                                 .bg(toggle_bg)
                                 .shadow_md()
                                 .size(bar_width)
+                                .when(self.loading, |this| {
+                                    this.child(
+                                        Indicator::new()
+                                            .with_size(bar_width - px(4.))
+                                            .color(cx.theme().muted_foreground),
+                                    )
+                                })
                                 .map(|this| {
                                     let prev_checked = toggle_state.read(cx);
-                                    if !self.disabled && *prev_checked != checked {
+                                    if !self.disabled
+                                        && cx.animations_enabled()
+                                        && *prev_checked != checked
+                                    {
                                         let duration = Duration::from_secs_f64(0.15);
                                         cx.spawn({
                                             let toggle_state = toggle_state.clone();
@@ -188,19 +247,35 @@ impl RenderOnce for Switch {
                                 }),
                         ),
                 )
+                .when_some(state_text, |this, text| {
+                    this.child(
+                        div()
+                            .line_height(bg_height)
+                            .text_xs()
+                            .text_color(cx.theme().muted_foreground)
+                            .child(text),
+                    )
+                })
                 .when_some(self.label, |this, label| {
-                    this.child(div().line_height(bg_height).child(label).map(
-                        |this| match self.size {
-                            Size::XSmall | Size::Small => this.text_sm(),
-                            _ => this.text_base(),
-                        },
-                    ))
+                    this.child(
+                        div()
+                            .line_height(bg_height)
+                            .child(label)
+                            .map(|this| match self.size {
+                                Size::XSmall | Size::Small => this.text_sm(),
+                                Size::Large => this.text_lg(),
+                                _ => this.text_base(),
+                            })
+                            .when(self.disabled, |this| {
+                                this.text_color(cx.theme().muted_foreground)
+                            }),
+                    )
                 })
                 .when_some(
                     on_click
                         .as_ref()
                         .map(|c| c.clone())
-                        .filter(|_| !self.disabled),
+                        .filter(|_| !self.disabled && !self.loading),
                     |this, on_click| {
                         let toggle_state = toggle_state.clone();
                         this.on_mouse_down(gpui::MouseButton::Left, move |_, window, cx| {
@@ -0,0 +1,343 @@
+use gpui::{
+    div, prelude::FluentBuilder as _, AnyElement, App, AppContext as _, Context, Entity,
+    InteractiveElement as _, IntoElement, ParentElement as _, SharedString,
+    StatefulInteractiveElement as _, Styled as _, Subscription, Window,
+};
+
+use crate::{
+    checkbox::Checkbox,
+    dropdown::{Dropdown, DropdownEvent, DropdownState},
+    h_flex,
+    input::{InputState, NumberInput, NumberInputEvent, StepAction, TextInput},
+    tooltip::Tooltip,
+    ActiveTheme as _, IndexPath, Sizable as _,
+};
+
+use super::{Table, TableDelegate};
+
+/// The editor to edit a table cell, see [`TableDelegate::editor_for`].
+#[derive(Debug, Clone, PartialEq)]
+pub enum CellEditor {
+    /// A text input.
+    Text,
+    /// A number input, the value must be a valid number.
+    Number,
+    /// A dropdown to select one of the options.
+    Dropdown(Vec<SharedString>),
+    /// A checkbox, the edit is committed when it is clicked.
+    Checkbox,
+}
+
+/// The value of a table cell, used to initialize the [`CellEditor`] and
+/// passed to [`TableDelegate::on_cell_edited`] when the edit is committed.
+#[derive(Debug, Clone, PartialEq)]
+pub enum CellValue {
+    Text(SharedString),
+    Number(f64),
+    Bool(bool),
+}
+
+impl CellValue {
+    pub(super) fn to_text(&self) -> SharedString {
+        match self {
+            CellValue::Text(text) => text.clone(),
+            CellValue::Number(number) => number.to_string().into(),
+            CellValue::Bool(checked) => checked.to_string().into(),
+        }
+    }
+}
+
+enum CellEditorState {
+    Text(Entity<InputState>),
+    Number(Entity<InputState>),
+    Dropdown(Entity<DropdownState<Vec<SharedString>>>),
+    Checkbox(bool),
+}
+
+/// The cell in editing.
+pub(super) struct EditingCell {
+    pub(super) row_ix: usize,
+    pub(super) col_ix: usize,
+    editor: CellEditorState,
+    /// The validation error, the cell keeps in editing until it is fixed or cancelled.
+    error: Option<SharedString>,
+    _subscriptions: Vec<Subscription>,
+}
+
+impl EditingCell {
+    fn value(&self, cx: &App) -> Result<CellValue, SharedString> {
+        match &self.editor {
+            CellEditorState::Text(state) => Ok(CellValue::Text(state.read(cx).value())),
+            CellEditorState::Number(state) => state
+                .read(cx)
+                .value()
+                .trim()
+                .parse::<f64>()
+                .map(CellValue::Number)
+                .map_err(|_| "Invalid number".into()),
+            CellEditorState::Dropdown(state) => state
+                .read(cx)
+                .selected_value()
+                .cloned()
+                .map(CellValue::Text)
+                .ok_or_else(|| "Please select an option".into()),
+            CellEditorState::Checkbox(checked) => Ok(CellValue::Bool(*checked)),
+        }
+    }
+}
+
+/// Returns the next (or previous if `reverse`) editable cell after the cell, in the row-major order.
+fn next_cell(
+    row_ix: usize,
+    col_ix: usize,
+    rows_count: usize,
+    columns_count: usize,
+    reverse: bool,
+    is_editable: impl Fn(usize, usize) -> bool,
+) -> Option<(usize, usize)> {
+    let total = rows_count * columns_count;
+    let current = row_ix * columns_count + col_ix;
+    if total == 0 || current >= total {
+        return None;
+    }
+
+    let cells: Box<dyn Iterator<Item = usize>> = if reverse {
+        Box::new((0..current).rev())
+    } else {
+        Box::new(current + 1..total)
+    };
+    cells
+        .map(|ix| (ix / columns_count, ix % columns_count))
+        .find(|(row_ix, col_ix)| is_editable(*row_ix, *col_ix))
+}
+
+impl<D> Table<D>
+where
+    D: TableDelegate,
+{
+    /// Returns the (row, column) of the cell in editing.
+    pub fn editing_cell(&self) -> Option<(usize, usize)> {
+        self.editing
+            .as_ref()
+            .map(|editing| (editing.row_ix, editing.col_ix))
+    }
+
+    /// Start editing the cell with the editor from [`TableDelegate::editor_for`],
+    /// the cell in editing is committed first.
+    ///
+    /// Do nothing if the cell is not editable, see [`TableDelegate::is_editable`].
+    pub fn start_editing(
+        &mut self,
+        row_ix: usize,
+        col_ix: usize,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if self.editing_cell() == Some((row_ix, col_ix)) {
+            return;
+        }
+        let delegate_row_ix = self.delegate_row_ix(row_ix);
+        if row_ix >= self.rows_count(cx) || !self.delegate.is_editable(delegate_row_ix, col_ix, cx)
+        {
+            return;
+        }
+        if !self.commit_editing(window, cx) {
+            return;
+        }
+
+        let value = self.delegate.cell_value(delegate_row_ix, col_ix, cx);
+        let mut _subscriptions = vec![];
+        let editor = match self.delegate.editor_for(delegate_row_ix, col_ix, cx) {
+            CellEditor::Text => {
+                let state = cx.new(|cx| InputState::new(window, cx).default_value(value.to_text()));
+                state.update(cx, |state, cx| state.focus(window, cx));
+                CellEditorState::Text(state)
+            }
+            CellEditor::Number => {
+                let state = cx.new(|cx| InputState::new(window, cx).default_value(value.to_text()));
+                state.update(cx, |state, cx| state.focus(window, cx));
+                _subscriptions.push(cx.subscribe_in(&state, window, Self::on_number_step));
+                CellEditorState::Number(state)
+            }
+            CellEditor::Dropdown(options) => {
+                let text = value.to_text();
+                let selected_index = options
+                    .iter()
+                    .position(|option| option == &text)
+                    .map(IndexPath::new);
+                let state = cx.new(|cx| DropdownState::new(options, selected_index, window, cx));
+                state.update(cx, |state, cx| state.focus(window, cx));
+                _subscriptions.push(cx.subscribe_in(
+                    &state,
+                    window,
+                    |this, _, _: &DropdownEvent<Vec<SharedString>>, window, cx| {
+                        this.commit_editing(window, cx);
+                    },
+                ));
+                CellEditorState::Dropdown(state)
+            }
+            CellEditor::Checkbox => {
+                CellEditorState::Checkbox(matches!(value, CellValue::Bool(true)))
+            }
+        };
+
+        self.selected_row = Some(row_ix);
+        self.selected_col = Some(col_ix);
+        self.editing = Some(EditingCell {
+            row_ix,
+            col_ix,
+            editor,
+            error: None,
+            _subscriptions,
+        });
+        cx.notify();
+    }
+
+    /// Commit the cell in editing by [`TableDelegate::on_cell_edited`].
+    ///
+    /// Returns false if the value is invalid, the cell keeps in editing with the error.
+    pub fn commit_editing(&mut self, window: &mut Window, cx: &mut Context<Self>) -> bool {
+        let Some(editing) = self.editing.as_mut() else {
+            return true;
+        };
+
+        let (row_ix, col_ix) = (editing.row_ix, editing.col_ix);
+        let row_ix = match self.filtered_rows.as_ref() {
+            Some(rows) => rows.get(row_ix).copied().unwrap_or(row_ix),
+            None => row_ix,
+        };
+        let result = match editing.value(cx) {
+            Ok(value) => self
+                .delegate
+                .on_cell_edited(row_ix, col_ix, value, window, cx),
+            Err(err) => Err(err),
+        };
+
+        cx.notify();
+        match result {
+            Ok(()) => {
+                self.editing = None;
+                self.focus_handle.focus(window);
+                true
+            }
+            Err(err) => {
+                if let Some(editing) = self.editing.as_mut() {
+                    editing.error = Some(err);
+                }
+                false
+            }
+        }
+    }
+
+    /// Cancel the cell in editing, the value is discarded.
+    pub fn cancel_editing(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        if self.editing.take().is_some() {
+            self.focus_handle.focus(window);
+            cx.notify();
+        }
+    }
+
+    /// Returns the next editable cell after the cell in editing or selected.
+    pub(super) fn next_editable_cell(&self, reverse: bool, cx: &App) -> Option<(usize, usize)> {
+        let (row_ix, col_ix) = self
+            .editing_cell()
+            .or_else(|| Some((self.selected_row?, self.selected_col?)))?;
+
+        next_cell(
+            row_ix,
+            col_ix,
+            self.rows_count(cx),
+            self.delegate.columns_count(cx),
+            reverse,
+            |row_ix, col_ix| {
+                self.delegate
+                    .is_editable(self.delegate_row_ix(row_ix), col_ix, cx)
+            },
+        )
+    }
+
+    fn on_number_step(
+        &mut self,
+        state: &Entity<InputState>,
+        event: &NumberInputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let NumberInputEvent::Step(action) = event;
+        state.update(cx, |state, cx| {
+            let value = state.value().trim().parse::<f64>().unwrap_or_default();
+            let value = match action {
+                StepAction::Decrement => value - 1.,
+                StepAction::Increment => value + 1.,
+            };
+            state.set_value(value.to_string(), window, cx);
+        });
+    }
+
+    /// Render the editor of the cell in editing, with the error indicator if the value is invalid.
+    pub(super) fn render_cell_editor(&self, _: &mut Window, cx: &mut Context<Self>) -> AnyElement {
+        let Some(editing) = self.editing.as_ref() else {
+            return div().into_any_element();
+        };
+
+        let editor = match &editing.editor {
+            CellEditorState::Text(state) => TextInput::new(state)
+                .with_size(self.size)
+                .into_any_element(),
+            CellEditorState::Number(state) => NumberInput::new(state)
+                .with_size(self.size)
+                .into_any_element(),
+            CellEditorState::Dropdown(state) => {
+                Dropdown::new(state).with_size(self.size).into_any_element()
+            }
+            CellEditorState::Checkbox(checked) => {
+                let view = cx.entity();
+                Checkbox::new("cell-editor-checkbox")
+                    .checked(*checked)
+                    .with_size(self.size)
+                    .on_click(move |checked, window, cx| {
+                        let checked = *checked;
+                        view.update(cx, |table, cx| {
+                            if let Some(editing) = table.editing.as_mut() {
+                                editing.editor = CellEditorState::Checkbox(checked);
+                            }
+                            table.commit_editing(window, cx);
+                        });
+                    })
+                    .into_any_element()
+            }
+        };
+
+        h_flex()
+            .id((
+                "cell-editor",
+                editing.row_ix * self.col_groups.len() + editing.col_ix,
+            ))
+            .size_full()
+            .child(editor)
+            .when_some(editing.error.clone(), |this, error| {
+                this.border_1()
+                    .border_color(cx.theme().danger)
+                    .tooltip(move |window, cx| Tooltip::new(error.clone()).build(window, cx))
+            })
+            .into_any_element()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::next_cell;
+
+    #[test]
+    fn test_next_cell() {
+        // The columns 1 and 2 are editable.
+        let is_editable = |_: usize, col_ix: usize| col_ix == 1 || col_ix == 2;
+
+        assert_eq!(next_cell(0, 1, 3, 4, false, is_editable), Some((0, 2)));
+        assert_eq!(next_cell(0, 2, 3, 4, false, is_editable), Some((1, 1)));
+        assert_eq!(next_cell(2, 2, 3, 4, false, is_editable), None);
+        assert_eq!(next_cell(1, 1, 3, 4, true, is_editable), Some((0, 2)));
+        assert_eq!(next_cell(0, 1, 3, 4, true, is_editable), None);
+        assert_eq!(next_cell(0, 0, 0, 4, false, is_editable), None);
+    }
+}
@@ -1,15 +1,17 @@
-use std::ops::Range;
+use std::{cmp::Ordering, ops::Range};
 
 use gpui::{
-    div, App, Context, Div, InteractiveElement as _, IntoElement, ParentElement as _, Stateful,
-    Styled as _, Window,
+    div, prelude::FluentBuilder as _, App, Context, Div, InteractiveElement as _, IntoElement,
+    ParentElement as _, SharedString, Stateful, Styled as _, Task, Window,
 };
+use rust_i18n::t;
 
 use crate::{
+    button::{Button, ButtonVariants as _},
     h_flex,
     popup_menu::PopupMenu,
-    table::{loading::Loading, Column, ColumnSort, Table},
-    ActiveTheme as _, Icon, IconName, Size,
+    table::{loading::Loading, CellEditor, CellValue, Column, ColumnSort, Table},
+    v_flex, ActiveTheme as _, Icon, IconName, Sizable as _, Size,
 };
 
 #[allow(unused)]
@@ -25,6 +27,9 @@ pub trait TableDelegate: Sized + 'static {
     fn column(&self, col_ix: usize, cx: &App) -> &Column;
 
     /// Perform sort on the column at the given index.
+    ///
+    /// Implement this to sort the rows by yourself (e.g. sort on the server), or implement
+    /// [`TableDelegate::compare_rows`] to let the table sort the rows.
     fn perform_sort(
         &mut self,
         col_ix: usize,
@@ -34,6 +39,47 @@ pub trait TableDelegate: Sized + 'static {
     ) {
     }
 
+    /// Compare the rows at the given indexes by the column, for the table to sort the rows,
+    /// the table sorts by multiple columns when `shift` click the sort icons.
+    ///
+    /// Return `None` (default) if the column is not sorted by the table.
+    fn compare_rows(
+        &self,
+        col_ix: usize,
+        row_a: usize,
+        row_b: usize,
+        cx: &App,
+    ) -> Option<Ordering> {
+        None
+    }
+
+    /// Reorder the rows after the table sorted them, `order[i]` is the current index of the row
+    /// to place at `i`.
+    fn reorder_rows(
+        &mut self,
+        order: &[usize],
+        window: &mut Window,
+        cx: &mut Context<Table<Self>>,
+    ) {
+    }
+
+    /// Returns the task to find the rows matching the `query` in the `rows`, see [`Table::filter`].
+    ///
+    /// This is called for each segment of the rows in order, the returned row indexes must be
+    /// in the `rows`. Match the rows in a background task for the large table, e.g.: spawn it
+    /// by `cx.background_spawn` with the cloned data of the rows.
+    ///
+    /// Default matches all the rows.
+    fn filter(
+        &mut self,
+        query: &str,
+        rows: Range<usize>,
+        window: &mut Window,
+        cx: &mut Context<Table<Self>>,
+    ) -> Task<Vec<usize>> {
+        Task::ready(rows.collect())
+    }
+
     /// Render the header cell at the given column index, default to the column name.
     fn render_th(
         &self,
@@ -70,6 +116,44 @@ pub trait TableDelegate: Sized + 'static {
         cx: &mut Context<Table<Self>>,
     ) -> impl IntoElement;
 
+    /// Return true if the cell at the given row and column can be edited, default false.
+    ///
+    /// Double click the cell or press `enter` on the selected cell to start editing.
+    fn is_editable(&self, row_ix: usize, col_ix: usize, cx: &App) -> bool {
+        false
+    }
+
+    /// Returns the editor to edit the cell at the given row and column, default is a text input.
+    fn editor_for(&self, row_ix: usize, col_ix: usize, cx: &App) -> CellEditor {
+        CellEditor::Text
+    }
+
+    /// Returns the value of the cell at the given row and column, to initialize the editor.
+    fn cell_value(&self, row_ix: usize, col_ix: usize, cx: &App) -> CellValue {
+        CellValue::Text(SharedString::default())
+    }
+
+    /// Returns the text of the cell at the given row and column, to export or copy the table data.
+    ///
+    /// Default is the text of [`TableDelegate::cell_value`].
+    fn cell_text(&self, row_ix: usize, col_ix: usize, cx: &App) -> SharedString {
+        self.cell_value(row_ix, col_ix, cx).to_text()
+    }
+
+    /// Called when the editing of the cell is committed by `enter` or `tab`.
+    ///
+    /// Return an error message to keep the cell in editing with an error indicator.
+    fn on_cell_edited(
+        &mut self,
+        row_ix: usize,
+        col_ix: usize,
+        value: CellValue,
+        window: &mut Window,
+        cx: &mut Context<Table<Self>>,
+    ) -> Result<(), SharedString> {
+        Ok(())
+    }
+
     /// Move the column at the given `col_ix` to insert before the column at the given `to_ix`.
     fn move_column(
         &mut self,
@@ -80,13 +164,68 @@ pub trait TableDelegate: Sized + 'static {
     ) {
     }
 
-    /// Return a Element to show when table is empty.
+    /// Return a Element to show when table is empty, the table head is still visible.
     fn render_empty(&self, window: &mut Window, cx: &mut Context<Table<Self>>) -> impl IntoElement {
-        h_flex()
+        v_flex()
             .size_full()
             .justify_center()
+            .items_center()
+            .gap_2()
             .text_color(cx.theme().muted_foreground.opacity(0.6))
             .child(Icon::new(IconName::Inbox).size_12())
+            .child(div().text_sm().child(SharedString::from(t!("Table.empty"))))
+            .into_any_element()
+    }
+
+    /// Returns the error message to show the error view instead of the rows, e.g.: failed to load.
+    ///
+    /// Default is None.
+    fn error(&self, cx: &App) -> Option<SharedString> {
+        None
+    }
+
+    /// Return true to show a retry button in the default error view.
+    ///
+    /// Default: false
+    fn can_retry(&self, cx: &App) -> bool {
+        false
+    }
+
+    /// Called when the retry button in the error view is clicked, you can reload the rows here.
+    fn perform_retry(&mut self, window: &mut Window, cx: &mut Context<Table<Self>>) {}
+
+    /// Return a Element to show when the [`TableDelegate::error`] is Some, the table head is still visible.
+    ///
+    /// Default is an icon with the error message, and a retry button if [`TableDelegate::can_retry`].
+    fn render_error(
+        &self,
+        error: SharedString,
+        window: &mut Window,
+        cx: &mut Context<Table<Self>>,
+    ) -> impl IntoElement {
+        v_flex()
+            .size_full()
+            .justify_center()
+            .items_center()
+            .gap_2()
+            .text_color(cx.theme().muted_foreground)
+            .child(
+                Icon::new(IconName::TriangleAlert)
+                    .size_12()
+                    .text_color(cx.theme().danger.opacity(0.8)),
+            )
+            .child(div().text_sm().child(error))
+            .when(self.can_retry(cx), |this| {
+                this.child(
+                    Button::new("retry")
+                        .outline()
+                        .small()
+                        .label(t!("Table.retry"))
+                        .on_click(cx.listener(|this, _, window, cx| {
+                            this.delegate_mut().perform_retry(window, cx);
+                        })),
+                )
+            })
             .into_any_element()
     }
 
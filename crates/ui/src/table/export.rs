@@ -0,0 +1,134 @@
+use std::borrow::Cow;
+
+use gpui::{App, ClipboardItem, Context, Window};
+
+use crate::input;
+
+use super::{SelectionState, Table, TableDelegate};
+
+/// Escape the field for the delimiter separated values, the field is quoted if it contains
+/// the delimiter, quotes or newlines, and the quotes are doubled.
+fn escape_field(text: &str, delimiter: char) -> Cow<'_, str> {
+    if text.contains(|c| c == delimiter || c == '"' || c == '\n' || c == '\r') {
+        Cow::Owned(format!("\"{}\"", text.replace('"', "\"\"")))
+    } else {
+        Cow::Borrowed(text)
+    }
+}
+
+/// Write the fields as a line separated by the delimiter.
+fn write_line<S: AsRef<str>>(
+    out: &mut String,
+    fields: impl IntoIterator<Item = S>,
+    delimiter: char,
+) {
+    for (ix, field) in fields.into_iter().enumerate() {
+        if ix > 0 {
+            out.push(delimiter);
+        }
+        out.push_str(&escape_field(field.as_ref(), delimiter));
+    }
+    out.push('\n');
+}
+
+impl<D> Table<D>
+where
+    D: TableDelegate,
+{
+    /// Serialize the cells to a delimiter separated text, in the current order of columns and rows.
+    fn serialize(
+        &self,
+        rows: impl IntoIterator<Item = usize>,
+        cols: &[usize],
+        include_headers: bool,
+        delimiter: char,
+        cx: &App,
+    ) -> String {
+        let mut out = String::new();
+        if include_headers {
+            let headers = cols
+                .iter()
+                .map(|col_ix| &self.col_groups[*col_ix].column.name);
+            write_line(&mut out, headers, delimiter);
+        }
+        for row_ix in rows {
+            let cells = cols.iter().map(|col_ix| {
+                self.delegate
+                    .cell_text(self.delegate_row_ix(row_ix), *col_ix, cx)
+            });
+            write_line(&mut out, cells, delimiter);
+        }
+        out
+    }
+
+    /// Export the rows in the current order as CSV, the cell text is from [`TableDelegate::cell_text`].
+    ///
+    /// Only the matched rows are exported if filtered, see [`Table::filter`].
+    /// The fields with commas, quotes or newlines are quoted, and the lines end with `\n`.
+    pub fn export_csv(&self, include_headers: bool, cx: &App) -> String {
+        let cols = (0..self.col_groups.len()).collect::<Vec<_>>();
+        self.serialize(0..self.rows_count(cx), &cols, include_headers, ',', cx)
+    }
+
+    /// Copy the selected row or column to the clipboard as TSV, to paste into the spreadsheets.
+    ///
+    /// All the rows of the visual selection are copied in [`Table::vim_mode`].
+    pub fn copy_selection(&self, include_headers: bool, cx: &mut App) {
+        let rows_count = self.rows_count(cx);
+        let text = match self.selection_state {
+            SelectionState::Row if self.visual_anchor.is_some() => {
+                let Some(rows) = self.visual_rows() else {
+                    return;
+                };
+                let rows = rows.filter(|ix| *ix < rows_count);
+                let cols = (0..self.col_groups.len()).collect::<Vec<_>>();
+                self.serialize(rows, &cols, include_headers, '\t', cx)
+            }
+            SelectionState::Row => {
+                let Some(row_ix) = self.selected_row.filter(|ix| *ix < rows_count) else {
+                    return;
+                };
+                let cols = (0..self.col_groups.len()).collect::<Vec<_>>();
+                self.serialize([row_ix], &cols, include_headers, '\t', cx)
+            }
+            SelectionState::Column => {
+                let Some(col_ix) = self.selected_col.filter(|ix| *ix < self.col_groups.len())
+                else {
+                    return;
+                };
+                self.serialize(0..rows_count, &[col_ix], include_headers, '\t', cx)
+            }
+        };
+
+        cx.write_to_clipboard(ClipboardItem::new_string(text));
+    }
+
+    pub(super) fn action_copy(&mut self, _: &input::Copy, _: &mut Window, cx: &mut Context<Self>) {
+        self.copy_selection(false, cx);
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{escape_field, write_line};
+
+    #[test]
+    fn test_escape_field() {
+        assert_eq!(escape_field("foo", ','), "foo");
+        assert_eq!(escape_field("", ','), "");
+        assert_eq!(escape_field("a,b", ','), "\"a,b\"");
+        assert_eq!(escape_field("a,b", '\t'), "a,b");
+        assert_eq!(escape_field("a\tb", '\t'), "\"a\tb\"");
+        assert_eq!(escape_field("say \"hi\"", ','), "\"say \"\"hi\"\"\"");
+        assert_eq!(escape_field("line1\nline2", ','), "\"line1\nline2\"");
+        assert_eq!(escape_field("你好, 世界", ','), "\"你好, 世界\"");
+    }
+
+    #[test]
+    fn test_write_line() {
+        let mut out = String::new();
+        write_line(&mut out, ["a", "", "b,c"], ',');
+        write_line(&mut out, ["1", "2", "3"], ',');
+        assert_eq!(out, "a,,\"b,c\"\n1,2,3\n");
+    }
+}
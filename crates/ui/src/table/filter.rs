@@ -0,0 +1,270 @@
+use std::{ops::Range, time::Duration};
+
+use gpui::{App, Context, ScrollStrategy, SharedString, Task, Window};
+use smol::Timer;
+
+use super::{Table, TableDelegate, TableEvent};
+
+/// The number of the rows to filter in a task, the matched rows are shown after each segment.
+const FILTER_SEGMENT_ROWS: usize = 10_000;
+/// Wait for the typing to stop before filtering.
+const FILTER_DEBOUNCE: Duration = Duration::from_millis(150);
+
+/// The progress of [`Table::filter`].
+#[derive(Debug, Default, Clone, Copy, PartialEq, Eq)]
+pub struct FilterProgress {
+    /// The number of the matched rows so far.
+    pub matched: usize,
+    /// The number of the rows filtered so far.
+    pub scanned: usize,
+    /// The number of the rows to filter.
+    pub total: usize,
+}
+
+impl FilterProgress {
+    /// Returns true if all the rows are filtered.
+    pub fn is_done(&self) -> bool {
+        self.scanned >= self.total
+    }
+}
+
+/// Returns the rows in the `range` to show, sorted and deduplicated,
+/// the delegate may return the rows in any order.
+fn normalize_rows(mut rows: Vec<usize>, range: &Range<usize>) -> Vec<usize> {
+    rows.retain(|row_ix| range.contains(row_ix));
+    rows.sort_unstable();
+    rows.dedup();
+    rows
+}
+
+impl<D> Table<D>
+where
+    D: TableDelegate,
+{
+    /// Filter the rows by the `query` with [`TableDelegate::filter`] in the background,
+    /// the empty query shows all the rows.
+    ///
+    /// The rows are filtered by segments after a short debounce, the matched rows are shown
+    /// as soon as each segment is done, and a new query cancels the previous one.
+    ///
+    /// While filtered, the row indexes of the table, e.g.: [`Table::selected_row`] and the
+    /// [`TableEvent`]s, are the indexes in the matched rows, use [`Table::delegate_row_ix`]
+    /// to get the row of the delegate. Call this again to filter the rows changed by the
+    /// delegate, e.g.: loaded more rows.
+    pub fn filter(
+        &mut self,
+        query: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let query = query.into();
+        if query.trim() == self.filter_query.trim() {
+            return;
+        }
+
+        self.filter_query = query;
+        self.start_filter(FILTER_DEBOUNCE, window, cx);
+    }
+
+    /// Returns the query of [`Table::filter`].
+    pub fn filter_query(&self) -> &SharedString {
+        &self.filter_query
+    }
+
+    /// Returns the progress of [`Table::filter`], `None` if the rows are not filtered.
+    pub fn filter_progress(&self) -> Option<FilterProgress> {
+        self.filter_progress
+    }
+
+    /// Returns the row index of the delegate for the row at `row_ix` of the table,
+    /// they are the same if the rows are not filtered.
+    pub fn delegate_row_ix(&self, row_ix: usize) -> usize {
+        match self.filtered_rows.as_ref() {
+            Some(rows) => rows.get(row_ix).copied().unwrap_or(row_ix),
+            None => row_ix,
+        }
+    }
+
+    /// Returns the number of the rows in the table, the matched rows if filtered.
+    pub(super) fn rows_count(&self, cx: &App) -> usize {
+        match self.filtered_rows.as_ref() {
+            Some(rows) => rows.len(),
+            None => self.delegate.rows_count(cx),
+        }
+    }
+
+    /// Returns the range of the delegate rows for the `range` of the table rows.
+    pub(super) fn delegate_rows_range(&self, range: Range<usize>) -> Range<usize> {
+        let Some(rows) = self.filtered_rows.as_ref() else {
+            return range;
+        };
+
+        let rows = &rows[range.start.min(rows.len())..range.end.min(rows.len())];
+        match (rows.first(), rows.last()) {
+            (Some(first), Some(last)) => *first..*last + 1,
+            _ => 0..0,
+        }
+    }
+
+    /// Filter the rows again without the debounce, e.g.: the delegate sorted the rows.
+    pub(super) fn refilter(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        if self.filter_progress.is_some() {
+            self.start_filter(Duration::ZERO, window, cx);
+        }
+    }
+
+    fn start_filter(&mut self, debounce: Duration, window: &mut Window, cx: &mut Context<Self>) {
+        if !self.commit_editing(window, cx) {
+            self.cancel_editing(window, cx);
+        }
+        self.visual_anchor = None;
+        // Keep the selection on the same row of the delegate.
+        self.filter_selected_row = self.selected_row.map(|ix| self.delegate_row_ix(ix));
+
+        let query = self.filter_query.trim().to_string();
+        if query.is_empty() {
+            self._filter_task = Task::ready(());
+            self.filtered_rows = None;
+            self.filter_progress = None;
+            self.right_clicked_row = None;
+            self.selected_row = self.filter_selected_row.take();
+            cx.emit(TableEvent::FilterChanged(None));
+            cx.notify();
+            return;
+        }
+
+        let total = self.delegate.rows_count(cx);
+        let progress = FilterProgress {
+            total,
+            ..Default::default()
+        };
+        self.filter_progress = Some(progress);
+        cx.emit(TableEvent::FilterChanged(Some(progress)));
+        self._filter_task = cx.spawn_in(window, async move |view, window| {
+            Timer::after(debounce).await;
+
+            let mut start = 0;
+            loop {
+                let range = start..total.min(start + FILTER_SEGMENT_ROWS);
+                let Ok(task) = view.update_in(window, |view, window, cx| {
+                    view.delegate.filter(&query, range.clone(), window, cx)
+                }) else {
+                    return;
+                };
+
+                let rows = normalize_rows(task.await, &range);
+                if view
+                    .update_in(window, |view, _, cx| {
+                        view.push_filtered_rows(start == 0, rows, range.end, total, cx)
+                    })
+                    .is_err()
+                    || range.end >= total
+                {
+                    return;
+                }
+                start = range.end;
+                // Let the UI render the matched rows, if the delegate filters in the foreground.
+                smol::future::yield_now().await;
+            }
+        });
+    }
+
+    /// Show the matched `rows` of a segment, the `first` segment replaces the rows of the last
+    /// query, and the later segments are appended to keep the selection and scroll position.
+    fn push_filtered_rows(
+        &mut self,
+        first: bool,
+        rows: Vec<usize>,
+        scanned: usize,
+        total: usize,
+        cx: &mut Context<Self>,
+    ) {
+        let mut filtered_rows = match self.filtered_rows.take() {
+            Some(filtered_rows) if !first => filtered_rows,
+            _ => {
+                self.selected_row = None;
+                self.right_clicked_row = None;
+                self.vertical_scroll_handle
+                    .scroll_to_item(0, ScrollStrategy::Top);
+                vec![]
+            }
+        };
+
+        let offset = filtered_rows.len();
+        if let Some(selected) = self.filter_selected_row {
+            if let Ok(ix) = rows.binary_search(&selected) {
+                self.selected_row = Some(offset + ix);
+                self.filter_selected_row = None;
+            }
+        }
+        filtered_rows.extend(rows);
+
+        let progress = FilterProgress {
+            matched: filtered_rows.len(),
+            scanned,
+            total,
+        };
+        self.filtered_rows = Some(filtered_rows);
+        self.filter_progress = Some(progress);
+        cx.emit(TableEvent::FilterChanged(Some(progress)));
+        cx.notify();
+    }
+
+    /// Apply the new positions of the delegate rows to the filtered rows, returns the new index
+    /// of each row of the table, `positions[i]` is the new index of the delegate row `i`.
+    pub(super) fn reorder_filtered_rows(&mut self, positions: Vec<usize>) -> Vec<usize> {
+        let Some(rows) = self.filtered_rows.as_ref() else {
+            return positions;
+        };
+
+        let (rows, table_positions) = reorder_rows(rows, &positions);
+        self.filtered_rows = Some(rows);
+        table_positions
+    }
+}
+
+/// Returns the filtered `rows` after the delegate rows moved to the `positions`, and the new
+/// index of each filtered row, the rows are kept in the order of the delegate.
+fn reorder_rows(rows: &[usize], positions: &[usize]) -> (Vec<usize>, Vec<usize>) {
+    let mut moved = rows
+        .iter()
+        .enumerate()
+        .map(|(ix, row_ix)| (positions[*row_ix], ix))
+        .collect::<Vec<_>>();
+    moved.sort_unstable();
+
+    let mut table_positions = vec![0; moved.len()];
+    for (new_ix, (_, ix)) in moved.iter().enumerate() {
+        table_positions[*ix] = new_ix;
+    }
+    (
+        moved.into_iter().map(|(row, _)| row).collect(),
+        table_positions,
+    )
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{normalize_rows, reorder_rows};
+
+    #[test]
+    fn test_normalize_rows() {
+        assert_eq!(
+            normalize_rows(vec![12, 10, 15, 10, 20], &(10..20)),
+            vec![10, 12, 15]
+        );
+        assert_eq!(normalize_rows(vec![], &(0..10)), Vec::<usize>::new());
+    }
+
+    #[test]
+    fn test_reorder_rows() {
+        // The delegate rows are reversed, the rows 1 and 3 move to 2 and 0.
+        let (rows, positions) = reorder_rows(&[1, 3], &[3, 2, 1, 0]);
+        assert_eq!(rows, vec![0, 2]);
+        assert_eq!(positions, vec![1, 0]);
+
+        let (rows, positions) = reorder_rows(&[0, 2, 4], &[0, 1, 2, 3, 4]);
+        assert_eq!(rows, vec![0, 2, 4]);
+        assert_eq!(positions, vec![0, 1, 2]);
+    }
+}
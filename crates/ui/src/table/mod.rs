@@ -1,30 +1,47 @@
 use std::{ops::Range, rc::Rc, time::Duration};
 
 use crate::{
-    actions::{Cancel, SelectNext, SelectPrev},
+    actions::{Cancel, Confirm, SelectNext, SelectPrev},
     context_menu::ContextMenuExt,
-    h_flex,
+    h_flex, input,
     popup_menu::PopupMenu,
-    scroll::{self, ScrollableMask, Scrollbar, ScrollbarState},
-    v_flex, ActiveTheme, Icon, IconName, Sizable, Size, StyleSized as _, StyledExt,
+    scroll::{self, ScrollAlign, ScrollableMask, Scrollbar, ScrollbarState},
+    v_flex, vim_mode, ActiveTheme, Icon, IconName, Sizable, Size, StyleSized as _, StyledExt,
     VirtualListScrollHandle,
 };
 use gpui::{
-    actions, canvas, div, prelude::FluentBuilder, px, uniform_list, App, AppContext, Axis, Bounds,
-    Context, Div, DragMoveEvent, Edges, EventEmitter, FocusHandle, Focusable, InteractiveElement,
-    IntoElement, KeyBinding, ListSizingBehavior, MouseButton, MouseDownEvent, ParentElement,
-    Pixels, Point, Render, ScrollStrategy, ScrollWheelEvent, SharedString,
+    actions, canvas, div, prelude::FluentBuilder, px, uniform_list, AnyElement, App, AppContext,
+    Axis, Bounds, Context, Div, DragMoveEvent, Edges, EventEmitter, FocusHandle, Focusable,
+    InteractiveElement, IntoElement, KeyBinding, ListSizingBehavior, MouseButton, MouseDownEvent,
+    ParentElement, Pixels, Point, Render, ScrollStrategy, ScrollWheelEvent, SharedString,
     StatefulInteractiveElement as _, Styled, Task, UniformListScrollHandle, Window,
 };
 
+mod cell_editor;
 mod column;
 mod delegate;
+mod export;
+mod filter;
 mod loading;
+mod sort;
+mod style;
+mod vim;
 
+pub use cell_editor::{CellEditor, CellValue};
 pub use column::*;
 pub use delegate::*;
-
-actions!(table, [SelectPrevColumn, SelectNextColumn]);
+pub use filter::FilterProgress;
+pub use style::TableStyle;
+
+actions!(
+    table,
+    [
+        SelectPrevColumn,
+        SelectNextColumn,
+        EditPrevCell,
+        EditNextCell
+    ]
+);
 
 pub fn init(cx: &mut App) {
     let context = Some("Table");
@@ -34,6 +51,13 @@ pub fn init(cx: &mut App) {
         KeyBinding::new("down", SelectNext, context),
         KeyBinding::new("left", SelectPrevColumn, context),
         KeyBinding::new("right", SelectNextColumn, context),
+        KeyBinding::new("enter", Confirm { secondary: false }, context),
+        KeyBinding::new("tab", EditNextCell, context),
+        KeyBinding::new("shift-tab", EditPrevCell, context),
+        #[cfg(target_os = "macos")]
+        KeyBinding::new("cmd-c", input::Copy, context),
+        #[cfg(not(target_os = "macos"))]
+        KeyBinding::new("ctrl-c", input::Copy, context),
     ]);
 }
 
@@ -52,6 +76,14 @@ pub enum TableEvent {
     SelectColumn(usize),
     ColumnWidthsChanged(Vec<Pixels>),
     MoveColumn(usize, usize),
+    /// The sorted columns changed, in priority of `(col_ix, sort)`.
+    SortChanged(Vec<(usize, ColumnSort)>),
+    /// The rows of [`Table::filter`] changed, while filtering or done, `None` if not filtered.
+    FilterChanged(Option<FilterProgress>),
+    /// Pressed `enter` on the row without an editable cell, only in [`Table::vim_mode`].
+    ActivateRow(usize),
+    /// Pressed `/` in [`Table::vim_mode`], to focus the input of [`Table::filter`].
+    StartFilter,
 }
 
 /// The visible range of the rows and columns.
@@ -115,11 +147,29 @@ pub struct Table<D: TableDelegate> {
 
     /// The column index that is being resized.
     resizing_col: Option<usize>,
+    /// The cell in editing.
+    editing: Option<cell_editor::EditingCell>,
+    /// The keys of the sorted columns in priority.
+    sort_keys: Vec<SharedString>,
+    /// The original indexes of the rows, after the rows sorted in table.
+    row_origins: Vec<usize>,
+    /// The query of [`Table::filter`].
+    filter_query: SharedString,
+    /// The delegate rows matched the `filter_query` in order, `None` if not filtered.
+    filtered_rows: Option<Vec<usize>>,
+    filter_progress: Option<FilterProgress>,
+    /// The delegate row selected before filtering, to select it again when it is matched.
+    filter_selected_row: Option<usize>,
+    vim_mode: bool,
+    /// The start row of the visual selection in the vim mode, the end is the selected row.
+    visual_anchor: Option<usize>,
 
     /// Set stripe style of the table.
     stripe: bool,
     /// Set to use border style of the table.
     border: bool,
+    /// The style overrides of this table.
+    style_overrides: TableStyle,
     /// The cell size of the table.
     size: Size,
     /// The visible range of the rows and columns.
@@ -127,6 +177,7 @@ pub struct Table<D: TableDelegate> {
 
     _measure: Vec<Duration>,
     _load_more_task: Task<()>,
+    _filter_task: Task<()>,
 }
 
 impl<D> Table<D>
@@ -147,10 +198,20 @@ where
             right_clicked_row: None,
             selected_col: None,
             resizing_col: None,
+            editing: None,
+            sort_keys: Vec::new(),
+            row_origins: Vec::new(),
+            filter_query: SharedString::default(),
+            filtered_rows: None,
+            filter_progress: None,
+            filter_selected_row: None,
+            vim_mode: false,
+            visual_anchor: None,
             bounds: Bounds::default(),
             fixed_head_cols_bounds: Bounds::default(),
             stripe: false,
             border: true,
+            style_overrides: TableStyle::default(),
             size: Size::default(),
             scrollbar_visible: Edges::all(true),
             visible_range: VisibleRangeState::default(),
@@ -162,6 +223,7 @@ where
             col_resizable: true,
             col_fixed: true,
             _load_more_task: Task::ready(()),
+            _filter_task: Task::ready(()),
             _measure: Vec::new(),
         };
 
@@ -194,6 +256,17 @@ where
         self
     }
 
+    /// Set the style overrides of this table, the `None` fields of the `style` use the theme.
+    pub fn style_overrides(mut self, style: TableStyle) -> Self {
+        self.style_overrides = style;
+        self
+    }
+
+    pub fn set_style_overrides(&mut self, style: TableStyle, cx: &mut Context<Self>) {
+        self.style_overrides = style;
+        cx.notify();
+    }
+
     /// Set to loop selection, default to true.
     pub fn loop_selection(mut self, loop_selection: bool) -> Self {
         self.loop_selection = loop_selection;
@@ -297,6 +370,45 @@ where
         cx.notify();
     }
 
+    /// Scroll to the row at the given index, and place it at the `align` position of the viewport.
+    pub fn scroll_to(&mut self, row_ix: usize, align: ScrollAlign, cx: &mut Context<Self>) {
+        let row_height = self.size.table_row_height();
+        let base_handle = self.vertical_scroll_handle.0.borrow().base_handle.clone();
+        let mut offset = base_handle.offset();
+        offset.y = align.offset_for(
+            row_height * row_ix as f32,
+            row_height,
+            base_handle.bounds().size.height,
+            base_handle.max_offset().height,
+        );
+        base_handle.set_offset(offset);
+        cx.notify();
+    }
+
+    /// Returns the current scroll offset of the table, can be used to restore it later.
+    ///
+    /// The `x` is the horizontal offset of the columns, the `y` is the vertical offset of the rows.
+    pub fn scroll_offset(&self) -> Point<Pixels> {
+        let vertical_offset = self.vertical_scroll_handle.0.borrow().base_handle.offset();
+        Point {
+            x: self.horizontal_scroll_handle.offset().x,
+            y: vertical_offset.y,
+        }
+    }
+
+    /// Set the scroll offset of the table, e.g.: restore a previously saved [`Self::scroll_offset`].
+    pub fn set_scroll_offset(&mut self, offset: Point<Pixels>, cx: &mut Context<Self>) {
+        let mut horizontal_offset = self.horizontal_scroll_handle.offset();
+        horizontal_offset.x = offset.x;
+        self.horizontal_scroll_handle.set_offset(horizontal_offset);
+
+        let base_handle = self.vertical_scroll_handle.0.borrow().base_handle.clone();
+        let mut vertical_offset = base_handle.offset();
+        vertical_offset.y = offset.y;
+        base_handle.set_offset(vertical_offset);
+        cx.notify();
+    }
+
     /// Returns the selected row index.
     pub fn selected_row(&self) -> Option<usize> {
         self.selected_row
@@ -324,6 +436,7 @@ where
     pub fn set_selected_col(&mut self, col_ix: usize, cx: &mut Context<Self>) {
         self.selection_state = SelectionState::Column;
         self.selected_col = Some(col_ix);
+        self.visual_anchor = None;
         if let Some(col_ix) = self.selected_col {
             self.scroll_to_col(col_ix, cx);
         }
@@ -336,6 +449,7 @@ where
         self.selection_state = SelectionState::Row;
         self.selected_row = None;
         self.selected_col = None;
+        self.visual_anchor = None;
         cx.notify();
     }
 
@@ -348,12 +462,22 @@ where
         &mut self,
         ev: &MouseDownEvent,
         row_ix: usize,
-        _: &mut Window,
+        window: &mut Window,
         cx: &mut Context<Self>,
     ) {
+        // Click other rows to commit the cell in editing.
+        if self
+            .editing_cell()
+            .is_some_and(|(editing_row_ix, _)| editing_row_ix != row_ix)
+            && !self.commit_editing(window, cx)
+        {
+            return;
+        }
+
         if ev.button == MouseButton::Right {
             self.right_clicked_row = Some(row_ix);
         } else {
+            self.visual_anchor = None;
             self.set_selected_row(row_ix, cx);
 
             if ev.click_count == 2 {
@@ -382,7 +506,15 @@ where
         self.selected_row.is_some() || self.selected_col.is_some()
     }
 
-    fn action_cancel(&mut self, _: &Cancel, _: &mut Window, cx: &mut Context<Self>) {
+    fn action_cancel(&mut self, _: &Cancel, window: &mut Window, cx: &mut Context<Self>) {
+        if self.editing.is_some() {
+            self.cancel_editing(window, cx);
+            return;
+        }
+        if self.visual_anchor.take().is_some() {
+            cx.notify();
+            return;
+        }
         if self.has_selection() {
             self.clear_selection(cx);
             return;
@@ -390,8 +522,73 @@ where
         cx.propagate();
     }
 
+    /// Start editing the selected cell, or commit the cell in editing and select the cell below.
+    fn action_confirm(&mut self, _: &Confirm, window: &mut Window, cx: &mut Context<Self>) {
+        if let Some((row_ix, col_ix)) = self.editing_cell() {
+            if self.commit_editing(window, cx) && row_ix + 1 < self.rows_count(cx) {
+                self.set_selected_row(row_ix + 1, cx);
+                self.selected_col = Some(col_ix);
+            }
+            return;
+        }
+
+        let Some(row_ix) = self.selected_row else {
+            cx.propagate();
+            return;
+        };
+        // Use the first editable column if no column is selected.
+        let delegate_row_ix = self.delegate_row_ix(row_ix);
+        let col_ix = self.selected_col.or_else(|| {
+            (0..self.delegate.columns_count(cx))
+                .find(|col_ix| self.delegate.is_editable(delegate_row_ix, *col_ix, cx))
+        });
+        match col_ix {
+            Some(col_ix) if self.delegate.is_editable(delegate_row_ix, col_ix, cx) => {
+                self.start_editing(row_ix, col_ix, window, cx);
+            }
+            _ if self.vim_mode => cx.emit(TableEvent::ActivateRow(row_ix)),
+            _ => cx.propagate(),
+        }
+    }
+
+    /// Commit the cell in editing and edit the next editable cell.
+    fn action_edit_next_cell(
+        &mut self,
+        _: &EditNextCell,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.edit_next_cell(false, window, cx);
+    }
+
+    fn action_edit_prev_cell(
+        &mut self,
+        _: &EditPrevCell,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.edit_next_cell(true, window, cx);
+    }
+
+    fn edit_next_cell(&mut self, reverse: bool, window: &mut Window, cx: &mut Context<Self>) {
+        if self.editing.is_none() {
+            cx.propagate();
+            return;
+        }
+
+        let next_cell = self.next_editable_cell(reverse, cx);
+        if !self.commit_editing(window, cx) {
+            return;
+        }
+        if let Some((row_ix, col_ix)) = next_cell {
+            self.start_editing(row_ix, col_ix, window, cx);
+            self.scroll_to_row(row_ix, cx);
+            self.scroll_to_col(col_ix, cx);
+        }
+    }
+
     fn action_select_prev(&mut self, _: &SelectPrev, _: &mut Window, cx: &mut Context<Self>) {
-        let rows_count = self.delegate.rows_count(cx);
+        let rows_count = self.rows_count(cx);
         if rows_count < 1 {
             return;
         }
@@ -409,7 +606,7 @@ where
     }
 
     fn action_select_next(&mut self, _: &SelectNext, _: &mut Window, cx: &mut Context<Self>) {
-        let rows_count = self.delegate.rows_count(cx);
+        let rows_count = self.rows_count(cx);
         if rows_count < 1 {
             return;
         }
@@ -525,38 +722,6 @@ where
         cx.notify();
     }
 
-    fn perform_sort(&mut self, col_ix: usize, window: &mut Window, cx: &mut Context<Self>) {
-        if !self.sortable {
-            return;
-        }
-
-        let sort = self.col_groups.get(col_ix).and_then(|g| g.column.sort);
-        if sort.is_none() {
-            return;
-        }
-
-        let sort = sort.unwrap();
-        let sort = match sort {
-            ColumnSort::Ascending => ColumnSort::Default,
-            ColumnSort::Descending => ColumnSort::Ascending,
-            ColumnSort::Default => ColumnSort::Descending,
-        };
-
-        for (ix, col_group) in self.col_groups.iter_mut().enumerate() {
-            if ix == col_ix {
-                col_group.column.sort = Some(sort);
-            } else {
-                if col_group.column.sort.is_some() {
-                    col_group.column.sort = Some(ColumnSort::Default);
-                }
-            }
-        }
-
-        self.delegate_mut().perform_sort(col_ix, sort, window, cx);
-
-        cx.notify();
-    }
-
     fn move_column(
         &mut self,
         col_ix: usize,
@@ -584,6 +749,11 @@ where
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
+        // The loaded rows are not filtered, see `Table::filter`.
+        if self.filter_progress.is_some() {
+            return;
+        }
+
         let threshold = self.delegate.load_more_threshold();
         // Securely handle subtract logic to prevent attempt to subtract with overflow
         if visible_end >= rows_count.saturating_sub(threshold) {
@@ -616,8 +786,9 @@ where
             if self.visible_range.rows == visible_range {
                 return;
             }
+            let delegate_rows = self.delegate_rows_range(visible_range.clone());
             self.delegate_mut()
-                .visible_rows_changed(visible_range.clone(), window, cx);
+                .visible_rows_changed(delegate_rows, window, cx);
             self.visible_range.rows = visible_range;
         } else {
             if self.visible_range.cols == visible_range {
@@ -644,6 +815,12 @@ where
             .overflow_hidden()
             .whitespace_nowrap()
             .table_cell_size(self.size)
+            .when_some(self.style_overrides.cell_padding, |this, padding| {
+                this.pl(padding.left)
+                    .pr(padding.right)
+                    .pt(padding.top)
+                    .pb(padding.bottom)
+            })
             .map(|this| match col_padding {
                 Some(padding) => this
                     .pl(padding.left)
@@ -656,6 +833,7 @@ where
 
     /// Show Column selection style, when the column is selected and the selection state is Column.
     fn render_col_wrap(&self, col_ix: usize, _: &mut Window, cx: &mut Context<Self>) -> Div {
+        let colors = self.style_overrides.colors(cx);
         let el = h_flex().h_full();
         let selectable = self.col_selectable
             && self
@@ -668,7 +846,7 @@ where
             && self.selected_col == Some(col_ix)
             && self.selection_state == SelectionState::Column
         {
-            el.bg(cx.theme().table_active)
+            el.bg(colors.active_bg)
         } else {
             el
         }
@@ -725,6 +903,7 @@ where
         _: &mut Window,
         cx: &mut Context<Self>,
     ) -> impl IntoElement {
+        let colors = self.style_overrides.colors(cx);
         const HANDLE_SIZE: Pixels = px(2.);
 
         let resizable = self.col_resizable
@@ -753,8 +932,8 @@ where
                 div()
                     .h_full()
                     .justify_center()
-                    .bg(cx.theme().table_row_border)
-                    .group_hover(group_id, |this| this.bg(cx.theme().border).h_full())
+                    .bg(colors.row_border)
+                    .group_hover(group_id, |this| this.bg(colors.border).h_full())
                     .w(px(1.)),
             )
             .on_drag_move(
@@ -835,10 +1014,22 @@ where
             ColumnSort::Default => (IconName::ChevronsUpDown, false),
         };
 
+        // Show the priority of the sorted column when sort by multiple columns.
+        let sorts = self.sorts();
+        let priority = if is_on && sorts.len() > 1 {
+            sorts
+                .iter()
+                .position(|(ix, _)| *ix == col_ix)
+                .map(|ix| ix + 1)
+        } else {
+            None
+        };
+
         Some(
-            div()
+            h_flex()
                 .id(("icon-sort", col_ix))
                 .p(px(2.))
+                .gap_0p5()
                 .rounded(cx.theme().radius / 2.)
                 .map(|this| match is_on {
                     true => this,
@@ -846,14 +1037,27 @@ where
                 })
                 .hover(|this| this.bg(cx.theme().secondary).opacity(7.))
                 .active(|this| this.bg(cx.theme().secondary_active).opacity(1.))
-                .on_click(
-                    cx.listener(move |table, _, window, cx| table.perform_sort(col_ix, window, cx)),
+                .on_mouse_down(
+                    MouseButton::Left,
+                    cx.listener(move |table, ev: &MouseDownEvent, window, cx| {
+                        cx.stop_propagation();
+                        // Hold `shift` to sort by multiple columns.
+                        table.perform_sort(col_ix, ev.modifiers.shift, window, cx);
+                    }),
                 )
                 .child(
                     Icon::new(icon)
                         .size_3()
                         .text_color(cx.theme().secondary_foreground),
-                ),
+                )
+                .when_some(priority, |this, priority| {
+                    this.child(
+                        div()
+                            .text_xs()
+                            .text_color(cx.theme().secondary_foreground)
+                            .child(priority.to_string()),
+                    )
+                }),
         )
     }
 
@@ -952,6 +1156,7 @@ where
         window: &mut Window,
         cx: &mut Context<Self>,
     ) -> impl IntoElement {
+        let colors = self.style_overrides.colors(cx);
         let view = cx.entity().clone();
         let horizontal_scroll_handle = self.horizontal_scroll_handle.clone();
 
@@ -965,8 +1170,8 @@ where
             .h(self.size.table_row_height())
             .flex_shrink_0()
             .border_b_1()
-            .border_color(cx.theme().border)
-            .text_color(cx.theme().table_head_foreground)
+            .border_color(colors.border)
+            .text_color(colors.head_fg)
             .when(left_columns_count > 0, |this| {
                 let view = view.clone();
                 // Render left fixed columns
@@ -974,7 +1179,7 @@ where
                     h_flex()
                         .relative()
                         .h_full()
-                        .bg(cx.theme().table_head)
+                        .bg(colors.head_bg)
                         .children(
                             self.col_groups
                                 .iter()
@@ -992,7 +1197,7 @@ where
                                 .w_0()
                                 .flex_shrink_0()
                                 .border_r_1()
-                                .border_color(cx.theme().border),
+                                .border_color(colors.border),
                         )
                         .child(
                             canvas(
@@ -1014,7 +1219,7 @@ where
                     .overflow_scroll()
                     .relative()
                     .track_scroll(&horizontal_scroll_handle)
-                    .bg(cx.theme().table_head)
+                    .bg(colors.head_bg)
                     .child(
                         h_flex()
                             .relative()
@@ -1044,9 +1249,13 @@ where
         window: &mut Window,
         cx: &mut Context<Self>,
     ) -> impl IntoElement {
+        let colors = self.style_overrides.colors(cx);
         let horizontal_scroll_handle = self.horizontal_scroll_handle.clone();
         let is_stripe_row = self.stripe && row_ix % 2 != 0;
-        let is_selected = self.selected_row == Some(row_ix);
+        let is_selected = self.selected_row == Some(row_ix)
+            || self
+                .visual_rows()
+                .is_some_and(|rows| rows.contains(&row_ix));
         let view = cx.entity().clone();
 
         if row_ix < rows_count {
@@ -1064,22 +1273,24 @@ where
                 true
             };
 
-            let mut tr = self.delegate.render_tr(row_ix, window, cx);
+            let mut tr = self
+                .delegate
+                .render_tr(self.delegate_row_ix(row_ix), window, cx);
             let style = tr.style().clone();
 
             tr.h_flex()
                 .w_full()
                 .h(self.size.table_row_height())
                 .when(need_render_border, |this| {
-                    this.border_b_1().border_color(cx.theme().table_row_border)
+                    this.border_b_1().border_color(colors.row_border)
                 })
-                .when(is_stripe_row, |this| this.bg(cx.theme().table_even))
+                .when(is_stripe_row, |this| this.bg(colors.even_bg))
                 .refine_style(&style)
                 .hover(|this| {
                     if is_selected || self.right_clicked_row == Some(row_ix) {
                         this
                     } else {
-                        this.bg(cx.theme().table_hover)
+                        this.bg(colors.hover_bg)
                     }
                 })
                 .when(left_columns_count > 0, |this| {
@@ -1092,11 +1303,13 @@ where
                                 let mut items = Vec::with_capacity(left_columns_count);
 
                                 (0..left_columns_count).for_each(|col_ix| {
-                                    items.push(self.render_col_wrap(col_ix, window, cx).child(
-                                        self.render_cell(col_ix, window, cx).child(
-                                            self.measure_render_td(row_ix, col_ix, window, cx),
+                                    items.push(
+                                        self.render_col_wrap(col_ix, window, cx).child(
+                                            self.render_cell(col_ix, window, cx).child(
+                                                self.render_body_td(row_ix, col_ix, window, cx),
+                                            ),
                                         ),
-                                    ));
+                                    );
                                 });
 
                                 items
@@ -1111,7 +1324,7 @@ where
                                     .w_0()
                                     .flex_shrink_0()
                                     .border_r_1()
-                                    .border_color(cx.theme().border),
+                                    .border_color(colors.border),
                             ),
                     )
                 })
@@ -1145,7 +1358,7 @@ where
                                             let el =
                                                 table.render_col_wrap(col_ix, window, cx).child(
                                                     table.render_cell(col_ix, window, cx).child(
-                                                        table.measure_render_td(
+                                                        table.render_body_td(
                                                             row_ix, col_ix, window, cx,
                                                         ),
                                                     ),
@@ -1174,9 +1387,9 @@ where
                                     .right(px(0.))
                                     .bottom(px(-1.))
                                     .absolute()
-                                    .bg(cx.theme().table_active)
+                                    .bg(colors.active_bg)
                                     .border_1()
-                                    .border_color(cx.theme().table_active_border),
+                                    .border_color(colors.active_border),
                             )
                         },
                     )
@@ -1214,8 +1427,8 @@ where
                 .w_full()
                 .h_full()
                 .border_t_1()
-                .border_color(cx.theme().table_row_border)
-                .when(is_stripe_row, |this| this.bg(cx.theme().table_even))
+                .border_color(colors.row_border)
+                .when(is_stripe_row, |this| this.bg(colors.even_bg))
                 .children((0..columns_count).map(|col_ix| {
                     h_flex()
                         .left(horizontal_scroll_handle.offset().x)
@@ -1249,6 +1462,41 @@ where
         extra_rows_needed
     }
 
+    /// Render the cell editor if the cell is in editing, otherwise the delegate `render_td`,
+    /// and double click the editable cell to start editing.
+    fn render_body_td(
+        &mut self,
+        row_ix: usize,
+        col_ix: usize,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) -> AnyElement {
+        if self.editing_cell() == Some((row_ix, col_ix)) {
+            return self.render_cell_editor(window, cx);
+        }
+
+        let delegate_row_ix = self.delegate_row_ix(row_ix);
+        let td = self.measure_render_td(delegate_row_ix, col_ix, window, cx);
+        if !self.delegate.is_editable(delegate_row_ix, col_ix, cx) {
+            return td.into_any_element();
+        }
+
+        div()
+            .size_full()
+            .child(td)
+            .on_mouse_down(
+                MouseButton::Left,
+                cx.listener(move |this, ev: &MouseDownEvent, window, cx| {
+                    if ev.click_count == 2 {
+                        this.start_editing(row_ix, col_ix, window, cx);
+                        // Keep the focus on the editor, not the table.
+                        window.prevent_default();
+                    }
+                }),
+            )
+            .into_any_element()
+    }
+
     #[inline]
     fn measure_render_td(
         &mut self,
@@ -1317,6 +1565,7 @@ where
     D: TableDelegate,
 {
     fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        let colors = self.style_overrides.colors(cx);
         self.measure(window, cx);
 
         let view = cx.entity().clone();
@@ -1328,7 +1577,7 @@ where
             .iter()
             .filter(|col| self.col_fixed && col.column.fixed == Some(ColumnFixed::Left))
             .count();
-        let rows_count = self.delegate.rows_count(cx);
+        let rows_count = self.rows_count(cx);
         let loading = self.delegate.loading(cx);
         let extra_rows_count = self.calculate_extra_rows_needed(rows_count);
         let render_rows_count = if self.stripe {
@@ -1337,32 +1586,54 @@ where
             rows_count
         };
 
+        // Type into the cell editor rather than navigate while it is focused.
+        let vim_active =
+            self.vim_mode && self.editing.is_none() && self.focus_handle.is_focused(window);
+
         let inner_table = v_flex()
-            .key_context("Table")
+            .key_context(vim_mode::key_context("Table", vim_active))
             .id("table")
             .track_focus(&self.focus_handle)
             .on_action(cx.listener(Self::action_cancel))
+            .on_action(cx.listener(Self::action_copy))
+            .on_action(cx.listener(Self::action_confirm))
+            .on_action(cx.listener(Self::action_edit_next_cell))
+            .on_action(cx.listener(Self::action_edit_prev_cell))
             .on_action(cx.listener(Self::action_select_next))
             .on_action(cx.listener(Self::action_select_prev))
             .on_action(cx.listener(Self::action_select_next_col))
             .on_action(cx.listener(Self::action_select_prev_col))
+            .when(self.vim_mode, |this| {
+                this.on_action(cx.listener(Self::action_move_down))
+                    .on_action(cx.listener(Self::action_move_up))
+                    .on_action(cx.listener(Self::action_move_to_top))
+                    .on_action(cx.listener(Self::action_move_to_bottom))
+                    .on_action(cx.listener(Self::action_start_filter))
+                    .on_action(cx.listener(Self::action_toggle_visual))
+            })
             .size_full()
             .overflow_hidden()
             .child(self.render_table_head(left_columns_count, window, cx))
             .context_menu({
                 let view = view.clone();
                 move |this, window: &mut Window, cx: &mut Context<PopupMenu>| {
-                    if let Some(row_ix) = view.read(cx).right_clicked_row {
-                        view.read(cx)
-                            .delegate
-                            .context_menu(row_ix, this, window, cx)
+                    let view = view.read(cx);
+                    if let Some(row_ix) = view.right_clicked_row {
+                        view.delegate
+                            .context_menu(view.delegate_row_ix(row_ix), this, window, cx)
                     } else {
                         this
                     }
                 }
             })
             .map(|this| {
-                if rows_count == 0 {
+                if let Some(error) = self.delegate.error(cx) {
+                    this.child(
+                        div()
+                            .size_full()
+                            .child(self.delegate.render_error(error, window, cx)),
+                    )
+                } else if rows_count == 0 {
                     this.child(
                         div()
                             .size_full()
@@ -1447,11 +1718,11 @@ where
         div()
             .size_full()
             .when(self.border, |this| {
-                this.rounded(cx.theme().radius)
+                this.rounded(colors.radius)
                     .border_1()
-                    .border_color(cx.theme().border)
+                    .border_color(colors.border)
             })
-            .bg(cx.theme().table)
+            .bg(colors.bg)
             .when(loading, |this| {
                 this.child(self.delegate().render_loading(self.size, window, cx))
             })
@@ -1489,3 +1760,101 @@ where
             })
     }
 }
+
+#[cfg(test)]
+mod tests {
+    use gpui::{
+        div, px, App, Context, InteractiveElement as _, IntoElement, ParentElement as _,
+        Styled as _, TestAppContext, Window,
+    };
+
+    use super::{Column, Table, TableDelegate};
+    use crate::{
+        test_support::{bounds, init_test, layout_snapshot, mount},
+        ThemeMode,
+    };
+
+    struct TestDelegate {
+        columns: Vec<Column>,
+        rows_count: usize,
+    }
+
+    impl TestDelegate {
+        fn new(rows_count: usize) -> Self {
+            Self {
+                columns: vec![
+                    Column::new("name", "Name").width(px(120.)),
+                    Column::new("value", "Value").width(px(80.)),
+                ],
+                rows_count,
+            }
+        }
+    }
+
+    impl TableDelegate for TestDelegate {
+        fn columns_count(&self, _: &App) -> usize {
+            self.columns.len()
+        }
+
+        fn rows_count(&self, _: &App) -> usize {
+            self.rows_count
+        }
+
+        fn column(&self, col_ix: usize, _: &App) -> &Column {
+            &self.columns[col_ix]
+        }
+
+        fn render_td(
+            &self,
+            row_ix: usize,
+            col_ix: usize,
+            _: &mut Window,
+            _: &mut Context<Table<Self>>,
+        ) -> impl IntoElement {
+            div()
+                .debug_selector(move || format!("cell-{}-{}", row_ix, col_ix))
+                .child(format!("{}-{}", row_ix, col_ix))
+        }
+    }
+
+    fn mount_table(cx: &mut TestAppContext, rows_count: usize) -> &mut gpui::VisualTestContext {
+        init_test(cx);
+        mount(cx, ThemeMode::Light, move |window, cx| {
+            let table = window.use_keyed_state("table", cx, |window, cx| {
+                Table::new(TestDelegate::new(rows_count), window, cx)
+            });
+
+            div()
+                .w(px(400.))
+                .h(px(200.))
+                .debug_selector(|| "table".into())
+                .child(table)
+        })
+    }
+
+    #[gpui::test]
+    fn test_table_layout(cx: &mut TestAppContext) {
+        let cx = mount_table(cx, 3);
+
+        let table = bounds(cx, "table");
+        let first = bounds(cx, "cell-0-0");
+        // The cells are placed by the column widths, below the header.
+        assert_eq!(bounds(cx, "cell-0-1").origin.x - first.origin.x, px(120.));
+        assert!(first.origin.y > table.origin.y);
+        assert!(bounds(cx, "cell-1-0").origin.y > first.origin.y);
+        assert_eq!(
+            bounds(cx, "cell-2-0").origin.y - bounds(cx, "cell-1-0").origin.y,
+            bounds(cx, "cell-1-0").origin.y - first.origin.y
+        );
+    }
+
+    #[gpui::test]
+    fn test_table_empty(cx: &mut TestAppContext) {
+        let cx = mount_table(cx, 0);
+
+        assert_eq!(
+            layout_snapshot(cx, &["table", "cell-0-0"]),
+            "table: 0, 0, 400 x 200\ncell-0-0: none"
+        );
+    }
+}
@@ -0,0 +1,215 @@
+use std::cmp::Ordering;
+
+use gpui::{Context, Window};
+
+use super::{ColumnSort, Table, TableDelegate, TableEvent};
+
+impl ColumnSort {
+    /// Returns the next sort when clicking the sort icon: ascending, descending, then no sorting.
+    pub(super) fn next(self) -> Self {
+        match self {
+            ColumnSort::Default => ColumnSort::Ascending,
+            ColumnSort::Ascending => ColumnSort::Descending,
+            ColumnSort::Descending => ColumnSort::Default,
+        }
+    }
+}
+
+impl<D> Table<D>
+where
+    D: TableDelegate,
+{
+    /// Returns the sorted columns in priority, the first is the primary sort.
+    pub fn sorts(&self) -> Vec<(usize, ColumnSort)> {
+        let mut sorts: Vec<(usize, ColumnSort)> = self
+            .col_groups
+            .iter()
+            .enumerate()
+            .filter_map(|(col_ix, col_group)| match col_group.column.sort {
+                Some(ColumnSort::Default) | None => None,
+                Some(sort) => Some((col_ix, sort)),
+            })
+            .collect();
+
+        // The columns sorted by default are in the column order, after the clicked columns.
+        sorts.sort_by_key(|(col_ix, _)| {
+            let key = &self.col_groups[*col_ix].column.key;
+            self.sort_keys
+                .iter()
+                .position(|k| k == key)
+                .unwrap_or(usize::MAX)
+        });
+        sorts
+    }
+
+    /// Sort by the column at the given index, or add it to the sorted columns if `append`.
+    pub(super) fn perform_sort(
+        &mut self,
+        col_ix: usize,
+        append: bool,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if !self.sortable {
+            return;
+        }
+
+        let Some(sort) = self.col_groups.get(col_ix).and_then(|g| g.column.sort) else {
+            return;
+        };
+
+        if !append {
+            // Only one column sorted at a time.
+            for (ix, _) in self.sorts() {
+                if ix != col_ix {
+                    self.col_groups[ix].column.sort = Some(ColumnSort::Default);
+                }
+            }
+            self.sort_keys.clear();
+        }
+
+        let sort = sort.next();
+        let key = self.col_groups[col_ix].column.key.clone();
+        self.col_groups[col_ix].column.sort = Some(sort);
+        self.sort_keys.retain(|k| k != &key);
+        if sort != ColumnSort::Default {
+            self.sort_keys.push(key);
+        }
+
+        self.delegate_mut().perform_sort(col_ix, sort, window, cx);
+        self.sort_rows(window, cx);
+
+        cx.emit(TableEvent::SortChanged(self.sorts()));
+        cx.notify();
+    }
+
+    /// Sort the rows in table if the delegate compares the sorted columns,
+    /// see [`TableDelegate::compare_rows`].
+    fn sort_rows(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let rows_count = self.delegate.rows_count(cx);
+        if rows_count == 0 {
+            return;
+        }
+
+        let sorts = self.sorts();
+        // The delegate returns `None` for the columns that it sorts by itself.
+        let comparable = sorts
+            .iter()
+            .all(|(col_ix, _)| self.delegate.compare_rows(*col_ix, 0, 0, cx).is_some());
+        if !comparable {
+            // The delegate sorted the rows by itself, filter the sorted rows again.
+            self.refilter(window, cx);
+            return;
+        }
+        // Keep to restore the original order if it has been sorted in table.
+        if sorts.is_empty() && self.row_origins.is_empty() {
+            return;
+        }
+
+        // The rows may be added or removed by the delegate, keep the origins of the rows still in table.
+        if self.row_origins.len() > rows_count {
+            self.row_origins.truncate(rows_count);
+        }
+        let len = self.row_origins.len();
+        self.row_origins.extend(len..rows_count);
+
+        let order = sort_order(&self.row_origins, &sorts, |col_ix, a, b| {
+            self.delegate.compare_rows(col_ix, a, b, cx)
+        });
+        if order.iter().enumerate().all(|(ix, row_ix)| ix == *row_ix) {
+            return;
+        }
+
+        self.delegate.reorder_rows(&order, window, cx);
+        self.row_origins = order.iter().map(|ix| self.row_origins[*ix]).collect();
+
+        // Keep the selection on the same rows.
+        let mut positions = vec![0; order.len()];
+        for (new_ix, ix) in order.iter().enumerate() {
+            positions[*ix] = new_ix;
+        }
+        let positions = self.reorder_filtered_rows(positions);
+        let position = |row_ix: usize| positions.get(row_ix).copied();
+        self.selected_row = self.selected_row.and_then(position);
+        self.right_clicked_row = self.right_clicked_row.and_then(position);
+        if let Some(editing) = self.editing.as_mut() {
+            if let Some(row_ix) = position(editing.row_ix) {
+                editing.row_ix = row_ix;
+            }
+        }
+        // The segments in filtering are in the order before sorting.
+        if self
+            .filter_progress
+            .is_some_and(|progress| !progress.is_done())
+        {
+            self.refilter(window, cx);
+        }
+    }
+}
+
+/// Returns the new order of the rows sorted by the columns, `order[i]` is the current index
+/// of the row to place at `i`.
+///
+/// The `origins` are the original indexes of the current rows, the rows are ordered by the
+/// `sorts` in priority, and then by the original indexes to restore the order without sorting.
+pub(super) fn sort_order(
+    origins: &[usize],
+    sorts: &[(usize, ColumnSort)],
+    compare: impl Fn(usize, usize, usize) -> Option<Ordering>,
+) -> Vec<usize> {
+    let mut order: Vec<usize> = (0..origins.len()).collect();
+    order.sort_by(|a, b| {
+        for (col_ix, sort) in sorts {
+            let ordering = compare(*col_ix, *a, *b).unwrap_or(Ordering::Equal);
+            let ordering = match sort {
+                ColumnSort::Descending => ordering.reverse(),
+                _ => ordering,
+            };
+            if ordering != Ordering::Equal {
+                return ordering;
+            }
+        }
+
+        origins[*a].cmp(&origins[*b])
+    });
+    order
+}
+
+#[cfg(test)]
+mod tests {
+    use super::{sort_order, ColumnSort};
+
+    #[test]
+    fn test_next_sort() {
+        assert_eq!(ColumnSort::Default.next(), ColumnSort::Ascending);
+        assert_eq!(ColumnSort::Ascending.next(), ColumnSort::Descending);
+        assert_eq!(ColumnSort::Descending.next(), ColumnSort::Default);
+    }
+
+    #[test]
+    fn test_sort_order() {
+        // The rows of (group, name).
+        let rows = [(2, "b"), (1, "c"), (2, "a"), (1, "a")];
+        let compare = |col_ix: usize, a: usize, b: usize| match col_ix {
+            0 => Some(rows[a].0.cmp(&rows[b].0)),
+            1 => Some(rows[a].1.cmp(rows[b].1)),
+            _ => None,
+        };
+        let origins = [0, 1, 2, 3];
+
+        assert_eq!(
+            sort_order(&origins, &[(0, ColumnSort::Ascending)], compare),
+            vec![1, 3, 0, 2]
+        );
+        assert_eq!(
+            sort_order(
+                &origins,
+                &[(0, ColumnSort::Descending), (1, ColumnSort::Ascending)],
+                compare
+            ),
+            vec![2, 0, 3, 1]
+        );
+        // No sorting to restore the original order.
+        assert_eq!(sort_order(&[3, 1, 2, 0], &[], compare), vec![3, 1, 2, 0]);
+    }
+}
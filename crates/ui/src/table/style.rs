@@ -0,0 +1,75 @@
+use gpui::{App, Edges, Hsla, Pixels};
+
+use crate::ActiveTheme as _;
+
+/// The style overrides of a [`super::Table`] instance, see [`super::Table::style_overrides`].
+///
+/// The `None` fields use the theme values, so only the changed fields need to be set:
+///
+/// ```ignore
+/// Table::new(delegate, window, cx).style_overrides(TableStyle {
+///     head_bg: Some(cx.theme().accent),
+///     radius: Some(px(0.)),
+///     ..Default::default()
+/// })
+/// ```
+#[derive(Debug, Default, Clone, Copy, PartialEq)]
+pub struct TableStyle {
+    /// The background of the table, default is `theme.table`.
+    pub bg: Option<Hsla>,
+    /// The background of the head, default is `theme.table_head`.
+    pub head_bg: Option<Hsla>,
+    /// The text color of the head, default is `theme.table_head_foreground`.
+    pub head_fg: Option<Hsla>,
+    /// The color of the outer border and the head border, default is `theme.border`.
+    pub border: Option<Hsla>,
+    /// The color of the border between the rows, default is `theme.table_row_border`.
+    pub row_border: Option<Hsla>,
+    /// The background of the even rows for [`super::Table::stripe`], default is `theme.table_even`.
+    pub even_bg: Option<Hsla>,
+    /// The background of the hovered row, default is `theme.table_hover`.
+    pub hover_bg: Option<Hsla>,
+    /// The background of the selected row or column, default is `theme.table_active`.
+    pub active_bg: Option<Hsla>,
+    /// The border of the selected row, default is `theme.table_active_border`.
+    pub active_border: Option<Hsla>,
+    /// The radius of the outer border, default is `theme.radius`.
+    pub radius: Option<Pixels>,
+    /// The padding of the cells, default is by the size of the table.
+    ///
+    /// The padding of the [`super::Column::paddings`] is still used first.
+    pub cell_padding: Option<Edges<Pixels>>,
+}
+
+/// The [`TableStyle`] merged with the theme.
+#[derive(Clone, Copy)]
+pub(super) struct TableColors {
+    pub(super) bg: Hsla,
+    pub(super) head_bg: Hsla,
+    pub(super) head_fg: Hsla,
+    pub(super) border: Hsla,
+    pub(super) row_border: Hsla,
+    pub(super) even_bg: Hsla,
+    pub(super) hover_bg: Hsla,
+    pub(super) active_bg: Hsla,
+    pub(super) active_border: Hsla,
+    pub(super) radius: Pixels,
+}
+
+impl TableStyle {
+    pub(super) fn colors(&self, cx: &App) -> TableColors {
+        let theme = cx.theme();
+        TableColors {
+            bg: self.bg.unwrap_or(theme.table),
+            head_bg: self.head_bg.unwrap_or(theme.table_head),
+            head_fg: self.head_fg.unwrap_or(theme.table_head_foreground),
+            border: self.border.unwrap_or(theme.border),
+            row_border: self.row_border.unwrap_or(theme.table_row_border),
+            even_bg: self.even_bg.unwrap_or(theme.table_even),
+            hover_bg: self.hover_bg.unwrap_or(theme.table_hover),
+            active_bg: self.active_bg.unwrap_or(theme.table_active),
+            active_border: self.active_border.unwrap_or(theme.table_active_border),
+            radius: self.radius.unwrap_or(theme.radius),
+        }
+    }
+}
@@ -0,0 +1,103 @@
+use std::ops::RangeInclusive;
+
+use gpui::{Context, Window};
+
+use crate::{
+    actions::{SelectNext, SelectPrev},
+    vim_mode::{MoveDown, MoveToBottom, MoveToTop, MoveUp, StartFilter, ToggleVisual},
+};
+
+use super::{SelectionState, Table, TableDelegate, TableEvent};
+
+impl<D> Table<D>
+where
+    D: TableDelegate,
+{
+    /// Set to navigate by the single keys while the table is focused, default is false,
+    /// see [`crate::vim_mode`].
+    ///
+    /// The `/` emits [`TableEvent::StartFilter`] to focus the input of [`Table::filter`], and the
+    /// `enter` on a row without an editable cell emits [`TableEvent::ActivateRow`].
+    pub fn vim_mode(mut self, vim_mode: bool) -> Self {
+        self.vim_mode = vim_mode;
+        self
+    }
+
+    /// Set the vim mode, see [`Table::vim_mode`].
+    pub fn set_vim_mode(&mut self, vim_mode: bool, cx: &mut Context<Self>) {
+        self.vim_mode = vim_mode;
+        self.visual_anchor = None;
+        cx.notify();
+    }
+
+    /// Returns the rows of the visual selection in the vim mode, None if it is not started.
+    pub fn visual_rows(&self) -> Option<RangeInclusive<usize>> {
+        let anchor = self.visual_anchor?;
+        let selected_row = self.selected_row?;
+        Some(anchor.min(selected_row)..=anchor.max(selected_row))
+    }
+
+    pub(super) fn action_move_down(
+        &mut self,
+        _: &MoveDown,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.action_select_next(&SelectNext, window, cx);
+    }
+
+    pub(super) fn action_move_up(
+        &mut self,
+        _: &MoveUp,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.action_select_prev(&SelectPrev, window, cx);
+    }
+
+    pub(super) fn action_move_to_top(
+        &mut self,
+        _: &MoveToTop,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        if self.rows_count(cx) > 0 {
+            self.set_selected_row(0, cx);
+        }
+    }
+
+    pub(super) fn action_move_to_bottom(
+        &mut self,
+        _: &MoveToBottom,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        let rows_count = self.rows_count(cx);
+        if rows_count > 0 {
+            self.set_selected_row(rows_count - 1, cx);
+        }
+    }
+
+    pub(super) fn action_start_filter(
+        &mut self,
+        _: &StartFilter,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        cx.emit(TableEvent::StartFilter);
+    }
+
+    pub(super) fn action_toggle_visual(
+        &mut self,
+        _: &ToggleVisual,
+        _: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        self.visual_anchor = match self.visual_anchor {
+            Some(_) => None,
+            None if self.selection_state == SelectionState::Row => self.selected_row,
+            None => None,
+        };
+        cx.notify();
+    }
+}
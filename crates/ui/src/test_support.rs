@@ -0,0 +1,101 @@
+//! Helpers to render the components in a headless GPUI context for the tests.
+//!
+//! Enable the `test-support` feature to use them in other crates, e.g.: the story crate.
+//!
+//! ```ignore
+//! #[gpui::test]
+//! fn test_button(cx: &mut TestAppContext) {
+//!     init_test(cx);
+//!     let cx = mount(cx, ThemeMode::Light, |_, _| {
+//!         Button::new("ok").label("OK").debug_selector(|| "ok".into())
+//!     });
+//!     assert_eq!(bounds(cx, "ok").size.height, px(32.));
+//! }
+//! ```
+//!
+//! The test platform does not rasterize, so the snapshot is the layout of the
+//! elements marked by `debug_selector`, see [`layout_snapshot`].
+use gpui::{
+    div, AnyElement, App, Bounds, Context, IntoElement, ParentElement as _, Pixels, Render,
+    Styled as _, TestAppContext, VisualTestContext, Window,
+};
+
+use crate::{animation::AnimationSettings as _, Theme, ThemeMode};
+
+/// Initialize the components for the tests, with the animations disabled to
+/// render the final state at once.
+pub fn init_test(cx: &mut TestAppContext) {
+    cx.update(|cx| {
+        crate::init(cx);
+        cx.set_animations_enabled(false);
+    });
+}
+
+/// The root view of the test window, to render the element by the `render` function.
+struct TestRoot {
+    render: Box<dyn Fn(&mut Window, &mut App) -> AnyElement>,
+}
+
+impl Render for TestRoot {
+    fn render(&mut self, window: &mut Window, cx: &mut Context<Self>) -> impl IntoElement {
+        // Align to the start to keep the intrinsic size of the element.
+        div()
+            .size_full()
+            .flex()
+            .items_start()
+            .child((self.render)(window, cx))
+    }
+}
+
+/// Mount the element built by `render` in a new test window with the theme `mode`,
+/// and run until all the pending work is done.
+///
+/// The returned context can be used to simulate the input, call
+/// `run_until_parked` on it to render again after that.
+pub fn mount<E>(
+    cx: &mut TestAppContext,
+    mode: ThemeMode,
+    render: impl Fn(&mut Window, &mut App) -> E + 'static,
+) -> &mut VisualTestContext
+where
+    E: IntoElement,
+{
+    let (_, cx) = cx.add_window_view(|window, cx| {
+        Theme::change(mode, Some(window), cx);
+        TestRoot {
+            render: Box::new(move |window, cx| render(window, cx).into_any_element()),
+        }
+    });
+    cx.run_until_parked();
+    cx
+}
+
+/// Returns the bounds of the element with the debug `selector` in the last frame.
+///
+/// Panics if there is no such element.
+pub fn bounds(cx: &mut VisualTestContext, selector: &'static str) -> Bounds<Pixels> {
+    cx.debug_bounds(selector)
+        .unwrap_or_else(|| panic!("no element with debug selector `{}`", selector))
+}
+
+/// Returns the layout of the elements by the debug `selectors` in the last frame,
+/// one `selector: x, y, width x height` line each, to compare in the assertions.
+///
+/// A missing element is `selector: none`, so the snapshot is still comparable.
+pub fn layout_snapshot(cx: &mut VisualTestContext, selectors: &[&'static str]) -> String {
+    selectors
+        .iter()
+        .map(|selector| match cx.debug_bounds(selector) {
+            Some(bounds) => format!(
+                "{}: {}, {}, {} x {}",
+                selector,
+                f32::from(bounds.origin.x),
+                f32::from(bounds.origin.y),
+                f32::from(bounds.size.width),
+                f32::from(bounds.size.height),
+            ),
+            None => format!("{}: none", selector),
+        })
+        .collect::<Vec<_>>()
+        .join("\n")
+}
@@ -1,3 +1,4 @@
 pub(super) mod html;
 mod html5minify;
 pub(super) mod markdown;
+pub(super) mod plain;
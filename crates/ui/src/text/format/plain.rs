@@ -0,0 +1,47 @@
+use std::ops::Range;
+
+use gpui::{App, Entity, HighlightStyle, IntoElement, RenderOnce, SharedString, Window};
+
+use crate::text::{inline::Inline, TextViewState};
+
+/// Plain text renderer, the text will be rendered as a single paragraph without parsing.
+///
+/// See also [`crate::text::TextView`]
+#[derive(IntoElement, Clone)]
+pub(crate) struct PlainElement {
+    text: SharedString,
+    highlights: Vec<(Range<usize>, HighlightStyle)>,
+    state: Entity<TextViewState>,
+}
+
+impl PlainElement {
+    pub(crate) fn new(raw: impl Into<SharedString>, state: Entity<TextViewState>) -> Self {
+        Self {
+            state,
+            text: raw.into(),
+            highlights: vec![],
+        }
+    }
+
+    /// Set the source of the plain text view.
+    pub(crate) fn text(mut self, raw: impl Into<SharedString>) -> Self {
+        self.text = raw.into();
+        self
+    }
+
+    /// Set the highlights of the text.
+    pub(crate) fn highlights(mut self, highlights: Vec<(Range<usize>, HighlightStyle)>) -> Self {
+        self.highlights = highlights;
+        self
+    }
+}
+
+impl RenderOnce for PlainElement {
+    fn render(self, _: &mut Window, cx: &mut App) -> impl IntoElement {
+        let inline_state = self
+            .state
+            .update(cx, |state, _| state.set_plain_if_needed(self.text.clone()));
+
+        Inline::new("plain", inline_state, vec![], self.highlights)
+    }
+}
@@ -3,8 +3,8 @@ use std::{cell::RefCell, ops::Range, rc::Rc};
 use gpui::{
     point, px, quad, App, BorderStyle, Bounds, CursorStyle, Edges, Element, ElementId,
     GlobalElementId, Half, HighlightStyle, Hitbox, HitboxBehavior, InspectorElementId, IntoElement,
-    LayoutId, MouseMoveEvent, MouseUpEvent, Pixels, Point, SharedString, StyledText, TextLayout,
-    Window,
+    LayoutId, MouseDownEvent, MouseMoveEvent, MouseUpEvent, Pixels, Point, SharedString,
+    StyledText, TextLayout, Window,
 };
 
 use crate::{
@@ -330,6 +330,50 @@ impl Element for Inline {
             self.paint_selection(&selection, &text_layout, &bounds, window, cx);
         }
 
+        // double click to select word, triple click to select line.
+        if is_selectable {
+            if let Some(text_view_state) = GlobalState::global(cx).text_view_state().cloned() {
+                window.on_mouse_event({
+                    let text = self.text.clone();
+                    let text_layout = text_layout.clone();
+                    move |event: &MouseDownEvent, phase, _, cx| {
+                        if !phase.bubble()
+                            || event.click_count < 2
+                            || !bounds.contains(&event.position)
+                        {
+                            return;
+                        }
+
+                        let offset = text_layout
+                            .index_for_position(event.position)
+                            .unwrap_or_else(|ix| ix);
+                        let range = if event.click_count == 2 {
+                            word_range(&text, offset)
+                        } else {
+                            line_range(&text, offset)
+                        };
+                        let (Some(start), Some(end)) = (
+                            text_layout.position_for_index(range.start),
+                            text_layout.position_for_index(range.end),
+                        ) else {
+                            return;
+                        };
+
+                        // Keep the selection bounds inside the lines of the range,
+                        // see `point_in_text_selection`.
+                        let line_height = text_layout.line_height();
+                        text_view_state.update(cx, |state, _| {
+                            state.select_between(
+                                point(start.x, start.y + line_height.half()),
+                                point(end.x, end.y + line_height - px(1.)),
+                            );
+                        });
+                        cx.notify(current_view);
+                    }
+                });
+            }
+        }
+
         // mouse move, update hovered link
         window.on_mouse_event({
             let hitbox = hitbox.clone();
@@ -373,6 +417,53 @@ impl Element for Inline {
     }
 }
 
+/// Return the range of the word at the `offset`.
+///
+/// If the `offset` is not on a word, the character at the `offset` is returned.
+fn word_range(text: &str, offset: usize) -> Range<usize> {
+    #[inline(always)]
+    fn is_word(c: char) -> bool {
+        c.is_alphanumeric() || matches!(c, '_')
+    }
+
+    let offset = offset.min(text.len());
+    let mut start = offset;
+    for c in text[..offset].chars().rev() {
+        if !is_word(c) {
+            break;
+        }
+        start -= c.len_utf8();
+    }
+
+    let mut end = offset;
+    for c in text[offset..].chars() {
+        if !is_word(c) {
+            break;
+        }
+        end += c.len_utf8();
+    }
+
+    if start == end {
+        if let Some(c) = text[offset..].chars().next() {
+            end += c.len_utf8();
+        }
+    }
+
+    start..end
+}
+
+/// Return the range of the line (split by `\n`) at the `offset`, without the line break.
+fn line_range(text: &str, offset: usize) -> Range<usize> {
+    let offset = offset.min(text.len());
+    let start = text[..offset].rfind('\n').map(|ix| ix + 1).unwrap_or(0);
+    let end = text[offset..]
+        .find('\n')
+        .map(|ix| offset + ix)
+        .unwrap_or(text.len());
+
+    start..end
+}
+
 /// Check if a `pos` is within a `bounds`, considering multi-line selections.
 fn point_in_text_selection(
     pos: Point<Pixels>,
@@ -410,9 +501,35 @@ fn point_in_text_selection(
 
 #[cfg(test)]
 mod tests {
-    use super::point_in_text_selection;
+    use super::{line_range, point_in_text_selection, word_range};
     use gpui::{point, px, size, Bounds};
 
+    #[test]
+    fn test_word_range() {
+        let text = "Hello world, foo_bar 你好";
+        assert_eq!(word_range(text, 0), 0..5);
+        assert_eq!(word_range(text, 3), 0..5);
+        assert_eq!(word_range(text, 5), 0..5);
+        assert_eq!(word_range(text, 8), 6..11);
+        // not on a word, select the char
+        assert_eq!(word_range(text, 12), 12..13);
+        assert_eq!(word_range(text, 15), 13..20);
+        assert_eq!(word_range(text, 21), 21..27);
+        assert_eq!(word_range(text, 100), 21..27);
+        assert_eq!(word_range("", 0), 0..0);
+    }
+
+    #[test]
+    fn test_line_range() {
+        let text = "first line\nsecond line\n\nlast";
+        assert_eq!(line_range(text, 0), 0..10);
+        assert_eq!(line_range(text, 10), 0..10);
+        assert_eq!(line_range(text, 11), 11..22);
+        assert_eq!(line_range(text, 23), 23..23);
+        assert_eq!(line_range(text, 26), 24..28);
+        assert_eq!(line_range("single", 3), 0..6);
+    }
+
     #[test]
     fn test_point_in_text_selection() {
         let line_height = px(20.);
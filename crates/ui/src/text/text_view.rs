@@ -1,18 +1,21 @@
-use std::{rc::Rc, sync::Arc, time::Instant};
+use std::{ops::Range, rc::Rc, sync::Arc, time::Instant};
 
 use gpui::{
     div, px, rems, AnyElement, App, Bounds, ClipboardItem, Element, ElementId, Entity, FocusHandle,
-    GlobalElementId, InspectorElementId, InteractiveElement, IntoElement, KeyBinding, LayoutId,
-    MouseDownEvent, MouseMoveEvent, MouseUpEvent, ParentElement, Pixels, Point, Rems, RenderOnce,
-    SharedString, Size, Window,
+    GlobalElementId, HighlightStyle, InspectorElementId, InteractiveElement, IntoElement,
+    KeyBinding, LayoutId, MouseDownEvent, MouseMoveEvent, MouseUpEvent, ParentElement, Pixels,
+    Point, Rems, RenderOnce, SharedString, Size, Window,
 };
 
-use super::format::{html::HtmlElement, markdown::MarkdownElement};
+use super::format::{html::HtmlElement, markdown::MarkdownElement, plain::PlainElement};
 use crate::{
     global_state::GlobalState,
     highlighter::HighlightTheme,
     input::{self},
-    text::node::{self, NodeContext},
+    text::{
+        inline::InlineState,
+        node::{self, NodeContext, Paragraph},
+    },
 };
 
 const CONTEXT: &'static str = "TextView";
@@ -30,6 +33,7 @@ pub(crate) fn init(cx: &mut App) {
 enum TextViewElement {
     Markdown(MarkdownElement),
     Html(HtmlElement),
+    Plain(PlainElement),
 }
 
 impl RenderOnce for TextViewElement {
@@ -37,11 +41,12 @@ impl RenderOnce for TextViewElement {
         match self {
             Self::Markdown(el) => el.render(window, cx).into_any_element(),
             Self::Html(el) => el.render(window, cx).into_any_element(),
+            Self::Plain(el) => el.render(window, cx).into_any_element(),
         }
     }
 }
 
-/// A text view that can render Markdown or HTML.
+/// A text view that can render Markdown, HTML or plain text.
 ///
 /// ## Goals
 ///
@@ -56,7 +61,7 @@ impl RenderOnce for TextViewElement {
 /// - As a Markdown editor or viewer (If you want to like this, you must fork your version).
 /// - As a HTML viewer, we not support CSS, we only support basic HTML tags for used to as a content reader.
 ///
-/// See also [`MarkdownElement`], [`HtmlElement`]
+/// See also [`MarkdownElement`], [`HtmlElement`], [`PlainElement`]
 #[derive(Clone)]
 pub struct TextView {
     id: ElementId,
@@ -147,6 +152,26 @@ impl TextViewState {
         self.clear_selection();
     }
 
+    /// Use the `text` as a single paragraph without parsing,
+    /// returns the [`InlineState`] to render it.
+    pub(super) fn set_plain_if_needed(&mut self, text: SharedString) -> InlineState {
+        if self.root.is_none() || self.raw != text {
+            let paragraph = Paragraph::default();
+            paragraph.state.set_text(text.clone());
+            self.raw = text;
+            self.root = Some(Ok(Rc::new(node::Node::Paragraph(paragraph))));
+            self.clear_selection();
+        }
+
+        match self.root() {
+            Ok(node) => match node.as_ref() {
+                node::Node::Paragraph(paragraph) => paragraph.state.clone(),
+                _ => InlineState::default(),
+            },
+            Err(_) => InlineState::default(),
+        }
+    }
+
     /// Save bounds and unselect if bounds changed.
     fn update_bounds(&mut self, bounds: Bounds<Pixels>) {
         if self.bounds.size != bounds.size {
@@ -177,6 +202,15 @@ impl TextViewState {
         self.is_selecting = false;
     }
 
+    /// Select between two window positions, used by double or triple click.
+    pub(super) fn select_between(&mut self, start: Point<Pixels>, end: Point<Pixels>) {
+        self.selection_positions = (
+            Some(start - self.bounds.origin),
+            Some(end - self.bounds.origin),
+        );
+        self.is_selecting = false;
+    }
+
     pub(crate) fn has_selection(&self) -> bool {
         if let (Some(start), Some(end)) = self.selection_positions {
             start != end
@@ -351,9 +385,43 @@ impl TextView {
         }
     }
 
+    /// Create a new plain text view, the text will be rendered as is.
+    pub fn plain(
+        id: impl Into<ElementId>,
+        raw: impl Into<SharedString>,
+        window: &mut Window,
+        cx: &mut App,
+    ) -> Self {
+        let id: ElementId = id.into();
+        let state =
+            window.use_keyed_state(SharedString::from(format!("{}/state", id)), cx, |_, cx| {
+                TextViewState::new(cx)
+            });
+
+        Self {
+            id,
+            state: state.clone(),
+            element: TextViewElement::Plain(PlainElement::new(raw, state)),
+            selectable: false,
+        }
+    }
+
     /// Set the text view to be selectable, default is false.
-    pub fn selectable(mut self) -> Self {
-        self.selectable = true;
+    ///
+    /// When selectable, drag to select text, double click to select a word,
+    /// triple click to select a line, and press `cmd-c` (`ctrl-c`) to copy.
+    pub fn selectable(mut self, selectable: bool) -> Self {
+        self.selectable = selectable;
+        self
+    }
+
+    /// Set the highlights of the plain text view.
+    ///
+    /// Do nothing if this is Markdown or HTML.
+    pub(crate) fn highlights(mut self, highlights: Vec<(Range<usize>, HighlightStyle)>) -> Self {
+        if let TextViewElement::Plain(el) = self.element {
+            self.element = TextViewElement::Plain(el.highlights(highlights));
+        }
         self
     }
 
@@ -362,6 +430,7 @@ impl TextView {
         self.element = match self.element {
             TextViewElement::Markdown(el) => TextViewElement::Markdown(el.text(raw)),
             TextViewElement::Html(el) => TextViewElement::Html(el.text(raw)),
+            TextViewElement::Plain(el) => TextViewElement::Plain(el.text(raw)),
         };
         self
     }
@@ -371,6 +440,7 @@ impl TextView {
         self.element = match self.element {
             TextViewElement::Markdown(el) => TextViewElement::Markdown(el.style(style)),
             TextViewElement::Html(el) => TextViewElement::Html(el.style(style)),
+            TextViewElement::Plain(el) => TextViewElement::Plain(el),
         };
         self
     }
@@ -475,11 +545,20 @@ impl Element for TextView {
 
             window.on_mouse_event({
                 let state = self.state.clone();
-                move |event: &MouseDownEvent, phase, _, cx| {
+                move |event: &MouseDownEvent, phase, window, cx| {
                     if !bounds.contains(&event.position) || !phase.bubble() {
                         return;
                     }
 
+                    if let Some(focus_handle) = state.read(cx).focus_handle.clone() {
+                        window.focus(&focus_handle);
+                    }
+
+                    // Double or triple click is handled by the `Inline`.
+                    if event.click_count > 1 {
+                        return;
+                    }
+
                     state.update(cx, |state, _| {
                         state.start_selection(event.position);
                     });
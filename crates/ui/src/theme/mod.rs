@@ -23,6 +23,8 @@ pub fn init(cx: &mut App) {
 
     Theme::sync_system_appearance(None, cx);
     Theme::sync_scrollbar_appearance(cx);
+    Theme::sync_reduced_motion(cx);
+    crate::animation::watch_system_reduced_motion(cx);
 }
 
 pub trait ActiveTheme {
@@ -58,6 +60,11 @@ pub struct Theme {
     pub tile_grid_size: Pixels,
     /// The shadow of the tile panel.
     pub tile_shadow: bool,
+    /// Reduce the non-essential animations, default follows the system setting, see
+    /// [`Theme::sync_reduced_motion`].
+    ///
+    /// Use [`crate::animation::AnimationSettings`] to toggle it.
+    pub reduced_motion: bool,
 }
 
 impl Default for Theme {
@@ -147,6 +154,34 @@ impl Theme {
         };
     }
 
+    /// Sync the reduced motion preference with the system, it is called by [`init`].
+    ///
+    /// The `GPUI_REDUCED_MOTION` env is applied at once, otherwise the OS setting is read in
+    /// the background and applied when done, unless the animations are toggled meanwhile.
+    ///
+    /// The OS setting is cached after the first read, and a later change is watched by [`init`].
+    pub fn sync_reduced_motion(cx: &mut App) {
+        if let Some(reduced_motion) = crate::animation::reduced_motion_override() {
+            Theme::global_mut(cx).reduced_motion = reduced_motion;
+            return;
+        }
+
+        let initial = Theme::global(cx).reduced_motion;
+        let detect = cx.background_spawn(async { crate::animation::system_reduced_motion() });
+        cx.spawn(async move |cx| {
+            let reduced_motion = detect.await;
+            _ = cx.update(|cx| {
+                let theme = Theme::global_mut(cx);
+                if theme.reduced_motion != initial || theme.reduced_motion == reduced_motion {
+                    return;
+                }
+                theme.reduced_motion = reduced_motion;
+                cx.refresh_windows();
+            });
+        })
+        .detach();
+    }
+
     pub fn change(mode: impl Into<ThemeMode>, window: Option<&mut Window>, cx: &mut App) {
         let mode = mode.into();
         if !cx.has_global::<Theme>() {
@@ -189,6 +224,7 @@ impl From<ThemeColor> for Theme {
             scrollbar_show: ScrollbarShow::default(),
             tile_grid_size: px(8.),
             tile_shadow: true,
+            reduced_motion: false,
             colors,
             light_theme: Rc::new(ThemeConfig::default()),
             dark_theme: Rc::new(ThemeConfig::default()),
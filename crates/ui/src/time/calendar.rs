@@ -14,7 +14,7 @@ use crate::{
     StyledExt as _,
 };
 
-use super::utils::days_in_month;
+use super::utils::{days_in_month, RANGE_SEPARATOR};
 
 pub enum CalendarEvent {
     /// The user selected a date.
@@ -120,7 +120,8 @@ impl Date {
         match self {
             Self::Single(Some(date)) => Some(date.format(format).to_string().into()),
             Self::Range(Some(start), Some(end)) => {
-                Some(format!("{} - {}", start.format(format), end.format(format)).into())
+                let (start, end) = (start.format(format), end.format(format));
+                Some(format!("{}{}{}", start, RANGE_SEPARATOR, end).into())
             }
             _ => None,
         }
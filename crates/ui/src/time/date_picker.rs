@@ -1,6 +1,6 @@
 use std::rc::Rc;
 
-use chrono::NaiveDate;
+use chrono::{Local, NaiveDate};
 use gpui::{
     anchored, deferred, div, prelude::FluentBuilder as _, px, App, AppContext, Context, ElementId,
     Empty, Entity, EventEmitter, FocusHandle, Focusable, InteractiveElement as _, IntoElement,
@@ -13,12 +13,18 @@ use crate::{
     actions::Cancel,
     button::{Button, ButtonVariants as _},
     h_flex,
-    input::clear_button,
+    input::{clear_button, InputEvent, InputState, TextInput},
     v_flex, ActiveTheme, Disableable, Icon, IconName, Sizable, Size, StyleSized as _,
     StyledExt as _,
 };
 
-use super::calendar::{Calendar, CalendarEvent, CalendarState, Date, Matcher};
+use super::{
+    calendar::{Calendar, CalendarEvent, CalendarState, Date, Matcher},
+    utils::{
+        last_days_range, last_quarter_range, parse_date, parse_date_range, this_month_range,
+        ParseDateError,
+    },
+};
 
 pub fn init(cx: &mut App) {
     let context = Some("DatePicker");
@@ -57,6 +63,48 @@ impl DateRangePreset {
             value: DateRangePresetValue::Range(start, end),
         }
     }
+
+    /// Creates a "Today" preset.
+    pub fn today() -> Self {
+        Self::single(t!("DatePicker.preset.today"), today())
+    }
+
+    /// Creates a "Last N days" preset, the range is end with today.
+    pub fn last_days(days: u32) -> Self {
+        let (start, end) = last_days_range(today(), days);
+        Self::range(t!("DatePicker.preset.last_days", days = days), start, end)
+    }
+
+    /// Creates a "This month" preset, the range is from the first day of the month to today.
+    pub fn this_month() -> Self {
+        let (start, end) = this_month_range(today());
+        Self::range(t!("DatePicker.preset.this_month"), start, end)
+    }
+
+    /// Creates a "Last quarter" preset, the range is the whole previous quarter.
+    pub fn last_quarter() -> Self {
+        let (start, end) = last_quarter_range(today());
+        Self::range(t!("DatePicker.preset.last_quarter"), start, end)
+    }
+
+    /// Returns the common presets for a range date picker:
+    /// "Today", "Last 7 days", "Last 30 days", "This month" and "Last quarter".
+    ///
+    /// The ranges are relative to the time of calling, so create them in render or refresh
+    /// them for the long-lived views.
+    pub fn defaults() -> Vec<Self> {
+        vec![
+            Self::today(),
+            Self::last_days(7),
+            Self::last_days(30),
+            Self::this_month(),
+            Self::last_quarter(),
+        ]
+    }
+}
+
+fn today() -> NaiveDate {
+    Local::now().naive_local().date()
 }
 
 /// Use to store the state of the date picker.
@@ -65,6 +113,10 @@ pub struct DatePickerState {
     date: Date,
     open: bool,
     calendar: Entity<CalendarState>,
+    /// The input to type the date in the `date_format`.
+    input: Entity<InputState>,
+    /// The error of the typed date, shown under the input.
+    input_error: Option<SharedString>,
     date_format: SharedString,
     number_of_months: usize,
     disabled_matcher: Option<Rc<Matcher>>,
@@ -102,21 +154,28 @@ impl DatePickerState {
             this
         });
 
-        let _subscriptions = vec![cx.subscribe_in(
-            &calendar,
-            window,
-            |this, _, ev: &CalendarEvent, window, cx| match ev {
-                CalendarEvent::Selected(date) => {
-                    this.update_date(*date, true, window, cx);
-                    this.focus_handle.focus(window);
-                }
-            },
-        )];
+        let input = cx.new(|cx| InputState::new(window, cx));
+
+        let _subscriptions = vec![
+            cx.subscribe_in(
+                &calendar,
+                window,
+                |this, _, ev: &CalendarEvent, window, cx| match ev {
+                    CalendarEvent::Selected(date) => {
+                        this.update_date(*date, true, window, cx);
+                        this.focus_handle.focus(window);
+                    }
+                },
+            ),
+            cx.subscribe_in(&input, window, Self::on_input_event),
+        ];
 
         Self {
             focus_handle: cx.focus_handle(),
             date,
             calendar,
+            input,
+            input_error: None,
             open: false,
             date_format: "%Y/%m/%d".into(),
             number_of_months: 1,
@@ -126,6 +185,8 @@ impl DatePickerState {
     }
 
     /// Set the date format of the date picker to display in Input, default: "%Y/%m/%d".
+    ///
+    /// This is also the format to parse the typed date, see [`DatePicker::editable`].
     pub fn date_format(mut self, format: impl Into<SharedString>) -> Self {
         self.date_format = format.into();
         self
@@ -153,6 +214,7 @@ impl DatePickerState {
             view.set_date(date, window, cx);
         });
         self.open = false;
+        self.sync_input(window, cx);
         if emit {
             cx.emit(DatePickerEvent::Change(date));
         }
@@ -213,25 +275,109 @@ impl DatePickerState {
         }
     }
 
-    fn toggle_calendar(&mut self, _: &gpui::ClickEvent, _: &mut Window, cx: &mut Context<Self>) {
+    fn toggle_calendar(
+        &mut self,
+        _: &gpui::ClickEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
         self.open = !self.open;
+        self.sync_input(window, cx);
         cx.notify();
     }
 
+    /// Reset the input to the current date, and clear the error.
+    fn sync_input(&mut self, window: &mut Window, cx: &mut Context<Self>) {
+        let text = self.date.format(&self.date_format).unwrap_or_default();
+        self.input.update(cx, |input, cx| {
+            if input.value() != text {
+                input.set_value(text, window, cx);
+            }
+        });
+        self.input_error = None;
+    }
+
+    fn on_input_event(
+        &mut self,
+        _: &Entity<InputState>,
+        event: &InputEvent,
+        window: &mut Window,
+        cx: &mut Context<Self>,
+    ) {
+        match event {
+            InputEvent::Change(_) => {
+                if self.input_error.take().is_some() {
+                    cx.notify();
+                }
+            }
+            InputEvent::PressEnter { .. } => match self.parse_input(cx) {
+                Ok(date) => {
+                    self.update_date(date, true, window, cx);
+                    self.focus_handle.focus(window);
+                }
+                Err(err) => {
+                    self.input_error = Some(err);
+                    cx.notify();
+                }
+            },
+            _ => {}
+        }
+    }
+
+    /// Parse the typed date in the `date_format`, an empty input is to clear the date.
+    fn parse_input(&self, cx: &App) -> Result<Date, SharedString> {
+        let text = self.input.read(cx).value();
+        let text = text.trim();
+        let format = self.date_format.as_ref();
+
+        let date = match self.date {
+            Date::Single(_) if text.is_empty() => Date::Single(None),
+            Date::Range(_, _) if text.is_empty() => Date::Range(None, None),
+            Date::Single(_) => parse_date(text, format).map(|date| Date::Single(Some(date))),
+            Date::Range(_, _) => parse_date_range(text, format).map(Date::from),
+        }
+        .map_err(|err| -> SharedString {
+            match err {
+                ParseDateError::Invalid => {
+                    let today = today();
+                    let example = match self.date {
+                        Date::Single(_) => Date::from(today),
+                        Date::Range(_, _) => Date::from((today, today)),
+                    };
+                    let example = example.format(format).unwrap_or_default();
+                    t!("DatePicker.invalid", example = example).into()
+                }
+                ParseDateError::InvalidRange => t!("DatePicker.invalid_range").into(),
+            }
+        })?;
+
+        if let Some(matcher) = &self.disabled_matcher {
+            if matcher.date_matched(&date) {
+                return Err(t!("DatePicker.unavailable").into());
+            }
+        }
+
+        Ok(date)
+    }
+
     fn select_preset(
         &mut self,
         preset: &DateRangePreset,
         window: &mut Window,
         cx: &mut Context<Self>,
     ) {
-        match preset.value {
-            DateRangePresetValue::Single(single) => {
-                self.update_date(Date::Single(Some(single)), true, window, cx)
+        // Keep the mode of the date picker, e.g.: "Today" is a one day range in range mode.
+        let date = match (self.date, &preset.value) {
+            (Date::Single(_), DateRangePresetValue::Single(date))
+            | (Date::Single(_), DateRangePresetValue::Range(date, _)) => Date::Single(Some(*date)),
+            (Date::Range(_, _), DateRangePresetValue::Single(date)) => {
+                Date::Range(Some(*date), Some(*date))
             }
-            DateRangePresetValue::Range(start, end) => {
-                self.update_date(Date::Range(Some(start), Some(end)), true, window, cx)
+            (Date::Range(_, _), DateRangePresetValue::Range(start, end)) => {
+                Date::Range(Some(*start), Some(*end))
             }
-        }
+        };
+        self.update_date(date, true, window, cx)
     }
 }
 
@@ -245,6 +391,7 @@ pub struct DatePicker {
     size: Size,
     number_of_months: usize,
     presets: Option<Vec<DateRangePreset>>,
+    editable: bool,
     appearance: bool,
     disabled: bool,
 }
@@ -291,6 +438,7 @@ impl DatePicker {
             style: StyleRefinement::default(),
             number_of_months: 2,
             presets: None,
+            editable: false,
             appearance: true,
             disabled: false,
         }
@@ -314,6 +462,15 @@ impl DatePicker {
         self
     }
 
+    /// Set true to show an input in the popup to type the date, default is false.
+    ///
+    /// The date is parsed in the [`DatePickerState::date_format`] after pressing Enter,
+    /// a range is typed as "start - end". An invalid date shows an error and is not committed.
+    pub fn editable(mut self, editable: bool) -> Self {
+        self.editable = editable;
+        self
+    }
+
     /// Set number of months to display in the calendar, default is 2.
     pub fn number_of_months(mut self, number_of_months: usize) -> Self {
         self.number_of_months = number_of_months;
@@ -455,11 +612,33 @@ impl RenderOnce for DatePicker {
                                             )
                                         })
                                         .child(
-                                            Calendar::new(&state.calendar)
-                                                .number_of_months(self.number_of_months)
-                                                .border_0()
-                                                .rounded_none()
-                                                .with_size(self.size),
+                                            v_flex()
+                                                .gap_2()
+                                                .when(self.editable, |this| {
+                                                    this.child(
+                                                        TextInput::new(&state.input)
+                                                            .small()
+                                                            .cleanable(),
+                                                    )
+                                                    .when_some(
+                                                        state.input_error.clone(),
+                                                        |this, err| {
+                                                            this.child(
+                                                                div()
+                                                                    .text_xs()
+                                                                    .text_color(cx.theme().danger)
+                                                                    .child(err),
+                                                            )
+                                                        },
+                                                    )
+                                                })
+                                                .child(
+                                                    Calendar::new(&state.calendar)
+                                                        .number_of_months(self.number_of_months)
+                                                        .border_0()
+                                                        .rounded_none()
+                                                        .with_size(self.size),
+                                                ),
                                         ),
                                 ),
                         ),
@@ -85,11 +85,120 @@ pub(crate) fn days_in_month(year: i32, month: u32) -> Vec<Vec<NaiveDate>> {
     days
 }
 
+/// The separator between the start and end of a formatted date range.
+pub(crate) const RANGE_SEPARATOR: &str = " - ";
+
+/// The error of parsing a typed date.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub(crate) enum ParseDateError {
+    /// The text does not match the format.
+    Invalid,
+    /// The end date is before the start date.
+    InvalidRange,
+}
+
+/// Parse the `text` as a date in the `format`, e.g.: "%Y/%m/%d".
+pub(crate) fn parse_date(text: &str, format: &str) -> Result<NaiveDate, ParseDateError> {
+    NaiveDate::parse_from_str(text.trim(), format).map_err(|_| ParseDateError::Invalid)
+}
+
+/// Parse the `text` as a date range in the `format`, the dates are separated by " - ".
+pub(crate) fn parse_date_range(
+    text: &str,
+    format: &str,
+) -> Result<(NaiveDate, NaiveDate), ParseDateError> {
+    let (start, end) = text
+        .split_once(RANGE_SEPARATOR)
+        .ok_or(ParseDateError::Invalid)?;
+    let start = parse_date(start, format)?;
+    let end = parse_date(end, format)?;
+    if end < start {
+        return Err(ParseDateError::InvalidRange);
+    }
+
+    Ok((start, end))
+}
+
+/// Returns the range of the last `days` days, including the `today`.
+pub(crate) fn last_days_range(today: NaiveDate, days: u32) -> (NaiveDate, NaiveDate) {
+    let start = today - Duration::days(days.saturating_sub(1) as i64);
+    (start, today)
+}
+
+/// Returns the range from the first day of the month to the `today`.
+pub(crate) fn this_month_range(today: NaiveDate) -> (NaiveDate, NaiveDate) {
+    (today.with_day(1).unwrap(), today)
+}
+
+/// Returns the range of the whole quarter before the quarter of the `today`.
+pub(crate) fn last_quarter_range(today: NaiveDate) -> (NaiveDate, NaiveDate) {
+    let quarter_start_month = (today.month() - 1) / 3 * 3 + 1;
+    let quarter_start = NaiveDate::from_ymd_opt(today.year(), quarter_start_month, 1).unwrap();
+    let end = quarter_start - Duration::days(1);
+    let start = NaiveDate::from_ymd_opt(end.year(), end.month() - 2, 1).unwrap();
+    (start, end)
+}
+
 #[cfg(test)]
 mod tests {
     use chrono::{Datelike, NaiveDate};
 
-    use super::{days_in_month, NaiveDateExt};
+    use super::{
+        days_in_month, last_days_range, last_quarter_range, parse_date, parse_date_range,
+        this_month_range, NaiveDateExt, ParseDateError,
+    };
+
+    fn date(year: i32, month: u32, day: u32) -> NaiveDate {
+        NaiveDate::from_ymd_opt(year, month, day).unwrap()
+    }
+
+    #[test]
+    fn test_parse_date() {
+        assert_eq!(parse_date("2024/02/29", "%Y/%m/%d"), Ok(date(2024, 2, 29)));
+        assert_eq!(parse_date(" 2024-01-05 ", "%Y-%m-%d"), Ok(date(2024, 1, 5)));
+        assert_eq!(
+            parse_date("2023/02/29", "%Y/%m/%d"),
+            Err(ParseDateError::Invalid)
+        );
+        assert_eq!(
+            parse_date("2024-01-05", "%Y/%m/%d"),
+            Err(ParseDateError::Invalid)
+        );
+        assert_eq!(parse_date("", "%Y/%m/%d"), Err(ParseDateError::Invalid));
+
+        assert_eq!(
+            parse_date_range("2024-01-05 - 2024-02-01", "%Y-%m-%d"),
+            Ok((date(2024, 1, 5), date(2024, 2, 1)))
+        );
+        assert_eq!(
+            parse_date_range("2024/01/05", "%Y/%m/%d"),
+            Err(ParseDateError::Invalid)
+        );
+        assert_eq!(
+            parse_date_range("2024/02/01 - 2024/01/05", "%Y/%m/%d"),
+            Err(ParseDateError::InvalidRange)
+        );
+    }
+
+    #[test]
+    fn test_preset_ranges() {
+        let today = date(2024, 3, 5);
+        assert_eq!(last_days_range(today, 1), (today, today));
+        assert_eq!(last_days_range(today, 7), (date(2024, 2, 28), today));
+        assert_eq!(this_month_range(today), (date(2024, 3, 1), today));
+        assert_eq!(
+            last_quarter_range(today),
+            (date(2023, 10, 1), date(2023, 12, 31))
+        );
+        assert_eq!(
+            last_quarter_range(date(2024, 8, 20)),
+            (date(2024, 4, 1), date(2024, 6, 30))
+        );
+        assert_eq!(
+            last_quarter_range(date(2024, 12, 31)),
+            (date(2024, 7, 1), date(2024, 9, 30))
+        );
+    }
 
     #[test]
     fn test_days_in_month() {
@@ -0,0 +1,65 @@
+//! The opt-in vim-like navigation of the [`crate::list::List`] and [`crate::table::Table`],
+//! enabled by `List::vim_mode` or `Table::vim_mode`.
+//!
+//! | Key      | Action                  | Description                                  |
+//! | -------- | ----------------------- | -------------------------------------------- |
+//! | `j`      | [`MoveDown`]            | Select the next item.                        |
+//! | `k`      | [`MoveUp`]              | Select the previous item.                    |
+//! | `g`      | [`MoveToTop`]           | Select the first item.                       |
+//! | `G`      | [`MoveToBottom`]        | Select the last item.                        |
+//! | `/`      | [`StartFilter`]         | Focus the query input to filter.             |
+//! | `v`      | [`ToggleVisual`]        | Start or stop selecting a range of items.    |
+//! | `enter`  | `Confirm`               | Activate the selected item.                  |
+//! | `escape` | `Cancel`                | Stop the visual selection, or cancel.        |
+//!
+//! The bindings are in the [`CONTEXT`] key context, which is only pushed while the list or
+//! table itself is focused, so the keys are typed as text in a focused input or cell editor.
+//! Bind the actions to other keys in the context to customize them, e.g.:
+//!
+//! ```ignore
+//! ContextBindings::new(vim_mode::CONTEXT)
+//!     .bind("ctrl-d", vim_mode::MoveDown)
+//!     .bind("ctrl-u", vim_mode::MoveUp)
+//!     .register(cx);
+//! ```
+use gpui::{actions, App, KeyContext};
+
+use crate::keymap::ContextBindings;
+
+/// The key context of the vim mode bindings, pushed with the context of the component.
+pub const CONTEXT: &str = "VimMode";
+
+actions!(
+    vim_mode,
+    [
+        MoveDown,
+        MoveUp,
+        MoveToTop,
+        MoveToBottom,
+        StartFilter,
+        ToggleVisual
+    ]
+);
+
+pub(crate) fn init(cx: &mut App) {
+    ContextBindings::new(CONTEXT)
+        .bind("j", MoveDown)
+        .bind("k", MoveUp)
+        .bind("g", MoveToTop)
+        .bind("shift-g", MoveToBottom)
+        .bind("/", StartFilter)
+        .bind("v", ToggleVisual)
+        .register(cx);
+}
+
+/// Returns the key context of the component `context`, with the [`CONTEXT`] if `active`.
+///
+/// An element has one key context, so both are pushed on the same element.
+pub(crate) fn key_context(context: &'static str, active: bool) -> KeyContext {
+    let mut key_context = KeyContext::default();
+    key_context.add(context);
+    if active {
+        key_context.add(CONTEXT);
+    }
+    key_context
+}
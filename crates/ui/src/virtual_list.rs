@@ -10,6 +10,9 @@
 //! Unlike the `uniform_list`, the each item can have different size.
 //!
 //! This is useful for more complex layout, for example, a table with different row height.
+//!
+//! Use [`v_virtual_list_measured`] if the item sizes are unknown before rendering, e.g.: chat messages,
+//! the items will be measured once when they are visible, and the measured sizes are cached.
 use std::{
     cell::RefCell,
     cmp,
@@ -26,12 +29,19 @@ use gpui::{
 };
 use smallvec::SmallVec;
 
-use crate::{scroll::ScrollHandleOffsetable, AxisExt};
+use crate::{
+    scroll::{ScrollAlign, ScrollHandleOffsetable},
+    AxisExt,
+};
 
 struct VirtualListScrollHandleState {
     axis: Axis,
     items_count: usize,
     pub deferred_scroll_to_item: Option<DeferredScrollToItem>,
+    deferred_scroll_align: Option<(usize, ScrollAlign)>,
+    /// The items to be measured again in the next frame.
+    invalidated_items: Vec<usize>,
+    invalidate_all: bool,
 }
 
 #[derive(Clone)]
@@ -83,6 +93,9 @@ impl VirtualListScrollHandle {
                 axis: Axis::Vertical,
                 items_count: 0,
                 deferred_scroll_to_item: None,
+                deferred_scroll_align: None,
+                invalidated_items: Vec::new(),
+                invalidate_all: false,
             })),
             base_handle: ScrollHandle::default(),
         }
@@ -107,11 +120,32 @@ impl VirtualListScrollHandle {
         });
     }
 
+    /// Scroll to the item at the given index, and place it at the `align` position of the viewport.
+    ///
+    /// Unlike [`Self::scroll_to_item`], this always scrolls even if the item is already visible.
+    pub fn scroll_to(&self, ix: usize, align: ScrollAlign) {
+        let mut state = self.state.borrow_mut();
+        state.deferred_scroll_to_item = None;
+        state.deferred_scroll_align = Some((ix, align));
+    }
+
     /// Scrolls to the bottom of the list.
     pub fn scroll_to_bottom(&self) {
         let items_count = self.state.borrow().items_count;
         self.scroll_to_item(items_count.saturating_sub(1), ScrollStrategy::Top);
     }
+
+    /// Clear the cached size of the item, it will be measured again when it is visible.
+    ///
+    /// Call this when the content of the item is changed, only for [`v_virtual_list_measured`].
+    pub fn invalidate_item_size(&self, ix: usize) {
+        self.state.borrow_mut().invalidated_items.push(ix);
+    }
+
+    /// Clear all the cached item sizes, e.g.: the items have been reloaded.
+    pub fn invalidate_item_sizes(&self) {
+        self.state.borrow_mut().invalidate_all = true;
+    }
 }
 
 /// Create a [`VirtualList`] in vertical direction.
@@ -152,6 +186,32 @@ where
     virtual_list(view, id, Axis::Horizontal, item_sizes, f)
 }
 
+/// Create a [`VirtualList`] in vertical direction with unknown item heights.
+///
+/// Each item is measured once when it becomes visible and the height is cached,
+/// the `estimated_height` is used for the items that have not been measured yet.
+///
+/// When the items above the viewport are measured, the scroll offset is adjusted to keep
+/// the visible content in place.
+///
+/// Use [`VirtualListScrollHandle::invalidate_item_size`] to measure an item again after its content changed.
+pub fn v_virtual_list_measured<R, V>(
+    view: Entity<V>,
+    id: impl Into<ElementId>,
+    items_count: usize,
+    estimated_height: Pixels,
+    f: impl 'static + Fn(&mut V, Range<usize>, &mut Window, &mut Context<V>) -> Vec<R>,
+) -> VirtualList
+where
+    R: IntoElement,
+    V: Render,
+{
+    let mut list = virtual_list(view, id, Axis::Vertical, Rc::new(vec![]), f);
+    list.items_count = items_count;
+    list.estimated_size = Some(estimated_height);
+    list
+}
+
 pub(crate) fn virtual_list<R, V>(
     view: Entity<V>,
     id: impl Into<ElementId>,
@@ -187,6 +247,7 @@ where
         item_sizes,
         render_items: Box::new(render_range),
         sizing_behavior: ListSizingBehavior::default(),
+        estimated_size: None,
     }
 }
 
@@ -202,6 +263,8 @@ pub struct VirtualList {
         dyn for<'a> Fn(Range<usize>, &'a mut Window, &'a mut App) -> SmallVec<[AnyElement; 64]>,
     >,
     sizing_behavior: ListSizingBehavior,
+    /// The size of the unmeasured items, `Some` means the items have variable sizes to be measured.
+    estimated_size: Option<Pixels>,
 }
 
 impl Styled for VirtualList {
@@ -295,6 +358,42 @@ pub struct ItemSizeLayout {
     sizes: Vec<Pixels>,
     origins: Vec<Pixels>,
     last_layout_bounds: Bounds<Pixels>,
+    gap: Pixels,
+    /// The cached item sizes along the axis (without gap), `None` means not measured yet.
+    measured_sizes: Rc<RefCell<Vec<Option<Pixels>>>>,
+}
+
+impl ItemSizeLayout {
+    /// Apply the invalidated items, and returns the item sizes with the measured or estimated size.
+    fn measured_item_sizes(
+        &self,
+        axis: Axis,
+        items_count: usize,
+        estimated_size: Pixels,
+        scroll_state: &mut VirtualListScrollHandleState,
+    ) -> Vec<Size<Pixels>> {
+        let mut measured_sizes = self.measured_sizes.borrow_mut();
+        if std::mem::take(&mut scroll_state.invalidate_all) {
+            measured_sizes.clear();
+        }
+        for ix in scroll_state.invalidated_items.drain(..) {
+            if let Some(size) = measured_sizes.get_mut(ix) {
+                *size = None;
+            }
+        }
+        measured_sizes.resize(items_count, None);
+
+        measured_sizes
+            .iter()
+            .map(|measured| {
+                let item_size = measured.unwrap_or(estimated_size);
+                match axis {
+                    Axis::Horizontal => size(item_size, px(0.)),
+                    Axis::Vertical => size(px(0.), item_size),
+                }
+            })
+            .collect()
+    }
 }
 
 impl IntoElement for VirtualList {
@@ -344,6 +443,16 @@ impl Element for VirtualList {
                             .gap
                             .along(self.axis)
                             .to_pixels(font_size.into(), rem_size);
+                        state.gap = gap;
+
+                        if let Some(estimated_size) = self.estimated_size {
+                            self.item_sizes = Rc::new(state.measured_item_sizes(
+                                self.axis,
+                                self.items_count,
+                                estimated_size,
+                                &mut self.scroll_handle.state.borrow_mut(),
+                            ));
+                        }
 
                         if state.items_sizes != self.item_sizes {
                             state.items_sizes = self.item_sizes.clone();
@@ -499,6 +608,8 @@ impl Element for VirtualList {
 
         let item_sizes = &layout.size_layout.sizes;
         let item_origins = &layout.size_layout.origins;
+        let gap = layout.size_layout.gap;
+        let measured_sizes = layout.size_layout.measured_sizes.clone();
 
         let content_bounds = Bounds::from_corners(
             bounds.origin
@@ -540,7 +651,8 @@ impl Element for VirtualList {
         scroll_state.items_count = self.items_count;
 
         let mut scroll_offset = self.scroll_handle.offset();
-        if let Some(scroll_to_item) = scroll_state.deferred_scroll_to_item.take() {
+        let deferred_scroll_to_item = scroll_state.deferred_scroll_to_item.take();
+        if let Some(scroll_to_item) = deferred_scroll_to_item.clone() {
             scroll_offset = self.scroll_to_deferred_item(
                 scroll_offset,
                 &items_bounds,
@@ -548,6 +660,25 @@ impl Element for VirtualList {
                 scroll_to_item,
             );
         }
+        if let Some((ix, align)) = scroll_state.deferred_scroll_align.take() {
+            if let Some(item_bounds) = items_bounds.get(ix) {
+                let item_start = item_bounds.origin.along(axis) - content_bounds.origin.along(axis);
+                let viewport = content_bounds.size.along(axis);
+                let max_offset = layout.size_layout.content_size.along(axis) - viewport;
+                let offset = align.offset_for(
+                    item_start,
+                    item_bounds.size.along(axis),
+                    viewport,
+                    max_offset,
+                );
+                if axis.is_vertical() {
+                    scroll_offset.y = offset;
+                } else {
+                    scroll_offset.x = offset;
+                }
+                self.scroll_handle.set_offset(scroll_offset);
+            }
+        }
         scroll_offset = scroll_offset.min(&point(px(0.), px(0.)));
 
         self.base.interactivity().prepaint(
@@ -643,19 +774,93 @@ impl Element for VirtualList {
                     let visible_range = first_visible_element_ix
                         ..cmp::min(last_visible_element_ix, self.items_count);
 
-                    let items = (self.render_items)(visible_range.clone(), window, cx);
+                    let mut items = (self.render_items)(visible_range.clone(), window, cx);
+
+                    // The origins of the visible items, this may be changed by the measured sizes.
+                    let mut visible_origins = visible_range
+                        .clone()
+                        .map(|ix| item_origins[ix])
+                        .collect::<Vec<_>>();
+
+                    if self.estimated_size.is_some() {
+                        let available_space = match self.axis {
+                            Axis::Horizontal => size(
+                                AvailableSpace::MinContent,
+                                AvailableSpace::Definite(content_bounds.size.height),
+                            ),
+                            Axis::Vertical => size(
+                                AvailableSpace::Definite(content_bounds.size.width),
+                                AvailableSpace::MinContent,
+                            ),
+                        };
+                        let viewport_start = -scroll_offset.along(self.axis);
+
+                        let mut measured_sizes = measured_sizes.borrow_mut();
+                        let mut changed = false;
+                        let mut shift = px(0.);
+                        for (i, (item, ix)) in
+                            items.iter_mut().zip(visible_range.clone()).enumerate()
+                        {
+                            visible_origins[i] += shift;
+
+                            let measured = item
+                                .layout_as_root(available_space, window, cx)
+                                .along(self.axis);
+                            if measured_sizes.get(ix).copied().flatten() == Some(measured) {
+                                continue;
+                            }
+
+                            let new_size = if ix + 1 == self.items_count {
+                                measured
+                            } else {
+                                measured + gap
+                            };
+                            let delta = new_size - item_sizes[ix];
+                            measured_sizes[ix] = Some(measured);
+                            shift += delta;
+                            changed = true;
+
+                            // Keep the content anchored, if the item starts above the viewport,
+                            // the size change should not push the visible content.
+                            if item_origins[ix] < viewport_start {
+                                match self.axis {
+                                    Axis::Horizontal => scroll_offset.x -= delta,
+                                    Axis::Vertical => scroll_offset.y -= delta,
+                                }
+                            }
+                        }
+
+                        if changed {
+                            self.scroll_handle.set_offset(scroll_offset);
+                            // Scroll to the item again with the measured sizes.
+                            if deferred_scroll_to_item.is_some() {
+                                scroll_state.deferred_scroll_to_item =
+                                    deferred_scroll_to_item.clone();
+                            }
+                            // Layout again with the measured sizes in the next frame.
+                            window.request_animation_frame();
+                        }
+                    }
 
                     let content_mask = ContentMask { bounds };
                     window.with_content_mask(Some(content_mask), |window| {
-                        for (mut item, ix) in items.into_iter().zip(visible_range.clone()) {
+                        for (i, (mut item, ix)) in
+                            items.into_iter().zip(visible_range.clone()).enumerate()
+                        {
                             let item_origin = match self.axis {
                                 Axis::Horizontal => {
                                     content_bounds.origin
-                                        + point(item_origins[ix] + scroll_offset.x, scroll_offset.y)
+                                        + point(
+                                            visible_origins[i] + scroll_offset.x,
+                                            scroll_offset.y,
+                                        )
                                 }
                                 Axis::Vertical => {
                                     content_bounds.origin
-                                        + point(scroll_offset.x, item_origins[ix] + scroll_offset.y)
+                                        + point(
+                                            scroll_offset.x,
+                                            visible_origins[i] + scroll_offset.y,
+                                        )
                                 }
                             };
 
@@ -670,7 +875,10 @@ impl Element for VirtualList {
                                 ),
                             };
 
-                            item.layout_as_root(available_space, window, cx);
+                            // The measured items have been laid out above.
+                            if self.estimated_size.is_none() {
+                                item.layout_as_root(available_space, window, cx);
+                            }
                             item.prepaint_at(item_origin, window, cx);
                             layout.items.push(item);
                         }